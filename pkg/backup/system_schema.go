@@ -788,6 +788,15 @@ var systemTableBackupConfiguration = map[string]systemBackupConfiguration{
 	systemschema.SystemJobMessageTable.GetName(): {
 		shouldIncludeInClusterBackup: optOutOfClusterBackup,
 	},
+	systemschema.StatementPlanBaselinesTable.GetName(): {
+		shouldIncludeInClusterBackup: optOutOfClusterBackup,
+	},
+	systemschema.StatementHintsTable.GetName(): {
+		shouldIncludeInClusterBackup: optOutOfClusterBackup,
+	},
+	systemschema.IndexRecommendationsTable.GetName(): {
+		shouldIncludeInClusterBackup: optOutOfClusterBackup,
+	},
 	systemschema.SQLInstancesTable().GetName(): {
 		shouldIncludeInClusterBackup: optOutOfClusterBackup,
 	},
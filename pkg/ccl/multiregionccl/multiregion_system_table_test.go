@@ -288,6 +288,7 @@ func TestMrSystemDatabase(t *testing.T) {
 			tDB.CheckQueryResults(t, query, [][]string{
 				{"TABLE system.public.eventlog"},
 				{"TABLE system.public.external_connections"},
+				{"TABLE system.public.index_recommendations"},
 				{"TABLE system.public.job_info"},
 				{"TABLE system.public.job_message"},
 				{"TABLE system.public.job_progress"},
@@ -317,6 +318,8 @@ func TestMrSystemDatabase(t *testing.T) {
 				{"TABLE system.public.statement_diagnostics"},
 				{"TABLE system.public.statement_diagnostics_requests"},
 				{"TABLE system.public.statement_execution_insights"},
+				{"TABLE system.public.statement_hints"},
+				{"TABLE system.public.statement_plan_baselines"},
 				{"TABLE system.public.statement_statistics"},
 				{"TABLE system.public.table_metadata"},
 				{"TABLE system.public.task_payloads"},
@@ -333,6 +336,7 @@ func TestMrSystemDatabase(t *testing.T) {
 			sDB.CheckQueryResults(t, query, [][]string{
 				{"TABLE system.public.eventlog"},
 				{"TABLE system.public.external_connections"},
+				{"TABLE system.public.index_recommendations"},
 				{"TABLE system.public.job_info"},
 				{"TABLE system.public.job_message"},
 				{"TABLE system.public.job_progress"},
@@ -363,6 +367,8 @@ func TestMrSystemDatabase(t *testing.T) {
 				{"TABLE system.public.statement_diagnostics"},
 				{"TABLE system.public.statement_diagnostics_requests"},
 				{"TABLE system.public.statement_execution_insights"},
+				{"TABLE system.public.statement_hints"},
+				{"TABLE system.public.statement_plan_baselines"},
 				{"TABLE system.public.statement_statistics"},
 				{"TABLE system.public.table_metadata"},
 				{"TABLE system.public.task_payloads"},
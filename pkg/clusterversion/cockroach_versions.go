@@ -242,6 +242,17 @@ const (
 	// from ReplicaState to its own field.
 	V25_1_MoveRaftTruncatedState
 
+	// V25_1_AddStatementPlanBaselinesTable adds the
+	// system.statement_plan_baselines table.
+	V25_1_AddStatementPlanBaselinesTable
+
+	// V25_1_AddStatementHintsTable adds the system.statement_hints table.
+	V25_1_AddStatementHintsTable
+
+	// V25_1_AddIndexRecommendationsTable adds the
+	// system.index_recommendations table and the index advisor schedule.
+	V25_1_AddIndexRecommendationsTable
+
 	// *************************************************
 	// Step (1) Add new versions above this comment.
 	// Do not add new versions to a patch release.
@@ -297,8 +308,11 @@ var versionTable = [numKeys]roachpb.Version{
 	// v25.1 versions. Internal versions must be even.
 	V25_1_Start: {Major: 24, Minor: 3, Internal: 2},
 
-	V25_1_AddJobsTables:          {Major: 24, Minor: 3, Internal: 4},
-	V25_1_MoveRaftTruncatedState: {Major: 24, Minor: 3, Internal: 6},
+	V25_1_AddJobsTables:                  {Major: 24, Minor: 3, Internal: 4},
+	V25_1_MoveRaftTruncatedState:         {Major: 24, Minor: 3, Internal: 6},
+	V25_1_AddStatementPlanBaselinesTable: {Major: 24, Minor: 3, Internal: 8},
+	V25_1_AddStatementHintsTable:         {Major: 24, Minor: 3, Internal: 10},
+	V25_1_AddIndexRecommendationsTable:   {Major: 24, Minor: 3, Internal: 12},
 
 	// *************************************************
 	// Step (2): Add new versions above this comment.
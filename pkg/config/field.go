@@ -20,16 +20,18 @@ var _ redact.SafeValue = Field(0)
 //go:generate stringer --type=Field --linecomment
 
 const (
-	_                Field = iota
-	RangeMinBytes          // range_min_bytes
-	RangeMaxBytes          // range_max_bytes
-	GlobalReads            // global_reads
-	NumReplicas            // num_replicas
-	NumVoters              // num_voters
-	GCTTL                  // gc.ttlseconds
-	Constraints            // constraints
-	VoterConstraints       // voter_constraints
-	LeasePreferences       // lease_preferences
+	_                            Field = iota
+	RangeMinBytes                      // range_min_bytes
+	RangeMaxBytes                      // range_max_bytes
+	GlobalReads                        // global_reads
+	NumReplicas                        // num_replicas
+	NumVoters                          // num_voters
+	GCTTL                              // gc.ttlseconds
+	Constraints                        // constraints
+	VoterConstraints                   // voter_constraints
+	LeasePreferences                   // lease_preferences
+	RateLimitRequestsPerSecond         // rate_limit.requests_per_second
+	RateLimitWriteBytesPerSecond       // rate_limit.write_bytes_per_second
 
 	// NumFields is the number of fields in the config.
 	NumFields int = iota - 1
@@ -22,6 +22,8 @@ func _() {
 	_ = x[Constraints-7]
 	_ = x[VoterConstraints-8]
 	_ = x[LeasePreferences-9]
+	_ = x[RateLimitRequestsPerSecond-10]
+	_ = x[RateLimitWriteBytesPerSecond-11]
 }
 
 func (i Field) String() string {
@@ -44,6 +46,10 @@ func (i Field) String() string {
 		return "voter_constraints"
 	case LeasePreferences:
 		return "lease_preferences"
+	case RateLimitRequestsPerSecond:
+		return "rate_limit.requests_per_second"
+	case RateLimitWriteBytesPerSecond:
+		return "rate_limit.write_bytes_per_second"
 	default:
 		return "Field(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -419,6 +419,15 @@ func (z *ZoneConfig) Validate() error {
 			*z.RangeMinBytes, *z.RangeMaxBytes)
 	}
 
+	if z.RateLimitRequestsPerSecond != nil && *z.RateLimitRequestsPerSecond < 0 {
+		return fmt.Errorf("RateLimitRequestsPerSecond %d less than minimum allowed 0",
+			*z.RateLimitRequestsPerSecond)
+	}
+	if z.RateLimitWriteBytesPerSecond != nil && *z.RateLimitWriteBytesPerSecond < 0 {
+		return fmt.Errorf("RateLimitWriteBytesPerSecond %d less than minimum allowed 0",
+			*z.RateLimitWriteBytesPerSecond)
+	}
+
 	// Reserve the value 0 to potentially have some special meaning in the future,
 	// such as to disable GC.
 	if z.GC != nil && z.GC.TTLSeconds < 1 {
@@ -587,6 +596,16 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.RangeMaxBytes = proto.Int64(*parent.RangeMaxBytes)
 		}
 	}
+	if z.RateLimitRequestsPerSecond == nil {
+		if parent.RateLimitRequestsPerSecond != nil {
+			z.RateLimitRequestsPerSecond = proto.Int64(*parent.RateLimitRequestsPerSecond)
+		}
+	}
+	if z.RateLimitWriteBytesPerSecond == nil {
+		if parent.RateLimitWriteBytesPerSecond != nil {
+			z.RateLimitWriteBytesPerSecond = proto.Int64(*parent.RateLimitWriteBytesPerSecond)
+		}
+	}
 
 	if z.ShouldInheritGC(parent) {
 		tempGC := *parent.GC
@@ -651,6 +670,16 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 		case "lease_preferences":
 			z.LeasePreferences = other.LeasePreferences
 			z.InheritedLeasePreferences = other.InheritedLeasePreferences
+		case "rate_limit.requests_per_second":
+			z.RateLimitRequestsPerSecond = nil
+			if other.RateLimitRequestsPerSecond != nil {
+				z.RateLimitRequestsPerSecond = proto.Int64(*other.RateLimitRequestsPerSecond)
+			}
+		case "rate_limit.write_bytes_per_second":
+			z.RateLimitWriteBytesPerSecond = nil
+			if other.RateLimitWriteBytesPerSecond != nil {
+				z.RateLimitWriteBytesPerSecond = proto.Int64(*other.RateLimitWriteBytesPerSecond)
+			}
 		}
 	}
 }
@@ -760,6 +789,30 @@ func (z *ZoneConfig) DiffWithZone(
 					Actual:   int64ToString(z.RangeMaxBytes),
 				}, nil
 			}
+		case "rate_limit.requests_per_second":
+			if other.RateLimitRequestsPerSecond == nil && z.RateLimitRequestsPerSecond == nil {
+				continue
+			}
+			if z.RateLimitRequestsPerSecond == nil || other.RateLimitRequestsPerSecond == nil ||
+				*z.RateLimitRequestsPerSecond != *other.RateLimitRequestsPerSecond {
+				return false, DiffWithZoneMismatch{
+					Field:    "rate_limit.requests_per_second",
+					Expected: int64ToString(other.RateLimitRequestsPerSecond),
+					Actual:   int64ToString(z.RateLimitRequestsPerSecond),
+				}, nil
+			}
+		case "rate_limit.write_bytes_per_second":
+			if other.RateLimitWriteBytesPerSecond == nil && z.RateLimitWriteBytesPerSecond == nil {
+				continue
+			}
+			if z.RateLimitWriteBytesPerSecond == nil || other.RateLimitWriteBytesPerSecond == nil ||
+				*z.RateLimitWriteBytesPerSecond != *other.RateLimitWriteBytesPerSecond {
+				return false, DiffWithZoneMismatch{
+					Field:    "rate_limit.write_bytes_per_second",
+					Expected: int64ToString(other.RateLimitWriteBytesPerSecond),
+					Actual:   int64ToString(z.RateLimitWriteBytesPerSecond),
+				}, nil
+			}
 		case "global_reads":
 			if other.GlobalReads == nil && z.GlobalReads == nil {
 				continue
@@ -1326,6 +1379,13 @@ func (z *ZoneConfig) toSpanConfig() (roachpb.SpanConfig, error) {
 	if z.NumVoters != nil {
 		sc.NumVoters = *z.NumVoters
 	}
+	// Rate limits are disabled by default.
+	if z.RateLimitRequestsPerSecond != nil {
+		sc.RateLimitRequestsPerSecond = *z.RateLimitRequestsPerSecond
+	}
+	if z.RateLimitWriteBytesPerSecond != nil {
+		sc.RateLimitWriteBytesPerSecond = *z.RateLimitWriteBytesPerSecond
+	}
 
 	toSpanConfigConstraints := func(src []Constraint) ([]roachpb.Constraint, error) {
 		spanConfigConstraints := make([]roachpb.Constraint, len(src))
@@ -203,6 +203,8 @@ type marshalableZoneConfig struct {
 	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
 	VoterConstraints             ConstraintsList   `json:"voter_constraints" yaml:"voter_constraints,flow"`
 	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
+	RateLimitRequestsPerSecond   *int64            `json:"rate_limit.requests_per_second,omitempty" yaml:"rate_limit.requests_per_second,omitempty"`
+	RateLimitWriteBytesPerSecond *int64            `json:"rate_limit.write_bytes_per_second,omitempty" yaml:"rate_limit.write_bytes_per_second,omitempty"`
 	ExperimentalLeasePreferences []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
 	Subzones                     []Subzone         `json:"subzones" yaml:"-"`
 	SubzoneSpans                 []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
@@ -238,6 +240,12 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	if !c.InheritedLeasePreferences {
 		m.LeasePreferences = c.LeasePreferences
 	}
+	if c.RateLimitRequestsPerSecond != nil {
+		m.RateLimitRequestsPerSecond = proto.Int64(*c.RateLimitRequestsPerSecond)
+	}
+	if c.RateLimitWriteBytesPerSecond != nil {
+		m.RateLimitWriteBytesPerSecond = proto.Int64(*c.RateLimitWriteBytesPerSecond)
+	}
 	// We intentionally do not round-trip ExperimentalLeasePreferences. We never
 	// want to return yaml containing it.
 	m.Subzones = c.Subzones
@@ -275,6 +283,12 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.LeasePreferences != nil {
 		c.LeasePreferences = m.LeasePreferences
 	}
+	if m.RateLimitRequestsPerSecond != nil {
+		c.RateLimitRequestsPerSecond = proto.Int64(*m.RateLimitRequestsPerSecond)
+	}
+	if m.RateLimitWriteBytesPerSecond != nil {
+		c.RateLimitWriteBytesPerSecond = proto.Int64(*m.RateLimitWriteBytesPerSecond)
+	}
 
 	// Prefer a provided m.ExperimentalLeasePreferences value over whatever is in
 	// m.LeasePreferences, since we know that m.ExperimentalLeasePreferences can
@@ -26,6 +26,7 @@ EXECGEN_SRCS = [
     "//pkg/sql/colexec/colexecagg:window_bool_and_or_agg.eg.go",
     "//pkg/sql/colexec/colexecagg:window_concat_agg.eg.go",
     "//pkg/sql/colexec/colexecagg:window_count_agg.eg.go",
+    "//pkg/sql/colexec/colexecagg:window_default_agg.eg.go",
     "//pkg/sql/colexec/colexecagg:window_min_max_agg.eg.go",
     "//pkg/sql/colexec/colexecagg:window_sum_agg.eg.go",
     "//pkg/sql/colexec/colexecagg:window_sum_int_agg.eg.go",
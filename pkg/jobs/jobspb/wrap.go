@@ -46,6 +46,7 @@ var (
 	_ Details = LogicalReplicationDetails{}
 	_ Details = UpdateTableMetadataCacheDetails{}
 	_ Details = StandbyReadTSPollerDetails{}
+	_ Details = IndexAdvisorDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -76,6 +77,7 @@ var (
 	_ ProgressDetails = LogicalReplicationProgress{}
 	_ ProgressDetails = UpdateTableMetadataCacheProgress{}
 	_ ProgressDetails = StandbyReadTSPollerProgress{}
+	_ ProgressDetails = IndexAdvisorProgress{}
 )
 
 // Type returns the payload's job type and panics if the type is invalid.
@@ -169,6 +171,7 @@ var AutomaticJobTypes = [...]Type{
 	TypeAutoUpdateSQLActivity,
 	TypeMVCCStatisticsUpdate,
 	TypeUpdateTableMetadataCache,
+	TypeAutoIndexAdvisor,
 }
 
 // DetailsType returns the type for a payload detail.
@@ -236,6 +239,8 @@ func DetailsType(d isPayload_Details) (Type, error) {
 		return TypeUpdateTableMetadataCache, nil
 	case *Payload_StandbyReadTsPollerDetails:
 		return TypeStandbyReadTSPoller, nil
+	case *Payload_IndexAdvisorDetails:
+		return TypeAutoIndexAdvisor, nil
 	default:
 		return TypeUnspecified, errors.Newf("Payload.Type called on a payload with an unknown details type: %T", d)
 	}
@@ -289,6 +294,7 @@ var JobDetailsForEveryJobType = map[Type]Details{
 	TypeLogicalReplication:           LogicalReplicationDetails{},
 	TypeUpdateTableMetadataCache:     UpdateTableMetadataCacheDetails{},
 	TypeStandbyReadTSPoller:          StandbyReadTSPollerDetails{},
+	TypeAutoIndexAdvisor:             IndexAdvisorDetails{},
 }
 
 // WrapProgressDetails wraps a ProgressDetails object in the protobuf wrapper
@@ -356,6 +362,8 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_TableMetadataCache{TableMetadataCache: &d}
 	case StandbyReadTSPollerProgress:
 		return &Progress_StandbyReadTsPoller{StandbyReadTsPoller: &d}
+	case IndexAdvisorProgress:
+		return &Progress_IndexAdvisor{IndexAdvisor: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown progress type %T", d))
 	}
@@ -421,6 +429,8 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.UpdateTableMetadataCacheDetails
 	case *Payload_StandbyReadTsPollerDetails:
 		return *d.StandbyReadTsPollerDetails
+	case *Payload_IndexAdvisorDetails:
+		return *d.IndexAdvisorDetails
 	default:
 		return nil
 	}
@@ -486,6 +496,8 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.TableMetadataCache
 	case *Progress_StandbyReadTsPoller:
 		return *d.StandbyReadTsPoller
+	case *Progress_IndexAdvisor:
+		return *d.IndexAdvisor
 	default:
 		return nil
 	}
@@ -575,6 +587,8 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_UpdateTableMetadataCacheDetails{UpdateTableMetadataCacheDetails: &d}
 	case StandbyReadTSPollerDetails:
 		return &Payload_StandbyReadTsPollerDetails{StandbyReadTsPollerDetails: &d}
+	case IndexAdvisorDetails:
+		return &Payload_IndexAdvisorDetails{IndexAdvisorDetails: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -610,7 +624,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 31
+const NumJobTypes = 32
 
 // ChangefeedDetailsMarshaler allows for dependency injection of
 // cloud.SanitizeExternalStorageURI to avoid the dependency from this
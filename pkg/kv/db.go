@@ -698,6 +698,24 @@ func (db *DB) AdminScatter(
 	return resp, nil
 }
 
+// AdminRunGC runs every range overlapping the specified span through the MVCC
+// GC queue on its leaseholder, regardless of the queue's scoring heuristics,
+// and returns the per-range outcome.
+func (db *DB) AdminRunGC(ctx context.Context, span roachpb.Span) (*kvpb.AdminRunGCResponse, error) {
+	req := &kvpb.AdminRunGCRequest{
+		RequestHeader: kvpb.RequestHeaderFromSpan(span),
+	}
+	raw, pErr := SendWrapped(ctx, db.NonTransactionalSender(), req)
+	if pErr != nil {
+		return nil, pErr.GoError()
+	}
+	resp, ok := raw.(*kvpb.AdminRunGCResponse)
+	if !ok {
+		return nil, errors.Errorf("unexpected response of type %T for AdminRunGC", raw)
+	}
+	return resp, nil
+}
+
 // AdminUnsplit removes the sticky bit of the range specified by splitKey.
 //
 // splitKey is the start key of the range whose sticky bit should be removed.
@@ -188,7 +188,20 @@ func (tc *TxnCoordSender) CanUseSavepoint(ctx context.Context, s kv.SavepointTok
 	}
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
-	if tc.mu.txnState != txnPending {
+	switch tc.mu.txnState {
+	case txnPending:
+	case txnRetryableError:
+		// A retryable error that does not require the transaction to restart from
+		// the beginning (e.g. for read committed) can be handled by rolling back
+		// to a savepoint and then partially retrying the transaction. See
+		// kv.Txn.PrepareForPartialRetry. A retryable error that does require a
+		// restart can only be handled by rolling back to a savepoint taken before
+		// any activity, since rolling back to it is equivalent to restarting the
+		// transaction. See kv.Txn.PrepareForRetry.
+		if tc.mu.storedRetryableErr.TxnMustRestartFromBeginning() && !s.Initial() {
+			return false
+		}
+	default:
 		return false
 	}
 	// We swallow the error here because we aren't actually performing any
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverbase"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -87,6 +88,9 @@ func TestSavepoints(t *testing.T) {
 			switch td.Cmd {
 			case "begin":
 				txn = kv.NewTxn(ctx, db, 0)
+				if td.HasArg("read-committed") {
+					require.NoError(t, txn.SetIsoLevel(isolation.ReadCommitted))
+				}
 				ptxn()
 
 			case "commit":
@@ -132,6 +136,13 @@ func TestSavepoints(t *testing.T) {
 				fmt.Fprintf(&buf, "txn error cleared\n")
 				fmt.Fprintf(&buf, "txn id %s\n", changed)
 
+			case "reset-partial":
+				if err := txn.PrepareForPartialRetry(ctx); err != nil {
+					fmt.Fprintf(&buf, "(%T) %v\n", err, err)
+				} else {
+					fmt.Fprintf(&buf, "txn error cleared\n")
+				}
+
 			case "put":
 				b := txn.NewBatch()
 				b.Put(td.CmdArgs[0].Key, td.CmdArgs[1].Key)
@@ -632,6 +632,20 @@ func (r *AdminScatterResponse) combine(_ context.Context, c combinable, _ *Batch
 
 var _ combinable = &AdminScatterResponse{}
 
+// combine implements the combinable interface.
+func (r *AdminRunGCResponse) combine(_ context.Context, c combinable, _ *BatchRequest) error {
+	if r != nil {
+		otherR := c.(*AdminRunGCResponse)
+		if err := r.ResponseHeader.combine(otherR.Header()); err != nil {
+			return err
+		}
+		r.Ranges = append(r.Ranges, otherR.Ranges...)
+	}
+	return nil
+}
+
+var _ combinable = &AdminRunGCResponse{}
+
 func (avptr *AdminVerifyProtectedTimestampResponse) combine(
 	_ context.Context, c combinable, _ *BatchRequest,
 ) error {
@@ -964,6 +978,9 @@ func (*ExportRequest) Method() Method { return Export }
 // Method implements the Request interface.
 func (*AdminScatterRequest) Method() Method { return AdminScatter }
 
+// Method implements the Request interface.
+func (*AdminRunGCRequest) Method() Method { return AdminRunGC }
+
 // Method implements the Request interface.
 func (*AddSSTableRequest) Method() Method { return AddSSTable }
 
@@ -1230,6 +1247,12 @@ func (r *AddSSTableRequest) ShallowCopy() Request {
 	return &shallowCopy
 }
 
+// ShallowCopy implements the Request interface.
+func (r *AdminRunGCRequest) ShallowCopy() Request {
+	shallowCopy := *r
+	return &shallowCopy
+}
+
 // ShallowCopy implements the Request interface.
 func (r *LinkExternalSSTableRequest) ShallowCopy() Request {
 	shallowCopy := *r
@@ -1522,6 +1545,12 @@ func (r *AddSSTableResponse) ShallowCopy() Response {
 	return &shallowCopy
 }
 
+// ShallowCopy implements the Response interface.
+func (r *AdminRunGCResponse) ShallowCopy() Response {
+	shallowCopy := *r
+	return &shallowCopy
+}
+
 // ShallowCopy implements the Response interface.
 func (r *LinkExternalSSTableResponse) ShallowCopy() Response {
 	shallowCopy := *r
@@ -2087,6 +2116,7 @@ func (*ExportRequest) flags() flag {
 	return isRead | isRange | updatesTSCache | bypassesReplicaCircuitBreaker
 }
 func (*AdminScatterRequest) flags() flag                  { return isAdmin | isRange | isAlone }
+func (*AdminRunGCRequest) flags() flag                    { return isAdmin | isRange | isAlone }
 func (*AdminVerifyProtectedTimestampRequest) flags() flag { return isAdmin | isRange | isAlone }
 func (r *AddSSTableRequest) flags() flag {
 	flags := isWrite | isRange | isAlone | isUnsplittable | canBackpressure | bypassesReplicaCircuitBreaker
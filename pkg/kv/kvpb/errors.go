@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -49,6 +50,13 @@ type ClientVisibleAmbiguousError interface {
 	ClientVisibleAmbiguousError()
 }
 
+// ClientVisibleRateLimitError is to be implemented by errors visible by
+// layers above and that indicate that a request was rejected by a rate limit.
+// Such errors can be handled by retrying the transaction after backing off.
+type ClientVisibleRateLimitError interface {
+	ClientVisibleRateLimitError()
+}
+
 func (e *UnhandledRetryableError) Error() string {
 	return e.String()
 }
@@ -296,6 +304,7 @@ const (
 	LockConflictErrType                     ErrorDetailType = 45
 	ReplicaUnavailableErrType               ErrorDetailType = 46
 	ProxyFailedErrType                      ErrorDetailType = 47
+	SpanRateLimitExceededErrType            ErrorDetailType = 48
 	// When adding new error types, don't forget to update NumErrors below.
 
 	// CommunicationErrType indicates a gRPC error; this is not an ErrorDetail.
@@ -305,7 +314,7 @@ const (
 	// detail. The value 25 is chosen because it's reserved in the errors proto.
 	InternalErrType ErrorDetailType = 25
 
-	NumErrors int = 48
+	NumErrors int = 49
 )
 
 // Register the migration of all errors that used to be in the roachpb package
@@ -1766,6 +1775,53 @@ var _ errors.SafeFormatter = (*ProxyFailedError)(nil)
 var _ fmt.Formatter = (*ProxyFailedError)(nil)
 var _ errors.Wrapper = (*ProxyFailedError)(nil)
 
+// NewSpanRateLimitExceededError initializes a new SpanRateLimitExceededError.
+func NewSpanRateLimitExceededError(
+	rangeID roachpb.RangeID, limit string, retryAfter time.Duration,
+) *SpanRateLimitExceededError {
+	return &SpanRateLimitExceededError{
+		RangeID:    rangeID,
+		Limit:      limit,
+		RetryAfter: retryAfter,
+	}
+}
+
+// Type is part of the ErrorDetailInterface.
+func (e *SpanRateLimitExceededError) Type() ErrorDetailType {
+	return SpanRateLimitExceededErrType
+}
+
+func (e *SpanRateLimitExceededError) Error() string {
+	return redact.Sprint(e).StripMarkers()
+}
+
+func (e *SpanRateLimitExceededError) SafeFormatError(p errors.Printer) (next error) {
+	p.Printf("r%d: span rate limit %s exceeded; retry after %s",
+		e.RangeID, redact.SafeString(e.Limit), e.RetryAfter)
+	return nil
+}
+
+// ClientVisibleRateLimitError implements the ClientVisibleRateLimitError
+// interface.
+func (e *SpanRateLimitExceededError) ClientVisibleRateLimitError() {}
+
+// ErrorDetail implements the errors.ErrorDetailer interface.
+func (e *SpanRateLimitExceededError) ErrorDetail() string {
+	return fmt.Sprintf("the request to r%d was rejected by the %s limit in the "+
+		"zone configuration of the accessed data", e.RangeID, e.Limit)
+}
+
+// ErrorHint implements the errors.ErrorHinter interface.
+func (e *SpanRateLimitExceededError) ErrorHint() string {
+	return fmt.Sprintf("retry the transaction after %s or raise the %s zone config limit",
+		e.RetryAfter, e.Limit)
+}
+
+var _ ErrorDetailInterface = &SpanRateLimitExceededError{}
+var _ ClientVisibleRateLimitError = &SpanRateLimitExceededError{}
+var _ errors.ErrorDetailer = &SpanRateLimitExceededError{}
+var _ errors.ErrorHinter = &SpanRateLimitExceededError{}
+
 // KeyCollisionError represents a failed attempt to ingest the same key twice.
 type KeyCollisionError struct {
 	Key   roachpb.Key
@@ -1853,4 +1909,5 @@ var _ errors.SafeFormatter = &MVCCHistoryMutationError{}
 var _ errors.SafeFormatter = &UnhandledRetryableError{}
 var _ errors.SafeFormatter = &ReplicaUnavailableError{}
 var _ errors.SafeFormatter = &ProxyFailedError{}
+var _ errors.SafeFormatter = &SpanRateLimitExceededError{}
 var _ errors.SafeFormatter = &KeyCollisionError{}
@@ -171,6 +171,9 @@ const (
 	// IsSpanEmpty is a non-transaction read request used to determine whether
 	// a span contains any keys whatsoever (garbage or otherwise).
 	IsSpanEmpty
+	// AdminRunGC runs a range through the MVCC GC queue on its leaseholder,
+	// bypassing the queue's scoring heuristics.
+	AdminRunGC
 	// MaxMethod is the maximum method.
 	MaxMethod Method = iota - 1
 	// NumMethods represents the total number of API methods.
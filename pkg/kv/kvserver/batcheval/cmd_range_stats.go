@@ -16,6 +16,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -37,16 +39,53 @@ func declareKeysRangeStats(
 	// The request will return the descriptor and lease.
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(rs.GetStartKey())})
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeLeaseKey(rs.GetRangeID())})
+	// If requested, the request scans the lock table of the entire range to
+	// find its oldest intent.
+	if req.(*kvpb.RangeStatsRequest).IncludeOldestIntent {
+		// Like QueryResolvedTimestamp, the scan is best-effort and must not block
+		// or be blocked by writes to the range, so it is only permitted for
+		// INCONSISTENT reads, which don't acquire latches. The span is still
+		// declared so that the lock table accesses pass span assertions.
+		//
+		// NOTE: we don't know what the end key of the Range will be at the time
+		// of request evaluation (see ImmutableRangeState), so we declare the span
+		// up to the end of the keyspace, like declareAllKeys.
+		if header.ReadConsistency != kvpb.INCONSISTENT {
+			return errors.AssertionFailedf(
+				"RangeStats with IncludeOldestIntent requires INCONSISTENT reads, found %s",
+				header.ReadConsistency)
+		}
+		latchSpans.AddMVCC(spanset.SpanReadOnly, roachpb.Span{
+			Key: rs.GetStartKey().AsRawKey(), EndKey: keys.MaxKey,
+		}, header.Timestamp)
+	}
 	return nil
 }
 
-// RangeStats returns the MVCC statistics for a range.
+// RangeStats returns the MVCC statistics for a range and, if requested, the
+// timestamp of its oldest intent.
 func RangeStats(
-	ctx context.Context, _ storage.Reader, cArgs CommandArgs, resp kvpb.Response,
+	ctx context.Context, reader storage.Reader, cArgs CommandArgs, resp kvpb.Response,
 ) (result.Result, error) {
+	args := cArgs.Args.(*kvpb.RangeStatsRequest)
 	reply := resp.(*kvpb.RangeStatsResponse)
 	reply.MVCCStats = cArgs.EvalCtx.GetMVCCStats()
 
+	if args.IncludeOldestIntent {
+		// The MVCC stats only track the total age of the range's intents, which
+		// can't be used to find a single stuck intent, so the lock table is
+		// scanned instead. No intents are collected for cleanup.
+		span := cArgs.EvalCtx.Desc().RSpan().AsRawSpanWithNoLocals()
+		oldestIntentTS, _, err := computeMinIntentTimestamp(
+			ctx, reader, span, 0 /* maxEncounteredIntents */, 0, /* maxEncounteredIntentKeyBytes */
+			hlc.Timestamp{}, /* intentCleanupThresh */
+		)
+		if err != nil {
+			return result.Result{}, errors.Wrapf(err, "computing oldest intent timestamp")
+		}
+		reply.OldestIntentTimestamp = oldestIntentTS
+	}
+
 	maxQPS, qpsOK := cArgs.EvalCtx.GetMaxSplitQPS(ctx)
 	maxCPU, cpuOK := cArgs.EvalCtx.GetMaxSplitCPU(ctx)
 	// See comment on MaxQueriesPerSecond and MaxCPUPerSecond. -1 means !ok.
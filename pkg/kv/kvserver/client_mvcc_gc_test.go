@@ -82,6 +82,88 @@ func TestMVCCGCCorrectStats(t *testing.T) {
 	require.Equal(t, oldValBytes, newStats.ValBytes)
 }
 
+// TestAdminRunGC verifies that AdminRunGC runs a range through the mvcc gc
+// queue regardless of its score, and reports the range's garbage before and
+// after the run.
+func TestAdminRunGC(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	var args base.TestServerArgs
+	args.Knobs.Store = &kvserver.StoreTestingKnobs{DisableCanAckBeforeApplication: true}
+	s := serverutils.StartServerOnly(t, args)
+	defer s.Stopper().Stop(ctx)
+
+	key, err := s.ScratchRange()
+	require.NoError(t, err)
+	store, err := s.GetStores().(*kvserver.Stores).GetStore(s.GetFirstStoreID())
+	require.NoError(t, err)
+
+	repl := store.LookupReplica(roachpb.RKey(key))
+	require.NoError(t, store.DB().Put(ctx, key, "foo"))
+
+	// Inflate the garbage in the stats. Since the GC run can't find the garbage,
+	// it recomputes the stats, which drops the bogus garbage again.
+	ms := repl.GetMVCCStats()
+	ms.ValBytes += 16 * (1 << 20) // 16mb
+	ms.GCBytesAge = 16 * (1 << 20) * 100 * int64(time.Hour.Seconds())
+	repl.SetMVCCStatsForTesting(&ms)
+
+	resp, err := s.DB().AdminRunGC(ctx, roachpb.Span{Key: key, EndKey: key.PrefixEnd()})
+	require.NoError(t, err)
+	require.Len(t, resp.Ranges, 1)
+	res := resp.Ranges[0]
+	require.Equal(t, repl.RangeID, res.RangeID)
+	require.Empty(t, res.Error)
+	require.Equal(t, ms.GCBytes(), res.GarbageBytesBefore)
+	require.Less(t, res.GarbageBytesAfter, res.GarbageBytesBefore)
+}
+
+// TestRangeStatsOldestIntent verifies that a RangeStats request reports the
+// timestamp of the range's oldest unresolved intent when requested.
+func TestRangeStatsOldestIntent(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s := serverutils.StartServerOnly(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	key, err := s.ScratchRange()
+	require.NoError(t, err)
+	db := s.DB()
+
+	oldestIntent := func() hlc.Timestamp {
+		var ba kv.Batch
+		ba.AddRawRequest(&kvpb.RangeStatsRequest{
+			RequestHeader:       kvpb.RequestHeader{Key: key},
+			IncludeOldestIntent: true,
+		})
+		ba.Header.ReadConsistency = kvpb.INCONSISTENT
+		require.NoError(t, db.Run(ctx, &ba))
+		return ba.RawResponse().Responses[0].GetRangeStats().OldestIntentTimestamp
+	}
+	require.True(t, oldestIntent().IsEmpty())
+
+	// Write intents from two transactions. The first one is the oldest.
+	txn1 := db.NewTxn(ctx, "txn1")
+	require.NoError(t, txn1.Put(ctx, key.Next(), "a"))
+	txn2 := db.NewTxn(ctx, "txn2")
+	require.NoError(t, txn2.Put(ctx, key.Next().Next(), "b"))
+	require.Equal(t, txn1.ProvisionalCommitTimestamp(), oldestIntent())
+
+	// Once the oldest transaction commits, the other intent is the oldest.
+	require.NoError(t, txn1.Commit(ctx))
+	testutils.SucceedsSoon(t, func() error {
+		if ts := oldestIntent(); ts != txn2.ProvisionalCommitTimestamp() {
+			return errors.Errorf("expected oldest intent at %s, found %s", txn2.ProvisionalCommitTimestamp(), ts)
+		}
+		return nil
+	})
+	require.NoError(t, txn2.Rollback(ctx))
+}
+
 // TestSystemSpanConfigProtectionPoliciesApplyAfterGC is a regression test for
 // https://github.com/cockroachdb/cockroach/issues/113867. This test attempts
 // to recreate the following observed timeline:
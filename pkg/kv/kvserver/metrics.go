@@ -2248,6 +2248,22 @@ not occurring.
 		Unit:        metric.Unit_COUNT,
 	}
 
+	// Span rate limit metrics.
+	metaSpanRateLimitWaitedRequests = metric.Metadata{
+		Name: "requests.span_rate_limit.waited",
+		Help: `Number of requests that waited for the rate limit quota configured in
+the span config of their range.`,
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaSpanRateLimitRejectedRequests = metric.Metadata{
+		Name: "requests.span_rate_limit.rejected",
+		Help: `Number of requests rejected because the rate limit quota configured in
+the span config of their range was not available within kv.span_rate_limit.max_wait.`,
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+
 	// AddSSTable metrics.
 	metaAddSSTableProposals = metric.Metadata{
 		Name:        "addsstable.proposals",
@@ -2926,6 +2942,10 @@ type StoreMetrics struct {
 	// Backpressure counts.
 	BackpressuredOnSplitRequests *metric.Gauge
 
+	// Span rate limit counts.
+	SpanRateLimitWaitedRequests   *metric.Counter
+	SpanRateLimitRejectedRequests *metric.Counter
+
 	// AddSSTable stats: how many AddSSTable commands were proposed and how many
 	// were applied? How many applications required writing a copy?
 	AddSSTableProposals           *metric.Counter
@@ -3692,6 +3712,10 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		// Backpressure counters.
 		BackpressuredOnSplitRequests: metric.NewGauge(metaBackpressuredOnSplitRequests),
 
+		// Span rate limit counters.
+		SpanRateLimitWaitedRequests:   metric.NewCounter(metaSpanRateLimitWaitedRequests),
+		SpanRateLimitRejectedRequests: metric.NewCounter(metaSpanRateLimitRejectedRequests),
+
 		// AddSSTable proposal + applications counters.
 		AddSSTableProposals:           metric.NewCounter(metaAddSSTableProposals),
 		AddSSTableApplications:        metric.NewCounter(metaAddSSTableApplications),
@@ -970,3 +970,27 @@ func gcAdmissionHeader(st *cluster.Settings) kvpb.AdmissionHeader {
 		NoMemoryReservedAtSource: true,
 	}
 }
+
+// adminRunGC runs the replica through the MVCC GC queue, bypassing the queue's
+// scoring heuristics, and reports the range's garbage before and after the
+// run. Only garbage that has expired past the range's GC TTL and isn't
+// protected by a protected timestamp is reclaimed.
+func (r *Replica) adminRunGC(
+	ctx context.Context, _ kvpb.AdminRunGCRequest,
+) (kvpb.AdminRunGCResponse, error) {
+	res := kvpb.AdminRunGCResponse_Range{
+		RangeID:            r.RangeID,
+		GarbageBytesBefore: r.GetMVCCStats().GCBytes(),
+	}
+	processErr, enqueueErr := r.store.Enqueue(
+		ctx, r.store.mvccGCQueue.Name(), r, true /* skipShouldQueue */, false, /* async */
+	)
+	if enqueueErr != nil {
+		return kvpb.AdminRunGCResponse{}, enqueueErr
+	}
+	if processErr != nil {
+		res.Error = processErr.Error()
+	}
+	res.GarbageBytesAfter = r.GetMVCCStats().GCBytes()
+	return kvpb.AdminRunGCResponse{Ranges: []kvpb.AdminRunGCResponse_Range{res}}, nil
+}
@@ -7,11 +7,16 @@ package kvserver
 
 import (
 	"context"
+	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcostmodel"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -75,3 +80,279 @@ func (r *Replica) recordImpactOnRateLimiter(
 
 	r.tenantLimiter.RecordRead(ctx, info)
 }
+
+// spanRateLimitMaxWait is the maximum duration a request waits for span rate
+// limit quota before it is rejected.
+var spanRateLimitMaxWait = settings.RegisterDurationSetting(
+	settings.SystemOnly,
+	"kv.span_rate_limit.max_wait",
+	"maximum duration a request waits for the rate limit quota configured in its "+
+		"range's span config before being rejected",
+	time.Second,
+	settings.NonNegativeDuration,
+)
+
+// spanRateLimiter enforces the rate limits configured in a span config. The
+// underlying limiters are (re)configured lazily, when a request observes a
+// change in the span config's limits.
+type spanRateLimiter struct {
+	options []quotapool.Option
+	mu      struct {
+		syncutil.Mutex
+		requestsPerSecond   int64
+		writeBytesPerSecond int64
+		requests            *quotapool.RateLimiter
+		writeBytes          *quotapool.RateLimiter
+		// lastUsed is the time at which the limiter was last used, which is used
+		// to release the limiters of spans that no longer receive requests.
+		lastUsed time.Time
+	}
+}
+
+// limiters returns the request and write byte limiters for the provided limits.
+// A nil limiter is returned for a limit that is not configured.
+func (l *spanRateLimiter) limiters(
+	requestsPerSecond, writeBytesPerSecond int64,
+) (requests, writeBytes *quotapool.RateLimiter) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// The burst is one second worth of quota.
+	update := func(limiter **quotapool.RateLimiter, cur *int64, name string, rate int64) {
+		if *cur == rate {
+			return
+		}
+		*cur = rate
+		switch {
+		case rate <= 0:
+			*limiter = nil
+		case *limiter == nil:
+			*limiter = quotapool.NewRateLimiter(name, quotapool.Limit(rate), rate, l.options...)
+		default:
+			(*limiter).UpdateLimit(quotapool.Limit(rate), rate)
+		}
+	}
+	update(&l.mu.requests, &l.mu.requestsPerSecond, "span-requests", requestsPerSecond)
+	update(&l.mu.writeBytes, &l.mu.writeBytesPerSecond, "span-write-bytes", writeBytesPerSecond)
+	return l.mu.requests, l.mu.writeBytes
+}
+
+// spanRateLimiterIdleTimeout is the duration after which the limiter of a span
+// that receives no requests is released.
+const spanRateLimiterIdleTimeout = time.Minute
+
+// spanRateLimiters holds the span rate limiters of a store, keyed by the span
+// that the limits were configured for, restricted to a single table (see
+// spanRateLimiterSpan). All of the ranges in a configured span whose leases
+// are held by the store share its limiter, so that a span's quota does not
+// grow with the number of ranges it is split into.
+type spanRateLimiters struct {
+	options []quotapool.Option
+	mu      struct {
+		syncutil.Mutex
+		limiters map[spanRateLimiterKey]*spanRateLimiter
+		// nextSweep is the number of limiters at which idle limiters are next
+		// released.
+		nextSweep int
+	}
+}
+
+// spanRateLimiterKey identifies the span a rate limit was configured for.
+type spanRateLimiterKey struct {
+	key, endKey string
+}
+
+// get returns the limiter of the provided configured span.
+func (s *spanRateLimiters) get(span roachpb.Span, now time.Time) *spanRateLimiter {
+	k := spanRateLimiterKey{key: string(span.Key), endKey: string(span.EndKey)}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.mu.limiters[k]
+	if !ok {
+		if s.mu.limiters == nil {
+			s.mu.limiters = make(map[spanRateLimiterKey]*spanRateLimiter)
+		}
+		if len(s.mu.limiters) >= s.mu.nextSweep {
+			s.sweepLocked(now)
+		}
+		l = &spanRateLimiter{options: s.options}
+		s.mu.limiters[k] = l
+	}
+	l.mu.Lock()
+	l.mu.lastUsed = now
+	l.mu.Unlock()
+	return l
+}
+
+// sweepLocked releases the limiters that have not been used within
+// spanRateLimiterIdleTimeout, e.g. because the span's limits were removed or
+// its leases moved to other stores.
+func (s *spanRateLimiters) sweepLocked(now time.Time) {
+	for k, l := range s.mu.limiters {
+		l.mu.Lock()
+		idle := now.Sub(l.mu.lastUsed) > spanRateLimiterIdleTimeout
+		l.mu.Unlock()
+		if idle {
+			delete(s.mu.limiters, k)
+		}
+	}
+	s.mu.nextSweep = 2 * len(s.mu.limiters)
+	if s.mu.nextSweep < 64 {
+		s.mu.nextSweep = 64
+	}
+}
+
+// spanRateLimitableSpans contains spans of keys where span rate limits are
+// enforced: the user table data of the system tenant and the data of all
+// secondary tenants. Requests to system ranges are never rate limited.
+var spanRateLimitableSpans = []roachpb.Span{
+	{Key: keys.SystemSQLCodec.TablePrefix(keys.MaxReservedDescID + 1), EndKey: keys.TableDataMax},
+	{Key: keys.TenantTableDataMin, EndKey: keys.TenantTableDataMax},
+}
+
+// spanRateLimiterSpan returns the span whose limiter a batch starting at key is
+// subject to. This is the span that the limits were configured for, restricted
+// to the table containing key. Span configs of adjacent tables are coalesced
+// when they are identical, in which case a range's configured span covers
+// multiple tables; restricting the span to a table ensures that each table
+// gets the quota configured for it, rather than sharing it with its
+// neighbors.
+func spanRateLimiterSpan(confSpan roachpb.Span, key roachpb.Key) roachpb.Span {
+	_, tenantID, err := keys.DecodeTenantPrefix(key)
+	if err != nil {
+		return confSpan
+	}
+	codec := keys.MakeSQLCodec(tenantID)
+	_, tableID, err := codec.DecodeTablePrefix(key)
+	if err != nil {
+		return confSpan
+	}
+	tablePrefix := codec.TablePrefix(tableID)
+	tableSpan := confSpan.Intersect(roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()})
+	if !tableSpan.Valid() {
+		return confSpan
+	}
+	return tableSpan
+}
+
+// canSpanRateLimitBatch returns whether the provided BatchRequest is subject
+// to the rate limits configured in the span config of its range. Only user
+// traffic, i.e. batches issued by SQL on behalf of a client, is rate limited.
+// Admin requests, KV-internal traffic and requests that other requests depend
+// on to make progress (such as transaction pushes and intent resolution) are
+// exempt, as delaying or rejecting them could stall the range. Rollbacks and
+// commits that are sent on their own are exempt as well: they don't write any
+// user data, and rejecting them would leave the transaction's intents behind
+// until they are pushed by other transactions.
+func canSpanRateLimitBatch(ctx context.Context, ba *kvpb.BatchRequest) bool {
+	if ba.IsAdmin() || len(ba.Requests) == 0 {
+		return false
+	}
+	// Requests from secondary tenants are always user traffic. Requests from
+	// the system tenant are user traffic only if they originate from SQL.
+	if tenantID, ok := roachpb.ClientTenantFromContext(ctx); !ok || tenantID.IsSystem() {
+		if ba.AdmissionHeader.Source != kvpb.AdmissionHeader_FROM_SQL {
+			return false
+		}
+	}
+	for _, ru := range ba.Requests {
+		req := ru.GetInner()
+		switch t := req.(type) {
+		case *kvpb.HeartbeatTxnRequest, *kvpb.PushTxnRequest, *kvpb.QueryTxnRequest,
+			*kvpb.RecoverTxnRequest, *kvpb.QueryIntentRequest, *kvpb.QueryLocksRequest,
+			*kvpb.ResolveIntentRequest, *kvpb.ResolveIntentRangeRequest,
+			*kvpb.LeaseInfoRequest:
+			return false
+		case *kvpb.EndTxnRequest:
+			if !t.Commit || len(ba.Requests) == 1 {
+				return false
+			}
+		}
+		limitable := false
+		for _, s := range spanRateLimitableSpans {
+			if s.Contains(req.Header().Span()) {
+				limitable = true
+				break
+			}
+		}
+		if !limitable {
+			return false
+		}
+	}
+	return true
+}
+
+// maybeSpanRateLimitBatch blocks the batch until it fits within the rate limits
+// configured in the replica's span config, if any. The limits are enforced by
+// the leaseholder, using a limiter shared by all of the ranges of the
+// configured span whose leases are held by this store. If the batch can't be
+// admitted within kv.span_rate_limit.max_wait, a SpanRateLimitExceededError is
+// returned. The error is not retried by KV; SQL reports it to the client with
+// the SpanRateLimitExceeded error code, so that the client can retry the
+// transaction after backing off.
+func (r *Replica) maybeSpanRateLimitBatch(ctx context.Context, ba *kvpb.BatchRequest) error {
+	if !canSpanRateLimitBatch(ctx, ba) {
+		return nil
+	}
+	r.mu.RLock()
+	requestsPerSecond := r.mu.conf.RateLimitRequestsPerSecond
+	writeBytesPerSecond := r.mu.conf.RateLimitWriteBytesPerSecond
+	confSpan := r.mu.confSpan
+	if confSpan.Equal(roachpb.Span{}) {
+		confSpan = r.descRLocked().RSpan().AsRawSpanWithNoLocals()
+	}
+	r.mu.RUnlock()
+	if requestsPerSecond <= 0 && writeBytesPerSecond <= 0 {
+		return nil
+	}
+	// Only the leaseholder enforces the limits. Requests to other replicas are
+	// either redirected to the leaseholder or served as follower reads, and
+	// limiting them as well would consume a span's quota more than once.
+	now := r.store.Clock().NowAsClockTimestamp()
+	if !r.OwnsValidLease(ctx, now) {
+		return nil
+	}
+	limiterSpan := spanRateLimiterSpan(confSpan, ba.Requests[0].GetInner().Header().Key)
+	limiter := r.store.spanLimiters.get(limiterSpan, now.ToTimestamp().GoTime())
+	requests, writeBytes := limiter.limiters(requestsPerSecond, writeBytesPerSecond)
+
+	var writeBytesCount int64
+	if writeBytes != nil {
+		for i := range ba.Requests {
+			req := ba.Requests[i].GetInner()
+			if kvpb.IsReadOnly(req) {
+				continue
+			}
+			if swr, isSizedWrite := req.(kvpb.SizedWriteRequest); isSizedWrite {
+				writeBytesCount += swr.WriteBytes()
+			}
+		}
+	}
+
+	maxWait := spanRateLimitMaxWait.Get(&r.store.cfg.Settings.SV)
+	acquire := func(limiter *quotapool.RateLimiter, limit string, n int64) error {
+		if limiter == nil || limiter.AdmitN(n) {
+			return nil
+		}
+		r.store.metrics.SpanRateLimitWaitedRequests.Inc(1)
+		err := timeutil.RunWithTimeout(ctx, "span rate limit", maxWait, func(ctx context.Context) error {
+			return limiter.WaitN(ctx, n)
+		})
+		if err != nil && ctx.Err() == nil && errors.HasType(err, (*timeutil.TimeoutError)(nil)) {
+			r.store.metrics.SpanRateLimitRejectedRequests.Inc(1)
+			return kvpb.NewSpanRateLimitExceededError(r.RangeID, limit, maxWait)
+		}
+		return err
+	}
+	if err := acquire(requests, "rate_limit.requests_per_second", 1); err != nil {
+		return err
+	}
+	if err := acquire(writeBytes, "rate_limit.write_bytes_per_second", writeBytesCount); err != nil {
+		// The batch is not evaluated, so it should not count against the request
+		// rate limit.
+		if requests != nil {
+			requests.RefundN(1)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanRateLimiterLimiters(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var l spanRateLimiter
+
+	// Unconfigured limits don't have a limiter.
+	requests, writeBytes := l.limiters(0, 0)
+	require.Nil(t, requests)
+	require.Nil(t, writeBytes)
+
+	// The burst is one second worth of quota.
+	requests, writeBytes = l.limiters(2, 0)
+	require.NotNil(t, requests)
+	require.Nil(t, writeBytes)
+	require.True(t, requests.AdmitN(1))
+	require.True(t, requests.AdmitN(1))
+	require.False(t, requests.AdmitN(1))
+
+	// Unchanged limits reuse the same limiters, so their quota is shared.
+	requests2, writeBytes := l.limiters(2, 100)
+	require.Same(t, requests, requests2)
+	require.NotNil(t, writeBytes)
+	require.False(t, requests2.AdmitN(1))
+	require.True(t, writeBytes.AdmitN(100))
+	require.False(t, writeBytes.AdmitN(1))
+
+	// Removing a limit removes its limiter.
+	requests, writeBytes = l.limiters(0, 100)
+	require.Nil(t, requests)
+	require.NotNil(t, writeBytes)
+}
+
+func TestSpanRateLimitersGet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	var s spanRateLimiters
+	now := timeutil.Unix(0, 123)
+	spanA := roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("c")}
+	spanB := roachpb.Span{Key: roachpb.Key("c"), EndKey: roachpb.Key("e")}
+
+	// The ranges of a configured span share its limiter.
+	a := s.get(spanA, now)
+	require.Same(t, a, s.get(spanA.Clone(), now))
+	b := s.get(spanB, now)
+	require.NotSame(t, a, b)
+
+	// Idle limiters are released once enough limiters were created.
+	s.mu.nextSweep = 0
+	now = now.Add(spanRateLimiterIdleTimeout + time.Second)
+	require.Same(t, b, s.get(spanB, now))
+	s.get(roachpb.Span{Key: roachpb.Key("e"), EndKey: roachpb.Key("f")}, now)
+	require.Len(t, s.mu.limiters, 2)
+	require.NotSame(t, a, s.get(spanA, now))
+}
+
+// TestSpanRateLimitBatch sends requests through the store and verifies that
+// user requests are throttled and rejected once the span rate limit quota is
+// exhausted, while KV-internal requests and requests to system ranges are not
+// rate limited.
+func TestSpanRateLimitBatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	tc := testContext{manualClock: timeutil.NewManualTime(timeutil.Unix(0, 123))}
+	cfg := TestStoreConfig(hlc.NewClockForTesting(tc.manualClock))
+	cfg.TestingKnobs.DontCloseTimestamps = true
+	// The limiters use the manual clock, so that quota is only replenished
+	// when the test advances it.
+	cfg.TestingKnobs.SpanRateLimitQuotaPoolOptions = []quotapool.Option{
+		quotapool.WithTimeSource(tc.manualClock),
+	}
+	cfg.TestingKnobs.SetSpanConfigInterceptor = func(
+		_ *roachpb.RangeDescriptor, conf roachpb.SpanConfig,
+	) roachpb.SpanConfig {
+		// The burst is one second worth of quota, so a single request is
+		// admitted immediately and the next one has to wait for a second.
+		conf.RateLimitRequestsPerSecond = 1
+		return conf
+	}
+	spanRateLimitMaxWait.Override(ctx, &cfg.Settings.SV, time.Millisecond)
+	tc.StartWithStoreConfig(ctx, t, stopper, cfg)
+	tc.repl.SetSpanConfig(roachpb.TestingDefaultSpanConfig(), roachpb.Span{
+		Key: roachpb.KeyMin, EndKey: roachpb.KeyMax,
+	})
+	metrics := tc.store.Metrics()
+
+	userKey := append(keys.SystemSQLCodec.TablePrefix(100), "a"...)
+	systemKey := append(keys.SystemSQLCodec.TablePrefix(keys.DescriptorTableID), "a"...)
+	put := func(key roachpb.Key, source kvpb.AdmissionHeader_Source) *kvpb.Error {
+		ba := &kvpb.BatchRequest{}
+		ba.AdmissionHeader.Source = source
+		req := putArgs(key, []byte("value"))
+		ba.Add(&req)
+		_, pErr := tc.store.TestSender().Send(ctx, ba)
+		return pErr
+	}
+
+	// Only the leaseholder enforces span rate limits. Acquire the lease with a
+	// request that is not rate limited.
+	require.Nil(t, put(userKey, kvpb.AdmissionHeader_OTHER))
+
+	// The first user request is admitted using the burst quota.
+	require.Nil(t, put(userKey, kvpb.AdmissionHeader_FROM_SQL))
+	require.Zero(t, metrics.SpanRateLimitWaitedRequests.Count())
+
+	// The next user request is rejected after waiting for max_wait.
+	pErr := put(userKey, kvpb.AdmissionHeader_FROM_SQL)
+	require.NotNil(t, pErr)
+	require.IsType(t, &kvpb.SpanRateLimitExceededError{}, pErr.GetDetail())
+	require.Equal(t, int64(1), metrics.SpanRateLimitWaitedRequests.Count())
+	require.Equal(t, int64(1), metrics.SpanRateLimitRejectedRequests.Count())
+
+	// KV-internal requests and requests to system ranges are not limited.
+	require.Nil(t, put(userKey, kvpb.AdmissionHeader_OTHER))
+	require.Nil(t, put(userKey, kvpb.AdmissionHeader_ROOT_KV))
+	require.Nil(t, put(systemKey, kvpb.AdmissionHeader_FROM_SQL))
+	require.Equal(t, int64(1), metrics.SpanRateLimitWaitedRequests.Count())
+
+	// With a longer max_wait, a user request is throttled until quota is
+	// available instead of being rejected.
+	spanRateLimitMaxWait.Override(ctx, &cfg.Settings.SV, time.Hour)
+	errCh := make(chan *kvpb.Error, 1)
+	go func() { errCh <- put(userKey, kvpb.AdmissionHeader_FROM_SQL) }()
+	testutils.SucceedsSoon(t, func() error {
+		if n := metrics.SpanRateLimitWaitedRequests.Count(); n != 2 {
+			return errors.Errorf("expected 2 waiting requests, found %d", n)
+		}
+		return nil
+	})
+	select {
+	case pErr := <-errCh:
+		t.Fatalf("request unexpectedly admitted without quota: %v", pErr)
+	default:
+	}
+	tc.manualClock.Advance(time.Second)
+	require.Nil(t, <-errCh)
+	require.Equal(t, int64(1), metrics.SpanRateLimitRejectedRequests.Count())
+}
+
+func TestCanSpanRateLimitBatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	userKey := append(keys.SystemSQLCodec.TablePrefix(100), "a"...)
+	makeBatch := func(reqs ...kvpb.Request) *kvpb.BatchRequest {
+		ba := &kvpb.BatchRequest{}
+		ba.AdmissionHeader.Source = kvpb.AdmissionHeader_FROM_SQL
+		ba.Add(reqs...)
+		return ba
+	}
+	put := putArgs(userKey, []byte("value"))
+	endTxn := func(commit bool) *kvpb.EndTxnRequest {
+		return &kvpb.EndTxnRequest{
+			RequestHeader: kvpb.RequestHeader{Key: userKey},
+			Commit:        commit,
+		}
+	}
+
+	require.True(t, canSpanRateLimitBatch(ctx, makeBatch(&put)))
+	// Commits that write data in the same batch are limited like other writes.
+	require.True(t, canSpanRateLimitBatch(ctx, makeBatch(&put, endTxn(true))))
+	// Rollbacks and standalone commits are never limited, so that the
+	// transaction's intents can be cleaned up.
+	require.False(t, canSpanRateLimitBatch(ctx, makeBatch(endTxn(true))))
+	require.False(t, canSpanRateLimitBatch(ctx, makeBatch(endTxn(false))))
+	require.False(t, canSpanRateLimitBatch(ctx, makeBatch(&put, endTxn(false))))
+}
+
+func TestSpanRateLimiterSpan(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	codec := keys.SystemSQLCodec
+	// The configured span of coalesced span configs may cover several tables.
+	confSpan := roachpb.Span{Key: codec.TablePrefix(100), EndKey: codec.TablePrefix(103)}
+	key := append(codec.TablePrefix(101), "a"...)
+	require.Equal(t,
+		roachpb.Span{Key: codec.TablePrefix(101), EndKey: codec.TablePrefix(101).PrefixEnd()},
+		spanRateLimiterSpan(confSpan, key))
+
+	// A configured span within a table, e.g. of an index, is used as is.
+	indexSpan := roachpb.Span{Key: codec.IndexPrefix(101, 2), EndKey: codec.IndexPrefix(101, 3)}
+	require.Equal(t, indexSpan, spanRateLimiterSpan(indexSpan, append(codec.IndexPrefix(101, 2), "a"...)))
+
+	// Keys that are not table keys use the configured span.
+	span := roachpb.Span{Key: roachpb.Key("a"), EndKey: roachpb.Key("c")}
+	require.Equal(t, span, spanRateLimiterSpan(span, roachpb.Key("b")))
+}
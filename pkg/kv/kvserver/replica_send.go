@@ -74,6 +74,9 @@ var optimisticEvalLimitedScans = settings.RegisterBoolSetting(
 //	Replica.maybeRateLimitBatch (tenant rate limits)
 //	                       │
 //	                       ▼
+//	Replica.maybeSpanRateLimitBatch (span rate limits)
+//	                       │
+//	                       ▼
 //	  Replica.maybeCommitWaitBeforeCommitTrigger (if committing with commit-trigger)
 //	                       │
 //
@@ -147,6 +150,9 @@ func (r *Replica) SendWithWriteBytes(
 	if err := r.maybeRateLimitBatch(ctx, ba); err != nil {
 		return nil, nil, kvpb.NewError(err)
 	}
+	if err := r.maybeSpanRateLimitBatch(ctx, ba); err != nil {
+		return nil, nil, kvpb.NewError(err)
+	}
 	if err := r.maybeCommitWaitBeforeCommitTrigger(ctx, ba); err != nil {
 		return nil, nil, kvpb.NewError(err)
 	}
@@ -994,6 +1000,11 @@ func (r *Replica) executeAdminBatch(
 		pErr = kvpb.NewError(err)
 		resp = &reply
 
+	case *kvpb.AdminRunGCRequest:
+		reply, err := r.adminRunGC(ctx, *tArgs)
+		pErr = kvpb.NewError(err)
+		resp = &reply
+
 	default:
 		return nil, kvpb.NewErrorf("unrecognized admin command: %T", args)
 	}
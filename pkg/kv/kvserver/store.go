@@ -1142,6 +1142,10 @@ type Store struct {
 	// tenantRateLimiters manages tenantrate.Limiters
 	tenantRateLimiters *tenantrate.LimiterFactory
 
+	// spanLimiters enforces the rate limits configured in span configs for the
+	// ranges whose leases are held by the store.
+	spanLimiters spanRateLimiters
+
 	// eagerLeaseAcquisitionLimiter limits the number of concurrent eager lease
 	// acquisitions made during Raft ticks.
 	eagerLeaseAcquisitionLimiter *quotapool.IntPool
@@ -1716,6 +1720,7 @@ func NewStore(
 
 	s.tenantRateLimiters = tenantrate.NewLimiterFactory(&cfg.Settings.SV, &cfg.TestingKnobs.TenantRateKnobs, authorizer)
 	s.metrics.registry.AddMetricStruct(s.tenantRateLimiters.Metrics())
+	s.spanLimiters.options = cfg.TestingKnobs.SpanRateLimitQuotaPoolOptions
 
 	s.systemConfigUpdateQueueRateLimiter = quotapool.NewRateLimiter(
 		"SystemConfigUpdateQueue",
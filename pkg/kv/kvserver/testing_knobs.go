@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 )
 
@@ -47,6 +48,10 @@ type StoreTestingKnobs struct {
 	ReplicaPlannerKnobs     plan.ReplicaPlannerTestingKnobs
 	StoreLivenessKnobs      *storeliveness.TestingKnobs
 
+	// SpanRateLimitQuotaPoolOptions are passed to the limiters that enforce
+	// the rate limits configured in span configs.
+	SpanRateLimitQuotaPoolOptions []quotapool.Option
+
 	// TestingRequestFilter is called before evaluating each request on a
 	// replica. The filter is run before the request acquires latches, so
 	// blocking in the filter will not block interfering requests. If it
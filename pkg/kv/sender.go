@@ -169,6 +169,9 @@ type TxnSender interface {
 
 	// CanUseSavepoint checks whether it would be valid to roll back or release
 	// the given savepoint in the current transaction state. It will never error.
+	// If the transaction has a retryable error, this reports whether the
+	// savepoint can be rolled back to once the transaction has been prepared for
+	// a partial retry or, for a savepoint taken before any activity, a restart.
 	CanUseSavepoint(context.Context, SavepointToken) bool
 
 	// SetFixedTimestamp makes the transaction run in an unusual way, at
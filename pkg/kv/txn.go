@@ -1751,6 +1751,9 @@ func (txn *Txn) ReleaseSavepoint(ctx context.Context, s SavepointToken) error {
 
 // CanUseSavepoint checks whether it would be valid to roll back or release
 // the given savepoint in the current transaction state. It will never error.
+// If the transaction has a retryable error, this reports whether the
+// savepoint can be rolled back to once the transaction has been prepared for
+// a partial retry or, for a savepoint taken before any activity, a restart.
 func (txn *Txn) CanUseSavepoint(ctx context.Context, s SavepointToken) bool {
 	txn.mu.Lock()
 	defer txn.mu.Unlock()
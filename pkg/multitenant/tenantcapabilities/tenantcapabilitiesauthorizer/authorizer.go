@@ -243,6 +243,7 @@ var reqMethodToCap = map[kvpb.Method]tenantcapabilities.ID{
 	// TODO(knz,arul): Verify with the relevant teams whether secondary
 	// tenants have legitimate access to any of those.
 	kvpb.AdminMerge:                    onlySystemTenant,
+	kvpb.AdminRunGC:                    onlySystemTenant,
 	kvpb.AdminVerifyProtectedTimestamp: onlySystemTenant,
 	kvpb.ComputeChecksum:               onlySystemTenant,
 	kvpb.GC:                            onlySystemTenant,
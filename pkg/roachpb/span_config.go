@@ -98,6 +98,12 @@ func (s *SpanConfig) ValidateSystemTargetSpanConfig() error {
 	if s.ExcludeDataFromBackup {
 		return errors.AssertionFailedf("ExcludeDataFromBackup set on system span config")
 	}
+	if s.RateLimitRequestsPerSecond != 0 {
+		return errors.AssertionFailedf("RateLimitRequestsPerSecond set on system span config")
+	}
+	if s.RateLimitWriteBytesPerSecond != 0 {
+		return errors.AssertionFailedf("RateLimitWriteBytesPerSecond set on system span config")
+	}
 	return nil
 }
 
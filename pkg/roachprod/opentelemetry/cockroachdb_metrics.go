@@ -520,6 +520,7 @@ var cockroachdbMetrics = map[string]string{
 	"distsender_rpc_err_replicacorruptionerrtype":                         "distsender.rpc.err.replicacorruptionerrtype",
 	"distsender_rpc_err_replicatooolderrtype":                             "distsender.rpc.err.replicatooolderrtype",
 	"distsender_rpc_err_replicaunavailableerrtype":                        "distsender.rpc.err.replicaunavailableerrtype",
+	"distsender_rpc_err_spanratelimitexceedederrtype":                     "distsender.rpc.err.spanratelimitexceedederrtype",
 	"distsender_rpc_err_storenotfounderrtype":                             "distsender.rpc.err.storenotfounderrtype",
 	"distsender_rpc_err_transactionabortederrtype":                        "distsender.rpc.err.transactionabortederrtype",
 	"distsender_rpc_err_transactionpusherrtype":                           "distsender.rpc.err.transactionpusherrtype",
@@ -1409,6 +1410,8 @@ var cockroachdbMetrics = map[string]string{
 	"requests_slow_latch":                                         "requests.slow.latch",
 	"requests_slow_lease":                                         "requests.slow.lease",
 	"requests_slow_raft":                                          "requests.slow.raft",
+	"requests_span_rate_limit_rejected":                           "requests.span_rate_limit.rejected",
+	"requests_span_rate_limit_waited":                             "requests.span_rate_limit.waited",
 	"rocksdb.compactions.total":                                   "rocksdb.compactions",
 	"rocksdb_block_cache_hits":                                    "rocksdb.block.cache.hits",
 	"rocksdb_block_cache_misses":                                  "rocksdb.block.cache.misses",
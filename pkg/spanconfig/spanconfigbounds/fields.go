@@ -48,6 +48,8 @@ var fields = [config.NumFields]Field{
 	constraints,
 	voterConstraints,
 	leasePreferences,
+	rateLimitRequestsPerSecond,
+	rateLimitWriteBytesPerSecond,
 }
 
 const (
@@ -60,4 +62,7 @@ const (
 	constraints      = constraintsConjunctionField(config.Constraints)
 	voterConstraints = constraintsConjunctionField(config.VoterConstraints)
 	leasePreferences = leasePreferencesField(config.LeasePreferences)
+
+	rateLimitRequestsPerSecond   = int64Field(config.RateLimitRequestsPerSecond)
+	rateLimitWriteBytesPerSecond = int64Field(config.RateLimitWriteBytesPerSecond)
 )
@@ -26,6 +26,12 @@ func (f int64Field) SafeFormat(s redact.SafePrinter, verb rune) {
 }
 
 func (f int64Field) FieldBound(b *Bounds) ValueBounds {
+	switch f {
+	case rateLimitRequestsPerSecond, rateLimitWriteBytesPerSecond:
+		// Rate limits only throttle the tenant's own traffic, so they are not
+		// bounded.
+		return unbounded{}
+	}
 	getBound := func() *tenantcapabilitiespb.SpanConfigBounds_Int64Range {
 		switch f {
 		case rangeMaxBytes:
@@ -55,6 +61,10 @@ func (f int64Field) fieldValue(c *roachpb.SpanConfig) *int64 {
 		return &c.RangeMaxBytes
 	case rangeMinBytes:
 		return &c.RangeMinBytes
+	case rateLimitRequestsPerSecond:
+		return &c.RateLimitRequestsPerSecond
+	case rateLimitWriteBytesPerSecond:
+		return &c.RateLimitWriteBytesPerSecond
 	default:
 		// This is safe because we test that all the fields in the proto have
 		// a corresponding field, and we call this for each of them, and the user
@@ -640,6 +640,12 @@ func PrintSpanConfigDiffedAgainstDefaults(conf roachpb.SpanConfig) string {
 	if conf.RangefeedEnabled != defaultConf.RangefeedEnabled {
 		diffs = append(diffs, fmt.Sprintf("rangefeed_enabled=%t", conf.RangefeedEnabled))
 	}
+	if conf.RateLimitRequestsPerSecond != defaultConf.RateLimitRequestsPerSecond {
+		diffs = append(diffs, fmt.Sprintf("rate_limit_requests_per_second=%d", conf.RateLimitRequestsPerSecond))
+	}
+	if conf.RateLimitWriteBytesPerSecond != defaultConf.RateLimitWriteBytesPerSecond {
+		diffs = append(diffs, fmt.Sprintf("rate_limit_write_bytes_per_second=%d", conf.RateLimitWriteBytesPerSecond))
+	}
 	if !reflect.DeepEqual(conf.Constraints, defaultConf.Constraints) {
 		diffs = append(diffs, fmt.Sprintf("constraints=%v", conf.Constraints))
 	}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/build"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -69,6 +70,11 @@ func (n *alterFunctionOptionsNode) startExec(params runParams) error {
 	if err != nil {
 		return err
 	}
+	if err := checkRoutineAggregateKind(
+		fnDesc, &n.n.Function, false /* isAggregateStmt */, "Use ALTER AGGREGATE to alter aggregate functions.",
+	); err != nil {
+		return err
+	}
 	// TODO(chengxiong): add validation that a function can not be altered if it's
 	// referenced by other objects. This is needed when want to allow function
 	// references. Need to think about in what condition a function can be altered
@@ -87,6 +93,13 @@ func (n *alterFunctionOptionsNode) startExec(params runParams) error {
 		if err := maybeValidateNewFuncVolatility(params, fnDesc, option); err != nil {
 			return err
 		}
+		switch option.(type) {
+		case tree.RoutineCost, tree.RoutineRows, tree.RoutineParallel, *tree.RoutineSetVar:
+			if !params.p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.V25_1) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"%s is not supported until upgrade to v25.1 is finalized", tree.AsString(option))
+			}
+		}
 	}
 
 	if err := setFuncOptions(params, fnDesc, n.n.Options); err != nil {
@@ -168,6 +181,11 @@ func (n *alterFunctionRenameNode) startExec(params runParams) error {
 			pgcode.UndefinedFunction, "could not find a procedure named %q", &n.n.Function.FuncName,
 		)
 	}
+	if err := checkRoutineAggregateKind(
+		fnDesc, &n.n.Function, n.n.Aggregate, "Use ALTER AGGREGATE to alter aggregate functions.",
+	); err != nil {
+		return err
+	}
 	oldFnName, err := params.p.getQualifiedFunctionName(params.ctx, fnDesc)
 	if err != nil {
 		return err
@@ -290,6 +308,11 @@ func (n *alterFunctionSetOwnerNode) startExec(params runParams) error {
 			pgcode.UndefinedFunction, "could not find a procedure named %q", &n.n.Function.FuncName,
 		)
 	}
+	if err := checkRoutineAggregateKind(
+		fnDesc, &n.n.Function, n.n.Aggregate, "Use ALTER AGGREGATE to alter aggregate functions.",
+	); err != nil {
+		return err
+	}
 	newOwner, err := decodeusername.FromRoleSpec(
 		params.p.SessionData(), username.PurposeValidation, n.n.NewOwner,
 	)
@@ -364,6 +387,11 @@ func (n *alterFunctionSetSchemaNode) startExec(params runParams) error {
 			pgcode.UndefinedFunction, "could not find a procedure named %q", &n.n.Function.FuncName,
 		)
 	}
+	if err := checkRoutineAggregateKind(
+		fnDesc, &n.n.Function, n.n.Aggregate, "Use ALTER AGGREGATE to alter aggregate functions.",
+	); err != nil {
+		return err
+	}
 	oldFnName, err := params.p.getQualifiedFunctionName(params.ctx, fnDesc)
 	if err != nil {
 		return err
@@ -527,13 +555,17 @@ func toSchemaOverloadSignature(fnDesc *funcdesc.Mutable) descpb.SchemaDescriptor
 		ReturnType:  fnDesc.ReturnType.Type,
 		ReturnSet:   fnDesc.ReturnType.ReturnSet,
 		IsProcedure: fnDesc.IsProcedure(),
+		IsAggregate: fnDesc.IsAggregate(),
 	}
 	for paramIdx, param := range fnDesc.Params {
 		class := funcdesc.ToTreeRoutineParamClass(param.Class)
 		if tree.IsInParamClass(class) {
 			ret.ArgTypes = append(ret.ArgTypes, param.Type)
 		}
-		if class == tree.RoutineParamOut {
+		if class == tree.RoutineParamVariadic {
+			ret.IsVariadic = true
+		}
+		if tree.IsOutOnlyParamClass(class) {
 			ret.OutParamOrdinals = append(ret.OutParamOrdinals, int32(paramIdx))
 			ret.OutParamTypes = append(ret.OutParamTypes, param.Type)
 		}
@@ -1528,9 +1528,13 @@ StatsLoop:
 			return err
 		}
 
+		// Multi-column histograms are not included in JSON statistics, so ignore
+		// any histogram on a multi-column statistic.
+		if len(s.Columns) > 1 {
+			h = nil
+		}
+
 		// Check that the type matches.
-		// TODO(49698): When we support multi-column histograms this check will need
-		// adjustment.
 		if len(s.Columns) == 1 {
 			col := catalog.FindColumnByName(desc, s.Columns[0])
 			// Ignore dropped columns (they are handled below).
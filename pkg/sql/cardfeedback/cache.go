@@ -0,0 +1,153 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+// Package cardfeedback implements adaptive re-planning of statements whose
+// cardinality estimates turn out to be grossly wrong at execution time, along
+// with a cache of the observed cardinalities that is consulted by the
+// optimizer when planning later executions of the same statement fingerprint.
+package cardfeedback
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+)
+
+// Enabled controls whether operators that detect gross cardinality
+// misestimates may request that the statement be re-planned, and whether the
+// observed cardinalities are used when planning later executions of the same
+// statement fingerprint.
+var Enabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.adaptive_replanning.enabled",
+	"if enabled, read-only statements whose hash join build side is much larger "+
+		"than estimated are re-planned using the observed row counts, which are "+
+		"also remembered for later executions of the statement fingerprint; a "+
+		"re-planned statement is executed again from the beginning and the work "+
+		"done by the first execution is discarded, so statements with volatile "+
+		"expressions, such as sequence functions or volatile routines, and "+
+		"statements that already sent rows to the client are never re-planned",
+	false,
+	settings.WithPublic)
+
+// MisestimateFactor is the ratio between the observed and the estimated row
+// count above which an estimate is considered to be grossly wrong.
+var MisestimateFactor = settings.RegisterFloatSetting(
+	settings.ApplicationLevel,
+	"sql.adaptive_replanning.misestimate_factor",
+	"ratio between the observed and estimated row counts of a hash join build "+
+		"side above which the statement is re-planned",
+	100,
+	settings.FloatWithMinimum(2),
+)
+
+// MinRows is the minimum number of rows that must be observed before an
+// estimate is considered to be grossly wrong. It avoids re-planning
+// statements that are cheap to run to completion regardless of the estimate.
+var MinRows = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.adaptive_replanning.min_rows",
+	"minimum number of rows observed on a hash join build side before the "+
+		"statement can be re-planned",
+	10000,
+	settings.PositiveInt,
+)
+
+// MisestimateThreshold returns the number of rows above which an input
+// estimated to produce estimatedRowCount rows is considered to be grossly
+// misestimated.
+func MisestimateThreshold(sv *settings.Values, estimatedRowCount uint64) uint64 {
+	threshold := uint64(MisestimateFactor.Get(sv) * float64(estimatedRowCount))
+	if minRows := uint64(MinRows.Get(sv)); threshold < minRows {
+		threshold = minRows
+	}
+	return threshold
+}
+
+var cacheSize = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.adaptive_replanning.feedback_cache_size",
+	"maximum number of statement fingerprints for which observed cardinalities "+
+		"are remembered on each node",
+	1000,
+	settings.NonNegativeInt,
+)
+
+// Observations maps the keys of the relational expressions of a statement
+// (see memo.CardinalityFeedbackKeyBuilder) to the row counts observed when
+// executing them. An Observations map is never modified once it has been added to the
+// Cache, so it is safe to share.
+type Observations map[string]float64
+
+// Cache remembers the cardinalities observed during the execution of
+// statements, keyed by statement fingerprint. The least recently used
+// fingerprints are evicted once the cache holds more than
+// sql.adaptive_replanning.feedback_cache_size entries.
+type Cache struct {
+	st *cluster.Settings
+
+	mu struct {
+		syncutil.Mutex
+		cache *cache.UnorderedCache
+	}
+}
+
+// NewCache constructs a new Cache.
+func NewCache(st *cluster.Settings) *Cache {
+	c := &Cache{st: st}
+	c.mu.cache = cache.NewUnorderedCache(cache.Config{
+		Policy: cache.CacheLRU,
+		ShouldEvict: func(size int, _, _ interface{}) bool {
+			return int64(size) > cacheSize.Get(&st.SV)
+		},
+	})
+	return c
+}
+
+// Lookup returns the cardinalities observed for the given statement
+// fingerprint, or nil if there are none.
+func (c *Cache) Lookup(fingerprint string) Observations {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.mu.cache.Get(fingerprint); ok {
+		return v.(Observations)
+	}
+	return nil
+}
+
+// Record remembers that the relational expression identified by key produced
+// rowCount rows during an execution of the given statement fingerprint.
+func (c *Cache) Record(fingerprint string, key string, rowCount float64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var prev Observations
+	if v, ok := c.mu.cache.Get(fingerprint); ok {
+		prev = v.(Observations)
+	}
+	if existing, ok := prev[key]; ok && existing == rowCount {
+		return
+	}
+	// Observations are shared with concurrent planners, so copy on write.
+	next := make(Observations, len(prev)+1)
+	for k, v := range prev {
+		next[k] = v
+	}
+	next[key] = rowCount
+	c.mu.cache.Add(fingerprint, next)
+}
+
+// Clear removes all observations from the cache.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.cache.Clear()
+}
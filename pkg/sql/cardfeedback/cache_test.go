@@ -0,0 +1,47 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package cardfeedback
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	cacheSize.Override(ctx, &st.SV, 2)
+	c := NewCache(st)
+
+	require.Nil(t, c.Lookup("SELECT 1"))
+
+	c.Record("SELECT a", "(1,2)/10", 5000)
+	first := c.Lookup("SELECT a")
+	require.Equal(t, Observations{"(1,2)/10": 5000}, first)
+
+	// Recording another observation must not modify the map returned earlier,
+	// since it may be in use by a concurrent planner.
+	c.Record("SELECT a", "(3)/1", 200)
+	require.Equal(t, Observations{"(1,2)/10": 5000}, first)
+	require.Equal(t, Observations{"(1,2)/10": 5000, "(3)/1": 200}, c.Lookup("SELECT a"))
+
+	// The least recently used fingerprint is evicted once the cache is full.
+	c.Record("SELECT b", "(4)/1", 100)
+	require.NotNil(t, c.Lookup("SELECT a"))
+	c.Record("SELECT c", "(5)/1", 100)
+	require.Nil(t, c.Lookup("SELECT b"))
+	require.NotNil(t, c.Lookup("SELECT a"))
+	require.NotNil(t, c.Lookup("SELECT c"))
+
+	c.Clear()
+	require.Nil(t, c.Lookup("SELECT a"))
+}
@@ -458,6 +458,9 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 	target.AddDescriptor(systemschema.SystemJobProgressHistoryTable)
 	target.AddDescriptor(systemschema.SystemJobStatusTable)
 	target.AddDescriptor(systemschema.SystemJobMessageTable)
+	target.AddDescriptor(systemschema.StatementPlanBaselinesTable)
+	target.AddDescriptor(systemschema.StatementHintsTable)
+	target.AddDescriptor(systemschema.IndexRecommendationsTable)
 
 	// Adding a new system table? It should be added here to the metadata schema,
 	// and also created as a migration for older clusters.
@@ -471,7 +474,7 @@ func addSystemDescriptorsToSchema(target *MetadataSchema) {
 // NumSystemTablesForSystemTenant is the number of system tables defined on
 // the system tenant. This constant is only defined to avoid having to manually
 // update auto stats tests every time a new system table is added.
-const NumSystemTablesForSystemTenant = 61
+const NumSystemTablesForSystemTenant = 64
 
 // addSplitIDs adds a split point for each of the PseudoTableIDs to the supplied
 // MetadataSchema.
@@ -78,6 +78,9 @@ var (
 		catconstants.JobsProgressHistoryTableName,
 		catconstants.JobsStatusTableName,
 		catconstants.JobsMessageTableName,
+		catconstants.StatementPlanBaselinesTableName,
+		catconstants.StatementHintsTableName,
+		catconstants.IndexRecommendationsTableName,
 	}
 
 	readWriteSystemSequences = []catconstants.SystemTableName{
@@ -240,6 +240,16 @@ var AlterTableScatterColumns = ResultColumns{
 	{Name: "pretty", Typ: types.String},
 }
 
+// AlterRunGCColumns are the result columns of an ALTER RANGE .. GC or
+// ALTER TABLE .. GC NOW statement.
+var AlterRunGCColumns = ResultColumns{
+	{Name: "range_id", Typ: types.Int},
+	{Name: "garbage_bytes_before", Typ: types.Int},
+	{Name: "garbage_bytes_after", Typ: types.Int},
+	{Name: "reclaimed_bytes", Typ: types.Int},
+	{Name: "result", Typ: types.String},
+}
+
 // AlterRangeRelocateColumns are the result columns of an
 // ALTER RANGE .. RELOCATE statement.
 var AlterRangeRelocateColumns = ResultColumns{
@@ -388,6 +388,10 @@ type TableDescriptor interface {
 	// GetViewQuery returns this view's CREATE VIEW declaration. Only valid if
 	// IsView is true.
 	GetViewQuery() string
+	// GetViewRefreshedAsOf returns the timestamp as of which the contents of a
+	// materialized view were last computed. Only valid if MaterializedView
+	// returns true.
+	GetViewRefreshedAsOf() hlc.Timestamp
 
 	// GetDropTime returns the timestamp at which the table is truncated or
 	// dropped. It's represented as the current time in nanoseconds since the
@@ -818,6 +822,11 @@ type TableDescriptor interface {
 	// IsSchemaLocked returns true if we don't allow performing schema changes
 	// on this table descriptor.
 	IsSchemaLocked() bool
+	// IsFamilyPerColumn returns true if each non-primary key column of the table
+	// is stored in its own column family, so that scans can skip the column
+	// families of columns that are not needed. The KVs of the table are still
+	// laid out row by row.
+	IsFamilyPerColumn() bool
 	// IsPrimaryKeySwapMutation returns true if the mutation is a primary key
 	// swap mutation or a secondary index used by the declarative schema changer
 	// for a primary index swap.
@@ -1059,8 +1068,27 @@ type FunctionDescriptor interface {
 	// returns false if the descriptor represents a user-defined function.
 	IsProcedure() bool
 
+	// IsAggregate returns true if the descriptor represents a user-defined
+	// aggregate function.
+	IsAggregate() bool
+
 	// GetSecurity returns the security specification of this function.
 	GetSecurity() catpb.Function_Security
+
+	// GetCost returns the estimated execution cost of this function, or zero if
+	// none was specified.
+	GetCost() float64
+
+	// GetRows returns the estimated number of rows returned by this function,
+	// or zero if none was specified.
+	GetRows() float64
+
+	// GetParallel returns the parallel safety of this function.
+	GetParallel() catpb.Function_Parallel
+
+	// GetConfig returns the session variables set while this function is
+	// executing.
+	GetConfig() []descpb.FunctionDescriptor_SessionVar
 }
 
 // FilterDroppedDescriptor returns an error if the descriptor state is DROP.
@@ -228,6 +228,18 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			vea.Report(errors.AssertionFailedf("invalid type id %d in depends-on-types references #%d", typeID, i))
 		}
 	}
+
+	if agg := desc.Aggregate; agg != nil {
+		if desc.IsProcedure() {
+			vea.Report(errors.AssertionFailedf("procedure cannot be an aggregate"))
+		}
+		if agg.StateFuncID == descpb.InvalidID {
+			vea.Report(errors.AssertionFailedf("aggregate state function not set"))
+		}
+		if agg.StateType == nil {
+			vea.Report(errors.AssertionFailedf("aggregate state type not set"))
+		}
+	}
 }
 
 // ValidateForwardReferences implements the catalog.Descriptor interface.
@@ -613,6 +625,48 @@ func (desc *Mutable) SetSecurity(v catpb.Function_Security) {
 	desc.Security = v
 }
 
+// SetCost sets the estimated execution cost of the function.
+func (desc *Mutable) SetCost(v float64) {
+	desc.Cost = v
+}
+
+// SetRows sets the estimated number of rows returned by the function.
+func (desc *Mutable) SetRows(v float64) {
+	desc.Rows = v
+}
+
+// SetParallel sets the parallel safety of the function.
+func (desc *Mutable) SetParallel(v catpb.Function_Parallel) {
+	desc.Parallel = v
+}
+
+// SetConfigVar sets the value of a session variable that is applied while the
+// function is executing, replacing any existing value for the variable.
+func (desc *Mutable) SetConfigVar(name, value string) {
+	for i := range desc.Config {
+		if desc.Config[i].Name == name {
+			desc.Config[i].Value = value
+			return
+		}
+	}
+	desc.Config = append(desc.Config, descpb.FunctionDescriptor_SessionVar{Name: name, Value: value})
+}
+
+// ResetConfigVar removes the setting of the given session variable from the
+// function. If name is empty, all settings are removed.
+func (desc *Mutable) ResetConfigVar(name string) {
+	if name == "" {
+		desc.Config = nil
+		return
+	}
+	for i := range desc.Config {
+		if desc.Config[i].Name == name {
+			desc.Config = append(desc.Config[:i], desc.Config[i+1:]...)
+			return
+		}
+	}
+}
+
 // SetName sets the function name.
 func (desc *Mutable) SetName(n string) {
 	desc.Name = n
@@ -883,6 +937,9 @@ func (desc *immutable) ToOverload() (ret *tree.Overload, err error) {
 		if tree.IsInParamClass(class) {
 			signatureTypes = append(signatureTypes, tree.ParamType{Name: param.Name, Typ: param.Type})
 		}
+		if class == tree.RoutineParamVariadic {
+			ret.Variadic = true
+		}
 		routineParam := tree.RoutineParam{
 			Name:  tree.Name(param.Name),
 			Type:  param.Type,
@@ -910,7 +967,29 @@ func (desc *immutable) ToOverload() (ret *tree.Overload, err error) {
 	if desc.ReturnType.ReturnSet {
 		ret.Class = tree.GeneratorClass
 	}
+	if agg := desc.Aggregate; agg != nil {
+		ret.Class = tree.AggregateClass
+		ret.UDFAggregate = &tree.UDFAggregate{
+			StateFunc: catid.FuncIDToOID(agg.StateFuncID),
+			StateType: agg.StateType,
+			InitCond:  agg.InitCond,
+		}
+		if agg.FinalFuncID != descpb.InvalidID {
+			ret.UDFAggregate.FinalFunc = catid.FuncIDToOID(agg.FinalFuncID)
+		}
+	}
 	ret.SecurityMode = desc.getCreateExprSecurity()
+	ret.Cost = desc.Cost
+	ret.Rows = desc.Rows
+	if len(desc.Config) > 0 {
+		ret.SessionVars = make([]tree.RoutineSessionVar, len(desc.Config))
+		for i := range desc.Config {
+			ret.SessionVars[i] = tree.RoutineSessionVar{
+				Name:  desc.Config[i].Name,
+				Value: desc.Config[i].Value,
+			}
+		}
+	}
 
 	return ret, nil
 }
@@ -974,15 +1053,30 @@ func (desc *immutable) ToCreateExpr() (ret *tree.CreateRoutine, err error) {
 			}
 		}
 	}
-	// We only store 6 function attributes at the moment. We may extend the
-	// pre-allocated capacity in the future.
-	ret.Options = make(tree.RoutineOptions, 0, 6)
+	// We always store 6 function attributes. The parallel mode, cost, rows, and
+	// session variable settings are only included if they were specified.
+	ret.Options = make(tree.RoutineOptions, 0, 9+len(desc.Config))
 	ret.Options = append(ret.Options, desc.getCreateExprVolatility())
 	ret.Options = append(ret.Options, tree.RoutineLeakproof(desc.LeakProof))
 	ret.Options = append(ret.Options, desc.getCreateExprNullInputBehavior())
 	ret.Options = append(ret.Options, tree.RoutineBodyStr(desc.FunctionBody))
 	ret.Options = append(ret.Options, desc.getCreateExprLang())
 	ret.Options = append(ret.Options, desc.getCreateExprSecurity())
+	if desc.Parallel != catpb.Function_UNSAFE {
+		ret.Options = append(ret.Options, desc.getCreateExprParallel())
+	}
+	if desc.Cost != 0 {
+		ret.Options = append(ret.Options, tree.RoutineCost(desc.Cost))
+	}
+	if desc.Rows != 0 {
+		ret.Options = append(ret.Options, tree.RoutineRows(desc.Rows))
+	}
+	for i := range desc.Config {
+		ret.Options = append(ret.Options, &tree.RoutineSetVar{
+			Name:   desc.Config[i].Name,
+			Values: tree.Exprs{tree.NewStrVal(desc.Config[i].Value)},
+		})
+	}
 	return ret, nil
 }
 
@@ -991,6 +1085,11 @@ func (desc *immutable) IsProcedure() bool {
 	return desc.FunctionDescriptor.IsProcedure
 }
 
+// IsAggregate implements the FunctionDescriptor interface.
+func (desc *immutable) IsAggregate() bool {
+	return desc.FunctionDescriptor.Aggregate != nil
+}
+
 func (desc *immutable) getCreateExprLang() tree.RoutineLanguage {
 	switch desc.Lang {
 	case catpb.Function_SQL:
@@ -1035,6 +1134,18 @@ func (desc *immutable) getCreateExprSecurity() tree.RoutineSecurity {
 	return 0
 }
 
+func (desc *immutable) getCreateExprParallel() tree.RoutineParallel {
+	switch desc.Parallel {
+	case catpb.Function_UNSAFE:
+		return tree.RoutineParallelUnsafe
+	case catpb.Function_RESTRICTED:
+		return tree.RoutineParallelRestricted
+	case catpb.Function_SAFE:
+		return tree.RoutineParallelSafe
+	}
+	return 0
+}
+
 // ToTreeRoutineParamClass converts the proto enum value to the corresponding
 // tree.RoutineParamClass.
 func ToTreeRoutineParamClass(class catpb.Function_Param_Class) tree.RoutineParamClass {
@@ -1049,6 +1160,8 @@ func ToTreeRoutineParamClass(class catpb.Function_Param_Class) tree.RoutineParam
 		return tree.RoutineParamInOut
 	case catpb.Function_Param_VARIADIC:
 		return tree.RoutineParamVariadic
+	case catpb.Function_Param_TABLE:
+		return tree.RoutineParamTable
 	}
 	return 0
 }
@@ -126,6 +126,8 @@ func ParamClassToProto(v tree.RoutineParamClass) (catpb.Function_Param_Class, er
 		return catpb.Function_Param_IN_OUT, nil
 	case tree.RoutineParamVariadic:
 		return catpb.Function_Param_VARIADIC, nil
+	case tree.RoutineParamTable:
+		return catpb.Function_Param_TABLE, nil
 	}
 
 	return -1, errors.AssertionFailedf("unknown function parameter class %q", v)
@@ -141,3 +143,16 @@ func SecurityToProto(v tree.RoutineSecurity) (catpb.Function_Security, error) {
 	}
 	return -1, errors.AssertionFailedf("unknown function security class %q", v)
 }
+
+// ParallelToProto converts sql statement input parallel mode to protobuf type.
+func ParallelToProto(v tree.RoutineParallel) (catpb.Function_Parallel, error) {
+	switch v {
+	case tree.RoutineParallelUnsafe:
+		return catpb.Function_UNSAFE, nil
+	case tree.RoutineParallelRestricted:
+		return catpb.Function_RESTRICTED, nil
+	case tree.RoutineParallelSafe:
+		return catpb.Function_SAFE, nil
+	}
+	return -1, errors.AssertionFailedf("unknown function parallel mode %q", v)
+}
@@ -595,10 +595,14 @@ func (desc *immutable) GetResolvedFuncDefinition(
 			Type:                     routineType,
 			UDFContainsOnlySignature: true,
 			OutParamOrdinals:         sig.OutParamOrdinals,
+			Variadic:                 sig.IsVariadic,
 		}
 		if funcDescPb.Signatures[i].ReturnSet {
 			overload.Class = tree.GeneratorClass
 		}
+		if sig.IsAggregate {
+			overload.Class = tree.AggregateClass
+		}
 		// There is no need to look at the parameter classes since ArgTypes
 		// already contains only parameters that are included into the
 		// signature of the overload.
@@ -324,6 +324,58 @@ CREATE TABLE system.job_progress (
 		CONSTRAINT "primary" PRIMARY KEY (job_id ASC, written DESC, kind ASC)
 	)`
 
+	// StatementPlanBaselinesTableSchema is the table that stores the plan
+	// baselines of statement fingerprints. The optimizer constrains planning of
+	// a fingerprint to its pinned plan, if there is one. Other plans are
+	// recorded as candidates when candidate verification is enabled, and are
+	// either rejected or promoted to replace the pinned plan once they have
+	// been compared with it.
+	StatementPlanBaselinesTableSchema = `
+	CREATE TABLE system.statement_plan_baselines (
+		fingerprint STRING NOT NULL,
+		plan_gist STRING NOT NULL,
+		status STRING NOT NULL, -- one of "pinned", "candidate", "rejected" or "retired"
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		last_verified_at TIMESTAMPTZ,
+		--
+		FAMILY "primary" ("fingerprint", "plan_gist", "status", "created_at", "last_verified_at"),
+		CONSTRAINT "primary" PRIMARY KEY (fingerprint ASC, plan_gist ASC)
+	)`
+
+	// StatementHintsTableSchema is the table that stores external hints for
+	// statement fingerprints. Each hint is a directive, such as an index hint,
+	// a join hint or a session variable override, that the optimizer applies
+	// when planning a statement with the given fingerprint.
+	StatementHintsTableSchema = `
+	CREATE TABLE system.statement_hints (
+		fingerprint STRING NOT NULL,
+		hint STRING NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		--
+		FAMILY "primary" ("fingerprint", "hint", "created_at"),
+		CONSTRAINT "primary" PRIMARY KEY (fingerprint ASC, hint ASC)
+	)`
+
+	// IndexRecommendationsTableSchema is the table that stores the ranked
+	// workload index recommendations of the index advisor job, together with
+	// the evidence that supports them. An operator reviews a recommendation by
+	// changing its status.
+	IndexRecommendationsTableSchema = `
+	CREATE TABLE system.index_recommendations (
+		id INT8 NOT NULL DEFAULT unique_rowid(),
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+		rank INT8 NOT NULL,
+		action STRING NOT NULL, -- one of "create", "drop" or "replace"
+		statements STRING NOT NULL,
+		estimated_improvement FLOAT8 NOT NULL,
+		write_amplification FLOAT8 NOT NULL,
+		evidence JSONB NOT NULL,
+		status STRING NOT NULL, -- one of "pending", "approved" or "rejected"
+		--
+		FAMILY "primary" ("id", "created_at", "rank", "action", "statements", "estimated_improvement", "write_amplification", "evidence", "status"),
+		CONSTRAINT "primary" PRIMARY KEY (id ASC)
+	)`
+
 	// web_sessions are used to track authenticated user actions over stateless
 	// connections, such as the cookie-based authentication used by the Admin
 	// UI.
@@ -1367,7 +1419,7 @@ const SystemDatabaseName = catconstants.SystemDatabaseName
 // release version).
 //
 // NB: Don't set this to clusterversion.Latest; use a specific version instead.
-var SystemDatabaseSchemaBootstrapVersion = clusterversion.V25_1_AddJobsTables.Version()
+var SystemDatabaseSchemaBootstrapVersion = clusterversion.V25_1_AddIndexRecommendationsTable.Version()
 
 // MakeSystemDatabaseDesc constructs a copy of the system database
 // descriptor.
@@ -1563,6 +1615,9 @@ func MakeSystemTables() []SystemTable {
 		SystemJobProgressHistoryTable,
 		SystemJobStatusTable,
 		SystemJobMessageTable,
+		StatementPlanBaselinesTable,
+		StatementHintsTable,
+		IndexRecommendationsTable,
 	}
 }
 
@@ -4395,6 +4450,106 @@ var (
 			}),
 	)
 
+	// StatementPlanBaselinesTable is described in comment on
+	// StatementPlanBaselinesTableSchema.
+	StatementPlanBaselinesTable = makeSystemTable(
+		StatementPlanBaselinesTableSchema,
+		systemTable(
+			catconstants.StatementPlanBaselinesTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "fingerprint", ID: 1, Type: types.String},
+				{Name: "plan_gist", ID: 2, Type: types.String},
+				{Name: "status", ID: 3, Type: types.String},
+				{Name: "created_at", ID: 4, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+				{Name: "last_verified_at", ID: 5, Type: types.TimestampTZ, Nullable: true},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"fingerprint", "plan_gist", "status", "created_at", "last_verified_at"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"fingerprint", "plan_gist"},
+				KeyColumnDirections: []catenumpb.IndexColumn_Direction{catenumpb.IndexColumn_ASC, catenumpb.IndexColumn_ASC},
+				KeyColumnIDs:        []descpb.ColumnID{1, 2},
+			}),
+	)
+
+	// StatementHintsTable is described in comment on StatementHintsTableSchema.
+	StatementHintsTable = makeSystemTable(
+		StatementHintsTableSchema,
+		systemTable(
+			catconstants.StatementHintsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "fingerprint", ID: 1, Type: types.String},
+				{Name: "hint", ID: 2, Type: types.String},
+				{Name: "created_at", ID: 3, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"fingerprint", "hint", "created_at"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"fingerprint", "hint"},
+				KeyColumnDirections: []catenumpb.IndexColumn_Direction{catenumpb.IndexColumn_ASC, catenumpb.IndexColumn_ASC},
+				KeyColumnIDs:        []descpb.ColumnID{1, 2},
+			}),
+	)
+
+	// IndexRecommendationsTable is described in comment on
+	// IndexRecommendationsTableSchema.
+	IndexRecommendationsTable = makeSystemTable(
+		IndexRecommendationsTableSchema,
+		systemTable(
+			catconstants.IndexRecommendationsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString},
+				{Name: "created_at", ID: 2, Type: types.TimestampTZ, DefaultExpr: &nowTZString},
+				{Name: "rank", ID: 3, Type: types.Int},
+				{Name: "action", ID: 4, Type: types.String},
+				{Name: "statements", ID: 5, Type: types.String},
+				{Name: "estimated_improvement", ID: 6, Type: types.Float},
+				{Name: "write_amplification", ID: 7, Type: types.Float},
+				{Name: "evidence", ID: 8, Type: types.Jsonb},
+				{Name: "status", ID: 9, Type: types.String},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name: "primary",
+					ID:   0,
+					ColumnNames: []string{
+						"id", "created_at", "rank", "action", "statements",
+						"estimated_improvement", "write_amplification", "evidence", "status",
+					},
+					ColumnIDs: []descpb.ColumnID{1, 2, 3, 4, 5, 6, 7, 8, 9},
+				},
+			},
+			descpb.IndexDescriptor{
+				Name:                "primary",
+				ID:                  1,
+				Unique:              true,
+				KeyColumnNames:      []string{"id"},
+				KeyColumnDirections: []catenumpb.IndexColumn_Direction{catenumpb.IndexColumn_ASC},
+				KeyColumnIDs:        []descpb.ColumnID{1},
+			}),
+	)
+
 	SystemJobInfoTable = makeSystemTable(
 		SystemJobInfoTableSchema,
 		systemTable(
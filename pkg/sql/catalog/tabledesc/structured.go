@@ -220,7 +220,9 @@ func (desc *wrapper) ForeachFamily(f func(family *descpb.ColumnFamilyDescriptor)
 	return nil
 }
 
-func generatedFamilyName(familyID descpb.FamilyID, columnNames []string) string {
+// GeneratedFamilyName returns the name given to a column family that was not
+// explicitly named by the user.
+func GeneratedFamilyName(familyID descpb.FamilyID, columnNames []string) string {
 	var buf strings.Builder
 	fmt.Fprintf(&buf, "fam_%d", familyID)
 	for _, n := range columnNames {
@@ -932,7 +934,7 @@ func (desc *Mutable) allocateColumnFamilyIDs(columnNames map[string]descpb.Colum
 	for i := range desc.Families {
 		family := &desc.Families[i]
 		if len(family.Name) == 0 {
-			family.Name = generatedFamilyName(family.ID, family.ColumnNames)
+			family.Name = GeneratedFamilyName(family.ID, family.ColumnNames)
 		}
 
 		if family.DefaultColumnID == 0 {
@@ -960,8 +962,15 @@ func (desc *Mutable) allocateColumnFamilyIDs(columnNames map[string]descpb.Colum
 // should be put in a new family.
 //
 // Current heuristics:
-//   - Put all columns in family 0.
+//   - Put each column in its own family for tables with family_per_column set.
+//   - Otherwise, put all columns in family 0.
 func fitColumnToFamily(desc *Mutable, col descpb.ColumnDescriptor) (int, bool) {
+	if desc.FamilyPerColumn {
+		// Tables with family_per_column set store each column in its own column
+		// family, i.e. in a separate KV of each row, so that scans that only
+		// need a few columns can skip the others.
+		return 0, false
+	}
 	// Fewer column families means fewer kv entries, which is generally faster.
 	// On the other hand, an update to any column in a family requires that they
 	// all are read and rewritten, so large (or numerous) columns that are not
@@ -1710,6 +1719,13 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 			// indexes with the new indexes that have been backfilled already.
 			desc.SetPrimaryIndex(t.MaterializedViewRefresh.NewPrimaryIndex)
 			desc.SetPublicNonPrimaryIndexes(t.MaterializedViewRefresh.NewIndexes)
+			// Remember the timestamp of the data in the view so that subsequent
+			// incremental refreshes only need to process the changes since then.
+			if t.MaterializedViewRefresh.ShouldBackfill {
+				desc.ViewRefreshedAsOf = t.MaterializedViewRefresh.AsOf
+			} else {
+				desc.ViewRefreshedAsOf = hlc.Timestamp{}
+			}
 		}
 
 	case descpb.DescriptorMutation_DROP:
@@ -2569,6 +2585,9 @@ func (desc *wrapper) GetStorageParams(spaceBetweenEqual bool) []string {
 	if desc.IsSchemaLocked() {
 		appendStorageParam(`schema_locked`, `true`)
 	}
+	if desc.IsFamilyPerColumn() {
+		appendStorageParam(`family_per_column`, `true`)
+	}
 	return storageParams
 }
 
@@ -666,6 +666,11 @@ func (desc *wrapper) IsSchemaLocked() bool {
 	return desc.SchemaLocked
 }
 
+// IsFamilyPerColumn implements the TableDescriptor interface.
+func (desc *wrapper) IsFamilyPerColumn() bool {
+	return desc.FamilyPerColumn
+}
+
 // IsPrimaryKeySwapMutation implements the TableDescriptor interface.
 func (desc *wrapper) IsPrimaryKeySwapMutation(m *descpb.DescriptorMutation) bool {
 	switch t := m.Descriptor_.(type) {
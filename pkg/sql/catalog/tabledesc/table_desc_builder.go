@@ -756,7 +756,7 @@ func upgradeToFamilyFormatVersion(desc *descpb.TableDescriptor) {
 		colNames := []string{col.Name}
 		family := descpb.ColumnFamilyDescriptor{
 			ID:              descpb.FamilyID(col.ID),
-			Name:            generatedFamilyName(descpb.FamilyID(col.ID), colNames),
+			Name:            GeneratedFamilyName(descpb.FamilyID(col.ID), colNames),
 			ColumnNames:     colNames,
 			ColumnIDs:       []descpb.ColumnID{col.ID},
 			DefaultColumnID: col.ID,
@@ -143,6 +143,8 @@ var validationMap = []struct {
 			"ReplicatedPCRVersion": {status: thisFieldReferencesNoObjects},
 			"Triggers":             {status: iSolemnlySwearThisFieldIsValidated},
 			"NextTriggerID":        {status: thisFieldReferencesNoObjects},
+			"FamilyPerColumn":      {status: thisFieldReferencesNoObjects},
+			"ViewRefreshedAsOf":    {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -130,6 +130,20 @@ func init() {
 				c.InheritedLeasePreferences = false
 			},
 		},
+		{
+			Field:        config.RateLimitRequestsPerSecond,
+			RequiredType: types.Int,
+			Setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.RateLimitRequestsPerSecond = proto.Int64(int64(tree.MustBeDInt(d)))
+			},
+		},
+		{
+			Field:        config.RateLimitWriteBytesPerSecond,
+			RequiredType: types.Int,
+			Setter: func(c *zonepb.ZoneConfig, d tree.Datum) {
+				c.RateLimitWriteBytesPerSecond = proto.Int64(int64(tree.MustBeDInt(d)))
+			},
+		},
 	}
 	SupportedZoneConfigOptions = make(map[tree.Name]ZoneConfigOption, len(opts))
 	ZoneOptionKeys = make([]string, len(opts))
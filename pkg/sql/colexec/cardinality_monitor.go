@@ -0,0 +1,108 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package colexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+)
+
+// cardinalityMonitor is an Operator that passes through the batches of its
+// input while counting the rows. It is planned on the build side of hash
+// joins and detects gross misestimates of the number of rows that the hash
+// table will hold. Once the number of rows exceeds the given threshold, the
+// monitor asks the ReplanCoordinator for a re-plan of the statement and, if
+// the request is granted, aborts the execution with
+// execinfra.ErrReplanRequested. If the request is denied because some rows
+// have already been sent to the client, the input is consumed as usual and the
+// observed row count is recorded once the input is exhausted, so that it can
+// still be used for later executions of the statement.
+type cardinalityMonitor struct {
+	colexecop.OneInputHelper
+	colexecop.NonExplainable
+
+	replan            *execinfra.ReplanCoordinator
+	key               string
+	estimatedRowCount uint64
+	threshold         uint64
+
+	rowCount uint64
+	// misestimated is set once rowCount exceeds threshold.
+	misestimated bool
+	// recorded is set once the misestimate has been recorded with the
+	// coordinator.
+	recorded bool
+}
+
+var _ colexecop.ResettableOperator = &cardinalityMonitor{}
+
+// NewCardinalityMonitor returns a new cardinalityMonitor. key and
+// estimatedRowCount describe the optimizer's estimate for the input, and
+// threshold is the number of rows above which the estimate is considered to
+// be grossly wrong.
+func NewCardinalityMonitor(
+	input colexecop.Operator,
+	replan *execinfra.ReplanCoordinator,
+	key string,
+	estimatedRowCount uint64,
+	threshold uint64,
+) colexecop.ResettableOperator {
+	return &cardinalityMonitor{
+		OneInputHelper:    colexecop.MakeOneInputHelper(input),
+		replan:            replan,
+		key:               key,
+		estimatedRowCount: estimatedRowCount,
+		threshold:         threshold,
+	}
+}
+
+// Next implements the colexecop.Operator interface.
+func (m *cardinalityMonitor) Next() coldata.Batch {
+	batch := m.Input.Next()
+	n := batch.Length()
+	if n == 0 {
+		if m.misestimated {
+			m.record()
+		}
+		return batch
+	}
+	m.rowCount += uint64(n)
+	if !m.misestimated && m.rowCount > m.threshold {
+		m.misestimated = true
+		if m.replan.RequestReplan() {
+			m.record()
+			colexecerror.ExpectedError(execinfra.ErrReplanRequested)
+		}
+	}
+	return batch
+}
+
+// record stores the observed row count with the coordinator.
+func (m *cardinalityMonitor) record() {
+	if m.recorded {
+		return
+	}
+	m.recorded = true
+	m.replan.RecordMisestimate(execinfra.CardinalityMisestimate{
+		Key:               m.key,
+		EstimatedRowCount: m.estimatedRowCount,
+		ActualRowCount:    m.rowCount,
+	})
+}
+
+// Reset implements the colexecop.Resetter interface.
+func (m *cardinalityMonitor) Reset(ctx context.Context) {
+	if r, ok := m.Input.(colexecop.Resetter); ok {
+		r.Reset(ctx)
+	}
+	m.rowCount = 0
+	m.misestimated = false
+	m.recorded = false
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package colexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityMonitor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	typs := []*types.T{types.Int}
+	batch := testAllocator.NewMemBatchWithMaxCapacity(typs)
+	batch.SetLength(coldata.BatchSize())
+	const numBatches = 4
+	threshold := uint64(2 * coldata.BatchSize())
+
+	for _, tc := range []struct {
+		name string
+		// dataPushed, if set, indicates that some rows have been sent to the
+		// client before the misestimate is detected.
+		dataPushed bool
+		// threshold is the number of rows above which a misestimate is
+		// detected.
+		threshold uint64
+		// expectReplan indicates whether the monitor is expected to abort
+		// the execution with a re-plan request.
+		expectReplan bool
+		// expectedRowCount is the recorded row count, or zero if no
+		// misestimate is expected to be recorded.
+		expectedRowCount uint64
+	}{
+		{
+			name:             "replan",
+			threshold:        threshold,
+			expectReplan:     true,
+			expectedRowCount: threshold + uint64(coldata.BatchSize()),
+		},
+		{
+			name:             "data pushed",
+			dataPushed:       true,
+			threshold:        threshold,
+			expectedRowCount: uint64(numBatches * coldata.BatchSize()),
+		},
+		{
+			name:      "accurate estimate",
+			threshold: uint64(numBatches * coldata.BatchSize()),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var replan execinfra.ReplanCoordinator
+			if tc.dataPushed {
+				require.True(t, replan.MarkDataPushed())
+			}
+			source := colexecop.NewRepeatableBatchSource(testAllocator, batch, typs)
+			source.ResetBatchesToReturn(numBatches)
+			m := NewCardinalityMonitor(source, &replan, "key", 10 /* estimatedRowCount */, tc.threshold)
+			m.Init(ctx)
+			var rowCount int
+			err := colexecerror.CatchVectorizedRuntimeError(func() {
+				for b := m.Next(); b.Length() > 0; b = m.Next() {
+					rowCount += b.Length()
+				}
+			})
+			if tc.expectReplan {
+				require.True(t, execinfra.IsReplanRequestedError(err))
+				require.True(t, replan.ReplanRequested())
+				// No more data may be pushed to the client.
+				require.False(t, replan.MarkDataPushed())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, numBatches*coldata.BatchSize(), rowCount)
+				require.False(t, replan.ReplanRequested())
+			}
+			misestimates := replan.Misestimates()
+			if tc.expectedRowCount == 0 {
+				require.Empty(t, misestimates)
+				return
+			}
+			require.Equal(t, []execinfra.CardinalityMisestimate{{
+				Key:               "key",
+				EstimatedRowCount: 10,
+				ActualRowCount:    tc.expectedRowCount,
+			}}, misestimates)
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/typeconv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/cardfeedback"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/colconv"
 	"github.com/cockroachdb/cockroach/pkg/sql/colexec"
@@ -199,11 +200,6 @@ func supportedNatively(core *execinfrapb.ProcessorCoreUnion) error {
 			if wf.FilterColIdx != tree.NoColumnIdx {
 				return errWindowFunctionFilterClause
 			}
-			if wf.Func.AggregateFunc != nil {
-				if !colexecagg.IsAggOptimized(*wf.Func.AggregateFunc) {
-					return errDefaultAggregateWindowFunction
-				}
-			}
 		}
 		return nil
 
@@ -239,7 +235,6 @@ var (
 	errNonInnerHashJoinWithOnExpr     = errors.New("can't plan vectorized non-inner hash joins with ON expressions")
 	errNonInnerMergeJoinWithOnExpr    = errors.New("can't plan vectorized non-inner merge joins with ON expressions")
 	errWindowFunctionFilterClause     = errors.New("window functions with FILTER clause are not supported")
-	errDefaultAggregateWindowFunction = errors.New("default aggregate window functions not supported")
 	errStreamIngestionWrap            = errors.New("core.StreamIngestion{Data,Frontier} is not supported because of #55758")
 	errFallbackToRenderWrapping       = errors.New("falling back to wrapping a row-by-row processor due to many renders and low estimated row count")
 	errUnhandledSelectionExpression   = errors.New("unhandled selection expression")
@@ -574,6 +569,10 @@ func (r opResult) createAndWrapRowSource(
 			if releasable, ok := rs.(execreleasable.Releasable); ok {
 				r.Releasables = append(r.Releasables, releasable)
 			}
+			r.WrappedProcessors = append(r.WrappedProcessors, colexecargs.WrappedProcessor{
+				RowSource: rs,
+				Cause:     causeToWrap,
+			})
 			return rs, nil
 		},
 		factory,
@@ -841,7 +840,11 @@ func NewColOperator(
 			estimatedRowCount := spec.EstimatedRowCount
 			var scanOp colfetcher.ScanOperator
 			var resultTypes []*types.T
-			if flowCtx.EvalCtx.SessionData().DirectColumnarScansEnabled {
+			// Tables with one column family per column always use the direct
+			// scans when possible since only then the KV server can skip the
+			// column families that are not needed.
+			familyPerColumn := len(core.TableReader.FetchSpec.NeededFamilyIDs) > 0
+			if flowCtx.EvalCtx.SessionData().DirectColumnarScansEnabled || familyPerColumn {
 				canUseDirectScan := func() bool {
 					// We currently don't use the direct scans if TraceKV is
 					// enabled (due to not being able to tell the KV server
@@ -1188,6 +1191,20 @@ func NewColOperator(
 				)
 				result.ToClose = append(result.ToClose, result.Root.(colexecop.Closer))
 			} else {
+				if flowCtx.Replan != nil && core.HashJoiner.RightCardinalityFeedbackKey != "" {
+					// Detect gross misestimates of the number of rows on the
+					// build side so that the statement can be re-planned
+					// instead of building (and possibly spilling) a much
+					// larger hash table than expected.
+					estimate := core.HashJoiner.RightEstimatedRowCount
+					inputs[1].Root = colexec.NewCardinalityMonitor(
+						inputs[1].Root,
+						flowCtx.Replan,
+						core.HashJoiner.RightCardinalityFeedbackKey,
+						estimate,
+						cardfeedback.MisestimateThreshold(&flowCtx.Cfg.Settings.SV, estimate),
+					)
+				}
 				opName := redact.RedactableString("hash-joiner")
 				hjArgs, hashJoinerMemMonitorName := makeNewHashJoinerArgs(
 					ctx,
@@ -1646,8 +1663,9 @@ func NewColOperator(
 							colIdx[i] = uint32(i)
 						}
 						aggregations := []execinfrapb.AggregatorSpec_Aggregation{{
-							Func:   aggType,
-							ColIdx: colIdx,
+							Func:        aggType,
+							ColIdx:      colIdx,
+							UserDefined: wf.UserDefined,
 						}}
 						aggArgs.Constructors, aggArgs.ConstArguments, aggArgs.OutputTypes, err =
 							colexecagg.ProcessAggregations(ctx, flowCtx.EvalCtx, args.SemaCtx, aggregations, argTypes)
@@ -2173,6 +2191,16 @@ func planSelectionOperators(
 				// FROM NULL.
 				negate := cmpOp.Symbol == treecmp.IsDistinctFrom
 				op = colexec.NewIsNullSelOp(leftOp, leftIdx, negate, false /* isTupleNull */)
+			case treecmp.Contains, treecmp.ContainedBy:
+				j, ok := constArg.(*tree.DJSON)
+				if !ok || lTyp.Family() != types.JsonFamily {
+					// Containment of other types (e.g. arrays) is handled by the
+					// default comparison operator.
+					break
+				}
+				op = colexecsel.GetJSONContainsOperator(
+					leftOp, leftIdx, j.JSON, cmpOp.Symbol == treecmp.ContainedBy,
+				)
 			}
 			if op == nil || err != nil {
 				// op hasn't been created yet, so let's try the constructor for
@@ -2824,6 +2852,17 @@ func planProjectionExpr(
 					op = colexec.NewIsNullProjOp(
 						allocator, input, leftIdx, resultIdx, negate, false, /* isTupleNull */
 					)
+				case treecmp.Contains, treecmp.ContainedBy:
+					j, ok := rConstArg.(*tree.DJSON)
+					if !ok || typs[leftIdx].Family() != types.JsonFamily {
+						// Containment of other types (e.g. arrays) is handled by
+						// the default comparison operator.
+						break
+					}
+					op = colexecprojconst.GetJSONContainsProjectionOperator(
+						allocator, input, leftIdx, resultIdx, j.JSON,
+						cmpProjOp.Symbol == treecmp.ContainedBy,
+					)
 				}
 			}
 			if op == nil || err != nil {
@@ -494,7 +494,10 @@ func NewAggregateFuncsAlloc(
 					len(aggFn.ColIdx), args.ConstArguments[i], args.OutputTypes[i], allocSize,
 				)
 			case WindowAggKind:
-				colexecerror.InternalError(errors.AssertionFailedf("default window aggregate not supported"))
+				funcAllocs[i] = newDefaultWindowAggAlloc(
+					ctx, args.Allocator, args.Constructors[i], args.EvalCtx, inputArgsConverter,
+					len(aggFn.ColIdx), args.ConstArguments[i], args.OutputTypes[i], allocSize,
+				)
 			default:
 				colexecerror.InternalError(errors.AssertionFailedf("unexpected agg kind"))
 			}
@@ -39,14 +39,24 @@ type default_AGGKINDAgg struct {
 	// {{end}}
 	fn  eval.AggregateFunc
 	ctx context.Context
+	// {{if eq "_AGGKIND" "Window"}}
+	// inputArgsConverter is shared with other aggregate functions, but the
+	// window aggregator doesn't manage it, so this function is responsible for
+	// converting the vectors before calling GetDatumColumn.
+	// {{else}}
 	// inputArgsConverter is managed by the aggregator, and this function can
 	// simply call GetDatumColumn.
+	// {{end}}
 	inputArgsConverter *colconv.VecToDatumConverter
 	resultConverter    func(tree.Datum) interface{}
 	scratch            struct {
 		// Note that this scratch space is shared among all aggregate function
 		// instances created by the same alloc object.
 		otherArgs []tree.Datum
+		// {{if eq "_AGGKIND" "Window"}}
+		// sel is used to convert only the tuples in the window frame.
+		sel []int
+		// {{end}}
 	}
 }
 
@@ -55,6 +65,25 @@ var _ AggregateFunc = &default_AGGKINDAgg{}
 func (a *default_AGGKINDAgg) Compute(
 	vecs []*coldata.Vec, inputIdxs []uint32, startIdx, endIdx int, sel []int,
 ) {
+	// {{if eq "_AGGKIND" "Window"}}
+	// The window aggregator never uses a selection vector, so we convert the
+	// tuples in [startIdx, endIdx) "sparsely" by constructing one ourselves.
+	// This way the converted values are at the same positions as the original
+	// ones.
+	a.scratch.sel = a.scratch.sel[:0]
+	for tupleIdx := startIdx; tupleIdx < endIdx; tupleIdx++ {
+		a.scratch.sel = append(a.scratch.sel, tupleIdx)
+	}
+	a.inputArgsConverter.ConvertVecs(vecs, endIdx-startIdx, a.scratch.sel)
+	// Unnecessary memory accounting can have significant overhead for window
+	// aggregate functions because Compute is called at least once for every
+	// row. For this reason, we do not use PerformOperation here. Note that the
+	// aggregate function itself accounts for the intermediate results of
+	// aggregation.
+	for _, tupleIdx := range a.scratch.sel {
+		_ADD_TUPLE(a, a.groups, a.nulls, tupleIdx, true)
+	}
+	// {{else}}
 	// Note that we only need to account for the memory of the output vector
 	// and not for the intermediate results of aggregation since the aggregate
 	// function itself does the latter.
@@ -84,6 +113,7 @@ func (a *default_AGGKINDAgg) Compute(
 			}
 		}
 	})
+	// {{end}}
 }
 
 func (a *default_AGGKINDAgg) Flush(outputIdx int) {
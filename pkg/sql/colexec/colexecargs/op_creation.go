@@ -114,10 +114,22 @@ type NewColOperatorResult struct {
 	ColumnTypes []*types.T
 	ToClose     colexecop.Closers
 	Releasables []execreleasable.Releasable
+	// WrappedProcessors contains all row-execution processors that were
+	// wrapped into the vectorized flow when planning the operator.
+	WrappedProcessors []WrappedProcessor
 }
 
 var _ execreleasable.Releasable = &NewColOperatorResult{}
 
+// WrappedProcessor describes a row-execution processor that was wrapped into
+// the vectorized flow because the vectorized engine doesn't natively support
+// some part of the processor spec.
+type WrappedProcessor struct {
+	execinfra.RowSource
+	// Cause is the reason for falling back to the row-execution processor.
+	Cause error
+}
+
 // TestCleanupNoError releases the resources associated with this result and
 // asserts that no error is returned. It should only be used in tests.
 func (r *NewColOperatorResult) TestCleanupNoError(t testing.TB) {
@@ -158,13 +170,17 @@ func (r *NewColOperatorResult) Release() {
 	for i := range r.Releasables {
 		r.Releasables[i] = nil
 	}
+	for i := range r.WrappedProcessors {
+		r.WrappedProcessors[i] = WrappedProcessor{}
+	}
 	*r = NewColOperatorResult{
 		OpWithMetaInfo: OpWithMetaInfo{
 			StatsCollectors: r.StatsCollectors[:0],
 			MetadataSources: r.MetadataSources[:0],
 		},
-		ToClose:     r.ToClose[:0],
-		Releasables: r.Releasables[:0],
+		ToClose:           r.ToClose[:0],
+		Releasables:       r.Releasables[:0],
+		WrappedProcessors: r.WrappedProcessors[:0],
 	}
 	newColOperatorResultPool.Put(r)
 }
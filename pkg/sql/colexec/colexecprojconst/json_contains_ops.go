@@ -0,0 +1,100 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package colexecprojconst
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexecutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// GetJSONContainsProjectionOperator returns a projection operator which
+// projects whether the JSON value in the column at colIdx contains the
+// constant (@>), or is contained by the constant (<@) if containedBy is true.
+func GetJSONContainsProjectionOperator(
+	allocator *colmem.Allocator,
+	input colexecop.Operator,
+	colIdx int,
+	resultIdx int,
+	constArg json.JSON,
+	containedBy bool,
+) colexecop.Operator {
+	input = colexecutils.NewVectorTypeEnforcer(allocator, input, types.Bool, resultIdx)
+	return &projJSONContainsConstOp{
+		projConstOpBase: projConstOpBase{
+			OneInputHelper: colexecop.MakeOneInputHelper(input),
+			allocator:      allocator,
+			colIdx:         colIdx,
+			outputIdx:      resultIdx,
+		},
+		constArg:    constArg,
+		containedBy: containedBy,
+	}
+}
+
+type projJSONContainsConstOp struct {
+	projConstOpBase
+	constArg    json.JSON
+	containedBy bool
+}
+
+var _ colexecop.Operator = &projJSONContainsConstOp{}
+
+// contains returns whether arg contains (or is contained by) the constant.
+func (p *projJSONContainsConstOp) contains(arg json.JSON) bool {
+	var res bool
+	var err error
+	if p.containedBy {
+		res, err = json.Contains(p.constArg, arg)
+	} else {
+		res, err = json.Contains(arg, p.constArg)
+	}
+	if err != nil {
+		colexecerror.ExpectedError(err)
+	}
+	return res
+}
+
+// Next implements the colexecop.Operator interface.
+func (p *projJSONContainsConstOp) Next() coldata.Batch {
+	batch := p.Input.Next()
+	n := batch.Length()
+	if n == 0 {
+		return coldata.ZeroBatch
+	}
+	vec := batch.ColVec(p.colIdx)
+	col := vec.JSON()
+	projVec := batch.ColVec(p.outputIdx)
+	p.allocator.PerformOperation([]*coldata.Vec{projVec}, func() {
+		projCol := projVec.Bool()
+		_outNulls := projVec.Nulls()
+		colNulls := vec.Nulls()
+		maybeHasNulls := colNulls.MaybeHasNulls()
+		if sel := batch.Selection(); sel != nil {
+			sel = sel[:n]
+			for _, i := range sel {
+				if !maybeHasNulls || !colNulls.NullAt(i) {
+					projCol[i] = p.contains(col.Get(i))
+				}
+			}
+		} else {
+			_ = projCol.Get(n - 1)
+			for i := 0; i < n; i++ {
+				if !maybeHasNulls || !colNulls.NullAt(i) {
+					projCol[i] = p.contains(col.Get(i))
+				}
+			}
+		}
+		if maybeHasNulls {
+			projVec.SetNulls(_outNulls.Or(*colNulls))
+		}
+	})
+	return batch
+}
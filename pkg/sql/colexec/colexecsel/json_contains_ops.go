@@ -0,0 +1,99 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package colexecsel
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// GetJSONContainsOperator returns a selection operator which selects the
+// tuples for which the JSON value in the column at colIdx contains the
+// constant (@>), or is contained by the constant (<@) if containedBy is true.
+// Unlike the default comparison operator, it doesn't need to convert the
+// values to tree.Datums.
+func GetJSONContainsOperator(
+	input colexecop.Operator, colIdx int, constArg json.JSON, containedBy bool,
+) colexecop.Operator {
+	return &selJSONContainsConstOp{
+		selConstOpBase: selConstOpBase{
+			OneInputHelper: colexecop.MakeOneInputHelper(input),
+			colIdx:         colIdx,
+		},
+		constArg:    constArg,
+		containedBy: containedBy,
+	}
+}
+
+type selJSONContainsConstOp struct {
+	selConstOpBase
+	constArg    json.JSON
+	containedBy bool
+}
+
+var _ colexecop.Operator = &selJSONContainsConstOp{}
+
+// matches returns whether arg contains (or is contained by) the constant.
+func (p *selJSONContainsConstOp) matches(arg json.JSON) bool {
+	var res bool
+	var err error
+	if p.containedBy {
+		res, err = json.Contains(p.constArg, arg)
+	} else {
+		res, err = json.Contains(arg, p.constArg)
+	}
+	if err != nil {
+		colexecerror.ExpectedError(err)
+	}
+	return res
+}
+
+// Next implements the colexecop.Operator interface.
+func (p *selJSONContainsConstOp) Next() coldata.Batch {
+	for {
+		batch := p.Input.Next()
+		n := batch.Length()
+		if n == 0 {
+			return batch
+		}
+
+		vec := batch.ColVec(p.colIdx)
+		col := vec.JSON()
+		nulls := vec.Nulls()
+		maybeHasNulls := nulls.MaybeHasNulls()
+		var idx int
+		if sel := batch.Selection(); sel != nil {
+			sel = sel[:n]
+			for _, i := range sel {
+				if maybeHasNulls && nulls.NullAt(i) {
+					continue
+				}
+				if p.matches(col.Get(i)) {
+					sel[idx] = i
+					idx++
+				}
+			}
+		} else {
+			batch.SetSelection(true)
+			sel := batch.Selection()
+			for i := 0; i < n; i++ {
+				if maybeHasNulls && nulls.NullAt(i) {
+					continue
+				}
+				if p.matches(col.Get(i)) {
+					sel[idx] = i
+					idx++
+				}
+			}
+		}
+		if idx > 0 {
+			batch.SetLength(idx)
+			return batch
+		}
+	}
+}
@@ -31,6 +31,6 @@ func genDefaultAgg(inputFileContents string, wr io.Writer) error {
 func init() {
 	registerAggGenerator(
 		genDefaultAgg, "default_agg.eg.go", /* filenameSuffix */
-		defaultAggTmpl, "defaultAgg" /* aggName */, false, /* genWindowVariant */
+		defaultAggTmpl, "defaultAgg" /* aggName */, true, /* genWindowVariant */
 	)
 }
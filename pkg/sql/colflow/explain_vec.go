@@ -7,6 +7,7 @@ package colflow
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"sort"
 
@@ -27,18 +28,24 @@ import (
 // returning a list of the leap operators or an error if the flow vectorization
 // is not supported. Note that it does so by setting up the full flow without
 // running the components asynchronously, so it is pretty expensive. It also
-// returns a non-nil cleanup function that closes all the closers as well as
-// releases all execreleasable.Releasable objects which can *only* be performed
-// once opChains are no longer needed.
+// returns the reasons for wrapping each of the row-execution processors into
+// the flow as well as a non-nil cleanup function that closes all the closers
+// and releases all execreleasable.Releasable objects which can *only* be
+// performed once opChains are no longer needed.
 func convertToVecTree(
 	ctx context.Context,
 	flowCtx *execinfra.FlowCtx,
 	flow *execinfrapb.FlowSpec,
 	localProcessors []execinfra.LocalProcessor,
 	recordingStats bool,
-) (opChains execopnode.OpChains, cleanup func(), err error) {
+) (
+	opChains execopnode.OpChains,
+	fallbackCauses map[execopnode.OpNode]error,
+	cleanup func(),
+	err error,
+) {
 	if !flowCtx.Local && len(localProcessors) > 0 {
-		return nil, func() {}, errors.AssertionFailedf("unexpectedly non-empty LocalProcessors when plan is not local")
+		return nil, nil, func() {}, errors.AssertionFailedf("unexpectedly non-empty LocalProcessors when plan is not local")
 	}
 	flowBase := flowinfra.NewFlowBase(
 		*flowCtx,
@@ -58,6 +65,8 @@ func convertToVecTree(
 		flowBase, nil /* componentCreator */, recordingStats,
 		colcontainer.DiskQueueCfg{}, flowCtx.Cfg.VecFDSemaphore,
 	)
+	fallbackCauses = make(map[execopnode.OpNode]error)
+	creator.fallbackCauses = fallbackCauses
 	fuseOpt := flowinfra.FuseNormally
 	if flowCtx.Local && !execinfra.HasParallelProcessors(flow) {
 		// TODO(yuzefovich): this check doesn't exactly match what we have on
@@ -74,7 +83,7 @@ func convertToVecTree(
 		creator.cleanup(ctx)
 		creator.Release()
 	}
-	return opChains, cleanup, err
+	return opChains, fallbackCauses, cleanup, err
 }
 
 // fakeBatchReceiver exists for the sole purpose of convertToVecTree method. In
@@ -97,11 +106,14 @@ type flowWithNode struct {
 }
 
 // ExplainVec converts the flows (that are assumed to be vectorizable) into the
-// corresponding string representation.
+// corresponding string representation. In verbose mode, each row-execution
+// processor wrapped into the flow is annotated with the reason why the
+// vectorized engine fell back to it.
 //
 // It also supports printing of already constructed operator chains which takes
 // priority if non-nil (flows are ignored). All operators in opChains are
-// assumed to be planned on the gateway.
+// assumed to be planned on the gateway. The fallback reasons are not available
+// in this case.
 func ExplainVec(
 	ctx context.Context,
 	flowCtx *execinfra.FlowCtx,
@@ -121,7 +133,7 @@ func ExplainVec(
 	// catching such errors.
 	if err = colexecerror.CatchVectorizedRuntimeError(func() {
 		if opChains != nil {
-			formatChains(root, gatewaySQLInstanceID, opChains, verbose)
+			formatChains(root, gatewaySQLInstanceID, opChains, nil /* fallbackCauses */, verbose)
 		} else {
 			sortedFlows := make([]flowWithNode, 0, len(flows))
 			for nodeID, flow := range flows {
@@ -131,8 +143,9 @@ func ExplainVec(
 			// last.
 			sort.Slice(sortedFlows, func(i, j int) bool { return sortedFlows[i].sqlInstanceID < sortedFlows[j].sqlInstanceID })
 			for _, flow := range sortedFlows {
+				var fallbackCauses map[execopnode.OpNode]error
 				var cleanup func()
-				opChains, cleanup, err = convertToVecTree(ctx, flowCtx, flow.flow, localProcessors, recordingStats)
+				opChains, fallbackCauses, cleanup, err = convertToVecTree(ctx, flowCtx, flow.flow, localProcessors, recordingStats)
 				// We need to delay the cleanup until after the tree has been
 				// formatted.
 				defer cleanup()
@@ -140,7 +153,7 @@ func ExplainVec(
 					conversionErr = err
 					return
 				}
-				formatChains(root, flow.sqlInstanceID, opChains, verbose)
+				formatChains(root, flow.sqlInstanceID, opChains, fallbackCauses, verbose)
 			}
 		}
 	}); err != nil {
@@ -156,11 +169,12 @@ func formatChains(
 	root treeprinter.Node,
 	sqlInstanceID base.SQLInstanceID,
 	opChains execopnode.OpChains,
+	fallbackCauses map[execopnode.OpNode]error,
 	verbose bool,
 ) {
 	node := root.Childf("Node %d", sqlInstanceID)
 	for _, op := range opChains {
-		formatOpChain(op, node, verbose)
+		formatOpChain(op, node, fallbackCauses, verbose)
 	}
 }
 
@@ -169,24 +183,47 @@ func shouldOutput(operator execopnode.OpNode, verbose bool) bool {
 	return !nonExplainable || verbose
 }
 
-func formatOpChain(operator execopnode.OpNode, node treeprinter.Node, verbose bool) {
+// opName returns the string representation of the operator. In verbose mode,
+// wrapped row-execution processors include the reason for the fallback.
+func opName(
+	operator execopnode.OpNode, fallbackCauses map[execopnode.OpNode]error, verbose bool,
+) string {
+	typ := reflect.TypeOf(operator)
+	name := typ.String()
+	// Note that we need to check whether the type is comparable since looking
+	// up an uncomparable key in the map results in a panic.
+	if verbose && len(fallbackCauses) > 0 && typ.Comparable() {
+		if cause, ok := fallbackCauses[operator]; ok && cause != nil {
+			name = fmt.Sprintf("%s (fallback: %v)", name, cause)
+		}
+	}
+	return name
+}
+
+func formatOpChain(
+	operator execopnode.OpNode,
+	node treeprinter.Node,
+	fallbackCauses map[execopnode.OpNode]error,
+	verbose bool,
+) {
 	seenOps := make(map[reflect.Value]struct{})
 	if shouldOutput(operator, verbose) {
-		doFormatOpChain(operator, node.Child(reflect.TypeOf(operator).String()), verbose, seenOps)
+		doFormatOpChain(operator, node.Child(opName(operator, fallbackCauses, verbose)), fallbackCauses, verbose, seenOps)
 	} else {
-		doFormatOpChain(operator, node, verbose, seenOps)
+		doFormatOpChain(operator, node, fallbackCauses, verbose, seenOps)
 	}
 }
 func doFormatOpChain(
 	operator execopnode.OpNode,
 	node treeprinter.Node,
+	fallbackCauses map[execopnode.OpNode]error,
 	verbose bool,
 	seenOps map[reflect.Value]struct{},
 ) {
 	for i := 0; i < operator.ChildCount(verbose); i++ {
 		child := operator.Child(i, verbose)
 		childOpValue := reflect.ValueOf(child)
-		childOpName := reflect.TypeOf(child).String()
+		childOpName := opName(child, fallbackCauses, verbose)
 		if _, seenOp := seenOps[childOpValue]; seenOp {
 			// We have already seen this operator, so in order to not repeat the full
 			// chain again, we will simply print out this operator's name and will
@@ -197,9 +234,9 @@ func doFormatOpChain(
 		}
 		seenOps[childOpValue] = struct{}{}
 		if shouldOutput(child, verbose) {
-			doFormatOpChain(child, node.Child(childOpName), verbose, seenOps)
+			doFormatOpChain(child, node.Child(childOpName), fallbackCauses, verbose, seenOps)
 		} else {
-			doFormatOpChain(child, node, verbose, seenOps)
+			doFormatOpChain(child, node, fallbackCauses, verbose, seenOps)
 		}
 	}
 }
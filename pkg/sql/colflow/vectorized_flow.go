@@ -595,6 +595,10 @@ type vectorizedFlowCreator struct {
 	// opChains accumulates all operators that have no further outputs on the
 	// current node, for the purposes of EXPLAIN output.
 	opChains execopnode.OpChains
+	// fallbackCauses, if non-nil, accumulates the reasons for wrapping each of
+	// the row-execution processors into the flow, for the purposes of EXPLAIN
+	// output.
+	fallbackCauses map[execopnode.OpNode]error
 	// operatorConcurrency is set if any operators are executed in parallel.
 	operatorConcurrency bool
 	recordingStats      bool
@@ -1198,6 +1202,13 @@ func (s *vectorizedFlowCreator) setupFlow(
 				return
 			}
 			s.closers = append(s.closers, result.ToClose...)
+			if s.fallbackCauses != nil {
+				for _, w := range result.WrappedProcessors {
+					if node, ok := w.RowSource.(execopnode.OpNode); ok {
+						s.fallbackCauses[node] = w.Cause
+					}
+				}
+			}
 			if flowCtx.EvalCtx.SessionData().TestingVectorizeInjectPanics {
 				result.Root = newPanicInjector(result.Root)
 			}
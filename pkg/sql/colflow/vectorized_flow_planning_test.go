@@ -49,7 +49,7 @@ func TestVectorizedPlanning(t *testing.T) {
 			"│",
 			"└ Node 1",
 			"  └ *colflow.FlowCoordinator",
-			"    └ *rowexec.joinReader",
+			"    └ *rowexec.joinReader (fallback: lookup join reader is unsupported in vectorized)",
 			"      └ *colexec.Materializer",
 			"        └ *colexec.invariantsChecker",
 			"          └ *colexecutils.CancelChecker",
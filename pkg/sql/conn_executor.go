@@ -1685,6 +1685,11 @@ type connExecutor struct {
 	// tenants.
 	cpuStatsCollector multitenantcpu.CPUUsageHelper
 
+	// replanCoordinator is reused across statements that may be re-planned
+	// when an operator detects a gross cardinality misestimate. See
+	// canReplanOnMisestimate.
+	replanCoordinator execinfra.ReplanCoordinator
+
 	// applicationName is the same as sessionData.ApplicationName. It's copied
 	// here as an atomic so that it can be read concurrently by serialize().
 	applicationName atomic.Value
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/appstatspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/cardfeedback"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
@@ -1989,9 +1990,17 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 		distribute = FullDistribution
 	}
 	ex.sessionTracing.TraceExecStart(ctx, "distributed")
+	if ex.canReplanOnMisestimate(planner, distribute) {
+		ex.replanCoordinator.Reset()
+		planner.replan = &ex.replanCoordinator
+		defer func() { planner.replan = nil }()
+	}
 	stats, err := ex.execWithDistSQLEngine(
 		ctx, planner, stmt.AST.StatementReturnType(), res, distribute, progAtomic, distSQLProhibitedErr,
 	)
+	if planner.replan != nil {
+		stats, err = ex.maybeReplanOnMisestimate(ctx, planner, res, progAtomic, stats, err)
+	}
 	if ppInfo := getPausablePortalInfo(); ppInfo != nil {
 		// For pausable portals, we log the stats when closing the portal, so we need
 		// to aggregate the stats for all executions.
@@ -2357,6 +2366,102 @@ func (ex *connExecutor) makeExecPlan(ctx context.Context, planner *planner) erro
 	return nil
 }
 
+// canReplanOnMisestimate returns whether the main query of the statement may
+// be re-planned when an operator detects a gross cardinality misestimate
+// during execution. Re-planning is only possible for local plans of read-only
+// statements that return rows and have no sub- or post-queries, since the
+// statement is executed again from scratch. For the same reason, plans with
+// volatile expressions are excluded: sequence functions and volatile routines
+// may have side effects, such as advancing a sequence or sending a notice to
+// the client, which would happen again during the second execution.
+func (ex *connExecutor) canReplanOnMisestimate(
+	planner *planner, distribute DistributionType,
+) bool {
+	if !cardfeedback.Enabled.Get(ex.server.cfg.SV()) {
+		return false
+	}
+	if ex.executorType == executorTypeInternal || planner.pausablePortal != nil {
+		return false
+	}
+	if distribute != LocalDistribution || planner.stmt.AST.StatementReturnType() != tree.Rows {
+		return false
+	}
+	if ih := &planner.instrumentation; ih.collectBundle || ih.outputMode != unmodifiedOutput {
+		return false
+	}
+	plan := &planner.curPlan
+	if plan.flags.IsSet(planFlagContainsMutation) || plan.flags.IsSet(planFlagIsDDL) ||
+		plan.flags.IsSet(planFlagContainsVolatile) {
+		return false
+	}
+	return len(plan.subqueryPlans) == 0 && len(plan.cascades) == 0 &&
+		len(plan.checkPlans) == 0 && len(plan.triggers) == 0
+}
+
+// maybeReplanOnMisestimate records the cardinality misestimates observed while
+// executing the main query so that later executions of the statement
+// fingerprint are planned with the observed row counts. If an operator
+// requested that the statement be re-planned, which is only granted before any
+// rows are sent to the client, the statement is planned again with the
+// observed row counts and the new plan is executed. The returned stats include
+// both executions.
+//
+// Note that it is the whole statement that is re-planned and executed again
+// from scratch, not only the part of the plan that remained to be executed
+// when the misestimate was detected: all the work done by the first execution
+// is discarded.
+func (ex *connExecutor) maybeReplanOnMisestimate(
+	ctx context.Context,
+	planner *planner,
+	res RestrictedCommandResult,
+	progressAtomic *uint64,
+	stats topLevelQueryStats,
+	err error,
+) (topLevelQueryStats, error) {
+	replan := planner.replan
+	fingerprint := planner.planningFingerprint()
+	recordMisestimates := func() {
+		for _, m := range replan.Misestimates() {
+			ex.server.cfg.CardinalityFeedbackCache.Record(fingerprint, m.Key, float64(m.ActualRowCount))
+		}
+	}
+	recordMisestimates()
+	if err != nil || !replan.ReplanRequested() || !execinfra.IsReplanRequestedError(res.Err()) {
+		return stats, err
+	}
+
+	log.VEventf(ctx, 1, "re-planning statement due to cardinality misestimate")
+	telemetry.Inc(sqltelemetry.AdaptiveReplanCounter)
+	// No rows have been sent to the client, so the result can be reused. The
+	// statement is only re-planned once; during the second execution the
+	// operators still record the row counts they observe.
+	res.SetError(nil)
+	replan.Reset()
+	replan.DisallowReplan()
+	planner.curPlan.close(ctx)
+	if planErr := ex.makeExecPlan(ctx, planner); planErr != nil {
+		res.SetError(planErr)
+		return stats, nil
+	}
+	distributePlan, distSQLProhibitedErr := getPlanDistribution(
+		ctx, planner.Descriptors().HasUncommittedTypes(),
+		ex.sessionData().DistSQLMode, planner.curPlan.main, &planner.distSQLVisitor,
+	)
+	distribute := DistributionType(LocalDistribution)
+	if distributePlan.WillDistribute() {
+		distribute = FullDistribution
+		planner.curPlan.flags.Set(planFlagFullyDistributed)
+	} else {
+		planner.curPlan.flags.Set(planFlagNotDistributed)
+	}
+	rerunStats, err := ex.execWithDistSQLEngine(
+		ctx, planner, planner.stmt.AST.StatementReturnType(), res, distribute, progressAtomic, distSQLProhibitedErr,
+	)
+	stats.add(&rerunStats)
+	recordMisestimates()
+	return stats, err
+}
+
 // topLevelQueryStats returns some basic statistics about the run of the query.
 type topLevelQueryStats struct {
 	// bytesRead is the number of bytes read from disk.
@@ -2421,6 +2526,10 @@ func (ex *connExecutor) execWithDistSQLEngine(
 		planCtx := ex.server.cfg.DistSQLPlanner.NewPlanningCtx(ctx, evalCtx, planner, planner.txn, distribute)
 		planCtx.setUpForMainQuery(ctx, planner, recv)
 		planCtx.distSQLProhibitedErr = distSQLProhibitedErr
+		if planner.replan != nil && planCtx.isLocal {
+			planCtx.replan = planner.replan
+			recv.replan = planner.replan
+		}
 
 		var evalCtxFactory func(usedConcurrently bool) *extendedEvalContext
 		if len(planner.curPlan.subqueryPlans) != 0 ||
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvflowcontrol/kvflowinspectpb"
@@ -231,6 +232,8 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalFullyQualifiedNamesViewID:          crdbInternalFullyQualifiedNamesView,
 		catconstants.CrdbInternalStoreLivenessSupportFrom:           crdbInternalStoreLivenessSupportFromTable,
 		catconstants.CrdbInternalStoreLivenessSupportFor:            crdbInternalStoreLivenessSupportForTable,
+		catconstants.CrdbInternalRangeMVCCGarbageTableID:            crdbInternalRangeMVCCGarbageTable,
+		catconstants.CrdbInternalIndexDropRecommendationsTableID:    crdbInternalIndexDropRecommendationsTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -6915,6 +6918,115 @@ CREATE TABLE crdb_internal.index_usage_statistics (
 	},
 }
 
+// crdbInternalIndexDropRecommendationsTable combines the index usage
+// statistics, the index definitions and the persisted statement statistics
+// to recommend dropping secondary indexes that are redundant, unused or
+// rarely used. Indexes can be dropped safely with
+// crdb_internal.schedule_index_drop.
+var crdbInternalIndexDropRecommendationsTable = virtualSchemaTable{
+	comment: `recommendations to drop redundant, unused and rarely used indexes. ` +
+		`Querying this table is an expensive operation since it creates a ` +
+		`cluster-wide RPC fanout.`,
+	schema: `
+CREATE TABLE crdb_internal.index_drop_recommendations (
+  database_name  STRING NOT NULL,
+  schema_name    STRING NOT NULL,
+  table_id       INT NOT NULL,
+  table_name     STRING NOT NULL,
+  index_id       INT NOT NULL,
+  index_name     STRING NOT NULL,
+  reason         STRING NOT NULL,
+  is_visible     BOOL NOT NULL,
+  total_reads    INT NOT NULL,
+  last_read      TIMESTAMPTZ,
+  covering_index STRING,
+  fingerprint    STRING,
+  executions     INT,
+  details        STRING NOT NULL
+);`,
+	generator: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, stopper *stop.Stopper) (virtualTableGenerator, cleanupFunc, error) {
+		// Perform RPC Fanout.
+		stats, err :=
+			p.extendedEvalCtx.SQLStatusServer.IndexUsageStatistics(ctx, &serverpb.IndexUsageStatisticsRequest{})
+		if err != nil {
+			return nil, nil, err
+		}
+		indexStats := idxusage.NewLocalIndexUsageStatsFromExistingStats(&idxusage.Config{}, stats.Statistics)
+
+		// Rarely used indexes are found with the persisted statement
+		// statistics, which are only shown to users that can view them.
+		var stmtUsage map[string]indexStatementUsage
+		if hasViewActivity, _, err := p.HasViewActivityOrViewActivityRedactedRole(ctx); err != nil {
+			return nil, nil, err
+		} else if hasViewActivity {
+			since := p.ExecCfg().Clock.PhysicalTime().Add(
+				-idxusage.DropUnusedIndexDuration.Get(&p.ExecCfg().Settings.SV))
+			if stmtUsage, err = p.loadIndexStatementUsage(ctx, since); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		const numDatums = 14
+		row := make(tree.Datums, numDatums)
+		worker := func(ctx context.Context, pusher rowPusher) error {
+			opts := forEachTableDescOptions{virtualOpts: hideVirtual}
+			return forEachTableDesc(ctx, p, dbContext, opts,
+				func(ctx context.Context, descCtx tableDescContext) error {
+					table := descCtx.table
+					dbName := descCtx.database.GetName()
+					if !table.IsTable() || dbName == catconstants.SystemDatabaseName {
+						return nil
+					}
+					for _, rec := range p.indexDropRecommendationsForTable(dbName, table, indexStats, stmtUsage) {
+						stats := indexStats.Get(roachpb.TableID(table.GetID()), roachpb.IndexID(rec.index.GetID()))
+						lastRead := tree.DNull
+						if !stats.LastRead.IsZero() {
+							lastRead, err = tree.MakeDTimestampTZ(stats.LastRead, time.Nanosecond)
+							if err != nil {
+								return err
+							}
+						}
+						coveringIndex := tree.DNull
+						if rec.coveringIndex != nil {
+							coveringIndex = tree.NewDString(rec.coveringIndex.GetName())
+						}
+						fingerprint, executions := tree.DNull, tree.DNull
+						if rec.reason == indexDropReasonRarelyUsed {
+							fingerprint = tree.NewDString(rec.usage.query)
+							executions = tree.NewDInt(tree.DInt(rec.usage.executions))
+						}
+						row = append(row[:0],
+							tree.NewDString(dbName),                               // database_name
+							tree.NewDString(descCtx.schema.GetName()),             // schema_name
+							tree.NewDInt(tree.DInt(table.GetID())),                // table_id
+							tree.NewDString(table.GetName()),                      // table_name
+							tree.NewDInt(tree.DInt(rec.index.GetID())),            // index_id
+							tree.NewDString(rec.index.GetName()),                  // index_name
+							tree.NewDString(rec.reason),                           // reason
+							tree.MakeDBool(tree.DBool(!rec.index.IsNotVisible())), // is_visible
+							tree.NewDInt(tree.DInt(stats.TotalReadCount)),         // total_reads
+							lastRead,                     // last_read
+							coveringIndex,                // covering_index
+							fingerprint,                  // fingerprint
+							executions,                   // executions
+							tree.NewDString(rec.details), // details
+						)
+						if buildutil.CrdbTestBuild {
+							if len(row) != numDatums {
+								return errors.AssertionFailedf("expected %d datums, got %d", numDatums, len(row))
+							}
+						}
+						if err := pusher.pushRow(row...); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+		}
+		return setupGenerator(ctx, worker, stopper)
+	},
+}
+
 // crdb_internal.cluster_statement_statistics contains cluster-wide statement statistics
 // that have not yet been flushed to disk.
 var crdbInternalClusterStmtStatsTable = virtualSchemaTable{
@@ -9665,3 +9777,141 @@ func populateStoreLivenessSupportResponse(
 	}
 	return nil
 }
+
+// rangeMVCCGarbageBatchSize is the number of ranges whose stats are fetched
+// with a single batch by crdb_internal.kv_range_mvcc_garbage.
+const rangeMVCCGarbageBatchSize = 128
+
+// makeRangeMVCCGarbageRows returns the crdb_internal.kv_range_mvcc_garbage
+// rows for the given ranges.
+func makeRangeMVCCGarbageRows(
+	ctx context.Context, execCfg *ExecutorConfig, rangeDescs []roachpb.RangeDescriptor,
+) ([]tree.Datums, error) {
+	// The lock table of each range is scanned to find its oldest intent, since
+	// the MVCC stats only track the total age of all of its locks. The scan
+	// doesn't acquire latches, which requires INCONSISTENT reads.
+	var statsBatch kv.Batch
+	statsBatch.Header.ReadConsistency = kvpb.INCONSISTENT
+	reqs := make([]kvpb.RangeStatsRequest, len(rangeDescs))
+	for i := range rangeDescs {
+		reqs[i].Key = rangeDescs[i].StartKey.AsRawKey()
+		reqs[i].IncludeOldestIntent = true
+		statsBatch.AddRawRequest(&reqs[i])
+	}
+	if err := execCfg.DB.Run(ctx, &statsBatch); err != nil {
+		return nil, err
+	}
+
+	// The time at which the MVCC GC queue last processed each range is stored
+	// in the range-local keyspace, which is only accessible to the system
+	// tenant.
+	lastGC := make([]tree.Datum, len(rangeDescs))
+	for i := range lastGC {
+		lastGC[i] = tree.DNull
+	}
+	if execCfg.Codec.ForSystemTenant() {
+		gcBatch := execCfg.DB.NewBatch()
+		for i := range rangeDescs {
+			gcBatch.Get(keys.QueueLastProcessedKey(rangeDescs[i].StartKey, "mvccGC"))
+		}
+		if err := execCfg.DB.Run(ctx, gcBatch); err != nil {
+			return nil, err
+		}
+		for i, res := range gcBatch.Results {
+			if len(res.Rows) == 0 || !res.Rows[0].Exists() {
+				continue
+			}
+			var ts hlc.Timestamp
+			if err := res.Rows[0].ValueProto(&ts); err != nil {
+				return nil, err
+			}
+			if ts.IsEmpty() {
+				continue
+			}
+			d, err := tree.MakeDTimestampTZ(ts.GoTime(), time.Microsecond)
+			if err != nil {
+				return nil, err
+			}
+			lastGC[i] = d
+		}
+	}
+
+	now := execCfg.Clock.PhysicalNow()
+	rows := make([]tree.Datums, len(rangeDescs))
+	for i, r := range statsBatch.RawResponse().Responses {
+		rangeDesc := &rangeDescs[i]
+		resp := r.GetInner().(*kvpb.RangeStatsResponse)
+		ms := resp.MVCCStats
+		oldestIntentAge := tree.DNull
+		if ts := resp.OldestIntentTimestamp; !ts.IsEmpty() {
+			age := time.Duration(now - ts.WallTime)
+			oldestIntentAge = tree.NewDInterval(
+				duration.MakeDuration(age.Nanoseconds(), 0, 0), types.DefaultIntervalTypeMetadata,
+			)
+		}
+		rows[i] = tree.Datums{
+			tree.NewDInt(tree.DInt(rangeDesc.RangeID)),
+			tree.NewDString(keys.PrettyPrint(nil /* valDirs */, rangeDesc.StartKey.AsRawKey())),
+			tree.NewDString(keys.PrettyPrint(nil /* valDirs */, rangeDesc.EndKey.AsRawKey())),
+			tree.NewDInt(tree.DInt(ms.LiveBytes)),
+			tree.NewDInt(tree.DInt(ms.GCBytes())),
+			tree.NewDInt(tree.DInt(ms.GCByteAge(now))),
+			tree.NewDInt(tree.DInt(ms.LockCount)),
+			oldestIntentAge,
+			lastGC[i],
+		}
+	}
+	return rows, nil
+}
+
+// crdbInternalRangeMVCCGarbageTable exposes, for every range, the MVCC garbage
+// that the MVCC GC queue would reclaim, along with when the queue last
+// processed the range. Ranges can be run through the GC queue on demand with
+// ALTER RANGE ... GC and ALTER TABLE ... GC NOW.
+var crdbInternalRangeMVCCGarbageTable = virtualSchemaTable{
+	comment: `per-range MVCC garbage estimates from range stats (KV join; expensive!)`,
+	schema: `
+CREATE TABLE crdb_internal.kv_range_mvcc_garbage (
+  range_id          INT NOT NULL,
+  start_pretty      STRING NOT NULL,
+  end_pretty        STRING NOT NULL,
+  live_bytes        INT NOT NULL,
+  garbage_bytes     INT NOT NULL,
+  garbage_bytes_age INT NOT NULL,
+  lock_count        INT NOT NULL,
+  oldest_intent_age INTERVAL,
+  last_gc           TIMESTAMPTZ
+)`,
+	generator: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, _ *stop.Stopper) (virtualTableGenerator, cleanupFunc, error) {
+		if err := p.CheckPrivilege(ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.VIEWCLUSTERMETADATA); err != nil {
+			return nil, nil, err
+		}
+		execCfg := p.ExecCfg()
+		rangeDescIterator, err := execCfg.RangeDescIteratorFactory.NewIterator(ctx, execCfg.Codec.TenantSpan())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// The ranges are processed in batches, so that the requests for the
+		// ranges of a batch are sent in parallel by the DistSender.
+		var rows []tree.Datums
+		return func() (tree.Datums, error) {
+			if len(rows) == 0 {
+				var rangeDescs []roachpb.RangeDescriptor
+				for len(rangeDescs) < rangeMVCCGarbageBatchSize && rangeDescIterator.Valid() {
+					rangeDescs = append(rangeDescs, rangeDescIterator.CurRangeDescriptor())
+					rangeDescIterator.Next()
+				}
+				if len(rangeDescs) == 0 {
+					return nil, nil
+				}
+				if rows, err = makeRangeMVCCGarbageRows(ctx, execCfg, rangeDescs); err != nil {
+					return nil, err
+				}
+			}
+			row := rows[0]
+			rows = rows[1:]
+			return row, nil
+		}, nil, nil
+	},
+}
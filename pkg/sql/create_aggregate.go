@@ -0,0 +1,349 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type createAggregateNode struct {
+	n *tree.CreateAggregate
+}
+
+// CreateAggregate creates a user-defined aggregate function. The aggregate is
+// stored as a function descriptor without a body of its own that references
+// its state transition and final functions.
+func (p *planner) CreateAggregate(ctx context.Context, n *tree.CreateAggregate) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE AGGREGATE",
+	); err != nil {
+		return nil, err
+	}
+	if !p.IsActive(ctx, clusterversion.V25_1) {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"user-defined aggregates are not supported until upgrade to v25.1 is finalized")
+	}
+	return &createAggregateNode{n: n}, nil
+}
+
+func (n *createAggregateNode) ReadingOwnWrites() {}
+
+func (n *createAggregateNode) startExec(params runParams) error {
+	p := params.p
+	ctx := params.ctx
+
+	un := n.n.Name.ToUnresolvedObjectName()
+	dbDesc, scDesc, prefix, err := p.ResolveTargetObject(ctx, un)
+	if err != nil {
+		return err
+	}
+	n.n.Name.ObjectNamePrefix = prefix
+	if scDesc.SchemaKind() == catalog.SchemaTemporary {
+		return unimplemented.NewWithIssue(104687, "cannot create UDFs under a temporary schema")
+	}
+	if err := p.canCreateOnSchema(
+		ctx, scDesc.GetID(), dbDesc.GetID(), p.User(), skipCheckPublicSchema,
+	); err != nil {
+		return err
+	}
+
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("aggregate"))
+
+	// Resolve the argument types of the aggregate.
+	pbParams := make([]descpb.FunctionDescriptor_Parameter, len(n.n.Params))
+	argTypes := make([]*types.T, len(n.n.Params))
+	typeDeps := make(typeDependencies)
+	addTypeDeps := func(typ *types.T) {
+		typedesc.GetTypeDescriptorClosure(typ).ForEach(func(id descpb.ID) {
+			typeDeps[id] = struct{}{}
+		})
+	}
+	if n.n.Options.CombineFunc != nil {
+		// User-defined aggregates are always evaluated on the gateway node, so a
+		// combine function would never be used.
+		return unimplemented.New("create aggregate combinefunc",
+			"COMBINEFUNC is not supported for user-defined aggregates")
+	}
+	for i, param := range n.n.Params {
+		switch param.Class {
+		case tree.RoutineParamDefault, tree.RoutineParamIn:
+		case tree.RoutineParamVariadic:
+			return unimplemented.NewWithIssue(88947, "variadic user-defined aggregates are not yet supported")
+		default:
+			return pgerror.New(pgcode.InvalidFunctionDefinition, "aggregates cannot have output arguments")
+		}
+		if param.DefaultVal != nil {
+			return pgerror.New(pgcode.InvalidFunctionDefinition, "aggregates cannot have default arguments")
+		}
+		pbParams[i], err = makeFunctionParam(ctx, p.SemaCtx(), param, p)
+		if err != nil {
+			return err
+		}
+		argTypes[i] = pbParams[i].Type
+		addTypeDeps(argTypes[i])
+	}
+
+	stateType, err := tree.ResolveType(ctx, n.n.Options.StateType, p)
+	if err != nil {
+		return err
+	}
+	switch stateType.Family() {
+	case types.AnyFamily, types.VoidFamily, types.TriggerFamily, types.UnknownFamily:
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"aggregate transition data type cannot be %s", stateType.SQLStandardName())
+	}
+	addTypeDeps(stateType)
+
+	// Resolve the support functions and check their signatures.
+	functionDeps := make(functionDependencies)
+	vol := catpb.Function_IMMUTABLE
+	resolveSupportFunc := func(
+		name *tree.RoutineName, paramTypes []*types.T, retType *types.T,
+	) (catalog.FunctionDescriptor, error) {
+		routineObj := tree.RoutineObj{
+			FuncName: *name,
+			Params:   make(tree.RoutineParams, len(paramTypes)),
+		}
+		for i := range paramTypes {
+			routineObj.Params[i] = tree.RoutineParam{Type: paramTypes[i], Class: tree.RoutineParamIn}
+		}
+		path := p.CurrentSearchPath()
+		fnDef, err := p.ResolveFunction(
+			ctx, tree.MakeUnresolvedFunctionName(name.ToUnresolvedObjectName().ToUnresolvedName()), &path,
+		)
+		if err != nil {
+			return nil, err
+		}
+		ol, err := fnDef.MatchOverload(
+			ctx, p, &routineObj, &path, tree.UDFRoutine|tree.BuiltinRoutine,
+			false /* inDropContext */, false, /* tryDefaultExprs */
+		)
+		if err != nil {
+			return nil, err
+		}
+		if ol.Type == tree.BuiltinRoutine {
+			return nil, unimplemented.NewWithIssuef(74775,
+				"builtin function %s cannot be used in an aggregate", tree.AsString(&routineObj))
+		}
+		fnID := funcdesc.UserDefinedFunctionOIDToID(ol.Oid)
+		fnDesc, err := p.Descriptors().ByIDWithLeased(p.Txn()).Get().Function(ctx, fnID)
+		if err != nil {
+			return nil, err
+		}
+		if dbID := fnDesc.GetParentID(); dbID != dbDesc.GetID() {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"dependent function %s cannot be from another database", fnDesc.GetName())
+		}
+		if fnDesc.IsAggregate() {
+			return nil, pgerror.Newf(pgcode.WrongObjectType,
+				"%s is an aggregate function", tree.AsString(&routineObj))
+		}
+		if fnDesc.GetReturnType().ReturnSet {
+			return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"function %s returns a set", tree.AsString(&routineObj))
+		}
+		if retType != nil && !fnDesc.GetReturnType().Type.Equivalent(retType) {
+			return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+				"return type of %s is not %s", tree.AsString(&routineObj), retType.SQLStandardName())
+		}
+		switch fnDesc.GetVolatility() {
+		case catpb.Function_VOLATILE:
+			vol = catpb.Function_VOLATILE
+		case catpb.Function_STABLE:
+			if vol == catpb.Function_IMMUTABLE {
+				vol = catpb.Function_STABLE
+			}
+		}
+		functionDeps[fnID] = struct{}{}
+		return fnDesc, nil
+	}
+
+	agg := &descpb.FunctionDescriptor_Aggregate{StateType: stateType}
+	sfuncDesc, err := resolveSupportFunc(
+		&n.n.Options.StateFunc, append([]*types.T{stateType}, argTypes...), stateType,
+	)
+	if err != nil {
+		return err
+	}
+	agg.StateFuncID = sfuncDesc.GetID()
+	returnType := stateType
+	if n.n.Options.FinalFunc != nil {
+		ffuncDesc, err := resolveSupportFunc(n.n.Options.FinalFunc, []*types.T{stateType}, nil /* retType */)
+		if err != nil {
+			return err
+		}
+		agg.FinalFuncID = ffuncDesc.GetID()
+		returnType = ffuncDesc.GetReturnType().Type
+	}
+	addTypeDeps(returnType)
+
+	if initCond := n.n.Options.InitCond; initCond != nil {
+		if _, _, err := tree.ParseAndRequireString(stateType, *initCond, p.EvalContext()); err != nil {
+			return err
+		}
+		agg.InitCond = initCond
+	} else if sfuncDesc.GetNullInputBehavior() != catpb.Function_CALLED_ON_NULL_INPUT {
+		// The first non-NULL input value is used as the initial state, so it
+		// must be of the state type.
+		if len(argTypes) == 0 || !argTypes[0].Equivalent(stateType) {
+			return pgerror.New(pgcode.InvalidFunctionDefinition,
+				"must not omit initial value when transition function is strict and "+
+					"transition type is not compatible with input type")
+		}
+	}
+
+	mutScDesc, err := p.Descriptors().MutableByID(p.Txn()).Schema(ctx, scDesc.GetID())
+	if err != nil {
+		return err
+	}
+
+	// Look for an existing routine with the same signature.
+	existing, err := p.matchRoutine(
+		ctx, &tree.RoutineObj{FuncName: n.n.Name, Params: n.n.Params}, false, /* required */
+		tree.UDFRoutine|tree.ProcedureRoutine, false, /* inDropContext */
+	)
+	if err != nil {
+		return err
+	}
+
+	var aggDesc *funcdesc.Mutable
+	if existing != nil {
+		if !n.n.Replace {
+			return pgerror.Newf(
+				pgcode.DuplicateFunction,
+				"function %q already exists with same argument types",
+				n.n.Name.Object(),
+			)
+		}
+		aggDesc, err = p.checkPrivilegesForDropFunction(ctx, funcdesc.UserDefinedFunctionOIDToID(existing.Oid))
+		if err != nil {
+			return err
+		}
+		if !aggDesc.IsAggregate() {
+			formatStr := "%q is a function"
+			if aggDesc.IsProcedure() {
+				formatStr = "%q is a procedure"
+			}
+			return errors.WithDetailf(
+				pgerror.Newf(pgcode.WrongObjectType, "cannot change routine kind"),
+				formatStr,
+				aggDesc.Name,
+			)
+		}
+		if !aggDesc.ReturnType.Type.Equivalent(returnType) {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition, "cannot change return type of existing function")
+		}
+		// Remove the existing references before adding the new ones.
+		for _, id := range aggDesc.DependsOnFunctions {
+			backRefDesc, err := p.Descriptors().MutableByID(p.Txn()).Function(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := backRefDesc.RemoveFunctionReference(aggDesc.ID); err != nil {
+				return err
+			}
+			if err := p.writeFuncSchemaChange(ctx, backRefDesc); err != nil {
+				return err
+			}
+		}
+		if err := p.removeTypeBackReferences(
+			ctx, aggDesc.DependsOnTypes, aggDesc.ID, "updating type back references for aggregate",
+		); err != nil {
+			return err
+		}
+	} else {
+		aggID, err := params.EvalContext().DescIDGenerator.GenerateUniqueDescID(ctx)
+		if err != nil {
+			return err
+		}
+		privileges, err := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+			dbDesc.GetDefaultPrivilegeDescriptor(),
+			scDesc.GetDefaultPrivilegeDescriptor(),
+			dbDesc.GetID(),
+			params.SessionData().User(),
+			privilege.Routines,
+		)
+		if err != nil {
+			return err
+		}
+		newDesc := funcdesc.NewMutableFunctionDescriptor(
+			aggID,
+			dbDesc.GetID(),
+			scDesc.GetID(),
+			string(n.n.Name.ObjectName),
+			pbParams,
+			returnType,
+			false, /* returnSet */
+			false, /* isProcedure */
+			privileges,
+		)
+		aggDesc = &newDesc
+	}
+
+	aggDesc.Aggregate = agg
+	aggDesc.SetVolatility(vol)
+	aggDesc.SetLeakProof(false)
+	// The aggregate itself is always called, even on NULL inputs. Strictness
+	// of the state transition function is handled during evaluation.
+	aggDesc.SetNullInputBehavior(catpb.Function_CALLED_ON_NULL_INPUT)
+
+	// Record the references to the support functions and types.
+	refs := createFunctionNode{
+		cf:           &tree.CreateRoutine{Name: n.n.Name},
+		dbDesc:       dbDesc,
+		scDesc:       scDesc,
+		typeDeps:     typeDeps,
+		functionDeps: functionDeps,
+	}
+	if err := refs.addUDFReferences(aggDesc, params); err != nil {
+		return err
+	}
+
+	if existing == nil {
+		if err := p.createDescriptor(
+			ctx, aggDesc, tree.AsStringWithFQNames(&n.n.Name, params.Ann()),
+		); err != nil {
+			return err
+		}
+		mutScDesc.AddFunction(aggDesc.GetName(), toSchemaOverloadSignature(aggDesc))
+		if err := p.writeSchemaDescChange(ctx, mutScDesc, "Create Aggregate"); err != nil {
+			return err
+		}
+	} else if err := p.writeFuncSchemaChange(ctx, aggDesc); err != nil {
+		return err
+	}
+
+	fnName := tree.MakeQualifiedRoutineName(dbDesc.GetName(), scDesc.GetName(), aggDesc.GetName())
+	event := eventpb.CreateFunction{
+		FunctionName: fnName.FQString(),
+		IsReplace:    existing != nil,
+	}
+	return p.logEvent(ctx, aggDesc.GetID(), &event)
+}
+
+func (*createAggregateNode) Next(params runParams) (bool, error) { return false, nil }
+func (*createAggregateNode) Values() tree.Datums                 { return tree.Datums{} }
+func (*createAggregateNode) Close(ctx context.Context)           {}
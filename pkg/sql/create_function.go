@@ -8,6 +8,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -20,10 +21,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
@@ -155,12 +158,16 @@ func (n *createFunctionNode) createNewFunction(
 	var outParamOrdinals []int32
 	var outParamTypes []*types.T
 	var defaultExprs []string
+	var isVariadic bool
 	for paramIdx, param := range udfDesc.Params {
 		class := funcdesc.ToTreeRoutineParamClass(param.Class)
 		if tree.IsInParamClass(class) {
 			signatureTypes = append(signatureTypes, param.Type)
 		}
-		if class == tree.RoutineParamOut {
+		if class == tree.RoutineParamVariadic {
+			isVariadic = true
+		}
+		if tree.IsOutOnlyParamClass(class) {
 			outParamOrdinals = append(outParamOrdinals, int32(paramIdx))
 			outParamTypes = append(outParamTypes, param.Type)
 		}
@@ -179,6 +186,7 @@ func (n *createFunctionNode) createNewFunction(
 			OutParamOrdinals: outParamOrdinals,
 			OutParamTypes:    outParamTypes,
 			DefaultExprs:     defaultExprs,
+			IsVariadic:       isVariadic,
 		},
 	)
 	if err := params.p.writeSchemaDescChange(params.ctx, scDesc, "Create Function"); err != nil {
@@ -195,10 +203,12 @@ func (n *createFunctionNode) replaceFunction(
 	existing *tree.QualifiedOverload,
 ) error {
 
-	if n.cf.IsProcedure != udfDesc.IsProcedure() {
+	if n.cf.IsProcedure != udfDesc.IsProcedure() || udfDesc.IsAggregate() {
 		formatStr := "%q is a function"
 		if udfDesc.IsProcedure() {
 			formatStr = "%q is a procedure"
+		} else if udfDesc.IsAggregate() {
+			formatStr = "%q is an aggregate function"
 		}
 		return errors.WithDetailf(
 			pgerror.Newf(pgcode.WrongObjectType, "cannot change routine kind"),
@@ -251,12 +261,16 @@ func (n *createFunctionNode) replaceFunction(
 	var outParamOrdinals []int32
 	var outParamTypes []*types.T
 	var defaultExprs []string
+	var isVariadic bool
 	for i, p := range n.cf.Params {
 		udfDesc.Params[i], err = makeFunctionParam(params.ctx, params.p.SemaCtx(), p, params.p)
 		if err != nil {
 			return err
 		}
-		if p.Class == tree.RoutineParamOut {
+		if p.Class == tree.RoutineParamVariadic {
+			isVariadic = true
+		}
+		if tree.IsOutOnlyParamClass(p.Class) {
 			outParamOrdinals = append(outParamOrdinals, int32(i))
 			outParamTypes = append(outParamTypes, udfDesc.Params[i].Type)
 		}
@@ -315,7 +329,7 @@ func (n *createFunctionNode) replaceFunction(
 	}
 
 	signatureChanged := len(existing.OutParamOrdinals) != len(outParamOrdinals) ||
-		len(existing.DefaultExprs) != len(defaultExprs)
+		len(existing.DefaultExprs) != len(defaultExprs) || existing.Variadic != isVariadic
 	for i := 0; !signatureChanged && i < len(outParamOrdinals); i++ {
 		signatureChanged = existing.OutParamOrdinals[i] != outParamOrdinals[i] ||
 			!existing.OutParamTypes.GetAt(i).Equivalent(outParamTypes[i])
@@ -343,6 +357,7 @@ func (n *createFunctionNode) replaceFunction(
 				OutParamOrdinals: outParamOrdinals,
 				OutParamTypes:    outParamTypes,
 				DefaultExprs:     defaultExprs,
+				IsVariadic:       isVariadic,
 			},
 		); err != nil {
 			return err
@@ -585,6 +600,24 @@ func setFuncOptions(
 				return err
 			}
 			udfDesc.SetSecurity(sec)
+		case tree.RoutineCost:
+			udfDesc.SetCost(float64(t))
+		case tree.RoutineRows:
+			if !udfDesc.ReturnType.ReturnSet {
+				return pgerror.New(pgcode.InvalidParameterValue,
+					"ROWS is not applicable when function does not return a set")
+			}
+			udfDesc.SetRows(float64(t))
+		case tree.RoutineParallel:
+			parallel, err := funcinfo.ParallelToProto(t)
+			if err != nil {
+				return err
+			}
+			udfDesc.SetParallel(parallel)
+		case *tree.RoutineSetVar:
+			if err := setFuncSessionVar(params, udfDesc, t); err != nil {
+				return err
+			}
 		default:
 			return pgerror.Newf(pgcode.InvalidParameterValue, "Unknown function option %q", t)
 		}
@@ -614,11 +647,123 @@ func setFuncOptions(
 	return nil
 }
 
+// routinePlanningSessionVars are the session variables which cannot be set by
+// the SET clause of a routine. The body of a routine is resolved when the
+// routine is created and planned with the session data of the calling
+// statement, while the SET clause is only applied as the routine executes, so
+// these variables would be silently ignored. In addition to the variables which
+// affect planning (see statementHintSessionVars), this includes the variables
+// used for name resolution.
+var routinePlanningSessionVars = map[string]struct{}{
+	"database":    {},
+	"optimizer":   {},
+	"search_path": {},
+}
+
+// isRoutinePlanningSessionVar returns true if the given session variable
+// affects how the body of a routine is resolved or planned.
+func isRoutinePlanningSessionVar(name string) bool {
+	if _, ok := statementHintSessionVars[name]; ok {
+		return true
+	}
+	_, ok := routinePlanningSessionVars[name]
+	return ok
+}
+
+// setFuncSessionVar applies a SET or RESET clause to the session variable
+// settings of a function. The value of the variable is validated, but it does
+// not take effect until the function is executed. Variables which affect the
+// resolution or planning of the body cannot be set.
+func setFuncSessionVar(params runParams, udfDesc *funcdesc.Mutable, n *tree.RoutineSetVar) error {
+	if n.ResetAll {
+		udfDesc.ResetConfigVar("" /* name */)
+		return nil
+	}
+	name := strings.ToLower(n.Name)
+	_, v, err := getSessionVar(name, false /* missingOk */)
+	if err != nil {
+		return err
+	}
+	if v.Set == nil {
+		if v.RuntimeSet == nil && v.SetWithPlanner == nil {
+			return newCannotChangeParameterError(name)
+		}
+		return unimplemented.Newf("routine set var",
+			"setting parameter %q in a function definition is not supported", name)
+	}
+	isReset := n.Reset
+	if len(n.Values) == 1 {
+		if _, ok := n.Values[0].(tree.DefaultVal); ok {
+			// "SET var = DEFAULT" has the same effect as "RESET var".
+			isReset = true
+		}
+	}
+	if isReset {
+		udfDesc.ResetConfigVar(name)
+		return nil
+	}
+	if isRoutinePlanningSessionVar(name) {
+		return errors.WithHint(
+			unimplemented.Newf("routine set var",
+				"setting parameter %q in a function definition is not supported", name),
+			"the SET clause is applied while the function executes, after its body "+
+				"has been resolved and planned, so parameters which affect name "+
+				"resolution or query planning cannot be set",
+		)
+	}
+
+	var strVal string
+	if n.FromCurrent {
+		strVal, err = v.Get(params.extendedEvalCtx, params.p.Txn())
+		if err != nil {
+			return err
+		}
+	} else {
+		typedValues := make([]tree.TypedExpr, len(n.Values))
+		for i, expr := range n.Values {
+			expr = paramparse.UnresolvedNameToStrVal(expr)
+			var dummyHelper tree.IndexedVarHelper
+			typedValue, err := params.p.analyzeExpr(
+				params.ctx, expr, dummyHelper, types.String, false, "SET "+name)
+			if err != nil {
+				return wrapSetVarError(err, name, expr.String())
+			}
+			d, err := eval.Expr(params.ctx, params.EvalContext(), typedValue)
+			if err != nil {
+				return err
+			}
+			typedValues[i] = d
+		}
+		if v.GetStringVal != nil {
+			strVal, err = v.GetStringVal(params.ctx, params.extendedEvalCtx, typedValues, params.p.Txn())
+		} else {
+			strVal, err = getStringVal(params.ctx, params.EvalContext(), name, typedValues)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	// Validate the value by applying it to a copy of the session data.
+	m := params.p.sessionDataMutatorIterator.mutator(
+		false /* applyCallbacks */, params.p.SessionData().Clone(),
+	)
+	if err := v.Set(params.ctx, m, strVal); err != nil {
+		return err
+	}
+	udfDesc.SetConfigVar(name, strVal)
+	return nil
+}
+
 // resetFuncOption sets all function options to default values.
 func resetFuncOption(udfDesc *funcdesc.Mutable) {
 	udfDesc.SetVolatility(catpb.Function_VOLATILE)
 	udfDesc.SetNullInputBehavior(catpb.Function_CALLED_ON_NULL_INPUT)
 	udfDesc.SetLeakProof(false)
+	udfDesc.SetCost(0)
+	udfDesc.SetRows(0)
+	udfDesc.SetParallel(catpb.Function_UNSAFE)
+	udfDesc.ResetConfigVar("" /* name */)
 }
 
 func makeFunctionParam(
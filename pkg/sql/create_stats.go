@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -73,7 +74,8 @@ func StubTableStats(
 ) ([]*stats.TableStatisticProto, error) {
 	colStats, err := createStatsDefaultColumns(
 		context.Background(), desc, false /* virtColEnabled */, false, /* multiColEnabled */
-		false /* partialStats */, nonIndexColHistogramBuckets, nil, /* evalCtx */
+		false /* multiColHistEnabled */, false /* partialStats */, nonIndexColHistogramBuckets,
+		nil, /* evalCtx */
 	)
 	if err != nil {
 		return nil, err
@@ -244,9 +246,10 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		// Disable multi-column stats and deleting stats if partial statistics at
 		// the extremes are requested.
 		// TODO(faizaanmadhani): Add support for multi-column stats.
-		var multiColEnabled bool
+		var multiColEnabled, multiColHistEnabled bool
 		if !n.Options.UsingExtremes {
 			multiColEnabled = stats.MultiColumnStatisticsClusterMode.Get(n.p.ExecCfg().SV())
+			multiColHistEnabled = n.multiColumnHistogramsEnabled(ctx)
 			deleteOtherStats = true
 		}
 		defaultHistogramBuckets := stats.GetDefaultHistogramBuckets(n.p.ExecCfg().SV(), tableDesc)
@@ -255,6 +258,7 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 			tableDesc,
 			virtColEnabled,
 			multiColEnabled,
+			multiColHistEnabled,
 			n.Options.UsingExtremes,
 			defaultHistogramBuckets,
 			n.p.EvalContext(),
@@ -296,11 +300,16 @@ func (n *createStatsNode) makeJobRecord(ctx context.Context) (*jobs.Record, erro
 		_ = stats.MakeSortedColStatKey(columnIDs)
 		isInvIndex := colinfo.ColumnTypeIsOnlyInvertedIndexable(col.GetType())
 		defaultHistogramBuckets := stats.GetDefaultHistogramBuckets(n.p.ExecCfg().SV(), tableDesc)
+		// By default, create histograms on all explicitly requested column stats
+		// with a single column that doesn't use an inverted index. Histograms on
+		// multi-column stats are only created if enabled.
+		hasHistogram := len(columnIDs) == 1 && !isInvIndex
+		if len(columnIDs) > 1 && !n.Options.UsingExtremes && n.multiColumnHistogramsEnabled(ctx) {
+			hasHistogram = multiColumnHistogramSupported(tableDesc, columnIDs)
+		}
 		colStats = []jobspb.CreateStatsDetails_ColStat{{
-			ColumnIDs: columnIDs,
-			// By default, create histograms on all explicitly requested column stats
-			// with a single column that doesn't use an inverted index.
-			HasHistogram:        len(columnIDs) == 1 && !isInvIndex,
+			ColumnIDs:           columnIDs,
+			HasHistogram:        hasHistogram,
 			HistogramMaxBuckets: defaultHistogramBuckets,
 		}}
 		// Make histograms for inverted index column types.
@@ -370,9 +379,10 @@ const maxNonIndexCols = 100
 // useful to have statistics on prefixes of those columns. For example, if a
 // table abc contains indexes on (a ASC, b ASC) and (b ASC, c ASC), we will
 // collect statistics on a, {a, b}, b, and {b, c}. (But if multiColEnabled is
-// false, we will only collect stats on a and b). Columns in partial index
-// predicate expressions are also likely to appear in query filters, so stats
-// are collected for those columns as well.
+// false, we will only collect stats on a and b). Histograms are only collected
+// on the multi-column stats if multiColHistEnabled is true. Columns in partial
+// index predicate expressions are also likely to appear in query filters, so
+// stats are collected for those columns as well.
 //
 // If partialStats is true, we only collect statistics on single columns that
 // are prefixes of forward indexes, and skip over partial, sharded, and
@@ -385,7 +395,7 @@ const maxNonIndexCols = 100
 func createStatsDefaultColumns(
 	ctx context.Context,
 	desc catalog.TableDescriptor,
-	virtColEnabled, multiColEnabled, partialStats bool,
+	virtColEnabled, multiColEnabled, multiColHistEnabled, partialStats bool,
 	defaultHistogramBuckets uint32,
 	evalCtx *eval.Context,
 ) ([]jobspb.CreateStatsDetails_ColStat, error) {
@@ -549,10 +559,11 @@ func createStatsDefaultColumns(
 		// Remember the requested stats so we don't request duplicates.
 		_ = sortAndTrackStatsExists(colIDs)
 
-		// Only generate non-histogram multi-column stats.
+		// Only generate histograms on multi-column stats if enabled.
 		colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-			ColumnIDs:    colIDs,
-			HasHistogram: false,
+			ColumnIDs:           colIDs,
+			HasHistogram:        multiColHistEnabled && multiColumnHistogramSupported(desc, colIDs),
+			HistogramMaxBuckets: defaultHistogramBuckets,
 		})
 	}
 
@@ -603,10 +614,11 @@ func createStatsDefaultColumns(
 				continue
 			}
 
-			// Only generate non-histogram multi-column stats.
+			// Only generate histograms on multi-column stats if enabled.
 			colStats = append(colStats, jobspb.CreateStatsDetails_ColStat{
-				ColumnIDs:    colIDs,
-				HasHistogram: false,
+				ColumnIDs:           colIDs,
+				HasHistogram:        multiColHistEnabled && multiColumnHistogramSupported(desc, colIDs),
+				HistogramMaxBuckets: defaultHistogramBuckets,
 			})
 		}
 
@@ -673,6 +685,30 @@ func createStatsDefaultColumns(
 	return colStats, nil
 }
 
+// multiColumnHistogramsEnabled returns true if histograms should be collected
+// on multi-column statistics.
+func (n *createStatsNode) multiColumnHistogramsEnabled(ctx context.Context) bool {
+	return stats.MultiColumnHistogramsClusterMode.Get(n.p.ExecCfg().SV()) &&
+		n.p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V25_1)
+}
+
+// multiColumnHistogramSupported returns true if a histogram can be collected on
+// a multi-column statistic on the given columns. Histograms are not collected
+// if any of the columns can only be indexed by an inverted index, or has a
+// user-defined type.
+func multiColumnHistogramSupported(desc catalog.TableDescriptor, colIDs []descpb.ColumnID) bool {
+	for _, colID := range colIDs {
+		col := catalog.FindColumnByID(desc, colID)
+		if col == nil {
+			return false
+		}
+		if typ := col.GetType(); colinfo.ColumnTypeIsOnlyInvertedIndexable(typ) || typ.UserDefined() {
+			return false
+		}
+	}
+	return true
+}
+
 // createStatsResumer implements the jobs.Resumer interface for CreateStats
 // jobs. A new instance is created for each job.
 type createStatsResumer struct {
@@ -77,6 +77,9 @@ func TryDelegate(
 	case *tree.ShowIndexes:
 		return d.delegateShowIndexes(t)
 
+	case *tree.ShowIndexRecommendations:
+		return d.delegateShowIndexRecommendations(t)
+
 	case *tree.ShowColumns:
 		return d.delegateShowColumns(t)
 
@@ -0,0 +1,51 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package delegate
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// delegateShowIndexRecommendations implements SHOW INDEX RECOMMENDATIONS, which
+// returns the indexes of the given table, or of all tables in the given or
+// current database, that are recommended to be dropped.
+func (d *delegator) delegateShowIndexRecommendations(
+	n *tree.ShowIndexRecommendations,
+) (tree.Statement, error) {
+	const getRecommendationsQuery = `
+SELECT
+    table_name,
+    index_name,
+    reason,
+    covering_index,
+    fingerprint,
+    executions,
+    total_reads,
+    last_read,
+    is_visible AS visible,
+    details
+FROM
+    %[1]s.crdb_internal.index_drop_recommendations
+%[2]s
+ORDER BY
+    schema_name, table_name, index_name, reason;`
+
+	if n.Table != nil {
+		// showTableDetails formats the catalog name as its fourth argument and
+		// the table ID as its sixth argument.
+		return d.showTableDetails(
+			n.Table, fmt.Sprintf(getRecommendationsQuery, "%[4]s", "WHERE table_id = %[6]d"),
+		)
+	}
+
+	name, err := d.getSpecifiedOrCurrentDatabase(n.Database)
+	if err != nil {
+		return nil, err
+	}
+	return d.parse(fmt.Sprintf(getRecommendationsQuery, name.String(), ""))
+}
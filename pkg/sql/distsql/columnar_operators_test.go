@@ -129,6 +129,10 @@ func TestAggregateFuncToNumArguments(t *testing.T) {
 	fns := make([]execinfrapb.AggregatorSpec_Func, 0,
 		len(execinfrapb.AggregatorSpec_Func_name))
 	for fn := range execinfrapb.AggregatorSpec_Func_name {
+		if execinfrapb.AggregatorSpec_Func(fn) == execinfrapb.UserDefined {
+			// User-defined aggregates have no builtin overloads.
+			continue
+		}
 		fns = append(fns, execinfrapb.AggregatorSpec_Func(fn))
 	}
 	sort.Slice(fns, func(i, j int) bool { return fns[i] < fns[j] })
@@ -1306,8 +1310,10 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 
 	for aggFnIdx := 0; aggFnIdx < len(execinfrapb.AggregatorSpec_Func_name); aggFnIdx++ {
 		aggFn := execinfrapb.AggregatorSpec_Func(aggFnIdx)
-		if !colexecagg.IsAggOptimized(aggFn) || aggFn == execinfrapb.AnyNotNull {
-			// any_not_null is an internal function.
+		if (!colexecagg.IsAggOptimized(aggFn) && aggFn != execinfrapb.StringAgg) ||
+			aggFn == execinfrapb.AnyNotNull {
+			// any_not_null is an internal function. Of the aggregate functions
+			// without an optimized implementation, we only test string_agg.
 			continue
 		}
 		var argTypes []*types.T
@@ -1318,6 +1324,8 @@ func TestWindowFunctionsAgainstProcessor(t *testing.T) {
 			argTypes = []*types.T{types.Bool}
 		case execinfrapb.ConcatAgg:
 			argTypes = []*types.T{types.String}
+		case execinfrapb.StringAgg:
+			argTypes = []*types.T{types.String, types.String}
 		default:
 			argTypes = []*types.T{types.Int}
 			if rand.Float64() < randTypesProbability &&
@@ -453,6 +453,9 @@ func (ds *ServerImpl) newFlowContext(
 		Gateway:        isGatewayNode,
 		DiskMonitor:    diskMonitor,
 	}
+	if localState.IsLocal && isGatewayNode {
+		flowCtx.Replan = localState.Replan
+	}
 
 	if localState.IsLocal && localState.Collection != nil {
 		// If we were passed a descs.Collection to use, then take it. In this
@@ -528,6 +531,10 @@ type LocalState struct {
 	// mapping to coldata.Batch, use any to avoid injecting new
 	// dependencies.
 	LocalVectorSources map[int32]any
+
+	// Replan, if set, is the coordinator through which operators of a local
+	// flow can request that the statement be re-planned.
+	Replan *execinfra.ReplanCoordinator
 }
 
 // MustUseLeafTxn returns true if a LeafTxn must be used. It is valid to call
@@ -734,6 +734,13 @@ func checkSupportForPlanNode(
 		if err != nil {
 			return cannotDistribute, err
 		}
+		for _, f := range n.funcs {
+			if f.userDefined != nil {
+				return cannotDistribute, newQueryNotSupportedErrorf(
+					"user-defined aggregate %q cannot be executed with distsql", f.expr.Func.String(),
+				)
+			}
+		}
 		for _, f := range n.funcs {
 			if len(f.partitionIdxs) > 0 {
 				// If at least one function has PARTITION BY clause, then we
@@ -926,6 +933,11 @@ type PlanningCtx struct {
 	// OverridePlannerMon, if set, will be used instead of the Planner.Mon() as
 	// the parent monitor for the DistSQL flow.
 	OverridePlannerMon *mon.BytesMonitor
+
+	// replan, if set, allows operators of the local flow to request that the
+	// main query be re-planned when they detect a gross cardinality
+	// misestimate. It is only set for local plans of read-only statements.
+	replan *execinfra.ReplanCoordinator
 }
 
 var _ physicalplan.ExprContext = &PlanningCtx{}
@@ -2110,6 +2122,7 @@ func (dsp *DistSQLPlanner) createTableReaders(
 			spans:             n.spans,
 			reverse:           n.reverse,
 			parallelize:       n.parallelize,
+			splitWithinRanges: n.splitWithinRanges,
 			estimatedRowCount: n.estimatedRowCount,
 			reqOrdering:       n.reqOrdering,
 		},
@@ -2127,6 +2140,7 @@ type tableReaderPlanningInfo struct {
 	spans             []roachpb.Span
 	reverse           bool
 	parallelize       bool
+	splitWithinRanges bool
 	estimatedRowCount uint64
 	reqOrdering       ReqOrdering
 }
@@ -2152,11 +2166,12 @@ func (dsp *DistSQLPlanner) maybeParallelizeLocalScans(
 ) (spanPartitions []SpanPartition, parallelizeLocal bool) {
 	// For local plans, if:
 	// - there is no required ordering,
-	// - the scan is safe to parallelize, and
+	// - the scan is safe to parallelize (or can be split within ranges), and
 	// - the parallelization of scans in local flows is allowed,
 	// - there is still quota for running more parallel local TableReaders,
 	// then we will split all spans according to the leaseholder boundaries and
-	// will create a separate TableReader for each node.
+	// will create a separate TableReader for each node. Large scans might be
+	// additionally split within ranges (see canSplitScanWithinRanges).
 	sd := planCtx.ExtendedEvalCtx.SessionData()
 	// If we have locality optimized search enabled and we won't use the
 	// vectorized engine, using the parallel scans might actually be
@@ -2165,7 +2180,7 @@ func (dsp *DistSQLPlanner) maybeParallelizeLocalScans(
 	// remote regions and would block until all come back in the row-based flow.
 	prohibitParallelScans := sd.LocalityOptimizedSearch && sd.VectorizeMode == sessiondatapb.VectorizeOff
 	if len(info.reqOrdering) == 0 &&
-		info.parallelize &&
+		(info.parallelize || info.splitWithinRanges) &&
 		planCtx.parallelizeScansIfLocal &&
 		!prohibitParallelScans &&
 		dsp.parallelLocalScansSem.ApproximateQuota() > 0 &&
@@ -2191,9 +2206,11 @@ func (dsp *DistSQLPlanner) maybeParallelizeLocalScans(
 		for i := range spanPartitions {
 			spanPartitions[i].SQLInstanceID = dsp.gatewaySQLInstanceID
 		}
+		spanPartitions = dsp.maybeSplitLocalScanWithinRanges(ctx, planCtx, info, spanPartitions)
 		if len(spanPartitions) > 1 {
-			// We're touching ranges that have leaseholders on multiple nodes,
-			// so it'd be beneficial to parallelize such a scan.
+			// We're touching ranges that have leaseholders on multiple nodes
+			// (or the scan is large enough to be split within ranges), so it'd
+			// be beneficial to parallelize such a scan.
 			//
 			// Determine the desired concurrency. The concurrency is limited by
 			// the number of partitions as well as maxConcurrency constant (the
@@ -2465,11 +2482,20 @@ func (dsp *DistSQLPlanner) addAggregators(
 	aggregations := make([]execinfrapb.AggregatorSpec_Aggregation, len(n.funcs))
 	argumentsColumnTypes := make([][]*types.T, len(n.funcs))
 	for i, fholder := range n.funcs {
-		funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
-		if err != nil {
-			return err
+		if fholder.userDefined != nil {
+			aggregations[i].Func = execinfrapb.UserDefined
+			spec, err := makeUserDefinedAggregateSpec(ctx, planCtx, fholder.userDefined)
+			if err != nil {
+				return err
+			}
+			aggregations[i].UserDefined = spec
+		} else {
+			funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
+			if err != nil {
+				return err
+			}
+			aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		}
-		aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		aggregations[i].Distinct = fholder.isDistinct
 		for _, renderIdx := range fholder.argRenderIdxs {
 			aggregations[i].ColIdx = append(aggregations[i].ColIdx, uint32(p.PlanToStreamColMap[renderIdx]))
@@ -2504,6 +2530,31 @@ func (dsp *DistSQLPlanner) addAggregators(
 	})
 }
 
+// makeUserDefinedAggregateSpec returns the specification of a user-defined
+// aggregate. The routines of the aggregate can only be evaluated locally.
+func makeUserDefinedAggregateSpec(
+	ctx context.Context, planCtx *PlanningCtx, info *exec.UserDefinedAggInfo,
+) (*execinfrapb.AggregatorSpec_UserDefinedAggregate, error) {
+	spec := &execinfrapb.AggregatorSpec_UserDefinedAggregate{
+		StrictTransition: info.StrictTransition,
+	}
+	var ef physicalplan.ExprFactory
+	ef.Init(ctx, planCtx, nil /* indexVarMap */)
+	var err error
+	if spec.Transition, err = ef.Make(info.Transition); err != nil {
+		return nil, err
+	}
+	if info.Final != nil {
+		if spec.Final, err = ef.Make(info.Final); err != nil {
+			return nil, err
+		}
+	}
+	if spec.InitCond, err = ef.Make(info.InitCond); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
 // planAggregators plans the aggregator processors. An evaluator stage is added
 // if necessary.
 // Invariants assumed:
@@ -3050,7 +3101,13 @@ func (dsp *DistSQLPlanner) planAggregators(
 			argTypes = append(argTypes, inputTypes[c])
 		}
 		argTypes = append(argTypes, info.argumentsColumnTypes[i]...)
-		returnTyp, err := execagg.GetAggregateOutputType(agg.Func, argTypes)
+		var returnTyp *types.T
+		var err error
+		if agg.UserDefined != nil {
+			returnTyp, err = execagg.GetUserDefinedAggregateOutputType(agg.UserDefined)
+		} else {
+			returnTyp, err = execagg.GetAggregateOutputType(agg.Func, argTypes)
+		}
 		if err != nil {
 			return err
 		}
@@ -3768,6 +3825,9 @@ func (dsp *DistSQLPlanner) createPlanForJoin(
 		leftPlanDistribution:  leftPlan.GetLastStageDistribution(),
 		rightPlanDistribution: rightPlan.GetLastStageDistribution(),
 	}
+	if planCtx.replan != nil {
+		info.rightEstimate = n.rightEstimate
+	}
 	return dsp.planJoiners(ctx, planCtx, &info, n.reqOrdering), nil
 }
 
@@ -5004,7 +5064,7 @@ func (c *localScanParallelizationChecker) enterNode(
 		}
 		return true, nil
 	case *scanNode:
-		if len(n.reqOrdering) == 0 && n.parallelize {
+		if len(n.reqOrdering) == 0 && (n.parallelize || n.splitWithinRanges) {
 			c.hasScanNodeToParallelize = true
 		}
 		return true, nil
@@ -39,6 +39,10 @@ type joinPlanningInfo struct {
 	leftMergeOrd, rightMergeOrd                 execinfrapb.Ordering
 	leftPlanDistribution, rightPlanDistribution physicalplan.PlanDistribution
 	allowPartialDistribution                    bool
+	// rightEstimate, if set, allows a hash joiner to request that the
+	// statement be re-planned when the right input turns out to be much larger
+	// than estimated.
+	rightEstimate exec.CardinalityEstimate
 }
 
 // makeCoreSpec creates a processor core for hash and merge joins based on the
@@ -61,6 +65,9 @@ func (info *joinPlanningInfo) makeCoreSpec() execinfrapb.ProcessorCoreUnion {
 			Type:                 info.joinType,
 			LeftEqColumnsAreKey:  info.leftEqColsAreKey,
 			RightEqColumnsAreKey: info.rightEqColsAreKey,
+
+			RightEstimatedRowCount:      info.rightEstimate.RowCount,
+			RightCardinalityFeedbackKey: info.rightEstimate.Key,
 		}
 	} else {
 		core.MergeJoiner = &execinfrapb.MergeJoinerSpec{
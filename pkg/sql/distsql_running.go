@@ -692,6 +692,7 @@ func (dsp *DistSQLPlanner) Run(
 	localState.Txn = txn
 	localState.LocalProcs = plan.LocalProcessors
 	localState.LocalVectorSources = plan.LocalVectorSources
+	localState.Replan = planCtx.replan
 	if planCtx.planner != nil {
 		// Note that the planner's collection will only be used for local plans.
 		localState.Collection = planCtx.planner.Descriptors()
@@ -973,6 +974,11 @@ type DistSQLReceiver struct {
 	// piece of metadata is pushed to the result writer.
 	dataPushed bool
 
+	// replan, if set, is consulted before any data is pushed to the result
+	// writer so that the rows produced by a plan that is being abandoned in
+	// favor of a re-plan are never sent to the client.
+	replan *execinfra.ReplanCoordinator
+
 	// commErr keeps track of the error received from interacting with the
 	// resultWriter. This represents a "communication error" and as such is unlike
 	// query execution errors: when the DistSQLReceiver is used within a SQL
@@ -1504,6 +1510,13 @@ func (r *DistSQLReceiver) Push(
 		return r.status
 	}
 
+	if !r.replan.MarkDataPushed() {
+		// A re-plan of the statement has been requested, so the rows produced
+		// by the current plan must be discarded.
+		r.status = execinfra.DrainRequested
+		return r.status
+	}
+
 	if r.stmtType != tree.Rows {
 		n := int(tree.MustBeDInt(row[0].Datum))
 		// We only need the row count. planNodeToRowSource is set up to handle
@@ -1595,6 +1608,13 @@ func (r *DistSQLReceiver) PushBatch(
 		return r.status
 	}
 
+	if !r.replan.MarkDataPushed() {
+		// A re-plan of the statement has been requested, so the rows produced
+		// by the current plan must be discarded.
+		r.status = execinfra.DrainRequested
+		return r.status
+	}
+
 	if r.stmtType != tree.Rows {
 		// We only need the row count. planNodeToRowSource is set up to handle
 		// ensuring that the last stage in the pipeline will return a single-column
@@ -2253,8 +2273,9 @@ func (dsp *DistSQLPlanner) PlanAndRunPostQueries(
 		for ; triggersIdx < numTriggers; triggersIdx++ {
 			trigger := &plan.triggers[triggersIdx]
 			hasBuffer, numBufferedRows := checkPostQueryBuffer(plan.triggers[triggersIdx])
-			if hasBuffer && numBufferedRows == 0 {
-				// No rows were actually modified.
+			if hasBuffer && numBufferedRows == 0 && !trigger.StatementLevel {
+				// No rows were actually modified. Statement-level triggers still fire
+				// in this case.
 				continue
 			}
 			if log.ExpensiveLogEnabled(ctx, 2) {
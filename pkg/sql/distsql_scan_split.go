@@ -0,0 +1,235 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// localScansHistogramSplitEnabled determines whether large unordered scans in
+// local plans can be split at the bucket boundaries of the histogram on the
+// leading index column, so that the sub-spans are read concurrently even if
+// all of them belong to a single range.
+var localScansHistogramSplitEnabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.local_scans.histogram_split.enabled",
+	"if enabled, large unordered scans in local plans are split into sub-spans at "+
+		"the bucket boundaries of the histogram on the leading index column, and "+
+		"each sub-span is read by a separate table reader even if all of them "+
+		"belong to a single range",
+	false,
+)
+
+// localScansMinRowsPerSubSpan is the minimum estimated number of rows in each
+// sub-span when splitting a scan within ranges.
+var localScansMinRowsPerSubSpan = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.local_scans.histogram_split.min_rows_per_span",
+	"minimum estimated number of rows in each concurrently read sub-span of a "+
+		"large scan in a local plan",
+	100000,
+	settings.PositiveInt,
+)
+
+// maxLocalScanSubSpans is the maximum number of sub-spans a single scan is
+// split into.
+const maxLocalScanSubSpans = 64
+
+// canSplitScanWithinRanges returns whether the scan with the given parameters
+// might be split into sub-spans that are read concurrently. Unlike
+// exec.ScanParams.Parallelize, this doesn't require the number of rows to be
+// bounded since each of the sub-spans is read with the usual batch limits.
+func canSplitScanWithinRanges(
+	sv *settings.Values, index catalog.Index, params exec.ScanParams,
+) bool {
+	return localScansHistogramSplitEnabled.Get(sv) &&
+		params.HardLimit == 0 && params.SoftLimit == 0 && !params.Reverse &&
+		index.GetType() == descpb.IndexDescriptor_FORWARD &&
+		params.EstimatedRowCount >= 2*uint64(localScansMinRowsPerSubSpan.Get(sv))
+}
+
+// maybeSplitLocalScanWithinRanges splits the spans of the given partitions
+// into sub-spans at the boundaries derived from the table statistics, and
+// returns a separate partition for each group of sub-spans. The partitions
+// are returned unchanged if the scan is too small to benefit from it or the
+// statistics aren't available.
+//
+// The splitting is deliberately limited in scope:
+//   - Only local plans are split. Distributed plans are already parallelized
+//     across the leaseholders of the scanned ranges.
+//   - Only scans without a required ordering are split, since the results of
+//     the sub-spans are combined by an unordered synchronizer.
+//   - Each group of sub-spans is read by a separate TableReader in the local
+//     flow. The streamer is not involved.
+//   - The split keys only come from the histogram on the leading column of
+//     the scanned index (see scanSplitKeys). They are never derived from the
+//     SST boundaries of the storage engine or from range statistics, so scans
+//     of tables without such a histogram, or whose row count the optimizer
+//     can't estimate, are not split.
+func (dsp *DistSQLPlanner) maybeSplitLocalScanWithinRanges(
+	ctx context.Context,
+	planCtx *PlanningCtx,
+	info *tableReaderPlanningInfo,
+	spanPartitions []SpanPartition,
+) []SpanPartition {
+	if !info.splitWithinRanges {
+		return spanPartitions
+	}
+	// Aim for at least the configured number of rows per sub-span while not
+	// exceeding the maximum number of sub-spans.
+	rowsPerSubSpan := uint64(localScansMinRowsPerSubSpan.Get(&dsp.st.SV))
+	if r := info.estimatedRowCount / maxLocalScanSubSpans; r > rowsPerSubSpan {
+		rowsPerSubSpan = r
+	}
+	splitKeys, err := scanSplitKeys(ctx, planCtx, info, rowsPerSubSpan)
+	if err != nil {
+		log.VEventf(ctx, 2, "couldn't compute split keys for scan of %s: %v", info.desc.GetName(), err)
+		return spanPartitions
+	}
+	if len(splitKeys) == 0 {
+		return spanPartitions
+	}
+	return splitSpanPartitions(spanPartitions, splitKeys)
+}
+
+// scanSplitKeys returns the keys at which the spans of the scan can be split
+// so that each sub-span is estimated to contain about rowsPerSubSpan rows.
+// The keys are derived from the newest histogram on the leading column of the
+// scanned index, so they always fall on row boundaries. No keys are returned
+// if there is no such histogram or the leading column is descending. The keys
+// are returned in ascending order.
+func scanSplitKeys(
+	ctx context.Context, planCtx *PlanningCtx, info *tableReaderPlanningInfo, rowsPerSubSpan uint64,
+) ([]roachpb.Key, error) {
+	idx, err := catalog.MustFindIndexByID(info.desc, info.spec.FetchSpec.IndexID)
+	if err != nil {
+		return nil, err
+	}
+	if idx.GetKeyColumnDirection(0) != catenumpb.IndexColumn_ASC {
+		// The histogram buckets are ordered by value, so the keys of a
+		// descending column would have to be produced in reverse. Keep things
+		// simple and don't split such scans.
+		return nil, nil
+	}
+	colID := idx.GetKeyColumnID(0)
+	tableStats, err := planCtx.ExtendedEvalCtx.ExecCfg.TableStatsCache.GetTableStats(ctx, info.desc)
+	if err != nil {
+		return nil, err
+	}
+	var histogram []cat.HistogramBucket
+	// The statistics are ordered from newest to oldest.
+	for _, s := range tableStats {
+		if len(s.ColumnIDs) == 1 && s.ColumnIDs[0] == colID && len(s.Histogram) > 0 {
+			histogram = s.Histogram
+			break
+		}
+	}
+	prefix := rowenc.MakeIndexKeyPrefix(planCtx.ExtendedEvalCtx.Codec, info.desc.GetID(), idx.GetID())
+	var splitKeys []roachpb.Key
+	var rows float64
+	for i := range histogram {
+		if i == len(histogram)-1 {
+			// The upper bound of the last bucket is the maximum value, so
+			// splitting at it would leave only a handful of rows in the last
+			// sub-span.
+			break
+		}
+		b := &histogram[i]
+		if b.UpperBound == tree.DNull {
+			continue
+		}
+		key, err := keyside.Encode(append([]byte(nil), prefix...), b.UpperBound, encoding.Ascending)
+		if err != nil {
+			return nil, err
+		}
+		if !spansStrictlyContainKey(info.spans, key) {
+			// Only the rows within the scanned spans contribute to the size of
+			// the sub-spans.
+			continue
+		}
+		// The rows in the range of the bucket precede the split key whereas
+		// the rows equal to the upper bound follow it.
+		rows += b.NumRange
+		if rows >= float64(rowsPerSubSpan) {
+			splitKeys = append(splitKeys, key)
+			if len(splitKeys) == maxLocalScanSubSpans-1 {
+				break
+			}
+			rows = 0
+		}
+		rows += b.NumEq
+	}
+	return splitKeys, nil
+}
+
+// spansStrictlyContainKey returns whether key is within one of the spans and
+// is not equal to the start key of that span, i.e. whether the span can be
+// split at key.
+func spansStrictlyContainKey(spans roachpb.Spans, key roachpb.Key) bool {
+	for _, sp := range spans {
+		if sp.Key.Compare(key) < 0 && key.Compare(sp.EndKey) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// splitSpanPartitions splits the spans of each partition at the given keys,
+// which must be in ascending order, and groups the resulting sub-spans of
+// each partition according to the interval between split keys they belong
+// to. Each group becomes a separate partition assigned to the same SQL
+// instance as the original one.
+func splitSpanPartitions(
+	spanPartitions []SpanPartition, splitKeys []roachpb.Key,
+) []SpanPartition {
+	// groupIdx returns the index of the interval between split keys that key
+	// belongs to.
+	groupIdx := func(key roachpb.Key) int {
+		return sort.Search(len(splitKeys), func(i int) bool {
+			return splitKeys[i].Compare(key) > 0
+		})
+	}
+	var result []SpanPartition
+	groups := make([]roachpb.Spans, len(splitKeys)+1)
+	for _, partition := range spanPartitions {
+		for _, sp := range partition.Spans {
+			g := groupIdx(sp.Key)
+			if len(sp.EndKey) == 0 {
+				groups[g] = append(groups[g], sp)
+				continue
+			}
+			for ; g < len(splitKeys) && splitKeys[g].Compare(sp.EndKey) < 0; g++ {
+				groups[g] = append(groups[g], roachpb.Span{Key: sp.Key, EndKey: splitKeys[g]})
+				sp.Key = splitKeys[g]
+			}
+			groups[g] = append(groups[g], sp)
+		}
+		for i, spans := range groups {
+			if len(spans) > 0 {
+				result = append(result, SpanPartition{
+					SQLInstanceID: partition.SQLInstanceID,
+					Spans:         spans,
+				})
+				groups[i] = nil
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitSpanPartitions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	sp := func(start, end string) roachpb.Span {
+		s := roachpb.Span{Key: roachpb.Key(start)}
+		if end != "" {
+			s.EndKey = roachpb.Key(end)
+		}
+		return s
+	}
+	keys := func(ks ...string) []roachpb.Key {
+		res := make([]roachpb.Key, len(ks))
+		for i, k := range ks {
+			res[i] = roachpb.Key(k)
+		}
+		return res
+	}
+
+	for _, tc := range []struct {
+		name       string
+		partitions []SpanPartition
+		splitKeys  []roachpb.Key
+		expected   []SpanPartition
+	}{
+		{
+			name:       "single span",
+			partitions: []SpanPartition{{SQLInstanceID: 1, Spans: roachpb.Spans{sp("a", "z")}}},
+			splitKeys:  keys("c", "m"),
+			expected: []SpanPartition{
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("a", "c")}},
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("c", "m")}},
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("m", "z")}},
+			},
+		},
+		{
+			name: "multiple spans",
+			partitions: []SpanPartition{{SQLInstanceID: 1, Spans: roachpb.Spans{
+				sp("a", "b"), sp("d", ""), sp("e", "k"), sp("m", "p"),
+			}}},
+			splitKeys: keys("c", "g", "m"),
+			expected: []SpanPartition{
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("a", "b")}},
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("d", ""), sp("e", "g")}},
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("g", "k")}},
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("m", "p")}},
+			},
+		},
+		{
+			name: "multiple partitions",
+			partitions: []SpanPartition{
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("a", "f")}},
+				{SQLInstanceID: 2, Spans: roachpb.Spans{sp("f", "z")}},
+			},
+			splitKeys: keys("c", "f", "x"),
+			expected: []SpanPartition{
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("a", "c")}},
+				{SQLInstanceID: 1, Spans: roachpb.Spans{sp("c", "f")}},
+				{SQLInstanceID: 2, Spans: roachpb.Spans{sp("f", "x")}},
+				{SQLInstanceID: 2, Spans: roachpb.Spans{sp("x", "z")}},
+			},
+		},
+		{
+			name:       "split keys outside of spans",
+			partitions: []SpanPartition{{SQLInstanceID: 1, Spans: roachpb.Spans{sp("d", "f")}}},
+			splitKeys:  keys("a", "x"),
+			expected:   []SpanPartition{{SQLInstanceID: 1, Spans: roachpb.Spans{sp("d", "f")}}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, splitSpanPartitions(tc.partitions, tc.splitKeys))
+		})
+	}
+}
@@ -72,6 +72,11 @@ func (p *planner) DropFunction(ctx context.Context, n *tree.DropRoutine) (ret pl
 		if err != nil {
 			return nil, err
 		}
+		if err := checkRoutineAggregateKind(
+			mut, &fn, n.Aggregate, "Use DROP AGGREGATE to drop aggregate functions.",
+		); err != nil {
+			return nil, err
+		}
 		if n.DropBehavior != tree.DropCascade && len(mut.DependedOnBy) > 0 {
 			dependedOnByIDs := make([]descpb.ID, 0, len(mut.DependedOnBy))
 			for _, ref := range mut.DependedOnBy {
@@ -173,6 +178,27 @@ func (p *planner) checkPrivilegesForDropFunction(
 	return mutable, nil
 }
 
+// checkRoutineAggregateKind returns an error if a statement that targets
+// aggregate functions (e.g. DROP AGGREGATE) resolved a routine that is not an
+// aggregate, or if any other statement resolved an aggregate. The hint is
+// added to the latter error.
+func checkRoutineAggregateKind(
+	fnDesc catalog.FunctionDescriptor, routineObj *tree.RoutineObj, isAggregateStmt bool, hint string,
+) error {
+	if isAggregateStmt && !fnDesc.IsAggregate() {
+		return pgerror.Newf(
+			pgcode.WrongObjectType, "function %s is not an aggregate", tree.AsString(routineObj),
+		)
+	}
+	if !isAggregateStmt && fnDesc.IsAggregate() {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%s is an aggregate function", tree.AsString(routineObj)),
+			hint,
+		)
+	}
+	return nil
+}
+
 func (p *planner) canDropFunction(ctx context.Context, fnDesc catalog.FunctionDescriptor) error {
 	hasOwernship, err := p.HasOwnershipOnSchema(ctx, fnDesc.GetParentSchemaID(), fnDesc.GetParentID())
 	if err != nil {
@@ -84,6 +84,17 @@ func GetAggregateConstructor(
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	if aggInfo.UserDefined != nil {
+		if len(aggInfo.ColIdx) != 1 {
+			return nil, nil, nil, errors.AssertionFailedf(
+				"user-defined aggregate needs 1 input, found %d", len(aggInfo.ColIdx),
+			)
+		}
+		constructor, outputType, err = getUserDefinedAggregateInfo(
+			ctx, evalCtx, semaCtx, aggInfo.UserDefined,
+		)
+		return constructor, nil /* arguments */, outputType, err
+	}
 	for j, c := range aggInfo.ColIdx {
 		if c >= uint32(len(inputTypes)) {
 			err = errors.Errorf("ColIdx out of range (%d)", aggInfo.ColIdx)
@@ -112,6 +123,89 @@ func GetAggregateConstructor(
 	return
 }
 
+// getUserDefinedAggregateRoutines returns the routines that implement the given
+// user-defined aggregate. They are never serialized, since plans that contain
+// user-defined aggregates are not distributed.
+func getUserDefinedAggregateRoutines(
+	spec *execinfrapb.AggregatorSpec_UserDefinedAggregate,
+) (transition, final *tree.RoutineExpr, err error) {
+	var ok bool
+	if transition, ok = spec.Transition.LocalExpr.(*tree.RoutineExpr); !ok {
+		return nil, nil, errors.AssertionFailedf(
+			"expected local routine for user-defined aggregate transition, found %v", &spec.Transition,
+		)
+	}
+	if !spec.Final.Empty() {
+		if final, ok = spec.Final.LocalExpr.(*tree.RoutineExpr); !ok {
+			return nil, nil, errors.AssertionFailedf(
+				"expected local routine for user-defined aggregate final function, found %v", &spec.Final,
+			)
+		}
+	}
+	return transition, final, nil
+}
+
+// getUserDefinedAggregateInfo returns the aggregate constructor and the return
+// type for the given user-defined aggregate.
+func getUserDefinedAggregateInfo(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+	spec *execinfrapb.AggregatorSpec_UserDefinedAggregate,
+) (AggregateConstructor, *types.T, error) {
+	transition, final, err := getUserDefinedAggregateRoutines(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	initCond := tree.Datum(tree.DNull)
+	if !spec.InitCond.Empty() {
+		h := execinfrapb.ExprHelper{}
+		// Pass nil types and row - there are no variables in the expression.
+		if err := h.Init(ctx, spec.InitCond, nil /* types */, semaCtx, evalCtx); err != nil {
+			return nil, nil, errors.Wrapf(err, "%s", &spec.InitCond)
+		}
+		if initCond, err = h.Eval(ctx, nil /* row */); err != nil {
+			return nil, nil, errors.Wrapf(err, "%s", &spec.InitCond)
+		}
+	}
+	constructor := builtins.NewUserDefinedAggregate(transition, final, initCond, spec.StrictTransition)
+	outputType := transition.ResolvedType()
+	if final != nil {
+		outputType = final.ResolvedType()
+	}
+	return constructor, outputType, nil
+}
+
+// GetUserDefinedAggregateOutputType returns the output type of the given
+// user-defined aggregate.
+func GetUserDefinedAggregateOutputType(
+	spec *execinfrapb.AggregatorSpec_UserDefinedAggregate,
+) (*types.T, error) {
+	transition, final, err := getUserDefinedAggregateRoutines(spec)
+	if err != nil {
+		return nil, err
+	}
+	if final != nil {
+		return final.ResolvedType(), nil
+	}
+	return transition.ResolvedType(), nil
+}
+
+// GetUserDefinedWindowFunctionInfo returns the windowFunc constructor and the
+// return type for the given user-defined aggregate used as a window function.
+func GetUserDefinedWindowFunctionInfo(
+	ctx context.Context,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+	spec *execinfrapb.AggregatorSpec_UserDefinedAggregate,
+) (windowConstructor func(*eval.Context) eval.WindowFunc, returnType *types.T, err error) {
+	constructor, returnType, err := getUserDefinedAggregateInfo(ctx, evalCtx, semaCtx, spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	return builtins.NewFramableAggregateWindowFunc(constructor), returnType, nil
+}
+
 // ParamTypesAllocator is a helper struct for batching allocations of aggregate
 // function parameter types.
 type ParamTypesAllocator struct {
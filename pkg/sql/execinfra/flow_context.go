@@ -89,6 +89,11 @@ type FlowCtx struct {
 	// Gateway is true if this flow is being run on the gateway node.
 	Gateway bool
 
+	// Replan, if set, allows operators that detect gross cardinality
+	// misestimates to request that the statement be re-planned. It is only set
+	// for local flows on the gateway.
+	Replan *ReplanCoordinator
+
 	// DiskMonitor is this flow's disk monitor. All disk usage for this flow must
 	// be registered through this monitor.
 	DiskMonitor *mon.BytesMonitor
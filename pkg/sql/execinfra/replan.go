@@ -0,0 +1,126 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package execinfra
+
+import (
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// ErrReplanRequested is raised by an operator that observed a gross
+// cardinality misestimate at a materialization point and asked for the
+// statement to be re-planned with the observed row counts. The error never
+// reaches the client: the gateway catches it, records the observed
+// cardinalities, and runs the statement again.
+var ErrReplanRequested = errors.New("query re-planning requested due to cardinality misestimate")
+
+// IsReplanRequestedError returns true if err indicates that an operator
+// requested the statement to be re-planned.
+func IsReplanRequestedError(err error) bool {
+	return errors.Is(err, ErrReplanRequested)
+}
+
+// CardinalityMisestimate describes a row count observed at runtime that
+// differed significantly from the optimizer's estimate.
+type CardinalityMisestimate struct {
+	// Key identifies the relational expression the estimate was made for. It is
+	// produced by the optimizer and is opaque to the execution engine.
+	Key string
+	// EstimatedRowCount is the row count estimated by the optimizer.
+	EstimatedRowCount uint64
+	// ActualRowCount is the number of rows observed during execution. If the
+	// operator requested a re-plan before consuming all of its input, this is a
+	// lower bound on the true row count.
+	ActualRowCount uint64
+}
+
+const (
+	replanStateNone int32 = iota
+	replanStateDataPushed
+	replanStateRequested
+)
+
+// ReplanCoordinator arbitrates between operators that want to abandon the
+// current plan and the consumer of the query results. A re-plan can only be
+// requested before any row has been sent to the client, and once a re-plan
+// has been requested no further rows may be sent. It also collects the
+// cardinality misestimates observed by the operators of a flow so that they
+// can be fed back to the optimizer.
+//
+// A ReplanCoordinator is only set up for gateway flows of local plans, so all
+// operators that use it run on the same node as the consumer.
+type ReplanCoordinator struct {
+	state atomic.Int32
+
+	mu struct {
+		syncutil.Mutex
+		misestimates []CardinalityMisestimate
+	}
+}
+
+// RequestReplan attempts to transition the coordinator into the "re-plan
+// requested" state. It returns false if some data has already been pushed to
+// the consumer, in which case the current plan must run to completion.
+func (c *ReplanCoordinator) RequestReplan() bool {
+	if c == nil {
+		return false
+	}
+	return c.state.CompareAndSwap(replanStateNone, replanStateRequested) ||
+		c.state.Load() == replanStateRequested
+}
+
+// MarkDataPushed records that data is being sent to the consumer. It returns
+// false if a re-plan has already been requested, in which case the data must
+// be discarded.
+func (c *ReplanCoordinator) MarkDataPushed() bool {
+	if c == nil {
+		return true
+	}
+	return c.state.CompareAndSwap(replanStateNone, replanStateDataPushed) ||
+		c.state.Load() == replanStateDataPushed
+}
+
+// ReplanRequested returns whether a re-plan has been requested.
+func (c *ReplanCoordinator) ReplanRequested() bool {
+	return c != nil && c.state.Load() == replanStateRequested
+}
+
+// DisallowReplan prevents any further re-plan requests from being granted.
+// Misestimates are still recorded.
+func (c *ReplanCoordinator) DisallowReplan() {
+	c.state.Store(replanStateDataPushed)
+}
+
+// RecordMisestimate stores an observed cardinality misestimate.
+func (c *ReplanCoordinator) RecordMisestimate(m CardinalityMisestimate) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.misestimates = append(c.mu.misestimates, m)
+}
+
+// Misestimates returns the cardinality misestimates recorded so far.
+func (c *ReplanCoordinator) Misestimates() []CardinalityMisestimate {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]CardinalityMisestimate(nil), c.mu.misestimates...)
+}
+
+// Reset prepares the coordinator for another execution of the statement. The
+// recorded misestimates are discarded.
+func (c *ReplanCoordinator) Reset() {
+	c.state.Store(replanStateNone)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.misestimates = c.mu.misestimates[:0]
+}
@@ -266,6 +266,16 @@ func (ex *connExecutor) recordStatementSummary(
 		ex.statsCollector.ObserveStatement(stmtFingerprintID, recordedStmtStats)
 	}
 
+	// Feed the latency of successful executions to the plan baseline registry,
+	// which uses them to verify candidate plans against pinned plans.
+	if registry := ex.server.cfg.PlanBaselineRegistry; registry != nil &&
+		stmtErr == nil && automaticRetryCount == 0 {
+		registry.RecordExecution(
+			ctx, stmt.StmtNoConstants, recordedStmtStats.PlanGist,
+			flags.IsSet(planFlagPlanBaseline), svcLatRaw,
+		)
+	}
+
 	// Do some transaction level accounting for the transaction this statement is
 	// a part of.
 
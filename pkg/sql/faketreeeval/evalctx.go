@@ -348,6 +348,20 @@ func (*DummyEvalPlanner) PLpgSQLFetchCursor(
 	return nil, errors.WithStack(errEvalPlanner)
 }
 
+// PLpgSQLOpenDynamicCursor is part of the eval.Planner interface.
+func (*DummyEvalPlanner) PLpgSQLOpenDynamicCursor(
+	context.Context, tree.Name, string, tree.Datums, bool,
+) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
+// PLpgSQLExecuteDynamic is part of the eval.Planner interface.
+func (*DummyEvalPlanner) PLpgSQLExecuteDynamic(
+	context.Context, string, tree.Datums,
+) (tree.Datums, int, error) {
+	return nil, 0, errors.WithStack(errEvalPlanner)
+}
+
 func (p *DummyEvalPlanner) StartHistoryRetentionJob(
 	ctx context.Context, desc string, protectTS hlc.Timestamp, expiration time.Duration,
 ) (jobspb.JobID, error) {
@@ -376,6 +390,26 @@ func (ep *DummyEvalPlanner) FingerprintSpan(
 	return 0, errors.AssertionFailedf("FingerprintSpan unimplemented")
 }
 
+func (ep *DummyEvalPlanner) TableDiff(
+	_ context.Context, _ int64, _ hlc.Timestamp,
+) (eval.InternalRows, error) {
+	return nil, errors.AssertionFailedf("TableDiff unimplemented")
+}
+
+func (ep *DummyEvalPlanner) PinStatementPlan(_ context.Context, _ string, _ string) error {
+	return errors.AssertionFailedf("PinStatementPlan unimplemented")
+}
+
+func (ep *DummyEvalPlanner) UnpinStatementPlan(_ context.Context, _ string) (bool, error) {
+	return false, errors.AssertionFailedf("UnpinStatementPlan unimplemented")
+}
+
+func (ep *DummyEvalPlanner) ScheduleIndexDrop(
+	_ context.Context, _ int64, _ int64, _ time.Duration,
+) (int64, error) {
+	return 0, errors.AssertionFailedf("ScheduleIndexDrop unimplemented")
+}
+
 // ResetMultiRegionZoneConfigsForTable is part of the eval.RegionOperator
 // interface.
 func (ep *DummyEvalPlanner) ResetMultiRegionZoneConfigsForTable(_ context.Context, _ int64) error {
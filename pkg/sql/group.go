@@ -77,6 +77,8 @@ type aggregateFuncHolder struct {
 	// distsqlBlocklist is set when this function cannot be evaluated in
 	// distributed fashion.
 	distsqlBlocklist bool
+	// userDefined is set if the function is a user-defined aggregate.
+	userDefined *exec.UserDefinedAggInfo
 }
 
 // newAggregateFuncHolder creates an aggregateFuncHolder.
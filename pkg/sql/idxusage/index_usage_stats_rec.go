@@ -46,6 +46,19 @@ var DropUnusedIndexDuration = settings.RegisterDurationSetting(
 	settings.WithPublic,
 )
 
+// RarelyUsedIndexMaxExecutions is the number of executions below which an
+// index that is only used by a single statement fingerprint is considered
+// rarely used.
+var RarelyUsedIndexMaxExecutions = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.index_recommendation.rarely_used_max_executions",
+	"the number of executions, within the index unused duration, below which an "+
+		"index that is only used by a single statement fingerprint is reported as rarely used",
+	10,
+	settings.NonNegativeInt,
+	settings.WithPublic,
+)
+
 const indexExceedUsageDurationReasonPlaceholder = "This index has not been used in over %sand can be removed for better write performance."
 const indexNeverUsedReason = "This index has not been used and can be removed for better write performance."
 
@@ -0,0 +1,102 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package idxusage
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+)
+
+// IndexColumn is a key column of an index.
+type IndexColumn struct {
+	ID         catid.ColumnID
+	Descending bool
+}
+
+// IndexDefinition describes the columns of an index of a table, for the
+// purpose of finding redundant indexes.
+type IndexDefinition struct {
+	ID roachpb.IndexID
+	// Primary is true for the primary index of the table, which stores all
+	// the columns of the table.
+	Primary bool
+	// Unique is true for indexes that enforce a uniqueness constraint, which
+	// are never redundant.
+	Unique bool
+	// Partial is true for partial indexes, which neither are redundant nor make
+	// another index redundant.
+	Partial bool
+	// Inverted is true for inverted and vector indexes, which neither are
+	// redundant nor make another index redundant.
+	Inverted bool
+	// KeyColumns are the key columns of the index, including the implicit
+	// partitioning and shard columns.
+	KeyColumns []IndexColumn
+	// StoredColumns are the columns stored by a secondary index.
+	StoredColumns []catid.ColumnID
+}
+
+// FindRedundantIndexes returns, for each secondary index of a table that is
+// redundant, the ID of an index of the same table that makes it redundant.
+//
+// An index is redundant with another index if its key columns, in the same
+// order and direction, are a prefix of the key columns of the other index,
+// and the other index stores or has as key columns all the columns it stores.
+// Any query that is served by the redundant index can be served by the other
+// index, so dropping it only reduces the write amplification of the table.
+// Of two identical indexes, the one with the higher ID is redundant.
+func FindRedundantIndexes(indexes []IndexDefinition) map[roachpb.IndexID]roachpb.IndexID {
+	redundant := make(map[roachpb.IndexID]roachpb.IndexID)
+	for i := range indexes {
+		idx := &indexes[i]
+		if idx.Primary || idx.Unique || idx.Partial || idx.Inverted {
+			continue
+		}
+		for j := range indexes {
+			other := &indexes[j]
+			if i == j || other.Partial || other.Inverted || !covers(other, idx) {
+				continue
+			}
+			if !other.Primary && covers(idx, other) && idx.ID < other.ID {
+				// The indexes are identical, and the other index is the one that
+				// is redundant.
+				continue
+			}
+			redundant[idx.ID] = other.ID
+			break
+		}
+	}
+	return redundant
+}
+
+// covers returns true if the index idx can serve all the queries served by
+// the index other.
+func covers(idx, other *IndexDefinition) bool {
+	if len(other.KeyColumns) > len(idx.KeyColumns) {
+		return false
+	}
+	for i := range other.KeyColumns {
+		if idx.KeyColumns[i] != other.KeyColumns[i] {
+			return false
+		}
+	}
+	if idx.Primary {
+		return true
+	}
+	cols := make(map[catid.ColumnID]struct{}, len(idx.KeyColumns)+len(idx.StoredColumns))
+	for _, col := range idx.KeyColumns {
+		cols[col.ID] = struct{}{}
+	}
+	for _, col := range idx.StoredColumns {
+		cols[col] = struct{}{}
+	}
+	for _, col := range other.StoredColumns {
+		if _, ok := cols[col]; !ok {
+			return false
+		}
+	}
+	return true
+}
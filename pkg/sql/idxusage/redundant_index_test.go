@@ -0,0 +1,112 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package idxusage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRedundantIndexes(t *testing.T) {
+	asc := func(ids ...catid.ColumnID) []IndexColumn {
+		cols := make([]IndexColumn, len(ids))
+		for i, id := range ids {
+			cols[i] = IndexColumn{ID: id}
+		}
+		return cols
+	}
+	primary := IndexDefinition{ID: 1, Primary: true, Unique: true, KeyColumns: asc(1, 2)}
+
+	testData := []struct {
+		name     string
+		indexes  []IndexDefinition
+		expected map[roachpb.IndexID]roachpb.IndexID
+	}{
+		{
+			name: "prefix",
+			indexes: []IndexDefinition{
+				primary,
+				{ID: 2, KeyColumns: asc(3)},
+				{ID: 3, KeyColumns: asc(3, 4)},
+			},
+			expected: map[roachpb.IndexID]roachpb.IndexID{2: 3},
+		},
+		{
+			name: "prefix of primary index",
+			indexes: []IndexDefinition{
+				primary,
+				{ID: 2, KeyColumns: asc(1), StoredColumns: []catid.ColumnID{3}},
+			},
+			expected: map[roachpb.IndexID]roachpb.IndexID{2: 1},
+		},
+		{
+			name: "identical",
+			indexes: []IndexDefinition{
+				primary,
+				{ID: 3, KeyColumns: asc(3), StoredColumns: []catid.ColumnID{4}},
+				{ID: 2, KeyColumns: asc(3), StoredColumns: []catid.ColumnID{4}},
+			},
+			expected: map[roachpb.IndexID]roachpb.IndexID{3: 2},
+		},
+		{
+			name: "stored column not covered",
+			indexes: []IndexDefinition{
+				primary,
+				{ID: 2, KeyColumns: asc(3), StoredColumns: []catid.ColumnID{5}},
+				{ID: 3, KeyColumns: asc(3, 4)},
+			},
+			expected: map[roachpb.IndexID]roachpb.IndexID{},
+		},
+		{
+			name: "stored column covered by key column",
+			indexes: []IndexDefinition{
+				primary,
+				{ID: 2, KeyColumns: asc(3), StoredColumns: []catid.ColumnID{4}},
+				{ID: 3, KeyColumns: asc(3, 4)},
+			},
+			expected: map[roachpb.IndexID]roachpb.IndexID{2: 3},
+		},
+		{
+			name: "different direction",
+			indexes: []IndexDefinition{
+				primary,
+				{ID: 2, KeyColumns: asc(3)},
+				{ID: 3, KeyColumns: []IndexColumn{{ID: 3, Descending: true}, {ID: 4}}},
+			},
+			expected: map[roachpb.IndexID]roachpb.IndexID{},
+		},
+		{
+			name: "not a prefix",
+			indexes: []IndexDefinition{
+				primary,
+				{ID: 2, KeyColumns: asc(4)},
+				{ID: 3, KeyColumns: asc(3, 4)},
+			},
+			expected: map[roachpb.IndexID]roachpb.IndexID{},
+		},
+		{
+			name: "unique, partial and inverted indexes",
+			indexes: []IndexDefinition{
+				primary,
+				{ID: 2, KeyColumns: asc(3), Unique: true},
+				{ID: 3, KeyColumns: asc(3), Partial: true},
+				{ID: 4, KeyColumns: asc(3, 4), Partial: true},
+				{ID: 5, KeyColumns: asc(5), Inverted: true},
+				{ID: 6, KeyColumns: asc(5), Inverted: true},
+			},
+			expected: map[roachpb.IndexID]roachpb.IndexID{},
+		},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, FindRedundantIndexes(tc.indexes))
+		})
+	}
+}
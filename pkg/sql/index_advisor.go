@@ -0,0 +1,211 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/indexrec"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// EstimateIndexRecommendationCost returns the estimated cost, as computed by
+// the optimizer, of the statement with the given fingerprint in the given
+// database before and after the given indexes are created. The indexes are
+// added to hypothetical copies of their tables, so nothing is written. The
+// constants that were removed from the fingerprint are replaced with
+// placeholders, so the costs are those of a generic plan. Indexes on tables
+// that are not referenced by the statement are ignored.
+//
+// Pinned plan baselines are not applied when the costs are estimated, since
+// they would hide the effect of the indexes.
+func EstimateIndexRecommendationCost(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	txn isql.Txn,
+	dbName string,
+	fingerprint string,
+	indexes []tree.CreateIndex,
+) (before, after float64, err error) {
+	ctx, sp := tracing.ChildSpan(ctx, "estimate index recommendation cost")
+	defer sp.Finish()
+
+	stmt, err := parser.ParseOne(fingerprint)
+	if err != nil {
+		return 0, 0, err
+	}
+	stmt.AST, stmt.NumPlaceholders, err = replaceFingerprintConstants(stmt.AST)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	const opName = "estimate-index-recommendation-cost"
+	sd := NewInternalSessionData(ctx, execCfg.Settings, opName)
+	sd.Database = dbName
+	p, cleanup := newInternalPlanner(opName, txn.KV(), username.NodeUserName(), &MemoryMetrics{}, execCfg, sd)
+	defer cleanup()
+
+	p.stmt = makeStatement(stmt, clusterunique.ID{}, /* queryID */
+		tree.FmtFlags(queryFormattingForFingerprintsMask.Get(&execCfg.Settings.SV)),
+	)
+	p.semaCtx.Placeholders.Init(stmt.NumPlaceholders, nil /* typeHints */)
+	p.extendedEvalCtx.PrepareOnly = true
+	return p.optPlanningCtx.estimateHypotheticalIndexCost(ctx, indexes)
+}
+
+// estimateHypotheticalIndexCost builds the statement in the planner and
+// returns the cost of its optimal plan before and after the given indexes are
+// added to hypothetical copies of their tables.
+func (opc *optPlanningCtx) estimateHypotheticalIndexCost(
+	ctx context.Context, indexes []tree.CreateIndex,
+) (before, after float64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			// This code allows us to propagate internal errors without having to add
+			// error checks everywhere throughout the code. This is only possible
+			// because the code does not update shared state and does not manipulate
+			// locks.
+			if ok, e := errorutil.ShouldCatch(r); ok {
+				err = e
+				log.VEventf(ctx, 1, "%v", err)
+			} else {
+				panic(r)
+			}
+		}
+	}()
+
+	opc.reset(ctx)
+	opc.useCache = false
+	opc.allowMemoReuse = false
+
+	p := opc.p
+	f := opc.optimizer.Factory()
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), opc.catalog, f, p.stmt.AST)
+	if err := bld.Build(); err != nil {
+		return 0, 0, err
+	}
+	savedMemo := opc.optimizer.DetachMemo(ctx)
+
+	optimize := func(hypTables map[cat.StableID]cat.Table) (float64, error) {
+		opc.optimizer.Init(ctx, p.EvalContext(), opc.catalog)
+		f.CopyAndReplace(
+			savedMemo.RootExpr().(memo.RelExpr),
+			savedMemo.RootProps(),
+			f.CopyWithoutAssigningPlaceholders,
+		)
+		if hypTables != nil {
+			opc.optimizer.Memo().Metadata().UpdateTableMeta(ctx, p.EvalContext(), hypTables)
+		}
+		root, err := opc.optimizer.Optimize()
+		if err != nil {
+			return 0, err
+		}
+		return float64(root.(memo.RelExpr).Cost()), nil
+	}
+
+	if before, err = optimize(nil /* hypTables */); err != nil {
+		return 0, 0, err
+	}
+
+	candidates, err := opc.hypotheticalIndexCandidates(ctx, savedMemo, indexes)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(candidates) == 0 {
+		return before, before, nil
+	}
+	_, hypTables := indexrec.BuildOptAndHypTableMaps(opc.catalog, candidates)
+	if after, err = optimize(hypTables); err != nil {
+		return 0, 0, err
+	}
+	return before, after, nil
+}
+
+// hypotheticalIndexCandidates maps each of the given indexes to the key
+// columns of a hypothetical index on the table it references in the given
+// memo. Hypothetical indexes store all columns of their table, so the STORING
+// columns of the indexes are not needed.
+func (opc *optPlanningCtx) hypotheticalIndexCandidates(
+	ctx context.Context, m *memo.Memo, indexes []tree.CreateIndex,
+) (map[cat.Table][][]cat.IndexColumn, error) {
+	candidates := make(map[cat.Table][][]cat.IndexColumn)
+	seen := make(map[cat.StableID]struct{})
+	for _, tm := range m.Metadata().AllTables() {
+		tab := tm.Table
+		if _, ok := seen[tab.ID()]; ok {
+			continue
+		}
+		seen[tab.ID()] = struct{}{}
+		name, err := opc.catalog.FullyQualifiedName(ctx, tab)
+		if err != nil {
+			return nil, err
+		}
+		for i := range indexes {
+			idx := &indexes[i]
+			if idx.Table.ObjectName != name.ObjectName ||
+				(idx.Table.ExplicitSchema && idx.Table.SchemaName != name.SchemaName) ||
+				(idx.Table.ExplicitCatalog && idx.Table.CatalogName != name.CatalogName) {
+				continue
+			}
+			cols, err := hypotheticalIndexColumns(tab, idx)
+			if err != nil {
+				return nil, err
+			}
+			candidates[tab] = append(candidates[tab], cols)
+		}
+	}
+	return candidates, nil
+}
+
+// hypotheticalIndexColumns returns the key columns of the given index on the
+// given table.
+func hypotheticalIndexColumns(tab cat.Table, idx *tree.CreateIndex) ([]cat.IndexColumn, error) {
+	cols := make([]cat.IndexColumn, len(idx.Columns))
+	for i, elem := range idx.Columns {
+		if elem.Column == "" {
+			return nil, errors.Newf("expression index %s is not supported", idx)
+		}
+		found := false
+		for j, n := 0, tab.ColumnCount(); j < n; j++ {
+			if col := tab.Column(j); col.ColName() == elem.Column {
+				cols[i] = cat.IndexColumn{Column: col, Descending: elem.Direction == tree.Descending}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Newf("column %q of index %s does not exist", elem.Column, idx)
+		}
+	}
+	return cols, nil
+}
+
+// replaceFingerprintConstants replaces the constants that were removed from a
+// statement fingerprint, which are formatted as "_" or "__more__", with
+// placeholders. It returns the new statement and its number of placeholders.
+func replaceFingerprintConstants(stmt tree.Statement) (tree.Statement, int, error) {
+	var n int
+	newStmt, err := tree.SimpleStmtVisit(stmt, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if name, ok := expr.(*tree.UnresolvedName); ok && name.NumParts == 1 &&
+			(name.Parts[0] == "_" || name.Parts[0] == "__more__") {
+			n++
+			return false, &tree.Placeholder{Idx: tree.PlaceholderIdx(n - 1)}, nil
+		}
+		return true, expr, nil
+	})
+	return newStmt, n, err
+}
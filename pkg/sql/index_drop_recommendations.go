@@ -0,0 +1,182 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+// Reasons for recommending that an index is dropped, as they are shown in
+// crdb_internal.index_drop_recommendations.
+const (
+	indexDropReasonRedundant  = "redundant"
+	indexDropReasonUnused     = "unused"
+	indexDropReasonRarelyUsed = "rarely_used"
+)
+
+// indexStatementUsage summarizes the statement fingerprints that used an
+// index, according to the persisted statement statistics.
+type indexStatementUsage struct {
+	// fingerprints is the number of distinct statement fingerprints that used
+	// the index.
+	fingerprints int64
+	// executions is the total number of executions of those fingerprints.
+	executions int64
+	// query is the fingerprint of one of the statements that used the index.
+	query string
+}
+
+// loadIndexStatementUsage returns the usage of each index, keyed by the
+// "tableID@indexID" strings under which indexes are recorded in the
+// statement statistics, by the statements that were executed since the given
+// time.
+func (p *planner) loadIndexStatementUsage(
+	ctx context.Context, since time.Time,
+) (map[string]indexStatementUsage, error) {
+	rows, err := p.InternalSQLTxn().QueryBufferedEx(
+		ctx, "crdb-internal-index-statement-usage", p.txn,
+		sessiondata.NodeUserSessionDataOverride, `
+SELECT
+  idx,
+  count(DISTINCT fingerprint_id)::INT8,
+  sum(execution_count)::INT8,
+  max(metadata->>'query')
+FROM system.statement_statistics, jsonb_array_elements_text(indexes_usage) AS idx
+WHERE aggregated_ts >= $1
+  AND app_name NOT LIKE '$ internal%'
+GROUP BY idx`,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[string]indexStatementUsage, len(rows))
+	for _, row := range rows {
+		u := indexStatementUsage{
+			fingerprints: int64(tree.MustBeDInt(row[1])),
+			executions:   int64(tree.MustBeDInt(row[2])),
+		}
+		if row[3] != tree.DNull {
+			u.query = string(tree.MustBeDString(row[3]))
+		}
+		usage[string(tree.MustBeDString(row[0]))] = u
+	}
+	return usage, nil
+}
+
+// indexDropRecommendation is a row of crdb_internal.index_drop_recommendations.
+type indexDropRecommendation struct {
+	index  catalog.Index
+	reason string
+	// coveringIndex is the index that makes a redundant index redundant.
+	coveringIndex catalog.Index
+	// usage is the statement usage of a rarely used index.
+	usage   indexStatementUsage
+	details string
+}
+
+// indexDropRecommendationsForTable returns the recommendations to drop
+// secondary indexes of the given table, which are redundant with another
+// index, have not been read within the unused index duration, or were only
+// used by a single statement fingerprint that was rarely executed. Statement
+// usage is nil if the user is not allowed to view the statement statistics.
+func (p *planner) indexDropRecommendationsForTable(
+	dbName string,
+	table catalog.TableDescriptor,
+	indexStats *idxusage.LocalIndexUsageStats,
+	stmtUsage map[string]indexStatementUsage,
+) []indexDropRecommendation {
+	st := p.ExecCfg().Settings
+	redundant := idxusage.FindRedundantIndexes(indexDefinitions(table))
+	var recs []indexDropRecommendation
+	for _, idx := range table.PublicNonPrimaryIndexes() {
+		if coveringID, ok := redundant[roachpb.IndexID(idx.GetID())]; ok {
+			if covering, err := catalog.MustFindIndexByID(table, descpb.IndexID(coveringID)); err == nil {
+				recs = append(recs, indexDropRecommendation{
+					index:         idx,
+					reason:        indexDropReasonRedundant,
+					coveringIndex: covering,
+					details: fmt.Sprintf("This index is redundant with index %s, which can serve "+
+						"all of its queries, and can be removed for better write performance.",
+						covering.GetName()),
+				})
+			}
+		}
+
+		stats := indexStats.Get(roachpb.TableID(table.GetID()), roachpb.IndexID(idx.GetID()))
+		row := idxusage.IndexStatsRow{
+			TableID:   roachpb.TableID(table.GetID()),
+			IndexID:   roachpb.IndexID(idx.GetID()),
+			LastRead:  stats.LastRead,
+			IndexType: "secondary",
+			IsUnique:  idx.IsUnique(),
+		}
+		if createdAt := idx.CreatedAt(); !createdAt.IsZero() {
+			row.CreatedAt = &createdAt
+		}
+		for _, rec := range row.GetRecommendationsFromIndexStats(dbName, st) {
+			recs = append(recs, indexDropRecommendation{
+				index:   idx,
+				reason:  indexDropReasonUnused,
+				details: rec.Reason,
+			})
+		}
+
+		if stmtUsage == nil || idx.IsUnique() {
+			continue
+		}
+		usage, ok := stmtUsage[fmt.Sprintf("%d@%d", table.GetID(), idx.GetID())]
+		if ok && usage.fingerprints == 1 &&
+			usage.executions < idxusage.RarelyUsedIndexMaxExecutions.Get(&st.SV) {
+			recs = append(recs, indexDropRecommendation{
+				index:  idx,
+				reason: indexDropReasonRarelyUsed,
+				usage:  usage,
+				details: fmt.Sprintf("This index is only used by a single statement fingerprint, "+
+					"which was executed %d times in the last %s, and can be removed for better "+
+					"write performance.", usage.executions, idxusage.DropUnusedIndexDuration.Get(&st.SV)),
+			})
+		}
+	}
+	return recs
+}
+
+// indexDefinitions returns the definitions of the public indexes of the given
+// table, for the purpose of finding redundant indexes.
+func indexDefinitions(table catalog.TableDescriptor) []idxusage.IndexDefinition {
+	indexes := table.ActiveIndexes()
+	defs := make([]idxusage.IndexDefinition, 0, len(indexes))
+	for _, idx := range indexes {
+		def := idxusage.IndexDefinition{
+			ID:       roachpb.IndexID(idx.GetID()),
+			Primary:  idx.Primary(),
+			Unique:   idx.IsUnique(),
+			Partial:  idx.IsPartial(),
+			Inverted: idx.GetType() != descpb.IndexDescriptor_FORWARD,
+		}
+		for i := 0; i < idx.NumKeyColumns(); i++ {
+			def.KeyColumns = append(def.KeyColumns, idxusage.IndexColumn{
+				ID:         idx.GetKeyColumnID(i),
+				Descending: idx.GetKeyColumnDirection(i) == catenumpb.IndexColumn_DESC,
+			})
+		}
+		for i := 0; i < idx.NumSecondaryStoredColumns(); i++ {
+			def.StoredColumns = append(def.StoredColumns, idx.GetStoredColumnID(i))
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
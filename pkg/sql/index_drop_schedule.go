@@ -0,0 +1,355 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// ScheduleIndexDrop is part of the eval.Planner interface. It creates a
+// schedule that makes the given index not visible, keeps it not visible for
+// the given observation period, and then drops it. Dropping the schedule
+// before the index is dropped makes the index visible again.
+func (p *planner) ScheduleIndexDrop(
+	ctx context.Context, tableID int64, indexID int64, observationPeriod time.Duration,
+) (int64, error) {
+	if observationPeriod <= 0 {
+		return 0, pgerror.New(pgcode.InvalidParameterValue,
+			"observation period must be positive")
+	}
+	table, err := p.Descriptors().ByIDWithLeased(p.txn).WithoutNonPublic().Get().Table(
+		ctx, descpb.ID(tableID),
+	)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.CheckPrivilege(ctx, table, privilege.CREATE); err != nil {
+		return 0, err
+	}
+	idx := catalog.FindIndexByID(table, descpb.IndexID(indexID))
+	if idx == nil || !idx.Public() {
+		return 0, pgerror.Newf(pgcode.UndefinedObject,
+			"index %d does not exist on table %q", indexID, table.GetName())
+	}
+	if idx.Primary() {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue,
+			"cannot schedule the drop of primary index %q", idx.GetName())
+	}
+	if idx.IsUnique() {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue,
+			"cannot schedule the drop of unique index %q", idx.GetName())
+	}
+
+	label := indexDropScheduleLabel(table.GetID(), idx.GetID())
+	row, err := p.InternalSQLTxn().QueryRowEx(
+		ctx, "check-index-drop-schedule", p.txn, sessiondata.NodeUserSessionDataOverride,
+		`SELECT count(*) FROM system.scheduled_jobs
+WHERE executor_type = $1 AND schedule_name = $2 AND next_run IS NOT NULL`,
+		tree.ScheduledIndexDropExecutor.InternalName(), label,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if tree.MustBeDInt(row[0]) > 0 {
+		return 0, pgerror.Newf(pgcode.DuplicateObject,
+			"the drop of index %q is already scheduled", idx.GetName())
+	}
+
+	env := JobSchedulerEnv(p.ExecCfg().JobsKnobs())
+	sj := jobs.NewScheduledJob(env)
+	sj.SetScheduleLabel(label)
+	sj.SetOwner(p.User())
+	sj.SetScheduleDetails(jobspb.ScheduleDetails{
+		Wait:                   jobspb.ScheduleDetails_WAIT,
+		OnError:                jobspb.ScheduleDetails_RETRY_SOON,
+		ClusterID:              p.extendedEvalCtx.ClusterID,
+		CreationClusterVersion: p.ExecCfg().Settings.Version.ActiveVersion(ctx),
+	})
+	// The schedule is a one-off schedule, which runs once right away to make
+	// the index not visible, and then re-arms itself to run once more at the
+	// end of the observation period.
+	sj.SetNextRun(env.Now())
+	if err := setIndexDropArgs(sj, &catpb.ScheduledIndexDropArgs{
+		TableID:           table.GetID(),
+		IndexID:           idx.GetID(),
+		ObservationPeriod: observationPeriod,
+	}); err != nil {
+		return 0, err
+	}
+	if err := jobs.ScheduledJobTxn(p.InternalSQLTxn()).Create(ctx, sj); err != nil {
+		return 0, err
+	}
+	return int64(sj.ScheduleID()), nil
+}
+
+func indexDropScheduleLabel(tableID descpb.ID, indexID descpb.IndexID) string {
+	return fmt.Sprintf("index-drop-%d-%d", tableID, indexID)
+}
+
+func setIndexDropArgs(sj *jobs.ScheduledJob, args *catpb.ScheduledIndexDropArgs) error {
+	any, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledIndexDropExecutor.InternalName(),
+		jobspb.ExecutionArguments{Args: any},
+	)
+	return nil
+}
+
+type indexDropExecutor struct {
+	metrics indexDropMetrics
+}
+
+var _ jobs.ScheduledJobController = (*indexDropExecutor)(nil)
+var _ jobs.ScheduledJobExecutor = (*indexDropExecutor)(nil)
+
+type indexDropMetrics struct {
+	*jobs.ExecutorMetrics
+}
+
+var _ metric.Struct = &indexDropMetrics{}
+
+// MetricStruct is part of the metric.Struct interface.
+func (m *indexDropMetrics) MetricStruct() {}
+
+// OnDrop is part of the jobs.ScheduledJobController interface. It makes the
+// index visible again if the schedule made it not visible and did not drop it
+// yet.
+func (e indexDropExecutor) OnDrop(
+	ctx context.Context,
+	scheduleControllerEnv scheduledjobs.ScheduleControllerEnv,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+	txn isql.Txn,
+	descsCol *descs.Collection,
+) (int, error) {
+	var args catpb.ScheduledIndexDropArgs
+	if err := pbtypes.UnmarshalAny(schedule.ExecutionArgs().Args, &args); err != nil {
+		return 0, err
+	}
+	// The index was never made not visible, or the schedule already ran to
+	// completion.
+	if args.NotVisibleSinceNanos == 0 || schedule.NextRun().IsZero() {
+		return 0, nil
+	}
+	name, idx, err := resolveScheduledIndex(ctx, txn, descsCol, args)
+	if err != nil || idx == nil || !idx.IsNotVisible() {
+		return 0, err
+	}
+	return 0, setScheduledIndexVisibility(ctx, txn, name, true /* visible */)
+}
+
+// ExecuteJob is part of the jobs.ScheduledJobExecutor interface. The first
+// execution makes the index not visible, and the execution at the end of the
+// observation period drops it, unless the index was made visible again or was
+// read in the meantime.
+func (e indexDropExecutor) ExecuteJob(
+	ctx context.Context,
+	txn isql.Txn,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+) (err error) {
+	defer func() {
+		if err == nil {
+			e.metrics.NumStarted.Inc(1)
+			e.metrics.NumSucceeded.Inc(1)
+		} else {
+			e.metrics.NumFailed.Inc(1)
+		}
+	}()
+	args := &catpb.ScheduledIndexDropArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return err
+	}
+	name, idx, err := resolveScheduledIndex(ctx, txn, descs.FromTxn(txn), *args)
+	if err != nil {
+		return err
+	}
+	if idx == nil {
+		sj.SetScheduleStatusf("index %d of table %d no longer exists", args.IndexID, args.TableID)
+		return nil
+	}
+
+	now := env.Now()
+	if args.NotVisibleSinceNanos == 0 {
+		if !idx.IsNotVisible() {
+			if err := setScheduledIndexVisibility(ctx, txn, name, false /* visible */); err != nil {
+				return err
+			}
+		}
+		args.NotVisibleSinceNanos = now.UnixNano()
+		if err := setIndexDropArgs(sj, args); err != nil {
+			return err
+		}
+		deadline := now.Add(args.ObservationPeriod)
+		sj.SetNextRun(deadline)
+		sj.SetScheduleStatusf("index %s is not visible and will be dropped at %s", name, deadline)
+		return nil
+	}
+
+	if !idx.IsNotVisible() {
+		sj.SetScheduleStatusf("index %s was made visible again and will not be dropped", name)
+		return nil
+	}
+	notVisibleSince := timeutil.Unix(0, args.NotVisibleSinceNanos)
+	read, err := scheduledIndexReadSince(ctx, txn, *args, notVisibleSince)
+	if err != nil {
+		return err
+	}
+	if read {
+		// Not visible indexes can still be used by index hints and for foreign
+		// key and uniqueness checks, so the index is still needed.
+		if err := setScheduledIndexVisibility(ctx, txn, name, true /* visible */); err != nil {
+			return err
+		}
+		sj.SetScheduleStatusf("index %s was read while it was not visible and was made visible again", name)
+		return nil
+	}
+	if deadline := notVisibleSince.Add(args.ObservationPeriod); now.Before(deadline) {
+		sj.SetNextRun(deadline)
+		return nil
+	}
+	if _, err := txn.ExecEx(
+		ctx, "scheduled-index-drop", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		fmt.Sprintf("DROP INDEX %s", name.String()),
+	); err != nil {
+		return err
+	}
+	sj.SetScheduleStatusf("dropped index %s", name)
+	return nil
+}
+
+// resolveScheduledIndex returns the fully-qualified name of the index of an
+// index drop schedule, or a nil index if the table or the index no longer
+// exists.
+func resolveScheduledIndex(
+	ctx context.Context, txn isql.Txn, descsCol *descs.Collection, args catpb.ScheduledIndexDropArgs,
+) (*tree.TableIndexName, catalog.Index, error) {
+	table, err := descsCol.ByIDWithLeased(txn.KV()).WithoutNonPublic().Get().Table(ctx, args.TableID)
+	if err != nil {
+		if sqlerrors.IsUndefinedRelationError(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	idx := catalog.FindIndexByID(table, args.IndexID)
+	if idx == nil || !idx.Public() {
+		return nil, nil, nil
+	}
+	tn, err := descs.GetObjectName(ctx, txn.KV(), descsCol, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &tree.TableIndexName{
+		Table: *tn.(*tree.TableName),
+		Index: tree.UnrestrictedName(idx.GetName()),
+	}, idx, nil
+}
+
+func setScheduledIndexVisibility(
+	ctx context.Context, txn isql.Txn, name *tree.TableIndexName, visible bool,
+) error {
+	visibility := "NOT VISIBLE"
+	if visible {
+		visibility = "VISIBLE"
+	}
+	_, err := txn.ExecEx(
+		ctx, "scheduled-index-drop-visibility", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		fmt.Sprintf("ALTER INDEX %s %s", name.String(), visibility),
+	)
+	return err
+}
+
+// scheduledIndexReadSince returns whether the index of an index drop schedule
+// was read by any node after the given time.
+func scheduledIndexReadSince(
+	ctx context.Context, txn isql.Txn, args catpb.ScheduledIndexDropArgs, since time.Time,
+) (bool, error) {
+	row, err := txn.QueryRowEx(
+		ctx, "scheduled-index-drop-reads", txn.KV(), sessiondata.NodeUserSessionDataOverride,
+		`SELECT count(*) FROM "".crdb_internal.index_usage_statistics
+WHERE table_id = $1 AND index_id = $2 AND last_read > $3`,
+		args.TableID, args.IndexID, since,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tree.MustBeDInt(row[0]) > 0, nil
+}
+
+// NotifyJobTermination is part of the jobs.ScheduledJobExecutor interface.
+func (e indexDropExecutor) NotifyJobTermination(
+	ctx context.Context,
+	txn isql.Txn,
+	jobID jobspb.JobID,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+) error {
+	// The schedule performs its schema changes directly, without creating jobs
+	// of its own.
+	return errors.AssertionFailedf(
+		"index drop schedule %d does not create jobs", sj.ScheduleID(),
+	)
+}
+
+// Metrics is part of the jobs.ScheduledJobExecutor interface.
+func (e indexDropExecutor) Metrics() metric.Struct {
+	return &e.metrics
+}
+
+// GetCreateScheduleStatement is part of the jobs.ScheduledJobExecutor interface.
+func (e indexDropExecutor) GetCreateScheduleStatement(
+	ctx context.Context, txn isql.Txn, env scheduledjobs.JobSchedulerEnv, sj *jobs.ScheduledJob,
+) (string, error) {
+	args := &catpb.ScheduledIndexDropArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"SELECT crdb_internal.schedule_index_drop(%d, %d, '%s')",
+		args.TableID, args.IndexID, args.ObservationPeriod,
+	), nil
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledIndexDropExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledIndexDropExecutor.InternalName())
+			return &indexDropExecutor{
+				metrics: indexDropMetrics{
+					ExecutorMetrics: &m,
+				},
+			}, nil
+		},
+	)
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package indexadvisor
+
+import (
+	"context"
+	gojson "encoding/json"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+type indexAdvisorResumer struct {
+	job *jobs.Job
+	st  *cluster.Settings
+}
+
+var _ jobs.Resumer = (*indexAdvisorResumer)(nil)
+
+// Resume is part of the jobs.Resumer interface.
+func (r indexAdvisorResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	if !Enabled.Get(&execCfg.Settings.SV) {
+		return nil
+	}
+	since := execCfg.Clock.PhysicalTime().Add(-Lookback.Get(&execCfg.Settings.SV))
+	limit := MaxStatements.Get(&execCfg.Settings.SV)
+
+	var recs []*recommendation
+	if err := execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) (err error) {
+		recs, err = computeRecommendations(ctx, execCfg, txn, since, limit)
+		return err
+	}); err != nil {
+		return err
+	}
+	log.Infof(ctx, "index advisor job recorded %d index recommendations", len(recs))
+	return execCfg.InternalDB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		return writeRecommendations(ctx, txn, recs)
+	})
+}
+
+// computeRecommendations returns the ranked workload index recommendations
+// for the statements that were executed since the given time. Only the
+// recommendations of the given number of statements with the highest total
+// service latency are considered.
+func computeRecommendations(
+	ctx context.Context, execCfg *sql.ExecutorConfig, txn isql.Txn, since time.Time, limit int64,
+) ([]*recommendation, error) {
+	stats, err := loadStatementStats(ctx, txn, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	recs, err := buildRecommendations(stats)
+	if err != nil {
+		return nil, err
+	}
+	mutations, err := countMutations(ctx, txn, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range recs {
+		if rec.create != nil {
+			estimateImprovement(ctx, execCfg, txn, rec)
+		}
+		tn := rec.table()
+		rec.estimateWriteAmplification(mutations[tableKey{db: string(tn.CatalogName), table: tn.ObjectName}])
+	}
+	return rankRecommendations(recs), nil
+}
+
+// estimateImprovement validates the recommendation by re-optimizing the
+// statements that benefit from it against a hypothetical copy of its table
+// with the new index. The improvement of the recommendation is the share of
+// the service latency of each statement that corresponds to the reduction of
+// its estimated cost.
+func estimateImprovement(
+	ctx context.Context, execCfg *sql.ExecutorConfig, txn isql.Txn, rec *recommendation,
+) {
+	for _, stmt := range rec.stmts {
+		before, after, err := sql.EstimateIndexRecommendationCost(
+			ctx, execCfg, txn, stmt.db, stmt.fingerprint, []tree.CreateIndex{*rec.create},
+		)
+		if err != nil {
+			// Some fingerprints can't be planned with placeholders, e.g. if a
+			// list of values was shortened. Skip them.
+			log.VEventf(ctx, 1, "unable to estimate the cost of %q: %v", stmt.fingerprint, err)
+			continue
+		}
+		if before > 0 && after < before {
+			rec.improvement += stmt.latency * (before - after) / before
+		}
+		rec.evidence.Statements = append(rec.evidence.Statements, statementEvidence{
+			Fingerprint:    stmt.fingerprint,
+			Database:       stmt.db,
+			Executions:     stmt.count,
+			ServiceLatency: stmt.latency,
+			CostBefore:     before,
+			CostAfter:      after,
+		})
+	}
+}
+
+// loadStatementStats returns the persisted statistics of the statements with
+// index recommendations that were executed since the given time, up to the
+// given number of statements with the highest total service latency.
+func loadStatementStats(
+	ctx context.Context, txn isql.Txn, since time.Time, limit int64,
+) ([]*statementStats, error) {
+	rows, err := txn.QueryBufferedEx(ctx, "index-advisor-load-statement-stats", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride, `
+SELECT
+  max(metadata->>'query'),
+  max(metadata->>'db'),
+  sum(execution_count)::INT8,
+  sum(total_estimated_execution_time)::FLOAT8 AS latency,
+  array_cat_agg(index_recommendations)
+FROM system.statement_statistics
+WHERE aggregated_ts >= $1
+  AND app_name NOT LIKE '$ internal%'
+  AND array_length(index_recommendations, 1) > 0
+GROUP BY fingerprint_id
+ORDER BY latency DESC
+LIMIT $2`,
+		since, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]*statementStats, 0, len(rows))
+	for _, row := range rows {
+		if row[0] == tree.DNull || row[1] == tree.DNull {
+			continue
+		}
+		stmt := &statementStats{
+			fingerprint: string(tree.MustBeDString(row[0])),
+			db:          string(tree.MustBeDString(row[1])),
+			count:       int64(tree.MustBeDInt(row[2])),
+			latency:     float64(tree.MustBeDFloat(row[3])),
+		}
+		for _, rec := range tree.MustBeDArray(row[4]).Array {
+			if s, ok := rec.(*tree.DString); ok {
+				stmt.indexRecs = append(stmt.indexRecs, string(*s))
+			}
+		}
+		stats = append(stats, stmt)
+	}
+	return stats, nil
+}
+
+// countMutations returns the number of executions of mutation statements on
+// each table since the given time.
+func countMutations(ctx context.Context, txn isql.Txn, since time.Time) (map[tableKey]int64, error) {
+	rows, err := txn.QueryBufferedEx(ctx, "index-advisor-count-mutations", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride, `
+SELECT
+  max(metadata->>'query'),
+  max(metadata->>'db'),
+  sum(execution_count)::INT8
+FROM system.statement_statistics
+WHERE aggregated_ts >= $1
+  AND app_name NOT LIKE '$ internal%'
+  AND metadata->>'query' ~ '^(INSERT|UPSERT|UPDATE|DELETE) '
+GROUP BY fingerprint_id`,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	mutations := make(map[tableKey]int64)
+	for _, row := range rows {
+		if row[0] == tree.DNull || row[1] == tree.DNull {
+			continue
+		}
+		key, ok := mutatedTable(string(tree.MustBeDString(row[1])), string(tree.MustBeDString(row[0])))
+		if !ok {
+			continue
+		}
+		mutations[key] += int64(tree.MustBeDInt(row[2]))
+	}
+	return mutations, nil
+}
+
+// writeRecommendations replaces the pending recommendations in
+// system.index_recommendations with the given ranked recommendations.
+// Recommendations that an operator already approved or rejected are not
+// recorded again.
+func writeRecommendations(ctx context.Context, txn isql.Txn, recs []*recommendation) error {
+	if _, err := txn.ExecEx(ctx, "index-advisor-delete-pending", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		`DELETE FROM system.index_recommendations WHERE status = $1`,
+		string(StatusPending),
+	); err != nil {
+		return err
+	}
+	rows, err := txn.QueryBufferedEx(ctx, "index-advisor-load-reviewed", txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT DISTINCT statements FROM system.index_recommendations`,
+	)
+	if err != nil {
+		return err
+	}
+	reviewed := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		reviewed[string(tree.MustBeDString(row[0]))] = struct{}{}
+	}
+	rank := 0
+	for _, rec := range recs {
+		statements := rec.statements()
+		if _, ok := reviewed[statements]; ok {
+			continue
+		}
+		evidence, err := gojson.Marshal(rec.evidence)
+		if err != nil {
+			return errors.NewAssertionErrorWithWrappedErrf(err, "failed to marshal index recommendation evidence")
+		}
+		rank++
+		if _, err := txn.ExecEx(ctx, "index-advisor-insert", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride, `
+INSERT INTO system.index_recommendations
+  (rank, action, statements, estimated_improvement, write_amplification, evidence, status)
+VALUES ($1, $2, $3, $4, $5, $6::JSONB, $7)`,
+			rank, string(rec.action), statements, rec.improvement, rec.writeAmplification,
+			string(evidence), string(StatusPending),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r indexAdvisorResumer) OnFailOrCancel(
+	ctx context.Context, execCtx interface{}, _ error,
+) error {
+	return nil
+}
+
+// CollectProfile is part of the jobs.Resumer interface.
+func (r indexAdvisorResumer) CollectProfile(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeAutoIndexAdvisor,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &indexAdvisorResumer{
+				job: job,
+				st:  settings,
+			}
+		},
+		jobs.DisablesTenantCostControl,
+	)
+}
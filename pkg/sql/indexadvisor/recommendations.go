@@ -0,0 +1,328 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package indexadvisor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/workloadindexrec"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// Action is the action of an index recommendation, as it is stored in the
+// action column of system.index_recommendations.
+type Action string
+
+const (
+	// ActionCreate recommends creating a new index.
+	ActionCreate Action = "create"
+	// ActionDrop recommends dropping an existing index.
+	ActionDrop Action = "drop"
+	// ActionReplace recommends creating a new index that replaces existing
+	// indexes, which are dropped.
+	ActionReplace Action = "replace"
+)
+
+// Status is the status of an index recommendation, as it is stored in the
+// status column of system.index_recommendations.
+type Status string
+
+const (
+	// StatusPending is the status of the recommendations that are recorded by
+	// the index advisor job. Pending recommendations are replaced every time
+	// the job runs.
+	StatusPending Status = "pending"
+	// StatusApproved is the status of recommendations that an operator
+	// approved.
+	StatusApproved Status = "approved"
+	// StatusRejected is the status of recommendations that an operator
+	// rejected. Rejected recommendations are not recorded again.
+	StatusRejected Status = "rejected"
+)
+
+// statementStats are the persisted statistics of a statement fingerprint that
+// are used by the index advisor.
+type statementStats struct {
+	fingerprint string
+	db          string
+	// count is the number of executions of the statement.
+	count int64
+	// latency is the total service latency of the statement, in seconds.
+	latency float64
+	// indexRecs are the index recommendations of the statement, as they are
+	// stored in system.statement_statistics.
+	indexRecs []string
+}
+
+// statementEvidence is the evidence for a recommendation that was gathered by
+// re-optimizing one of the statements that benefit from it.
+type statementEvidence struct {
+	Fingerprint    string  `json:"fingerprint"`
+	Database       string  `json:"database"`
+	Executions     int64   `json:"executions"`
+	ServiceLatency float64 `json:"serviceLatencySeconds"`
+	CostBefore     float64 `json:"costBefore"`
+	CostAfter      float64 `json:"costAfter"`
+}
+
+// evidence is the evidence for a recommendation, as it is stored in the
+// evidence column of system.index_recommendations.
+type evidence struct {
+	Statements []statementEvidence `json:"statements"`
+	// Mutations is the number of executions of mutation statements on the
+	// table of the recommendation.
+	Mutations int64 `json:"mutations"`
+}
+
+// recommendation is a workload index recommendation.
+type recommendation struct {
+	action Action
+	// create is the index that is created, or nil if the action is ActionDrop.
+	create *tree.CreateIndex
+	// drops are the indexes that are dropped.
+	drops []tree.TableIndexName
+	// stmts are the statements whose own index recommendations are covered by
+	// the recommendation.
+	stmts []*statementStats
+	// improvement is the estimated service latency, in seconds, that the
+	// statements would have saved if the recommendation had been applied.
+	improvement float64
+	// writeAmplification is the estimated number of additional index entries
+	// that mutation statements would have written if the recommendation had
+	// been applied. It is negative if indexes are dropped without being
+	// replaced.
+	writeAmplification float64
+	evidence           evidence
+}
+
+// table returns the table of the recommendation.
+func (r *recommendation) table() tree.TableName {
+	if r.create != nil {
+		return r.create.Table
+	}
+	return r.drops[0].Table
+}
+
+// statements returns the SQL statements that apply the recommendation.
+func (r *recommendation) statements() string {
+	var sb strings.Builder
+	if r.create != nil {
+		sb.WriteString(tree.AsString(r.create))
+		sb.WriteString(";")
+	}
+	for i := range r.drops {
+		if sb.Len() > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(tree.AsString(&tree.DropIndex{IndexList: tree.TableIndexNames{&r.drops[i]}}))
+		sb.WriteString(";")
+	}
+	return sb.String()
+}
+
+// buildRecommendations merges the index recommendations of the given
+// statements into workload recommendations. Every recommendation creates one
+// index that covers the indexes recommended for a set of statements, and drops
+// the indexes those statements recommended replacing. The recommendations are
+// sorted by their statements.
+func buildRecommendations(stats []*statementStats) ([]*recommendation, error) {
+	type origin struct {
+		stmt   *statementStats
+		create tree.CreateIndex
+		drops  []tree.DropIndex
+	}
+	var origins []origin
+	var cis []tree.CreateIndex
+	for _, stmt := range stats {
+		seen := make(map[string]struct{}, len(stmt.indexRecs))
+		for _, rec := range stmt.indexRecs {
+			if _, ok := seen[rec]; ok {
+				continue
+			}
+			seen[rec] = struct{}{}
+			recCis, recDis, err := workloadindexrec.ParseIndexRec(rec)
+			if err != nil {
+				return nil, err
+			}
+			for _, ci := range recCis {
+				origins = append(origins, origin{stmt: stmt, create: ci, drops: recDis})
+				cis = append(cis, ci)
+			}
+		}
+	}
+	merged, err := workloadindexrec.MergeCreateIndexes(cis)
+	if err != nil {
+		return nil, err
+	}
+	recs := make([]*recommendation, len(merged))
+	for i := range merged {
+		recs[i] = &recommendation{action: ActionCreate, create: &merged[i]}
+	}
+	for _, o := range origins {
+		var rec *recommendation
+		for _, r := range recs {
+			if covers(r.create, &o.create) {
+				rec = r
+				break
+			}
+		}
+		if rec == nil {
+			// This cannot happen, since the merged indexes cover all of the
+			// original indexes. Ignore the index rather than failing the job.
+			continue
+		}
+		rec.addStmt(o.stmt)
+		for _, di := range o.drops {
+			for _, idx := range di.IndexList {
+				rec.addDrop(*idx)
+			}
+		}
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		return recs[i].statements() < recs[j].statements()
+	})
+	return recs, nil
+}
+
+// addStmt adds the given statement to the statements that benefit from the
+// recommendation.
+func (r *recommendation) addStmt(stmt *statementStats) {
+	for _, s := range r.stmts {
+		if s == stmt {
+			return
+		}
+	}
+	r.stmts = append(r.stmts, stmt)
+}
+
+// addDrop adds the given index to the indexes dropped by the recommendation,
+// which makes it a replacement.
+func (r *recommendation) addDrop(idx tree.TableIndexName) {
+	for i := range r.drops {
+		if r.drops[i] == idx {
+			return
+		}
+	}
+	r.drops = append(r.drops, idx)
+	if r.create != nil {
+		r.action = ActionReplace
+	}
+}
+
+// covers returns true if the index ci covers the index other: both are on the
+// same table, the key columns of other are a prefix of the key columns of ci,
+// and the STORING columns of other are among the columns of ci.
+func covers(ci, other *tree.CreateIndex) bool {
+	if ci.Table != other.Table || len(other.Columns) > len(ci.Columns) {
+		return false
+	}
+	cols := make(map[tree.Name]struct{}, len(ci.Columns)+len(ci.Storing))
+	for i := range ci.Columns {
+		if i < len(other.Columns) && !sameIndexElem(&ci.Columns[i], &other.Columns[i]) {
+			return false
+		}
+		cols[ci.Columns[i].Column] = struct{}{}
+	}
+	for _, col := range ci.Storing {
+		cols[col] = struct{}{}
+	}
+	for _, col := range other.Storing {
+		if _, ok := cols[col]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func sameIndexElem(a, b *tree.IndexElem) bool {
+	dir := func(d tree.Direction) tree.Direction {
+		if d == tree.DefaultDirection {
+			return tree.Ascending
+		}
+		return d
+	}
+	return a.Column == b.Column && dir(a.Direction) == dir(b.Direction)
+}
+
+// rankRecommendations removes the create and replace recommendations that are
+// not estimated to improve the workload, since the optimizer would not use
+// their index, and sorts the remaining recommendations from the most to the
+// least beneficial. Recommendations with the same improvement are ordered by
+// their write amplification, and then by their statements.
+func rankRecommendations(recs []*recommendation) []*recommendation {
+	ranked := recs[:0]
+	for _, r := range recs {
+		if r.action == ActionDrop || r.improvement > 0 {
+			ranked = append(ranked, r)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.improvement != b.improvement {
+			return a.improvement > b.improvement
+		}
+		if a.writeAmplification != b.writeAmplification {
+			return a.writeAmplification < b.writeAmplification
+		}
+		return a.statements() < b.statements()
+	})
+	return ranked
+}
+
+// estimateWriteAmplification sets the write amplification of the
+// recommendation, given the number of executions of mutation statements on its
+// table. Every mutation writes to the created index, and no longer writes to
+// the dropped indexes.
+func (r *recommendation) estimateWriteAmplification(mutations int64) {
+	added := -len(r.drops)
+	if r.create != nil {
+		added++
+	}
+	r.writeAmplification = float64(mutations) * float64(added)
+	r.evidence.Mutations = mutations
+}
+
+// tableKey identifies a table in the map of mutation counts returned by
+// countMutations. Fingerprints usually refer to tables by their unqualified
+// names, so tables are identified by their database and name.
+type tableKey struct {
+	db    string
+	table tree.Name
+}
+
+// mutatedTable returns the table that is the target of the given mutation
+// statement fingerprint, or false if the fingerprint is not an INSERT, UPSERT,
+// UPDATE or DELETE statement on a table.
+func mutatedTable(db string, fingerprint string) (tableKey, bool) {
+	stmt, err := parser.ParseOne(fingerprint)
+	if err != nil {
+		return tableKey{}, false
+	}
+	var target tree.TableExpr
+	switch t := stmt.AST.(type) {
+	case *tree.Insert:
+		target = t.Table
+	case *tree.Update:
+		target = t.Table
+	case *tree.Delete:
+		target = t.Table
+	default:
+		return tableKey{}, false
+	}
+	if aliased, ok := target.(*tree.AliasedTableExpr); ok {
+		target = aliased.Expr
+	}
+	tn, ok := target.(*tree.TableName)
+	if !ok {
+		return tableKey{}, false
+	}
+	if tn.ExplicitCatalog {
+		db = string(tn.CatalogName)
+	}
+	return tableKey{db: db, table: tn.ObjectName}, true
+}
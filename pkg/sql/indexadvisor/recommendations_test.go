@@ -0,0 +1,123 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package indexadvisor
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRecommendations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	s1 := &statementStats{
+		fingerprint: "SELECT b FROM t WHERE a = _",
+		db:          "db",
+		indexRecs: []string{
+			"creation : CREATE INDEX ON db.public.t (a) STORING (b);",
+			"creation : CREATE INDEX ON db.public.t (a) STORING (b);",
+		},
+	}
+	s2 := &statementStats{
+		fingerprint: "SELECT * FROM t WHERE a = _ AND c = _",
+		db:          "db",
+		indexRecs: []string{
+			"replacement : CREATE INDEX ON db.public.t (a, c); DROP INDEX db.public.t@t_a_idx;",
+		},
+	}
+	s3 := &statementStats{
+		fingerprint: "SELECT * FROM u WHERE x = _",
+		db:          "db",
+		indexRecs: []string{
+			"creation : CREATE INDEX ON db.public.u (x);",
+			"alteration : ALTER INDEX db.public.u@u_x_idx VISIBLE;",
+		},
+	}
+
+	recs, err := buildRecommendations([]*statementStats{s1, s2, s3})
+	require.NoError(t, err)
+	require.Len(t, recs, 2)
+
+	require.Equal(t, ActionReplace, recs[0].action)
+	require.Equal(t,
+		"CREATE INDEX ON db.public.t (a, c) STORING (b); DROP INDEX db.public.t@t_a_idx;",
+		recs[0].statements(),
+	)
+	require.Equal(t, []*statementStats{s1, s2}, recs[0].stmts)
+
+	require.Equal(t, ActionCreate, recs[1].action)
+	require.Equal(t, "CREATE INDEX ON db.public.u (x);", recs[1].statements())
+	require.Equal(t, []*statementStats{s3}, recs[1].stmts)
+
+	_, err = buildRecommendations([]*statementStats{{indexRecs: []string{"foo"}}})
+	require.Error(t, err)
+}
+
+func TestRankRecommendations(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	create := func(table string, col tree.Name, improvement float64, mutations int64) *recommendation {
+		r := &recommendation{
+			action: ActionCreate,
+			create: &tree.CreateIndex{
+				Table:   tree.MakeTableNameWithSchema("db", "public", tree.Name(table)),
+				Columns: tree.IndexElemList{{Column: col}},
+			},
+			improvement: improvement,
+		}
+		r.estimateWriteAmplification(mutations)
+		return r
+	}
+	a := create("t", "a", 10, 100)
+	b := create("t", "b", 20, 100)
+	c := create("u", "c", 10, 5)
+	unused := create("u", "d", 0, 0)
+	drop := &recommendation{
+		action: ActionDrop,
+		drops: []tree.TableIndexName{{
+			Table: tree.MakeTableNameWithSchema("db", "public", "v"),
+			Index: "v_e_idx",
+		}},
+	}
+	drop.estimateWriteAmplification(50)
+
+	require.Equal(t, float64(100), a.writeAmplification)
+	require.Equal(t, float64(-50), drop.writeAmplification)
+	require.Equal(t, "DROP INDEX db.public.v@v_e_idx;", drop.statements())
+
+	ranked := rankRecommendations([]*recommendation{a, b, c, unused, drop})
+	require.Equal(t, []*recommendation{b, c, a, drop}, ranked)
+}
+
+func TestMutatedTable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		fingerprint string
+		expected    tableKey
+		ok          bool
+	}{
+		{"INSERT INTO t VALUES (_, _)", tableKey{db: "db", table: "t"}, true},
+		{"UPSERT INTO other.public.t (a) VALUES (_)", tableKey{db: "other", table: "t"}, true},
+		{"UPDATE t AS x SET a = _ WHERE b = _", tableKey{db: "db", table: "t"}, true},
+		{"DELETE FROM public.u WHERE a = _", tableKey{db: "db", table: "u"}, true},
+		{"SELECT * FROM t", tableKey{}, false},
+		{"INSERT INTO", tableKey{}, false},
+	} {
+		t.Run(tc.fingerprint, func(t *testing.T) {
+			key, ok := mutatedTable("db", tc.fingerprint)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, key)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+// Package indexadvisor implements the index advisor job. The job runs on a
+// schedule, merges the index recommendations of the statements in the
+// persisted statement statistics into workload recommendations, validates
+// them by re-optimizing the statements against hypothetical indexes, and
+// records the ranked recommendations in system.index_recommendations, where an
+// operator can approve or reject them.
+package indexadvisor
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+	"github.com/robfig/cron/v3"
+)
+
+// IndexAdvisorScheduleName is the name of the index advisor schedule.
+const IndexAdvisorScheduleName = "sql-index-advisor"
+
+// Enabled controls whether the index advisor job records index
+// recommendations.
+var Enabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.index_advisor.enabled",
+	"if enabled, the index advisor job periodically records validated workload "+
+		"index recommendations in system.index_recommendations",
+	true,
+	settings.WithPublic)
+
+// Recurrence is the cron-tab string specifying the recurrence of the index
+// advisor job.
+var Recurrence = settings.RegisterStringSetting(
+	settings.ApplicationLevel,
+	"sql.index_advisor.recurrence",
+	"cron-tab recurrence for the index advisor job",
+	"@daily", /* defaultValue */
+	settings.WithValidateString(func(_ *settings.Values, s string) error {
+		if _, err := cron.ParseStandard(s); err != nil {
+			return errors.Wrap(err, "invalid cron expression")
+		}
+		return nil
+	}),
+	settings.WithPublic,
+)
+
+// Lookback is the window of persisted statement statistics that is considered
+// by the index advisor job.
+var Lookback = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.index_advisor.lookback",
+	"the window of persisted statement statistics considered by the index advisor job",
+	7*24*time.Hour,
+	settings.PositiveDuration,
+)
+
+// MaxStatements is the maximum number of statement fingerprints whose index
+// recommendations are considered by the index advisor job.
+var MaxStatements = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.index_advisor.max_statements",
+	"the maximum number of statement fingerprints, by total service latency, "+
+		"whose index recommendations are considered by the index advisor job",
+	100,
+	settings.PositiveInt,
+)
+
+// ErrDuplicatedSchedules indicates that there is already a schedule for index
+// advisor jobs in the system.scheduled_jobs table.
+var ErrDuplicatedSchedules = errors.New("creating multiple index advisor schedules is disallowed")
+
+// CreateIndexAdvisorJobRecord creates a record for an index advisor job.
+func CreateIndexAdvisorJobRecord(createdByName string, createdByID int64) jobs.Record {
+	return jobs.Record{
+		Description: "SQL index advisor",
+		Username:    username.NodeUserName(),
+		Details:     jobspb.IndexAdvisorDetails{},
+		Progress:    jobspb.IndexAdvisorProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			ID:   createdByID,
+			Name: createdByName,
+		},
+	}
+}
+
+// CreateIndexAdvisorSchedule registers the index advisor job with the
+// scheduled job subsystem so that it runs periodically. This is done during
+// the upgrade that adds system.index_recommendations.
+func CreateIndexAdvisorSchedule(
+	ctx context.Context, txn isql.Txn, st *cluster.Settings, clusterID uuid.UUID,
+) (*jobs.ScheduledJob, error) {
+	id, err := GetIndexAdvisorScheduleID(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	if id != 0 {
+		return nil, ErrDuplicatedSchedules
+	}
+
+	scheduledJob := jobs.NewScheduledJob(scheduledjobs.ProdJobSchedulerEnv)
+
+	schedule := scheduledjobs.MaybeRewriteCronExpr(clusterID, Recurrence.Get(&st.SV))
+	if err := scheduledJob.SetSchedule(schedule); err != nil {
+		return nil, err
+	}
+
+	scheduledJob.SetScheduleDetails(jobspb.ScheduleDetails{
+		Wait:                   jobspb.ScheduleDetails_SKIP,
+		OnError:                jobspb.ScheduleDetails_RETRY_SCHED,
+		ClusterID:              clusterID,
+		CreationClusterVersion: st.Version.ActiveVersion(ctx),
+	})
+
+	scheduledJob.SetScheduleLabel(IndexAdvisorScheduleName)
+	scheduledJob.SetOwner(username.NodeUserName())
+
+	args, err := pbtypes.MarshalAny(&ScheduledIndexAdvisorExecutionArgs{})
+	if err != nil {
+		return nil, err
+	}
+	scheduledJob.SetExecutionDetails(
+		tree.ScheduledIndexAdvisorExecutor.InternalName(),
+		jobspb.ExecutionArguments{Args: args},
+	)
+
+	scheduledJob.SetScheduleStatus(string(jobs.StatusPending))
+	if err = jobs.ScheduledJobTxn(txn).Create(ctx, scheduledJob); err != nil {
+		return nil, err
+	}
+
+	return scheduledJob, nil
+}
+
+// GetIndexAdvisorScheduleID returns the ID of the index advisor schedule if it
+// exists, 0 if it does not exist yet.
+func GetIndexAdvisorScheduleID(ctx context.Context, txn isql.Txn) (id jobspb.ScheduleID, _ error) {
+	row, err := txn.QueryRowEx(
+		ctx,
+		"check-existing-index-advisor-schedule",
+		txn.KV(),
+		sessiondata.NodeUserSessionDataOverride,
+		`SELECT schedule_id FROM system.scheduled_jobs WHERE schedule_name = $1 ORDER BY schedule_id ASC LIMIT 1`,
+		IndexAdvisorScheduleName,
+	)
+	if err != nil || row == nil {
+		return 0, err
+	}
+	if len(row) != 1 {
+		return 0, errors.AssertionFailedf("unexpectedly received %d columns", len(row))
+	}
+	// Defensively check the type.
+	v, ok := tree.AsDInt(row[0])
+	if !ok {
+		return 0, errors.AssertionFailedf("unexpectedly received non-integer value %v", row[0])
+	}
+	return jobspb.ScheduleID(v), nil
+}
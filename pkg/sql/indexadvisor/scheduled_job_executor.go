@@ -0,0 +1,139 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package indexadvisor
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+)
+
+type indexAdvisorExecutor struct {
+	metrics indexAdvisorJobMetrics
+}
+
+var _ jobs.ScheduledJobController = (*indexAdvisorExecutor)(nil)
+var _ jobs.ScheduledJobExecutor = (*indexAdvisorExecutor)(nil)
+
+type indexAdvisorJobMetrics struct {
+	*jobs.ExecutorMetrics
+}
+
+var _ metric.Struct = &indexAdvisorJobMetrics{}
+
+// MetricStruct is part of the metric.Struct interface.
+func (m *indexAdvisorJobMetrics) MetricStruct() {}
+
+// OnDrop is part of the jobs.ScheduledJobController interface.
+func (s indexAdvisorExecutor) OnDrop(
+	ctx context.Context,
+	scheduleControllerEnv scheduledjobs.ScheduleControllerEnv,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+	txn isql.Txn,
+	descsCol *descs.Collection,
+) (int, error) {
+	return 0, errScheduleUndroppable
+}
+
+var errScheduleUndroppable = errors.New("SQL index advisor schedule cannot be dropped")
+
+// ExecuteJob is part of the jobs.ScheduledJobExecutor interface.
+func (s indexAdvisorExecutor) ExecuteJob(
+	ctx context.Context,
+	txn isql.Txn,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+) (err error) {
+	defer func() {
+		if err == nil {
+			s.metrics.NumStarted.Inc(1)
+		} else {
+			s.metrics.NumFailed.Inc(1)
+		}
+	}()
+	p, cleanup := cfg.PlanHookMaker(ctx, "invoke-index-advisor", txn.KV(), username.NodeUserName())
+	defer cleanup()
+	execCfg := p.(sql.PlanHookState).ExecCfg()
+
+	// The recurrence setting can change after the schedule was created. Pick up
+	// the new recurrence, which takes effect after this execution.
+	cronExpr := scheduledjobs.MaybeRewriteCronExpr(
+		sj.ScheduleDetails().ClusterID, Recurrence.Get(&execCfg.Settings.SV),
+	)
+	if sj.ScheduleExpr() != cronExpr {
+		if err := sj.SetSchedule(cronExpr); err != nil {
+			return err
+		}
+	}
+	if !Enabled.Get(&execCfg.Settings.SV) {
+		return nil
+	}
+
+	jr := execCfg.JobRegistry
+	r := CreateIndexAdvisorJobRecord(jobs.CreatedByScheduledJobs, int64(sj.ScheduleID()))
+	_, err = jr.CreateAdoptableJobWithTxn(ctx, r, jr.MakeJobID(), txn)
+	return err
+}
+
+// NotifyJobTermination is part of the jobs.ScheduledJobExecutor interface.
+func (s indexAdvisorExecutor) NotifyJobTermination(
+	ctx context.Context,
+	txn isql.Txn,
+	jobID jobspb.JobID,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+) error {
+	switch jobStatus {
+	case jobs.StatusFailed:
+		jobs.DefaultHandleFailedRun(sj, "SQL index advisor job failed")
+		s.metrics.NumFailed.Inc(1)
+		return nil
+	case jobs.StatusSucceeded:
+		s.metrics.NumSucceeded.Inc(1)
+	}
+	sj.SetScheduleStatus(string(jobStatus))
+	return nil
+}
+
+// Metrics is part of the jobs.ScheduledJobExecutor interface.
+func (s indexAdvisorExecutor) Metrics() metric.Struct {
+	return &s.metrics
+}
+
+// GetCreateScheduleStatement is part of the jobs.ScheduledJobExecutor interface.
+func (s indexAdvisorExecutor) GetCreateScheduleStatement(
+	ctx context.Context, txn isql.Txn, env scheduledjobs.JobSchedulerEnv, sj *jobs.ScheduledJob,
+) (string, error) {
+	// This schedule cannot be created manually.
+	return "", nil
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledIndexAdvisorExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledIndexAdvisorExecutor.InternalName())
+			return &indexAdvisorExecutor{
+				metrics: indexAdvisorJobMetrics{
+					ExecutorMetrics: &m,
+				},
+			}, nil
+		},
+	)
+}
@@ -952,6 +952,9 @@ func applyOverrides(o sessiondata.InternalExecutorOverride, sd *sessiondata.Sess
 	if o.OriginTimestampForLogicalDataReplication.IsSet() {
 		sd.OriginTimestampForLogicalDataReplication = o.OriginTimestampForLogicalDataReplication
 	}
+	if o.AllowMaterializedViewMutations {
+		sd.AllowMaterializedViewMutations = true
+	}
 	if o.PlanCacheMode != nil {
 		sd.PlanCacheMode = *o.PlanCacheMode
 	}
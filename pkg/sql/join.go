@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 )
 
@@ -29,6 +30,10 @@ type joinNode struct {
 
 	reqOrdering ReqOrdering
 
+	// rightEstimate, if set, is the estimated cardinality of the right input
+	// of a hash join, on which the hash table is built.
+	rightEstimate exec.CardinalityEstimate
+
 	// columns contains the metadata for the results of this node.
 	columns colinfo.ResultColumns
 }
@@ -815,6 +815,13 @@ func TestLogic_family(
 	runLogicTest(t, "family")
 }
 
+func TestLogic_family_per_column(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "family_per_column")
+}
+
 func TestLogic_fk(
 	t *testing.T,
 ) {
@@ -969,6 +976,20 @@ func TestLogic_impure(
 	runLogicTest(t, "impure")
 }
 
+func TestLogic_index_advisor(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "index_advisor")
+}
+
+func TestLogic_index_drop_recommendations(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "index_drop_recommendations")
+}
+
 func TestLogic_index_join(
 	t *testing.T,
 ) {
@@ -1172,6 +1193,13 @@ func TestLogic_materialized_view(
 	runLogicTest(t, "materialized_view")
 }
 
+func TestLogic_materialized_view_incremental(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "materialized_view_incremental")
+}
+
 func TestLogic_merge_join(
 	t *testing.T,
 ) {
@@ -1368,6 +1396,13 @@ func TestLogic_pgoidtype(
 	runLogicTest(t, "pgoidtype")
 }
 
+func TestLogic_plan_baselines(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "plan_baselines")
+}
+
 func TestLogic_plpgsql_builtins(
 	t *testing.T,
 ) {
@@ -1963,6 +1998,13 @@ func TestLogic_srfs(
 	runLogicTest(t, "srfs")
 }
 
+func TestLogic_statement_hints(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "statement_hints")
+}
+
 func TestLogic_statement_source(
 	t *testing.T,
 ) {
@@ -2201,6 +2243,13 @@ func TestLogic_udf(
 	runLogicTest(t, "udf")
 }
 
+func TestLogic_udf_aggregate(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_aggregate")
+}
+
 func TestLogic_udf_calling_udf(
 	t *testing.T,
 ) {
@@ -2327,6 +2376,13 @@ func TestLogic_udf_regressions(
 	runLogicTest(t, "udf_regressions")
 }
 
+func TestLogic_udf_returns_table(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_returns_table")
+}
+
 func TestLogic_udf_rewrite(
 	t *testing.T,
 ) {
@@ -2334,6 +2390,13 @@ func TestLogic_udf_rewrite(
 	runLogicTest(t, "udf_rewrite")
 }
 
+func TestLogic_udf_routine_options(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_routine_options")
+}
+
 func TestLogic_udf_schema_change(
 	t *testing.T,
 ) {
@@ -2390,6 +2453,13 @@ func TestLogic_udf_upsert(
 	runLogicTest(t, "udf_upsert")
 }
 
+func TestLogic_udf_variadic(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_variadic")
+}
+
 func TestLogic_union(
 	t *testing.T,
 ) {
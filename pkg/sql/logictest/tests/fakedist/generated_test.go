@@ -815,6 +815,13 @@ func TestLogic_family(
 	runLogicTest(t, "family")
 }
 
+func TestLogic_family_per_column(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "family_per_column")
+}
+
 func TestLogic_fk(
 	t *testing.T,
 ) {
@@ -976,6 +983,20 @@ func TestLogic_impure(
 	runLogicTest(t, "impure")
 }
 
+func TestLogic_index_advisor(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "index_advisor")
+}
+
+func TestLogic_index_drop_recommendations(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "index_drop_recommendations")
+}
+
 func TestLogic_index_join(
 	t *testing.T,
 ) {
@@ -1179,6 +1200,13 @@ func TestLogic_materialized_view(
 	runLogicTest(t, "materialized_view")
 }
 
+func TestLogic_materialized_view_incremental(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "materialized_view_incremental")
+}
+
 func TestLogic_merge_join(
 	t *testing.T,
 ) {
@@ -1186,6 +1214,13 @@ func TestLogic_merge_join(
 	runLogicTest(t, "merge_join")
 }
 
+func TestLogic_multi_column_histograms(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "multi_column_histograms")
+}
+
 func TestLogic_multi_statement(
 	t *testing.T,
 ) {
@@ -1382,6 +1417,13 @@ func TestLogic_pgoidtype(
 	runLogicTest(t, "pgoidtype")
 }
 
+func TestLogic_plan_baselines(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "plan_baselines")
+}
+
 func TestLogic_plpgsql_builtins(
 	t *testing.T,
 ) {
@@ -1977,6 +2019,13 @@ func TestLogic_srfs(
 	runLogicTest(t, "srfs")
 }
 
+func TestLogic_statement_hints(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "statement_hints")
+}
+
 func TestLogic_statement_source(
 	t *testing.T,
 ) {
@@ -2222,6 +2271,13 @@ func TestLogic_udf(
 	runLogicTest(t, "udf")
 }
 
+func TestLogic_udf_aggregate(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_aggregate")
+}
+
 func TestLogic_udf_calling_udf(
 	t *testing.T,
 ) {
@@ -2348,6 +2404,13 @@ func TestLogic_udf_regressions(
 	runLogicTest(t, "udf_regressions")
 }
 
+func TestLogic_udf_returns_table(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_returns_table")
+}
+
 func TestLogic_udf_rewrite(
 	t *testing.T,
 ) {
@@ -2355,6 +2418,13 @@ func TestLogic_udf_rewrite(
 	runLogicTest(t, "udf_rewrite")
 }
 
+func TestLogic_udf_routine_options(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_routine_options")
+}
+
 func TestLogic_udf_schema_change(
 	t *testing.T,
 ) {
@@ -2411,6 +2481,13 @@ func TestLogic_udf_upsert(
 	runLogicTest(t, "udf_upsert")
 }
 
+func TestLogic_udf_variadic(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runLogicTest(t, "udf_variadic")
+}
+
 func TestLogic_union(
 	t *testing.T,
 ) {
@@ -33,17 +33,26 @@ const (
 	T__pgvector  = oid.Oid(90007)
 )
 
+// OIDs in this block are defined by postgres, but are not present in
+// `github.com/lib/pq/oid`.
+const (
+	T_anycompatible      = oid.Oid(5077)
+	T_anycompatiblearray = oid.Oid(5078)
+)
+
 // ExtensionTypeName returns a mapping from extension oids
 // to their type name.
 var ExtensionTypeName = map[oid.Oid]string{
-	T_geometry:   "GEOMETRY",
-	T__geometry:  "_GEOMETRY",
-	T_geography:  "GEOGRAPHY",
-	T__geography: "_GEOGRAPHY",
-	T_box2d:      "BOX2D",
-	T__box2d:     "_BOX2D",
-	T_pgvector:   "VECTOR",
-	T__pgvector:  "_VECTOR",
+	T_geometry:           "GEOMETRY",
+	T__geometry:          "_GEOMETRY",
+	T_geography:          "GEOGRAPHY",
+	T__geography:         "_GEOGRAPHY",
+	T_box2d:              "BOX2D",
+	T__box2d:             "_BOX2D",
+	T_pgvector:           "VECTOR",
+	T__pgvector:          "_VECTOR",
+	T_anycompatible:      "ANYCOMPATIBLE",
+	T_anycompatiblearray: "ANYCOMPATIBLEARRAY",
 }
 
 // TypeName checks the name for a given type by first looking up oid.TypeName
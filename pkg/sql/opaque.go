@@ -161,6 +161,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		// it can't have placeholder arguments, and the execution can use the same
 		// logic as if it were a simple query. This matches the Postgres behavior.
 		return &zeroNode{}, nil
+	case *tree.CreateAggregate:
+		return p.CreateAggregate(ctx, n)
 	case *tree.CreateDatabase:
 		return p.CreateDatabase(ctx, n)
 	case *tree.CreateIndex:
@@ -175,6 +177,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.CreateRole(ctx, n)
 	case *tree.CreateSequence:
 		return p.CreateSequence(ctx, n)
+	case *tree.CreateStatementHint:
+		return p.CreateStatementHint(ctx, n)
 	case *tree.CreateExtension:
 		return p.CreateExtension(ctx, n)
 	case *tree.CreateExternalConnection:
@@ -203,6 +207,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.DropSchema(ctx, n)
 	case *tree.DropSequence:
 		return p.DropSequence(ctx, n)
+	case *tree.DropStatementHint:
+		return p.DropStatementHint(ctx, n)
 	case *tree.DropTable:
 		return p.DropTable(ctx, n)
 	case *tree.DropTenant:
@@ -239,6 +245,8 @@ func planOpaque(ctx context.Context, p *planner, stmt tree.Statement) (planNode,
 		return p.Revoke(ctx, n)
 	case *tree.RevokeRole:
 		return p.RevokeRole(ctx, n)
+	case *tree.RunGC:
+		return p.RunGC(ctx, n)
 	case *tree.Scatter:
 		return p.Scatter(ctx, n)
 	case *tree.Scrub:
@@ -348,6 +356,7 @@ func init() {
 		&tree.CommentOnConstraint{},
 		&tree.CommentOnTable{},
 		&tree.CopyTo{},
+		&tree.CreateAggregate{},
 		&tree.CreateDatabase{},
 		&tree.CreateExtension{},
 		&tree.CreateExternalConnection{},
@@ -355,6 +364,7 @@ func init() {
 		&tree.CreateIndex{},
 		&tree.CreateSchema{},
 		&tree.CreateSequence{},
+		&tree.CreateStatementHint{},
 		&tree.CreateTrigger{},
 		&tree.CreateType{},
 		&tree.CreateRole{},
@@ -370,6 +380,7 @@ func init() {
 		&tree.DropRole{},
 		&tree.DropSchema{},
 		&tree.DropSequence{},
+		&tree.DropStatementHint{},
 		&tree.DropTable{},
 		&tree.DropTenant{},
 		&tree.DropType{},
@@ -387,6 +398,7 @@ func init() {
 		&tree.ReparentDatabase{},
 		&tree.Revoke{},
 		&tree.RevokeRole{},
+		&tree.RunGC{},
 		&tree.Scatter{},
 		&tree.Scrub{},
 		&tree.SetClusterSetting{},
@@ -237,11 +237,47 @@ func HasRowLevelTriggers(
 // the order in which they should be executed.
 func GetRowLevelTriggers(
 	tab Table, actionTime tree.TriggerActionTime, eventsToMatch tree.TriggerEventTypeSet,
+) []Trigger {
+	return getTriggers(tab, true /* forEachRow */, actionTime, eventsToMatch)
+}
+
+// GetStatementLevelTriggers returns the set of statement-level triggers for the
+// given table and given trigger event type and timing. The triggers are
+// returned in the order in which they should be executed.
+func GetStatementLevelTriggers(
+	tab Table, actionTime tree.TriggerActionTime, eventsToMatch tree.TriggerEventTypeSet,
+) []Trigger {
+	return getTriggers(tab, false /* forEachRow */, actionTime, eventsToMatch)
+}
+
+// GetInsteadOfTriggers returns the set of INSTEAD OF triggers for the given
+// view and given trigger event type. INSTEAD OF triggers are always row-level.
+// The triggers are returned in the order in which they should be executed.
+func GetInsteadOfTriggers(view View, eventsToMatch tree.TriggerEventTypeSet) []Trigger {
+	return getTriggers(
+		view, true /* forEachRow */, tree.TriggerActionTimeInsteadOf, eventsToMatch,
+	)
+}
+
+// triggerSource is implemented by the data sources that can have triggers,
+// namely tables and views.
+type triggerSource interface {
+	TriggerCount() int
+	Trigger(i int) Trigger
+}
+
+// getTriggers returns the set of enabled triggers with the given level, timing,
+// and events, sorted in execution order.
+func getTriggers(
+	tab triggerSource,
+	forEachRow bool,
+	actionTime tree.TriggerActionTime,
+	eventsToMatch tree.TriggerEventTypeSet,
 ) []Trigger {
 	var neededTriggers intsets.Fast
 	for i := 0; i < tab.TriggerCount(); i++ {
 		trigger := tab.Trigger(i)
-		if !trigger.Enabled() || !trigger.ForEachRow() ||
+		if !trigger.Enabled() || trigger.ForEachRow() != forEachRow ||
 			trigger.ActionTime() != actionTime {
 			continue
 		}
@@ -112,6 +112,13 @@ type Builder struct {
 	// subqueries for statements inside a UDF.
 	planLazySubqueries bool
 
+	// allowRoutineOuterWithRefs is true when building the plan for a set of AFTER
+	// triggers, or for a routine nested within one. Trigger functions can
+	// reference the transition relations of the triggering statement, which are
+	// scanned from the buffered mutation input, so routines built in this
+	// context must be able to refer to outer With expressions.
+	allowRoutineOuterWithRefs bool
+
 	// tailCalls is used when building the last body statement of a routine. It
 	// identifies nested routines that are in tail-call position. This information
 	// is used to determine whether tail-call optimization is applicable.
@@ -121,6 +128,15 @@ type Builder struct {
 	// are planned as nested routines, and therefore it is useful to apply TCO.
 	tailCalls map[opt.ScalarExpr]struct{}
 
+	// BuildCardinalityEstimates is true if the builder should pass the
+	// estimated cardinality of the right input of hash joins to the factory,
+	// so that gross misestimates can be detected during execution.
+	BuildCardinalityEstimates bool
+
+	// cardinalityFeedbackKeys builds the keys of the estimates passed to the
+	// factory when BuildCardinalityEstimates is true.
+	cardinalityFeedbackKeys memo.CardinalityFeedbackKeyBuilder
+
 	// -- output --
 
 	// flags tracks various properties of the plan accumulated while building.
@@ -251,6 +267,7 @@ func New(
 		IsANSIDML:              isANSIDML,
 	}
 	b.colOrdsAlloc.Init(mem.Metadata().MaxColumn())
+	b.cardinalityFeedbackKeys.Init(ctx, mem)
 	if evalCtx != nil {
 		sd := evalCtx.SessionData()
 		if sd.SaveTablesPrefix != "" {
@@ -1110,7 +1110,17 @@ func (b *Builder) buildAfterTriggers(withID opt.WithID, triggers *memo.AfterTrig
 	if err != nil {
 		return err
 	}
-	b.triggers = append(b.triggers, tb.setupTriggers(triggers))
+	if len(triggers.Triggers) > 0 {
+		b.triggers = append(b.triggers,
+			tb.setupTriggers(triggers.Triggers, triggers.Builder, false /* statementLevel */),
+		)
+	}
+	if len(triggers.StatementTriggers) > 0 {
+		// Statement-level AFTER triggers fire after all row-level AFTER triggers.
+		b.triggers = append(b.triggers,
+			tb.setupTriggers(triggers.StatementTriggers, triggers.StatementBuilder, true /* statementLevel */),
+		)
+	}
 	return nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
@@ -164,19 +165,25 @@ func (cb *postQueryBuilder) setupCascade(cascade *memo.FKCascade) exec.PostQuery
 			allowAutoCommit bool,
 		) (exec.Plan, error) {
 			const actionName = "cascade"
+			const allowRoutineOuterWithRefs = false
 			return cb.planPostQuery(
 				ctx, semaCtx, evalCtx, execFactory, bufferRef, numBufferedRows, allowAutoCommit,
-				cascade.Builder, actionName,
+				cascade.Builder, actionName, allowRoutineOuterWithRefs,
 			)
 		},
 	}
 }
 
-// setupTriggers fills in an exec.PostQuery struct for the given triggers.
-func (cb *postQueryBuilder) setupTriggers(triggers *memo.AfterTriggers) exec.PostQuery {
+// setupTriggers fills in an exec.PostQuery struct for the given triggers. The
+// triggers are either all row-level or all statement-level, as indicated by
+// statementLevel.
+func (cb *postQueryBuilder) setupTriggers(
+	triggers []cat.Trigger, builder memo.PostQueryBuilder, statementLevel bool,
+) exec.PostQuery {
 	return exec.PostQuery{
-		Triggers: triggers.Triggers,
-		Buffer:   cb.mutationBuffer,
+		Triggers:       triggers,
+		StatementLevel: statementLevel,
+		Buffer:         cb.mutationBuffer,
 		PlanFn: func(
 			ctx context.Context,
 			semaCtx *tree.SemaContext,
@@ -187,9 +194,12 @@ func (cb *postQueryBuilder) setupTriggers(triggers *memo.AfterTriggers) exec.Pos
 			allowAutoCommit bool,
 		) (exec.Plan, error) {
 			const actionName = "trigger"
+			// Trigger functions may reference the transition relations of the
+			// triggering statement, which are scanned from the buffered input.
+			const allowRoutineOuterWithRefs = true
 			return cb.planPostQuery(
 				ctx, semaCtx, evalCtx, execFactory, bufferRef, numBufferedRows, allowAutoCommit,
-				triggers.Builder, actionName,
+				builder, actionName, allowRoutineOuterWithRefs,
 			)
 		},
 	}
@@ -211,6 +221,7 @@ func (cb *postQueryBuilder) planPostQuery(
 	allowAutoCommit bool,
 	builder memo.PostQueryBuilder,
 	actionName string,
+	allowRoutineOuterWithRefs bool,
 ) (exec.Plan, error) {
 	// 1. Set up a brand new memo in which to plan the cascading query.
 	var err error
@@ -317,6 +328,7 @@ func (cb *postQueryBuilder) planPostQuery(
 		// Set up the With binding.
 		eb.addBuiltWithExpr(postQueryInputWithID, bufferColMap, bufferRef)
 	}
+	eb.allowRoutineOuterWithRefs = allowRoutineOuterWithRefs
 	plan, err := eb.Build()
 	if err != nil {
 		return nil, errors.Wrapf(err, "while building %s plan", actionName)
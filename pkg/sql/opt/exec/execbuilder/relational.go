@@ -1435,6 +1435,15 @@ func (b *Builder) buildHashJoin(join memo.RelExpr) (_ execPlan, outputCols colOr
 	} else {
 		b.recordJoinAlgorithm(exec.HashJoin)
 	}
+	var rightEstimate exec.CardinalityEstimate
+	if b.BuildCardinalityEstimates && !isCrossJoin {
+		if key, ok := b.cardinalityFeedbackKeys.Key(rightExpr); ok {
+			rightEstimate = exec.CardinalityEstimate{
+				Key:      key,
+				RowCount: uint64(math.Ceil(rightExpr.Relational().Statistics().RowCount)),
+			}
+		}
+	}
 	var ep execPlan
 	ep.root, err = b.factory.ConstructHashJoin(
 		joinType,
@@ -1442,6 +1451,7 @@ func (b *Builder) buildHashJoin(join memo.RelExpr) (_ execPlan, outputCols colOr
 		leftEqOrdinals, rightEqOrdinals,
 		leftEqColsAreKey, rightEqColsAreKey,
 		onExpr,
+		rightEstimate,
 	)
 	if err != nil {
 		return execPlan{}, colOrdMap{}, err
@@ -1657,7 +1667,19 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (_ execPlan, outputCols col
 			agg = aggDistinct.Input
 		}
 
-		name, overload := memo.FindAggregateOverload(agg)
+		var name string
+		var distsqlBlocklist bool
+		var userDefined *exec.UserDefinedAggInfo
+		if udAgg, ok := agg.(*memo.UserDefinedAggExpr); ok {
+			// User-defined aggregates invoke routines, which are planned locally.
+			name = udAgg.Def.Name
+			distsqlBlocklist = true
+			userDefined = b.buildUserDefinedAggInfo(udAgg.Def)
+		} else {
+			var overload *tree.Overload
+			name, overload = memo.FindAggregateOverload(agg)
+			distsqlBlocklist = overload.DistsqlBlocklist
+		}
 
 		// Accumulate variable arguments in argCols and constant arguments in
 		// constArgs. Constant arguments must follow variable arguments.
@@ -1691,7 +1713,8 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (_ execPlan, outputCols col
 			ArgCols:          argCols[:len(argCols):len(argCols)],
 			ConstArgs:        constArgs[:len(constArgs):len(constArgs)],
 			Filter:           filterOrd,
-			DistsqlBlocklist: overload.DistsqlBlocklist,
+			DistsqlBlocklist: distsqlBlocklist,
+			UserDefined:      userDefined,
 		}
 		outputCols.Set(item.Col, len(groupingColIdx)+i)
 		// Slice argCols and constArgs so the rest of their capacity can be
@@ -3409,7 +3432,10 @@ func (b *Builder) buildCall(c *memo.CallExpr) (_ execPlan, outputCols colOrdMap,
 		false, /* blockStart */
 		nil,   /* blockState */
 		nil,   /* cursorDeclaration */
+		nil,   /* resultBuffer */
+		nil,   /* appendToResultBuffer */
 	)
+	r.SessionVars = udf.Def.SessionVars
 
 	var ep execPlan
 	ep.root, err = b.factory.ConstructCall(r)
@@ -3593,15 +3619,28 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (_ execPlan, outputCols colOrd
 	filterIdxs := make([]int, len(w.Windows))
 	exprs := make([]*tree.FuncExpr, len(w.Windows))
 	windowVals := make([]tree.WindowDef, len(w.Windows))
+	var userDefined []*exec.UserDefinedAggInfo
 
 	for i := range w.Windows {
 		item := &w.Windows[i]
 		fn := b.extractWindowFunction(item.Function)
-		name, overload := memo.FindWindowOverload(fn)
-		if !b.disableTelemetry {
-			telemetry.Inc(sqltelemetry.WindowFunctionCounter(name))
+		var name string
+		var overload *tree.Overload
+		var props *tree.FunctionProperties
+		udAgg, isUserDefined := fn.(*memo.UserDefinedAggExpr)
+		if isUserDefined {
+			name = udAgg.Def.Name
+			if userDefined == nil {
+				userDefined = make([]*exec.UserDefinedAggInfo, len(w.Windows))
+			}
+			userDefined[i] = b.buildUserDefinedAggInfo(udAgg.Def)
+		} else {
+			name, overload = memo.FindWindowOverload(fn)
+			if !b.disableTelemetry {
+				telemetry.Inc(sqltelemetry.WindowFunctionCounter(name))
+			}
+			props, _ = builtinsregistry.GetBuiltinProperties(name)
 		}
-		props, _ := builtinsregistry.GetBuiltinProperties(name)
 
 		args := make([]tree.TypedExpr, fn.ChildCount())
 		argIdxs[i] = make([]exec.NodeColumnOrdinal, fn.ChildCount())
@@ -3644,6 +3683,22 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (_ execPlan, outputCols colOrd
 			OrderBy:    orderingExprs,
 			Frame:      frame,
 		}
+		if isUserDefined {
+			// The name of a user-defined aggregate is only used for display, so
+			// it is not resolved.
+			unresolved := tree.MakeUnresolvedName(name)
+			exprs[i] = tree.NewTypedFuncExpr(
+				tree.ResolvableFunctionReference{FunctionReference: &unresolved},
+				0,
+				args,
+				builtFilter,
+				&windowVals[i],
+				udAgg.Def.Typ,
+				nil, /* props */
+				nil, /* overload */
+			)
+			continue
+		}
 		wrappedFn, err := b.wrapBuiltinFunction(name)
 		if err != nil {
 			return execPlan{}, colOrdMap{}, err
@@ -3693,13 +3748,14 @@ func (b *Builder) buildWindow(w *memo.WindowExpr) (_ execPlan, outputCols colOrd
 	}
 	var ep execPlan
 	ep.root, err = b.factory.ConstructWindow(input.root, exec.WindowInfo{
-		Cols:       resultCols,
-		Exprs:      exprs,
-		OutputIdxs: outputIdxs,
-		ArgIdxs:    argIdxs,
-		FilterIdxs: filterIdxs,
-		Partition:  partitionIdxs,
-		Ordering:   sqlOrdering,
+		Cols:        resultCols,
+		Exprs:       exprs,
+		OutputIdxs:  outputIdxs,
+		ArgIdxs:     argIdxs,
+		FilterIdxs:  filterIdxs,
+		Partition:   partitionIdxs,
+		Ordering:    sqlOrdering,
+		UserDefined: userDefined,
 	})
 	if err != nil {
 		return execPlan{}, colOrdMap{}, err
@@ -711,6 +711,8 @@ func (b *Builder) buildExistsSubquery(
 				false, /* blockStart */
 				nil,   /* blockState */
 				nil,   /* cursorDeclaration */
+				nil,   /* resultBuffer */
+				nil,   /* appendToResultBuffer */
 			),
 			tree.DBoolFalse,
 		}, types.Bool), nil
@@ -834,6 +836,8 @@ func (b *Builder) buildSubquery(
 			false, /* blockStart */
 			nil,   /* blockState */
 			nil,   /* cursorDeclaration */
+			nil,   /* resultBuffer */
+			nil,   /* appendToResultBuffer */
 		), nil
 	}
 
@@ -860,6 +864,7 @@ func (b *Builder) buildSubquery(
 			eb.withExprs = withExprs
 			eb.disableTelemetry = true
 			eb.planLazySubqueries = true
+			eb.allowRoutineOuterWithRefs = b.allowRoutineOuterWithRefs
 			eb.tailCalls = tailCalls
 			ePlan, _, err := eb.buildRelational(input)
 			if err != nil {
@@ -903,6 +908,8 @@ func (b *Builder) buildSubquery(
 			false, /* blockStart */
 			nil,   /* blockState */
 			nil,   /* cursorDeclaration */
+			nil,   /* resultBuffer */
+			nil,   /* appendToResultBuffer */
 		), nil
 	}
 
@@ -980,16 +987,23 @@ func (b *Builder) buildUDF(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.Typ
 			"expected more than one body statement for a routine that opens a cursor",
 		))
 	}
+	if udf.Def.AppendToResultBuffer != nil && len(udf.Def.Body) <= 1 {
+		panic(errors.AssertionFailedf(
+			"expected more than one body statement for a routine that adds to a result buffer",
+		))
+	}
 
 	// Create a tree.RoutinePlanFn that can plan the statements in the UDF body.
+	// Routines invoked by AFTER triggers may reference transition relations, so
+	// they are allowed to refer to outer With expressions.
 	// TODO(mgartner): Add support for WITH expressions inside UDF bodies.
 	planGen := b.buildRoutinePlanGenerator(
 		udf.Def.Params,
 		udf.Def.Body,
 		udf.Def.BodyProps,
 		udf.Def.BodyStmts,
-		false, /* allowOuterWithRefs */
-		nil,   /* wrapRootExpr */
+		b.allowRoutineOuterWithRefs,
+		nil, /* wrapRootExpr */
 	)
 
 	// Enable stepping for volatile functions so that statements within the UDF
@@ -1001,7 +1015,7 @@ func (b *Builder) buildUDF(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.Typ
 	// routine is in tail-call position.
 	_, tailCall := b.tailCalls[udf]
 
-	return tree.NewTypedRoutineExpr(
+	routine := tree.NewTypedRoutineExpr(
 		udf.Def.Name,
 		args,
 		planGen,
@@ -1016,7 +1030,14 @@ func (b *Builder) buildUDF(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.Typ
 		udf.Def.BlockStart,
 		blockState,
 		udf.Def.CursorDeclaration,
-	), nil
+		udf.Def.ResultBuffer,
+		udf.Def.AppendToResultBuffer,
+	)
+	if udf.Def.TxnControlResume != nil {
+		routine.ResumeAfterTxnControl = b.buildTxnControlResume(udf)
+	}
+	routine.SessionVars = udf.Def.SessionVars
+	return routine, nil
 }
 
 func (b *Builder) buildRoutineArgs(
@@ -1053,8 +1074,8 @@ func (b *Builder) initRoutineExceptionHandler(
 			action.Body,
 			action.BodyProps,
 			action.BodyStmts,
-			false, /* allowOuterWithRefs */
-			nil,   /* wrapRootExpr */
+			b.allowRoutineOuterWithRefs,
+			nil, /* wrapRootExpr */
 		)
 		// Build a routine with no arguments for the exception handler. The actual
 		// arguments will be supplied when (if) the handler is invoked.
@@ -1073,11 +1094,60 @@ func (b *Builder) initRoutineExceptionHandler(
 			false, /* blockStart */
 			nil,   /* blockState */
 			nil,   /* cursorDeclaration */
+			nil,   /* resultBuffer */
+			nil,   /* appendToResultBuffer */
 		)
 	}
 	blockState.ExceptionHandler = exceptionHandler
 }
 
+// buildUserDefinedAggInfo builds the routines that evaluate the given
+// user-defined aggregate. The routines are built with no arguments; the
+// aggregate state and the arguments of each input row are supplied when the
+// routines are invoked by the aggregator or windower.
+func (b *Builder) buildUserDefinedAggInfo(
+	def *memo.UDFAggregateDefinition,
+) *exec.UserDefinedAggInfo {
+	info := &exec.UserDefinedAggInfo{
+		Transition:       b.buildUserDefinedAggRoutine(def.Transition),
+		InitCond:         def.InitCond,
+		StrictTransition: def.StrictTransition,
+	}
+	if def.Final != nil {
+		info.Final = b.buildUserDefinedAggRoutine(def.Final)
+	}
+	return info
+}
+
+func (b *Builder) buildUserDefinedAggRoutine(def *memo.UDFDefinition) *tree.RoutineExpr {
+	planGen := b.buildRoutinePlanGenerator(
+		def.Params,
+		def.Body,
+		def.BodyProps,
+		def.BodyStmts,
+		false, /* allowOuterWithRefs */
+		nil,   /* wrapRootExpr */
+	)
+	return tree.NewTypedRoutineExpr(
+		def.Name,
+		nil, /* args */
+		planGen,
+		def.Typ,
+		def.Volatility == volatility.Volatile, /* enableStepping */
+		def.CalledOnNullInput,
+		false, /* multiColOutput */
+		false, /* generator */
+		false, /* tailCall */
+		false, /* procedure */
+		false, /* triggerFunc */
+		false, /* blockStart */
+		nil,   /* blockState */
+		nil,   /* cursorDeclaration */
+		nil,   /* resultBuffer */
+		nil,   /* appendToResultBuffer */
+	)
+}
+
 type wrapRootExprFn func(f *norm.Factory, e memo.RelExpr) opt.Expr
 
 // buildRoutinePlanGenerator returns a tree.RoutinePlanFn that can plan the
@@ -1225,6 +1295,7 @@ func (b *Builder) buildRoutinePlanGenerator(
 			eb.withExprs = withExprs
 			eb.disableTelemetry = true
 			eb.planLazySubqueries = true
+			eb.allowRoutineOuterWithRefs = b.allowRoutineOuterWithRefs
 			eb.tailCalls = tailCalls
 			plan, err := eb.Build()
 			if err != nil {
@@ -1273,27 +1344,7 @@ func (b *Builder) buildTxnControl(
 	gen := func(
 		ctx context.Context, evalArgs tree.Datums,
 	) (con tree.StoredProcContinuation, err error) {
-		defer func() {
-			if r := recover(); r != nil {
-				// This code allows us to propagate internal errors without
-				// having to add error checks everywhere throughout the code.
-				// This is only possible because the code does not update shared
-				// state and does not manipulate locks.
-				//
-				// This is the same panic-catching logic that exists in
-				// o.Optimize() below. It's required here because it's possible
-				// for factory functions to panic below, like
-				// CopyAndReplaceDefault.
-				if ok, e := errorutil.ShouldCatch(r); ok {
-					err = e
-					log.VEventf(ctx, 1, "%v", err)
-				} else {
-					// Other panic objects can't be considered "safe" and thus
-					// are propagated as crashes that terminate the session.
-					panic(r)
-				}
-			}
-		}()
+		defer catchTxnControlPlanPanic(ctx, &err)
 		// Build the plan for the "continuation" procedure that will resume
 		// execution of the parent stored procedure in a new transaction.
 		var f norm.Factory
@@ -1311,6 +1362,73 @@ func (b *Builder) buildTxnControl(
 		return f.DetachMemo(), nil
 	}
 	return tree.NewTxnControlExpr(
-		txnExpr.TxnOp, txnExpr.TxnModes, args, gen, txnExpr.Def.Name, txnExpr.Def.Typ,
+		txnExpr.TxnOp, txnExpr.TxnModes, txnExpr.Chain, args, gen, txnExpr.Def.Name, txnExpr.Def.Typ,
 	), nil
 }
+
+// buildTxnControlResume returns a generator for the plan that resumes execution
+// of a stored procedure after a nested CALL statement paused in order to COMMIT
+// or ROLLBACK. The plan invokes the given continuation routine with the
+// evaluated arguments, except for the last argument, which is replaced by the
+// plan that resumes execution of the nested procedure. This ensures that the
+// nested procedure finishes execution before the calling procedure continues.
+func (b *Builder) buildTxnControlResume(udf *memo.UDFCallExpr) tree.StoredProcResumeGenerator {
+	resume := udf.Def.TxnControlResume
+	return func(
+		ctx context.Context, evalArgs tree.Datums, nested tree.StoredProcContinuation,
+	) (con tree.StoredProcContinuation, err error) {
+		defer catchTxnControlPlanPanic(ctx, &err)
+		nestedMemo := nested.(*memo.Memo)
+		nestedRoot, ok := nestedMemo.RootExpr().(*memo.CallExpr)
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				"expected CALL expression for nested continuation, found %T", nestedMemo.RootExpr(),
+			)
+		}
+		if len(evalArgs) == 0 {
+			return nil, errors.AssertionFailedf("expected argument for the result of nested CALL")
+		}
+		var f norm.Factory
+		f.Init(ctx, b.evalCtx, b.catalog)
+		var replaceFn norm.ReplaceFunc
+		replaceFn = func(e opt.Expr) opt.Expr {
+			if e != nestedRoot {
+				return f.CopyAndReplaceDefault(e, replaceFn)
+			}
+			// Use the evaluated arguments to construct the continuation procedure,
+			// with the nested continuation procedure supplying the last argument.
+			memoArgs := make(memo.ScalarListExpr, len(evalArgs))
+			for i := range evalArgs[:len(evalArgs)-1] {
+				memoArgs[i] = f.ConstructConstVal(evalArgs[i], evalArgs[i].ResolvedType())
+			}
+			memoArgs[len(memoArgs)-1] = f.CopyAndReplaceDefault(nestedRoot.Proc, replaceFn).(opt.ScalarExpr)
+			continuationProc := f.ConstructUDFCall(memoArgs, &memo.UDFCallPrivate{Def: udf.Def})
+			return f.ConstructCall(continuationProc, &memo.CallPrivate{Columns: resume.OutCols})
+		}
+		f.CopyAndReplace(nestedRoot, resume.Props, replaceFn)
+		return f.DetachMemo(), nil
+	}
+}
+
+// catchTxnControlPlanPanic is used when building the plan for a continuation
+// stored procedure to convert panics into errors.
+func catchTxnControlPlanPanic(ctx context.Context, err *error) {
+	if r := recover(); r != nil {
+		// This code allows us to propagate internal errors without
+		// having to add error checks everywhere throughout the code.
+		// This is only possible because the code does not update shared
+		// state and does not manipulate locks.
+		//
+		// This is the same panic-catching logic that exists in
+		// o.Optimize(). It's required here because it's possible for
+		// factory functions to panic, like CopyAndReplaceDefault.
+		if ok, e := errorutil.ShouldCatch(r); ok {
+			*err = e
+			log.VEventf(ctx, 1, "%v", *err)
+		} else {
+			// Other panic objects can't be considered "safe" and thus
+			// are propagated as crashes that terminate the session.
+			panic(r)
+		}
+	}
+}
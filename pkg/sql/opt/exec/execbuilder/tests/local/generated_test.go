@@ -85,6 +85,13 @@ func TestLogic_tmp(t *testing.T) {
 	logictest.RunLogicTests(t, serverArgs, configIdx, glob)
 }
 
+func TestExecBuild_adaptive_replanning(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runExecBuildLogicTest(t, "adaptive_replanning")
+}
+
 func TestExecBuild_aggregate(
 	t *testing.T,
 ) {
@@ -232,6 +239,13 @@ func TestExecBuild_expression_index(
 	runExecBuildLogicTest(t, "expression_index")
 }
 
+func TestExecBuild_family_per_column(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runExecBuildLogicTest(t, "family_per_column")
+}
+
 func TestExecBuild_fk(
 	t *testing.T,
 ) {
@@ -484,6 +498,13 @@ func TestExecBuild_scalar(
 	runExecBuildLogicTest(t, "scalar")
 }
 
+func TestExecBuild_scan_histogram_split(
+	t *testing.T,
+) {
+	defer leaktest.AfterTest(t)()
+	runExecBuildLogicTest(t, "scan_histogram_split")
+}
+
 func TestExecBuild_schema_change_in_txn(
 	t *testing.T,
 ) {
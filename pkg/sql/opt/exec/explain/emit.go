@@ -199,6 +199,9 @@ func emitInternal(
 		for _, trigger := range afterTriggers.Triggers {
 			ob.Attr("trigger", trigger.Name())
 		}
+		if afterTriggers.StatementLevel {
+			ob.Attr("level", "statement")
+		}
 		// Only allow new plans to be built for AFTER triggers if the transaction is
 		// still open. This is necessary because the transaction might have been
 		// auto-committed by the time we are emitting the plan (see #135157).
@@ -231,6 +231,107 @@ func DecodePlanGistToPlan(s string, cat cat.Catalog) (plan *Plan, retErr error)
 	return plan, nil
 }
 
+// PlanGistShape summarizes the indexes and join algorithms used by the plan
+// encoded in a gist.
+type PlanGistShape struct {
+	// Indexes maps the ID of each table accessed by the plan to the IDs of the
+	// indexes used to access it.
+	Indexes map[cat.StableID][]cat.StableID
+	// The following fields are set if the plan contains a join that uses the
+	// respective algorithm.
+	HashJoin     bool
+	MergeJoin    bool
+	LookupJoin   bool
+	InvertedJoin bool
+	ZigzagJoin   bool
+}
+
+// DecodePlanGistToShape decodes a gist and summarizes the indexes and join
+// algorithms used by the plan. Tables and indexes that no longer exist in the
+// catalog are omitted.
+func DecodePlanGistToShape(gist string, catalog cat.Catalog) (_ PlanGistShape, retErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			// This code allows us to propagate internal errors without having
+			// to add error checks everywhere throughout the code. This is only
+			// possible because the code does not update shared state and does
+			// not manipulate locks.
+			if ok, e := errorutil.ShouldCatch(r); ok {
+				retErr = e
+			} else {
+				panic(r)
+			}
+		}
+	}()
+
+	plan, err := DecodePlanGistToPlan(gist, catalog)
+	if err != nil {
+		return PlanGistShape{}, err
+	}
+	shape := PlanGistShape{Indexes: make(map[cat.StableID][]cat.StableID)}
+	addIndex := func(tab cat.Table, idx cat.Index) {
+		if _, ok := tab.(*unknownTable); ok || tab == nil {
+			return
+		}
+		if _, ok := idx.(*unknownIndex); ok || idx == nil {
+			return
+		}
+		tabID, idxID := tab.ID(), idx.ID()
+		for _, id := range shape.Indexes[tabID] {
+			if id == idxID {
+				return
+			}
+		}
+		shape.Indexes[tabID] = append(shape.Indexes[tabID], idxID)
+	}
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n == nil {
+			return
+		}
+		switch n.op {
+		case scanOp:
+			a := n.args.(*scanArgs)
+			addIndex(a.Table, a.Index)
+		case indexJoinOp:
+			a := n.args.(*indexJoinArgs)
+			if _, ok := a.Table.(*unknownTable); !ok && a.Table != nil {
+				addIndex(a.Table, a.Table.Index(cat.PrimaryIndex))
+			}
+		case hashJoinOp:
+			shape.HashJoin = true
+		case mergeJoinOp:
+			shape.MergeJoin = true
+		case lookupJoinOp:
+			a := n.args.(*lookupJoinArgs)
+			shape.LookupJoin = true
+			addIndex(a.Table, a.Index)
+		case invertedJoinOp:
+			a := n.args.(*invertedJoinArgs)
+			shape.InvertedJoin = true
+			addIndex(a.Table, a.Index)
+		case zigzagJoinOp:
+			a := n.args.(*zigzagJoinArgs)
+			shape.ZigzagJoin = true
+			addIndex(a.LeftTable, a.LeftIndex)
+			addIndex(a.RightTable, a.RightIndex)
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(plan.Root)
+	for i := range plan.Subqueries {
+		if n, ok := plan.Subqueries[i].Root.(*Node); ok {
+			walk(n)
+		}
+	}
+	for _, n := range plan.Checks {
+		walk(n)
+	}
+	return shape, nil
+}
+
 func (f *PlanGistFactory) decodeOp() execOperator {
 	val, err := f.buffer.ReadByte()
 	if err != nil || val == 0 {
@@ -130,6 +130,19 @@ type ScanParams struct {
 	LocalityOptimized bool
 }
 
+// CardinalityEstimate is the estimated number of rows produced by the input of
+// an operator that can detect gross misestimates during execution.
+type CardinalityEstimate struct {
+	// Key identifies the relational expression for which the estimate was
+	// made. See memo.CardinalityFeedbackKeyBuilder. It is empty if the
+	// expression has no stable key, in which case misestimates are not
+	// detected.
+	Key string
+
+	// RowCount is the estimated number of rows, rounded up.
+	RowCount uint64
+}
+
 // OutputOrdering indicates the required output ordering on a Node that is being
 // created. It refers to the output columns of the node by ordinal.
 //
@@ -214,6 +227,32 @@ type AggInfo struct {
 	// DistsqlBlocklist is set to true when this aggregate function cannot be
 	// evaluated in distributed fashion.
 	DistsqlBlocklist bool
+
+	// UserDefined is set if the aggregate was created with CREATE AGGREGATE. Its
+	// only argument is a tuple of the arguments of the aggregate.
+	UserDefined *UserDefinedAggInfo
+}
+
+// UserDefinedAggInfo represents the information about a user-defined aggregate
+// that must be passed through to the execution engine.
+type UserDefinedAggInfo struct {
+	// Transition is invoked with the current state followed by the arguments of
+	// the aggregate for each input row, and returns the next state.
+	Transition *tree.RoutineExpr
+
+	// Final, if set, is invoked with the state once all rows have been added,
+	// and returns the result of the aggregate. If unset, the state is the
+	// result.
+	Final *tree.RoutineExpr
+
+	// InitCond, if set, is the initial value of the state. If unset, the state
+	// starts out NULL.
+	InitCond tree.TypedExpr
+
+	// StrictTransition is true if Transition is not invoked for rows with NULL
+	// arguments, and if the first row with no NULL arguments initializes a NULL
+	// state instead.
+	StrictTransition bool
 }
 
 // WindowInfo represents the information about a window function that must be
@@ -243,6 +282,11 @@ type WindowInfo struct {
 
 	// Ordering is the set of input columns to order on.
 	Ordering colinfo.ColumnOrdering
+
+	// UserDefined is the information about each window function that is a
+	// user-defined aggregate, in the same order as Exprs. It is nil if there are
+	// no such functions, and the entries for builtin window functions are nil.
+	UserDefined []*UserDefinedAggInfo
 }
 
 // ExplainEnvData represents the data that's going to be displayed in EXPLAIN (env).
@@ -288,6 +332,11 @@ type PostQuery struct {
 	// PostQuery describes a foreign-key cascade action.
 	Triggers []cat.Trigger
 
+	// StatementLevel is set if this PostQuery describes a set of statement-level
+	// AFTER triggers. Unlike row-level triggers and cascades, these must be
+	// executed even if the mutation did not modify any rows.
+	StatementLevel bool
+
 	// Buffer is the Node returned by ConstructBuffer which stores the input to
 	// the mutation. It is nil if the cascade does not require a buffer.
 	Buffer Node
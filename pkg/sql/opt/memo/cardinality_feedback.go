@@ -0,0 +1,172 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package memo
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+)
+
+// cardinalityFeedbackFmtFlags are the flags used to format the operators and
+// scalar expressions that make up the fingerprint of a memo group. They show
+// constant values, so that the same statement executed with different
+// constants does not share observed row counts.
+const cardinalityFeedbackFmtFlags = ExprFmtHideAll
+
+// CardinalityFeedbackKeyBuilder builds the keys under which the row counts
+// observed when executing relational expressions are recorded, so that they
+// can be used in place of the estimates the next time the same statement is
+// planned.
+//
+// A key is made up of the output columns of the expression, its estimated row
+// count (before any feedback is applied) and a fingerprint of the memo group
+// of the expression. The fingerprint is computed from the normalized
+// expression of the group, i.e., its first expression, including its
+// operator, private, scalar children and the fingerprints of its relational
+// children. Since all of these are deterministic for a given statement, the
+// key identifies the same memo group across plannings of the statement, while
+// different groups with the same output columns and estimate (e.g., a Select
+// and its input) have different keys. Including the estimate makes the key
+// stop matching once new table statistics change it.
+//
+// The fingerprint of each group is memoized, so a CardinalityFeedbackKeyBuilder
+// should only be used with a single memo.
+type CardinalityFeedbackKeyBuilder struct {
+	ctx          context.Context
+	mem          *Memo
+	fingerprints map[RelExpr]uint64
+}
+
+// Init initializes a CardinalityFeedbackKeyBuilder for the given memo.
+func (kb *CardinalityFeedbackKeyBuilder) Init(ctx context.Context, mem *Memo) {
+	// This initialization pattern ensures that fields are not unwittingly
+	// reused. Field reuse must be explicit.
+	*kb = CardinalityFeedbackKeyBuilder{
+		ctx: ctx,
+		mem: mem,
+	}
+}
+
+// Key returns the key of the given relational expression. It returns false if
+// the expression has no stable key, which is the case if it contains
+// placeholders: a generic plan is shared by all placeholder values, and so
+// must not be planned with the row count observed for any one of them.
+func (kb *CardinalityFeedbackKeyBuilder) Key(e RelExpr) (key string, ok bool) {
+	relProps := e.Relational()
+	if relProps.HasPlaceholder {
+		return "", false
+	}
+	s := relProps.Statistics()
+	estimate := s.RowCount
+	if s.RowCountBeforeFeedback != 0 {
+		estimate = s.RowCountBeforeFeedback
+	}
+	return kb.key(relProps.OutputCols, estimate, e.FirstExpr()), true
+}
+
+// key returns the key of the given expression, which must be the first
+// expression in its group.
+func (kb *CardinalityFeedbackKeyBuilder) key(
+	cols opt.ColSet, estimatedRowCount float64, e RelExpr,
+) string {
+	prefix := cardinalityFeedbackKeyPrefix(cols, estimatedRowCount)
+	return fmt.Sprintf("%s/%x", prefix, kb.fingerprint(e))
+}
+
+// cardinalityFeedbackKeyPrefix returns the part of a key that can be computed
+// cheaply from the logical properties of an expression. It is used to skip
+// fingerprinting groups that cannot have any feedback.
+func cardinalityFeedbackKeyPrefix(cols opt.ColSet, estimatedRowCount float64) string {
+	return fmt.Sprintf("%s/%.0f", cols, estimatedRowCount)
+}
+
+// fingerprint returns the fingerprint of the memo group of the given
+// expression, which must be the first expression in its group. It does not
+// depend on the physical properties required of the group, since those are not
+// known when the statistics of the group are built.
+func (kb *CardinalityFeedbackKeyBuilder) fingerprint(e RelExpr) uint64 {
+	if fp, ok := kb.fingerprints[e]; ok {
+		return fp
+	}
+	f := MakeExprFmtCtx(
+		kb.ctx, cardinalityFeedbackFmtFlags, false /* redactableValues */, kb.mem, nil, /* catalog */
+	)
+	fmt.Fprintf(f.Buffer, "%v", e.Op())
+	// Only include the privates that are formatted deterministically; the
+	// others are formatted with %v, which may include pointers.
+	switch e.Private().(type) {
+	case *ScanPrivate, *SequenceSelectPrivate, *MutationPrivate, *LockPrivate, *OrdinalityPrivate,
+		*GroupingPrivate, *SetPrivate, *IndexJoinPrivate, *InvertedFilterPrivate, *LookupJoinPrivate,
+		*InvertedJoinPrivate, *ValuesPrivate, *ZigzagJoinPrivate, *MergeJoinPrivate, *WindowPrivate:
+		FormatPrivate(&f, e.Private(), physical.MinRequired)
+	}
+	fmt.Fprintf(f.Buffer, " %s", e.Relational().OutputCols)
+	for i, n := 0, e.ChildCount(); i < n; i++ {
+		switch t := e.Child(i).(type) {
+		case RelExpr:
+			fmt.Fprintf(f.Buffer, " %x", kb.fingerprint(t))
+		case opt.ScalarExpr:
+			f.Buffer.WriteByte(' ')
+			f.Buffer.WriteString(FormatExpr(
+				kb.ctx, t, cardinalityFeedbackFmtFlags, false /* redactableValues */, kb.mem, nil, /* catalog */
+			))
+		}
+	}
+	h := fnv.New64a()
+	_, _ = h.Write(f.Buffer.Bytes())
+	fp := h.Sum64()
+	if kb.fingerprints == nil {
+		kb.fingerprints = make(map[RelExpr]uint64)
+	}
+	kb.fingerprints[e] = fp
+	return fp
+}
+
+// SetCardinalityFeedback sets the row counts observed during previous
+// executions of the statement, keyed by the keys built by
+// CardinalityFeedbackKeyBuilder. When the statistics of a relational
+// expression are built, its estimated row count is replaced by the observed
+// one, if any. It must be called after Init and before any expressions are
+// added to the memo.
+func (m *Memo) SetCardinalityFeedback(feedback map[string]float64) {
+	m.cardinalityFeedback = feedback
+	sb := &m.logPropsBuilder.sb
+	sb.cardinalityFeedback = feedback
+	sb.cardinalityFeedbackPrefixes = make(map[string]struct{}, len(feedback))
+	for key := range feedback {
+		if i := strings.LastIndexByte(key, '/'); i >= 0 {
+			sb.cardinalityFeedbackPrefixes[key[:i]] = struct{}{}
+		}
+	}
+	sb.cardinalityFeedbackKeys.Init(sb.ctx, m)
+}
+
+// HasCardinalityFeedback returns true if the memo was built using row counts
+// observed during previous executions of the statement.
+func (m *Memo) HasCardinalityFeedback() bool {
+	return len(m.cardinalityFeedback) != 0
+}
+
+// CardinalityFeedbackMatches returns true if the memo was built using exactly
+// the given observed row counts. A cached memo that was built with different
+// (or no) observed row counts than are currently known for the statement must
+// not be reused.
+func (m *Memo) CardinalityFeedbackMatches(feedback map[string]float64) bool {
+	if len(m.cardinalityFeedback) != len(feedback) {
+		return false
+	}
+	for key, rowCount := range feedback {
+		if existing, ok := m.cardinalityFeedback[key]; !ok || existing != rowCount {
+			return false
+		}
+	}
+	return true
+}
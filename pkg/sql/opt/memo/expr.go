@@ -760,6 +760,82 @@ type UDFDefinition struct {
 	// result of the routine. This invariant is enforced when the PLpgSQL routine
 	// is built. CursorDeclaration may be unset.
 	CursorDeclaration *tree.RoutineOpenCursor
+
+	// ResultBuffer is set for a set-returning PL/pgSQL routine. It accumulates
+	// the rows added by RETURN NEXT and RETURN QUERY statements, which become
+	// the result of the routine. The result of the last body statement is
+	// discarded. ResultBuffer may be unset.
+	ResultBuffer *tree.RoutineResultBuffer
+
+	// AppendToResultBuffer, if set, is the buffer of a set-returning ancestor
+	// routine. The result of the *first* body statement is added to the buffer.
+	// Similar to CursorDeclaration, if it is set there will be at least two body
+	// statements. AppendToResultBuffer may be unset.
+	AppendToResultBuffer *tree.RoutineResultBuffer
+
+	// TxnControlResume is set for a PL/pgSQL routine that continues execution
+	// of a stored procedure after a nested CALL statement, when the called
+	// procedure may COMMIT or ROLLBACK. The last parameter of the routine is the
+	// result of the nested CALL. TxnControlResume may be unset.
+	TxnControlResume *TxnControlResume
+
+	// Cost is the user-provided estimated cost of executing the routine, in
+	// units of cpu_operator_cost. It is zero if no COST was specified, in which
+	// case the routine is not costed.
+	Cost float64
+
+	// Rows is the user-provided estimated number of rows returned by a
+	// set-returning routine. It is zero if no ROWS was specified.
+	Rows float64
+
+	// SessionVars contains the session variables set by the SET clauses of the
+	// routine definition. They are applied while the routine is executing. It
+	// is only set for the outermost routine, and not for any sub-routines that
+	// implement the PL/pgSQL body.
+	SessionVars []tree.RoutineSessionVar
+}
+
+// UDFAggregateDefinition stores details about an aggregate created with CREATE
+// AGGREGATE. The state transition and final functions are wrapped in routines
+// that are invoked once per input row and once per group, respectively.
+type UDFAggregateDefinition struct {
+	// Name is the name of the aggregate.
+	Name string
+
+	// Typ is the result type of the aggregate.
+	Typ *types.T
+
+	// Volatility is the most volatile of the state transition and final
+	// functions.
+	Volatility volatility.V
+
+	// Transition is a routine that takes the current state followed by the
+	// aggregate arguments and returns the next state.
+	Transition *UDFDefinition
+
+	// Final, if set, is a routine that maps the state to the result of the
+	// aggregate. If unset, the state is the result.
+	Final *UDFDefinition
+
+	// InitCond, if set, is the initial value of the state. If unset, the state
+	// starts out NULL.
+	InitCond tree.TypedExpr
+
+	// StrictTransition is true if the state transition function is STRICT.
+	// Rows with a NULL argument are then skipped, and if there is no InitCond
+	// the first row's argument becomes the initial state.
+	StrictTransition bool
+}
+
+// TxnControlResume contains the information needed to resume execution of a
+// PL/pgSQL stored procedure after a procedure it called paused execution in
+// order to COMMIT or ROLLBACK the current transaction.
+type TxnControlResume struct {
+	// Props is used when building the plan for the continuation SP.
+	Props *physical.Required
+
+	// OutCols is used when building the plan for the continuation SP.
+	OutCols opt.ColList
 }
 
 // ExceptionBlock contains the information needed to match and handle errors in
@@ -1356,12 +1432,23 @@ type FKCascade struct {
 // AfterTriggers stores metadata necessary for building a set of AFTER triggers.
 // AFTER triggers are built as needed, after the original query is executed.
 type AfterTriggers struct {
+	// Triggers is the set of row-level AFTER triggers. It may be empty if there
+	// are only statement-level triggers.
 	Triggers []cat.Trigger
 
-	// Builder is an object that can be used as the "optbuilder" for the cascading
-	// query.
+	// Builder is an object that can be used as the "optbuilder" for the
+	// row-level triggers. It is nil if Triggers is empty.
 	Builder PostQueryBuilder
 
+	// StatementTriggers is the set of statement-level AFTER triggers. Unlike
+	// row-level triggers, they fire once per statement, even if no rows were
+	// modified.
+	StatementTriggers []cat.Trigger
+
+	// StatementBuilder is an object that can be used as the "optbuilder" for the
+	// statement-level triggers. It is nil if StatementTriggers is empty.
+	StatementBuilder PostQueryBuilder
+
 	// WithID identifies the buffer for the mutation input in the original
 	// expression tree. It is always nonzero.
 	WithID opt.WithID
@@ -987,6 +987,11 @@ func (f *ExprFmtCtx) formatScalarWithLabel(
 					// The first statement is opening a cursor.
 					stmtNode = n.Child("open-cursor")
 				}
+				if i == 0 && def.AppendToResultBuffer != nil {
+					// The first statement adds rows to the result of a set-returning
+					// routine.
+					stmtNode = n.Child("return-rows")
+				}
 				prevTailCalls := f.tailCalls
 				if i == len(def.Body)-1 {
 					f.tailCalls = make(map[opt.ScalarExpr]struct{})
@@ -1100,7 +1105,11 @@ func (f *ExprFmtCtx) formatScalarWithLabel(
 
 	case opt.TxnControlOp:
 		controlExpr := scalar.(*TxnControlExpr)
-		fmt.Fprintf(f.Buffer, "%s; CALL %s", controlExpr.TxnOp, controlExpr.Def.Name)
+		fmt.Fprintf(f.Buffer, "%s", controlExpr.TxnOp)
+		if controlExpr.Chain {
+			f.Buffer.WriteString(" AND CHAIN")
+		}
+		fmt.Fprintf(f.Buffer, "; CALL %s", controlExpr.Def.Name)
 		f.FormatScalarProps(scalar)
 		tp = tp.Child(f.Buffer.String())
 		formatRoutineArgs(controlExpr.Args, tp)
@@ -1584,6 +1593,9 @@ func (f *ExprFmtCtx) formatMutationCommon(tp treeprinter.Node, p *MutationPrivat
 		for i := range p.AfterTriggers.Triggers {
 			c.Child(p.AfterTriggers.Triggers[i].Name().Normalize())
 		}
+		for i := range p.AfterTriggers.StatementTriggers {
+			c.Childf("%s (statement)", p.AfterTriggers.StatementTriggers[i].Name().Normalize())
+		}
 	}
 }
 
@@ -1849,6 +1861,9 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 	case *FunctionPrivate:
 		fmt.Fprintf(f.Buffer, " %s", t.Name)
 
+	case *UserDefinedAggPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.Def.Name)
+
 	case *WindowsItemPrivate:
 		fmt.Fprintf(f.Buffer, " frame=%q", &t.Frame)
 
@@ -534,7 +534,12 @@ func (h *hasher) HashFKCascades(val FKCascades) {
 
 func (h *hasher) HashAfterTriggers(val *AfterTriggers) {
 	if val != nil {
-		h.HashUint64(uint64(reflect.ValueOf(val.Builder).Pointer()))
+		if val.Builder != nil {
+			h.HashUint64(uint64(reflect.ValueOf(val.Builder).Pointer()))
+		}
+		if val.StatementBuilder != nil {
+			h.HashUint64(uint64(reflect.ValueOf(val.StatementBuilder).Pointer()))
+		}
 	}
 }
 
@@ -1023,7 +1028,7 @@ func (h *hasher) IsAfterTriggersEqual(l, r *AfterTriggers) bool {
 		return false
 	}
 	// It's sufficient to compare the TriggerBuilder instances.
-	return l.Builder == r.Builder
+	return l.Builder == r.Builder && l.StatementBuilder == r.StatementBuilder
 }
 
 func (h *hasher) IsExplainOptionsEqual(l, r tree.ExplainOptions) bool {
@@ -1348,6 +1353,12 @@ func (h *hasher) IsUDFDefinitionEqual(l, r *UDFDefinition) bool {
 	} else if r.CursorDeclaration != nil {
 		return false
 	}
+	if l.ResultBuffer != r.ResultBuffer || l.AppendToResultBuffer != r.AppendToResultBuffer {
+		return false
+	}
+	if l.TxnControlResume != r.TxnControlResume {
+		return false
+	}
 	return h.IsColListEqual(l.Params, r.Params) && l.IsRecursive == r.IsRecursive
 }
 
@@ -1757,6 +1757,10 @@ func BuildSharedProps(e opt.Expr, shared *props.Shared, evalCtx *eval.Context) {
 		shared.HasUDF = true
 		shared.VolatilitySet.Add(t.Def.Volatility)
 
+	case *UserDefinedAggExpr:
+		shared.HasUDF = true
+		shared.VolatilitySet.Add(t.Def.Volatility)
+
 	default:
 		if opt.IsUnaryOp(e) {
 			inputType := e.Child(0).(opt.ScalarExpr).DataType()
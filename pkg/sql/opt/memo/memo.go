@@ -198,12 +198,17 @@ type Memo struct {
 	useConditionalHoistFix                     bool
 	pushLimitIntoProjectFilteredScan           bool
 	unsafeAllowTriggersModifyingCascades       bool
+	allowMaterializedViewMutations             bool
 
 	// txnIsoLevel is the isolation level under which the plan was created. This
 	// affects the planning of some locking operations, so it must be included in
 	// memo staleness calculation.
 	txnIsoLevel isolation.Level
 
+	// cardinalityFeedback contains the row counts observed during previous
+	// executions of the statement. See SetCardinalityFeedback.
+	cardinalityFeedback map[string]float64
+
 	// curRank is the highest currently in-use scalar expression rank.
 	curRank opt.ScalarRank
 
@@ -287,6 +292,7 @@ func (m *Memo) Init(ctx context.Context, evalCtx *eval.Context) {
 		useConditionalHoistFix:                     evalCtx.SessionData().OptimizerUseConditionalHoistFix,
 		pushLimitIntoProjectFilteredScan:           evalCtx.SessionData().OptimizerPushLimitIntoProjectFilteredScan,
 		unsafeAllowTriggersModifyingCascades:       evalCtx.SessionData().UnsafeAllowTriggersModifyingCascades,
+		allowMaterializedViewMutations:             evalCtx.SessionData().AllowMaterializedViewMutations,
 		txnIsoLevel:                                evalCtx.TxnIsoLevel,
 	}
 	m.metadata.Init()
@@ -454,6 +460,7 @@ func (m *Memo) IsStale(
 		m.useConditionalHoistFix != evalCtx.SessionData().OptimizerUseConditionalHoistFix ||
 		m.pushLimitIntoProjectFilteredScan != evalCtx.SessionData().OptimizerPushLimitIntoProjectFilteredScan ||
 		m.unsafeAllowTriggersModifyingCascades != evalCtx.SessionData().UnsafeAllowTriggersModifyingCascades ||
+		m.allowMaterializedViewMutations != evalCtx.SessionData().AllowMaterializedViewMutations ||
 		m.txnIsoLevel != evalCtx.TxnIsoLevel {
 		return true, nil
 	}
@@ -527,6 +527,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData().UnsafeAllowTriggersModifyingCascades = false
 	notStale()
 
+	// Stale allow_materialized_view_mutations.
+	evalCtx.SessionData().AllowMaterializedViewMutations = true
+	stale()
+	evalCtx.SessionData().AllowMaterializedViewMutations = false
+	notStale()
+
 	// User no longer has access to view.
 	catalog.View(tree.NewTableNameWithSchema("t", catconstants.PublicSchemaName, "abcview")).Revoked = true
 	_, err = o.Memo().IsStale(ctx, &evalCtx, catalog)
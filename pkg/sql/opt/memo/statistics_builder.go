@@ -30,6 +30,10 @@ import (
 
 var statsAnnID = opt.NewTableAnnID()
 
+// multiColHistAnnID is the annotation ID for the multi-column histograms of a
+// base table.
+var multiColHistAnnID = opt.NewTableAnnID()
+
 const (
 	// This is the value used for inequality filters such as x < 1 in
 	// "Access Path Selection in a Relational Database Management System"
@@ -231,6 +235,20 @@ type statisticsBuilder struct {
 	ctx     context.Context
 	evalCtx *eval.Context
 	md      *opt.Metadata
+
+	// cardinalityFeedback contains the row counts observed during previous
+	// executions of the statement, keyed by CardinalityFeedbackKeyBuilder. See
+	// Memo.SetCardinalityFeedback.
+	cardinalityFeedback map[string]float64
+
+	// cardinalityFeedbackPrefixes contains the prefixes of the keys in
+	// cardinalityFeedback, which are used to avoid computing the keys of
+	// expressions that cannot have any feedback.
+	cardinalityFeedbackPrefixes map[string]struct{}
+
+	// cardinalityFeedbackKeys builds the keys of the expressions whose
+	// statistics are built.
+	cardinalityFeedbackKeys CardinalityFeedbackKeyBuilder
 }
 
 func (sb *statisticsBuilder) init(ctx context.Context, evalCtx *eval.Context, md *opt.Metadata) {
@@ -246,6 +264,9 @@ func (sb *statisticsBuilder) init(ctx context.Context, evalCtx *eval.Context, md
 func (sb *statisticsBuilder) clear() {
 	sb.evalCtx = nil
 	sb.md = nil
+	sb.cardinalityFeedback = nil
+	sb.cardinalityFeedbackPrefixes = nil
+	sb.cardinalityFeedbackKeys = CardinalityFeedbackKeyBuilder{}
 }
 
 // colStatCols returns the set of columns which may be looked up in
@@ -845,7 +866,7 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 	// scan on a non-partial index. The stats of the scan are the same as the
 	// underlying table stats.
 	if scan.Constraint == nil && scan.InvertedConstraint == nil && pred == nil {
-		sb.finalizeFromCardinality(relProps)
+		sb.finalizeFromCardinality(scan, relProps)
 		return
 	}
 
@@ -861,7 +882,7 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 			}
 		}
 		sb.filterRelExpr(pred, scan, notNullCols, relProps, s, MakeTableFuncDep(sb.md, scan.Table))
-		sb.finalizeFromCardinality(relProps)
+		sb.finalizeFromCardinality(scan, relProps)
 		return
 	}
 
@@ -892,7 +913,7 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 	// predicate (if they exist) to the underlying table stats.
 	if scan.Constraint == nil || scan.Constraint.Spans.Count() < 2 {
 		sb.constrainScan(scan, scan.Constraint, pred, relProps, s)
-		sb.finalizeFromCardinality(relProps)
+		sb.finalizeFromCardinality(scan, relProps)
 		return
 	}
 
@@ -944,7 +965,7 @@ func (sb *statisticsBuilder) buildScan(scan *ScanExpr, relProps *props.Relationa
 	s.Selectivity = props.MinSelectivity(s.Selectivity, spanStatsUnion.Selectivity)
 	s.RowCount = min(s.RowCount, spanStatsUnion.RowCount)
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(scan, relProps)
 }
 
 // constrainScan is called from buildScan to calculate the stats for the scan
@@ -1043,8 +1064,25 @@ func (sb *statisticsBuilder) constrainScan(
 
 	// Calculate row count and selectivity
 	// -----------------------------------
-	corr := sb.correlationFromMultiColDistinctCounts(constrainedCols, scan, s)
-	s.ApplySelectivity(sb.selectivityFromConstrainedCols(constrainedCols, histCols, scan, s, corr))
+	selectivityCols, selectivityHistCols := constrainedCols, histCols
+	if s.Available && scan.InvertedConstraint == nil && sb.shouldUseHistogram(relProps) {
+		eqVals := make(map[opt.ColumnID]tree.Datum)
+		if constraint != nil {
+			sb.addEqualityValuesFromConstraint(constraint, eqVals)
+		}
+		for i := range pred {
+			scalarProps := pred[i].ScalarProps()
+			if scalarProps.Constraints != nil && scalarProps.TightConstraints {
+				sb.addEqualityValuesFromConstraintSet(scalarProps.Constraints, eqVals)
+			}
+		}
+		selectivity, coveredCols := sb.selectivityFromMultiColHistograms(scan.Table, eqVals)
+		s.ApplySelectivity(selectivity)
+		selectivityCols = selectivityCols.Difference(coveredCols)
+		selectivityHistCols = selectivityHistCols.Difference(coveredCols)
+	}
+	corr := sb.correlationFromMultiColDistinctCounts(selectivityCols, scan, s)
+	s.ApplySelectivity(sb.selectivityFromConstrainedCols(selectivityCols, selectivityHistCols, scan, s, corr))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(unapplied))
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(scan, notNullCols, constrainedCols))
 }
@@ -1090,7 +1128,7 @@ func (sb *statisticsBuilder) buildSelect(sel *SelectExpr, relProps *props.Relati
 
 	sb.filterRelExpr(sel.Filters, sel, relProps.NotNullCols, relProps, s, &sel.Input.Relational().FuncDeps)
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(sel, relProps)
 }
 
 func (sb *statisticsBuilder) colStatSelect(
@@ -1130,7 +1168,7 @@ func (sb *statisticsBuilder) buildProject(prj *ProjectExpr, relProps *props.Rela
 
 	s.RowCount = inputStats.RowCount
 	s.VirtualCols.UnionWith(inputStats.VirtualCols)
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(prj, relProps)
 }
 
 func (sb *statisticsBuilder) colStatProject(
@@ -1247,7 +1285,7 @@ func (sb *statisticsBuilder) buildInvertedFilter(
 	s.ApplySelectivity(sb.selectivityFromConstrainedCols(constrainedCols, histCols, invFilter, s, corr))
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(invFilter, relProps.NotNullCols, constrainedCols))
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(invFilter, relProps)
 }
 
 func (sb *statisticsBuilder) colStatInvertedFilter(
@@ -1559,7 +1597,7 @@ func (sb *statisticsBuilder) buildJoin(
 		colStat.Histogram = nil
 	}
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(join, relProps)
 }
 
 func (sb *statisticsBuilder) colStatJoin(colSet opt.ColSet, join RelExpr) *props.ColumnStatistic {
@@ -1825,7 +1863,7 @@ func (sb *statisticsBuilder) buildIndexJoin(indexJoin *IndexJoinExpr, relProps *
 	s.RowCount = inputStats.RowCount
 	s.VirtualCols.UnionWith(inputStats.VirtualCols)
 	s.VirtualCols.UnionWith(sb.makeTableStatistics(indexJoin.Table).VirtualCols)
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(indexJoin, relProps)
 }
 
 func (sb *statisticsBuilder) colStatIndexJoin(
@@ -1987,7 +2025,7 @@ func (sb *statisticsBuilder) buildZigzagJoin(
 	// selectivityFromMultiColDistinctCounts and selectivityFromEquivalencies.
 	sb.applyEquivalencies(equivReps, &relProps.FuncDeps, zigzag, relProps.NotNullCols, s)
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(zigzag, relProps)
 }
 
 // +----------+
@@ -2043,7 +2081,7 @@ func (sb *statisticsBuilder) buildGroupBy(groupNode RelExpr, relProps *props.Rel
 		}
 	}
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(groupNode, relProps)
 }
 
 func (sb *statisticsBuilder) colStatGroupBy(
@@ -2146,7 +2184,7 @@ func (sb *statisticsBuilder) buildSetNode(setNode RelExpr, relProps *props.Relat
 		s.RowCount = colStat.DistinctCount
 	}
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(setNode, relProps)
 }
 
 func (sb *statisticsBuilder) colStatSetNode(
@@ -2220,7 +2258,7 @@ func (sb *statisticsBuilder) buildValues(values ValuesContainer, relProps *props
 	s.Available = sb.availabilityFromInput(values)
 
 	s.RowCount = float64(values.Len())
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(values, relProps)
 }
 
 func (sb *statisticsBuilder) colStatValues(
@@ -2339,7 +2377,7 @@ func (sb *statisticsBuilder) buildLimit(limit *LimitExpr, relProps *props.Relati
 		}
 	}
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(limit, relProps)
 }
 
 func (sb *statisticsBuilder) colStatLimit(
@@ -2379,7 +2417,7 @@ func (sb *statisticsBuilder) buildTopK(topK *TopKExpr, relProps *props.Relationa
 		s.Selectivity = props.MakeSelectivity(s.RowCount / inputStats.RowCount)
 	}
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(topK, relProps)
 }
 
 func (sb *statisticsBuilder) colStatTopK(colSet opt.ColSet, topK *TopKExpr) *props.ColumnStatistic {
@@ -2430,7 +2468,7 @@ func (sb *statisticsBuilder) buildOffset(offset *OffsetExpr, relProps *props.Rel
 		s.Selectivity = props.MakeSelectivity(s.RowCount / inputStats.RowCount)
 	}
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(offset, relProps)
 }
 
 func (sb *statisticsBuilder) colStatOffset(
@@ -2463,7 +2501,7 @@ func (sb *statisticsBuilder) buildMax1Row(max1Row *Max1RowExpr, relProps *props.
 	s.Available = true
 
 	s.RowCount = 1
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(max1Row, relProps)
 }
 
 func (sb *statisticsBuilder) colStatMax1Row(
@@ -2496,7 +2534,7 @@ func (sb *statisticsBuilder) buildOrdinality(ord *OrdinalityExpr, relProps *prop
 
 	s.RowCount = inputStats.RowCount
 	s.VirtualCols.UnionWith(inputStats.VirtualCols)
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(ord, relProps)
 }
 
 func (sb *statisticsBuilder) colStatOrdinality(
@@ -2542,7 +2580,7 @@ func (sb *statisticsBuilder) buildWindow(window *WindowExpr, relProps *props.Rel
 	// The row count of a window is equal to the row count of its input.
 	s.RowCount = inputStats.RowCount
 	s.VirtualCols.UnionWith(inputStats.VirtualCols)
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(window, relProps)
 }
 
 func (sb *statisticsBuilder) colStatWindow(
@@ -2606,27 +2644,30 @@ func (sb *statisticsBuilder) buildProjectSet(
 	s.Available = sb.availabilityFromInput(projectSet)
 
 	// The row count of a zip operation is equal to the maximum row count of its
-	// children.
-	var zipRowCount float64
+	// children. A scalar function generates one row.
+	zipRowCount := float64(1)
 	for i := range projectSet.Zip {
-		if fn, ok := projectSet.Zip[i].Fn.(*FunctionExpr); ok {
-			if fn.Overload.IsGenerator() {
+		switch fn := projectSet.Zip[i].Fn.(type) {
+		case *FunctionExpr:
+			if fn.Overload.IsGenerator() && zipRowCount < unknownGeneratorRowCount {
 				// TODO(rytaft): We may want to estimate the number of rows based on
 				// the type of generator function and its parameters.
 				zipRowCount = unknownGeneratorRowCount
-				break
+			}
+		case *UDFCallExpr:
+			if fn.Def.SetReturning && zipRowCount < fn.Def.Rows {
+				// Use the number of rows specified by the ROWS clause of the
+				// routine definition, if any.
+				zipRowCount = fn.Def.Rows
 			}
 		}
-
-		// A scalar function generates one row.
-		zipRowCount = 1
 	}
 
 	// Multiply by the input row count to get the total.
 	inputStats := projectSet.Input.Relational().Statistics()
 	s.RowCount = zipRowCount * inputStats.RowCount
 	s.VirtualCols.UnionWith(inputStats.VirtualCols)
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(projectSet, relProps)
 }
 
 func (sb *statisticsBuilder) colStatProjectSet(
@@ -2746,7 +2787,7 @@ func (sb *statisticsBuilder) buildWithScan(
 	// column IDs, and would need to store the rewritten expressions somewhere
 	// other than TableMeta.
 
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(withScan, relProps)
 }
 
 func (sb *statisticsBuilder) colStatWithScan(
@@ -2784,7 +2825,7 @@ func (sb *statisticsBuilder) buildMutation(mutation RelExpr, relProps *props.Rel
 
 	s.RowCount = inputStats.RowCount
 	s.VirtualCols.UnionWith(inputStats.VirtualCols)
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(mutation, relProps)
 }
 
 func (sb *statisticsBuilder) colStatMutation(
@@ -2822,7 +2863,7 @@ func (sb *statisticsBuilder) buildLock(lock *LockExpr, relProps *props.Relationa
 
 	s.RowCount = inputStats.RowCount
 	s.VirtualCols.UnionWith(inputStats.VirtualCols)
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(lock, relProps)
 }
 
 func (sb *statisticsBuilder) colStatLock(colSet opt.ColSet, lock *LockExpr) *props.ColumnStatistic {
@@ -2854,7 +2895,7 @@ func (sb *statisticsBuilder) buildBarrier(barrier *BarrierExpr, relProps *props.
 
 	s.RowCount = inputStats.RowCount
 	s.VirtualCols.UnionWith(inputStats.VirtualCols)
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(barrier, relProps)
 }
 
 func (sb *statisticsBuilder) colStatBarrier(
@@ -2884,7 +2925,7 @@ func (sb *statisticsBuilder) buildCall(call *CallExpr, relProps *props.Relationa
 	}
 	s.Available = true
 	s.RowCount = 1
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(call, relProps)
 }
 
 func (sb *statisticsBuilder) colStatCall(colSet opt.ColSet, call *CallExpr) *props.ColumnStatistic {
@@ -2904,7 +2945,7 @@ func (sb *statisticsBuilder) buildSequenceSelect(relProps *props.Relational) {
 	s := relProps.Statistics()
 	s.Available = true
 	s.RowCount = 1
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(nil /* e */, relProps)
 }
 
 func (sb *statisticsBuilder) colStatSequenceSelect(
@@ -2927,7 +2968,7 @@ func (sb *statisticsBuilder) buildUnknown(relProps *props.Relational) {
 	s := relProps.Statistics()
 	s.Available = false
 	s.RowCount = unknownGeneratorRowCount
-	sb.finalizeFromCardinality(relProps)
+	sb.finalizeFromCardinality(nil /* e */, relProps)
 }
 
 func (sb *statisticsBuilder) colStatUnknown(
@@ -2989,7 +3030,9 @@ func (sb *statisticsBuilder) copyColStat(
 	return colStat
 }
 
-func (sb *statisticsBuilder) finalizeFromCardinality(relProps *props.Relational) {
+// finalizeFromCardinality finalizes the statistics of the given expression.
+// If e is nil, no cardinality feedback is applied.
+func (sb *statisticsBuilder) finalizeFromCardinality(e RelExpr, relProps *props.Relational) {
 	s := relProps.Statistics()
 
 	// We don't ever want row count to be zero unless the cardinality is zero.
@@ -3006,6 +3049,22 @@ func (sb *statisticsBuilder) finalizeFromCardinality(relProps *props.Relational)
 		s.RowCount = float64(relProps.Cardinality.Min)
 	}
 
+	// Use the row count observed during a previous execution of the statement
+	// in place of the estimate, if there is one. The observed row count is
+	// always within the cardinality bounds. Expressions with placeholders are
+	// never recorded; see CardinalityFeedbackKeyBuilder.Key.
+	if len(sb.cardinalityFeedback) != 0 && e != nil && s.RowCount > 0 && !relProps.HasPlaceholder {
+		// Only fingerprint the expression if some feedback may match it.
+		prefix := cardinalityFeedbackKeyPrefix(relProps.OutputCols, s.RowCount)
+		if _, ok := sb.cardinalityFeedbackPrefixes[prefix]; ok {
+			key := sb.cardinalityFeedbackKeys.key(relProps.OutputCols, s.RowCount, e)
+			if observed, ok := sb.cardinalityFeedback[key]; ok && observed > 0 {
+				s.RowCountBeforeFeedback = s.RowCount
+				s.RowCount = observed
+			}
+		}
+	}
+
 	for i, n := 0, s.ColStats.Count(); i < n; i++ {
 		colStat := s.ColStats.Get(i)
 		sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
@@ -3298,8 +3357,24 @@ func (sb *statisticsBuilder) filterRelExpr(
 
 	// Calculate row count and selectivity
 	// -----------------------------------
-	corr := sb.correlationFromMultiColDistinctCounts(constrainedCols, e, s)
-	s.ApplySelectivity(sb.selectivityFromConstrainedCols(constrainedCols, histCols, e, s, corr))
+	selectivityCols, selectivityHistCols := constrainedCols, histCols
+	if sel, ok := e.(*SelectExpr); ok && s.Available && sb.shouldUseHistogram(relProps) {
+		if scan, ok := sel.Input.(*ScanExpr); ok && scan.IsUnfiltered(sb.md) {
+			eqVals := make(map[opt.ColumnID]tree.Datum)
+			for i := range filters {
+				scalarProps := filters[i].ScalarProps()
+				if scalarProps.Constraints != nil && scalarProps.TightConstraints {
+					sb.addEqualityValuesFromConstraintSet(scalarProps.Constraints, eqVals)
+				}
+			}
+			selectivity, coveredCols := sb.selectivityFromMultiColHistograms(scan.Table, eqVals)
+			s.ApplySelectivity(selectivity)
+			selectivityCols = selectivityCols.Difference(coveredCols)
+			selectivityHistCols = selectivityHistCols.Difference(coveredCols)
+		}
+	}
+	corr := sb.correlationFromMultiColDistinctCounts(selectivityCols, e, s)
+	s.ApplySelectivity(sb.selectivityFromConstrainedCols(selectivityCols, selectivityHistCols, e, s, corr))
 	s.ApplySelectivity(sb.selectivityFromEquivalencies(equivReps, &relProps.FuncDeps, e, s))
 	s.ApplySelectivity(sb.selectivityFromUnappliedConjuncts(unapplied))
 	s.ApplySelectivity(sb.selectivityFromNullsRemoved(e, notNullCols, constrainedCols))
@@ -4470,6 +4545,160 @@ func (sb *statisticsBuilder) selectivityFromConstrainedCols(
 	return selectivity
 }
 
+// multiColHistogram is a histogram on multiple columns of a base table. The
+// histogram upper bounds are tuples with one element for each column, in the
+// same order as cols.
+type multiColHistogram struct {
+	cols    []opt.ColumnID
+	colSet  opt.ColSet
+	typ     *types.T
+	hist    *props.Histogram
+	numRows float64
+}
+
+// multiColHistograms returns the multi-column histograms available for the
+// given table, using the most recent full statistic for each column set. The
+// histograms are derived lazily and are cached in the metadata.
+func (sb *statisticsBuilder) multiColHistograms(tabID opt.TableID) []multiColHistogram {
+	hists, ok := sb.md.TableAnnotation(tabID, multiColHistAnnID).([]multiColHistogram)
+	if ok {
+		// Already made.
+		return hists
+	}
+
+	sd := sb.evalCtx.SessionData()
+	if sd.OptimizerUseHistograms && sd.OptimizerUseMultiColStats {
+		tab := sb.md.Table(tabID)
+		var seen []opt.ColSet
+	EachStat:
+		for i := 0; i < tab.StatisticCount(); i++ {
+			stat := tab.Statistic(i)
+			if stat.IsPartial() ||
+				stat.IsMerged() && !sd.OptimizerUseMergedPartialStatistics ||
+				stat.IsForecast() && !sd.OptimizerUseForecasts {
+				continue
+			}
+			if stat.ColumnCount() < 2 {
+				continue
+			}
+			cols := make([]opt.ColumnID, stat.ColumnCount())
+			var colSet opt.ColSet
+			for j := range cols {
+				colOrd := stat.ColumnOrdinal(j)
+				if tab.Column(colOrd).IsVirtualComputed() &&
+					!sd.OptimizerUseVirtualComputedColumnStats {
+					continue EachStat
+				}
+				cols[j] = tabID.ColumnID(colOrd)
+				colSet.Add(cols[j])
+			}
+			for _, prev := range seen {
+				if prev.Equals(colSet) {
+					// Stats are ordered with most recent first, so we have already
+					// considered a more recent statistic on these columns.
+					continue EachStat
+				}
+			}
+			seen = append(seen, colSet)
+
+			typ := stat.HistogramType()
+			if len(stat.Histogram()) == 0 || typ == nil || typ.Family() != types.TupleFamily ||
+				len(typ.TupleContents()) != len(cols) {
+				continue
+			}
+			hist := &props.Histogram{}
+			hist.Init(sb.evalCtx, cols[0], stat.Histogram())
+			hists = append(hists, multiColHistogram{
+				cols:    cols,
+				colSet:  colSet,
+				typ:     typ,
+				hist:    hist,
+				numRows: max(float64(stat.RowCount()), 1),
+			})
+		}
+	}
+
+	sb.md.SetTableAnnotation(tabID, multiColHistAnnID, hists)
+	return hists
+}
+
+// addEqualityValuesFromConstraint adds to eqVals the value of each column in
+// the exact prefix of the given constraint, i.e., each column that is
+// constrained to a single non-NULL value.
+func (sb *statisticsBuilder) addEqualityValuesFromConstraint(
+	c *constraint.Constraint, eqVals map[opt.ColumnID]tree.Datum,
+) {
+	prefix := c.ExactPrefix(sb.ctx, sb.evalCtx)
+	if prefix == 0 {
+		return
+	}
+	key := c.Spans.Get(0).StartKey()
+	for i := 0; i < prefix; i++ {
+		if val := key.Value(i); val != tree.DNull {
+			eqVals[c.Columns.Get(i).ID()] = val
+		}
+	}
+}
+
+// addEqualityValuesFromConstraintSet calls addEqualityValuesFromConstraint for
+// each constraint in the given set.
+func (sb *statisticsBuilder) addEqualityValuesFromConstraintSet(
+	cs *constraint.Set, eqVals map[opt.ColumnID]tree.Datum,
+) {
+	for i := 0; i < cs.Length(); i++ {
+		sb.addEqualityValuesFromConstraint(cs.Constraint(i), eqVals)
+	}
+}
+
+// selectivityFromMultiColHistograms calculates the selectivity of equality
+// conditions on multiple columns of the given table using a multi-column
+// histogram. Unlike single-column histograms combined with distinct counts,
+// multi-column histograms capture the correlation between the values of the
+// columns. eqVals maps each column constrained to a single value to that
+// value.
+//
+// The histogram with the most columns that are all constrained is used. The
+// returned coveredCols are the columns whose selectivity has been accounted
+// for, and should not be considered again in selectivityFromConstrainedCols.
+func (sb *statisticsBuilder) selectivityFromMultiColHistograms(
+	tabID opt.TableID, eqVals map[opt.ColumnID]tree.Datum,
+) (selectivity props.Selectivity, coveredCols opt.ColSet) {
+	selectivity = props.OneSelectivity
+	if len(eqVals) < 2 {
+		return selectivity, opt.ColSet{}
+	}
+
+	var best *multiColHistogram
+	hists := sb.multiColHistograms(tabID)
+	for i := range hists {
+		h := &hists[i]
+		if best != nil && len(h.cols) <= len(best.cols) {
+			continue
+		}
+		allConstrained := true
+		for _, col := range h.cols {
+			if _, ok := eqVals[col]; !ok {
+				allConstrained = false
+				break
+			}
+		}
+		if allConstrained {
+			best = h
+		}
+	}
+	if best == nil {
+		return selectivity, opt.ColSet{}
+	}
+
+	vals := make(tree.Datums, len(best.cols))
+	for i, col := range best.cols {
+		vals[i] = eqVals[col]
+	}
+	tuple := tree.NewDTuple(best.typ, vals...)
+	selectivity = props.MakeSelectivity(best.hist.EqEstimate(sb.ctx, tuple) / best.numRows)
+	return selectivity, best.colSet
+}
+
 // selectivityFromNullsRemoved calculates the selectivity from null-rejecting
 // filters that were not already accounted for in selectivityFromMultiColDistinctCounts
 // or selectivityFromHistograms. The columns for filters already accounted for
@@ -200,6 +200,7 @@ func init() {
 	typingFuncMap[opt.ConstNotNullAggOp] = typeAsFirstArg
 	typingFuncMap[opt.AnyNotNullAggOp] = typeAsFirstArg
 	typingFuncMap[opt.FirstAggOp] = typeAsFirstArg
+	typingFuncMap[opt.UserDefinedAggOp] = typeUserDefinedAgg
 
 	typingFuncMap[opt.LagOp] = typeAsFirstArg
 	typingFuncMap[opt.LeadOp] = typeAsFirstArg
@@ -388,6 +389,11 @@ func typeUDFCall(e opt.ScalarExpr) *types.T {
 	return e.(*UDFCallExpr).Def.Typ
 }
 
+// typeUserDefinedAgg returns the result type of a UserDefinedAggExpr operator.
+func typeUserDefinedAgg(e opt.ScalarExpr) *types.T {
+	return e.(*UserDefinedAggExpr).Def.Typ
+}
+
 // typeTxnControl returns the type of a TxnControlExpr operator
 func typeTxnControl(e opt.ScalarExpr) *types.T {
 	return e.(*TxnControlExpr).Def.Typ
@@ -73,6 +73,11 @@ type Builder struct {
 	// a statement during session migration.
 	SkipAOST bool
 
+	// StatementHints is a control knob: if set, optbuilder applies the given
+	// external hints to table references and joins that don't have inline
+	// hints. This is used for statements that match a CREATE STATEMENT HINT.
+	StatementHints *StatementHints
+
 	// -- Results --
 	//
 	// These fields are set during the building process and can be used after
@@ -157,6 +162,11 @@ type Builder struct {
 	// CALL statement.
 	insideNestedPLpgSQLCall bool
 
+	// nestedCallHasTxnControl is set while processing a nested PLpgSQL CALL
+	// statement if the called procedure, or any procedure that it calls, can
+	// COMMIT or ROLLBACK the current transaction.
+	nestedCallHasTxnControl bool
+
 	// If set, we are collecting view dependencies in schemaDeps. This can only
 	// happen inside view/function definitions.
 	//
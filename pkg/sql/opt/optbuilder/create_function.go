@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -91,6 +92,11 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			if _, err := funcinfo.FunctionLangToProto(opt); err != nil {
 				panic(err)
 			}
+		case tree.RoutineCost, tree.RoutineRows, tree.RoutineParallel, *tree.RoutineSetVar:
+			if !b.evalCtx.Settings.Version.ActiveVersion(b.ctx).IsActive(clusterversion.V25_1) {
+				panic(pgerror.Newf(pgcode.FeatureNotSupported,
+					"%s is not supported until upgrade to v25.1 is finalized", tree.AsString(opt)))
+			}
 		}
 	}
 
@@ -155,6 +161,7 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 	// labels in the output RECORD type.
 	var outParamNames []string
 	var sawDefaultExpr, sawPolymorphicInParam, sawPolymorphicOutParam bool
+	var sawAnyCompatibleInParam, sawVariadicParam bool
 	for i := range cf.Params {
 		param := &cf.Params[i]
 		typ, err := tree.ResolveType(b.ctx, param.Type, b.semaCtx.TypeResolver)
@@ -178,16 +185,44 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 				))
 			}
 		}
+		if param.Class == tree.RoutineParamVariadic {
+			b.checkVariadicAndAnyCompatibleSupported("VARIADIC parameter")
+		}
+		if typ.IsAnyCompatibleType() {
+			b.checkVariadicAndAnyCompatibleSupported(fmt.Sprintf("type %s", typ.Name()))
+		}
 		if param.Class == tree.RoutineParamInOut && param.Name == "" {
 			panic(unimplemented.NewWithIssue(121251, "unnamed INOUT parameters are not yet supported"))
 		}
+		if param.Class == tree.RoutineParamTable &&
+			!b.evalCtx.Settings.Version.ActiveVersion(b.ctx).IsActive(clusterversion.V25_1) {
+			panic(pgerror.New(pgcode.FeatureNotSupported,
+				"RETURNS TABLE is not supported until upgrade to v25.1 is finalized"))
+		}
 		if param.IsInParam() {
 			if typ.Family() == types.VoidFamily {
 				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition, "SQL functions cannot have arguments of type VOID"))
 			}
-			if typ.IsPolymorphicType() {
+			if typ.IsAnyCompatibleType() {
+				sawAnyCompatibleInParam = true
+			} else if typ.IsPolymorphicType() {
 				sawPolymorphicInParam = true
 			}
+			if sawVariadicParam {
+				panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+					"VARIADIC parameter must be the last input parameter"))
+			}
+		}
+		if param.Class == tree.RoutineParamVariadic {
+			if typ.Family() != types.ArrayFamily {
+				panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+					"VARIADIC parameter must be an array"))
+			}
+			if param.DefaultVal != nil {
+				panic(unimplemented.NewWithIssue(88947,
+					"DEFAULT values for VARIADIC parameters are not yet supported"))
+			}
+			sawVariadicParam = true
 		}
 		if param.IsOutParam() {
 			outParamTypes = append(outParamTypes, typ)
@@ -213,7 +248,7 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 				))
 			}
 		}
-		if param.DefaultVal != nil && param.Class == tree.RoutineParamOut {
+		if param.DefaultVal != nil && tree.IsOutOnlyParamClass(param.Class) {
 			panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
 				"only input parameters can have default values"))
 		}
@@ -275,6 +310,11 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 		}
 	}
 
+	if sawVariadicParam && cf.IsProcedure && len(outParamTypes) > 0 {
+		panic(unimplemented.NewWithIssue(88947,
+			"VARIADIC procedures with OUT parameters are not yet supported"))
+	}
+
 	// Determine OUT parameter based return type.
 	var outParamType *types.T
 	if (cf.IsProcedure && len(outParamTypes) > 0) || len(outParamTypes) > 1 {
@@ -290,6 +330,9 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 		if err != nil {
 			panic(err)
 		}
+		if funcReturnType.IsAnyCompatibleType() {
+			b.checkVariadicAndAnyCompatibleSupported(fmt.Sprintf("type %s", funcReturnType.Name()))
+		}
 	}
 	if outParamType != nil {
 		if funcReturnType != nil && !funcReturnType.Equivalent(outParamType) {
@@ -299,7 +342,8 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 		// CREATE correctly.
 		funcReturnType = outParamType
 		cf.ReturnType = &tree.RoutineReturnType{
-			Type: outParamType,
+			Type:  outParamType,
+			SetOf: cf.ReturnType != nil && cf.ReturnType.SetOf,
 		}
 	} else if funcReturnType == nil {
 		if cf.IsProcedure {
@@ -316,9 +360,18 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 	if b.evalCtx.SessionData().OptimizerUsePolymorphicParameterFix &&
 		(funcReturnType.IsPolymorphicType() || sawPolymorphicOutParam) {
 		// The routine return type has or contains a polymorphic type. Validate that
-		// there is at least one polymorphic IN parameter.
-		if !sawPolymorphicInParam {
-			makeErr := func(polyTyp *types.T) {
+		// there is at least one polymorphic IN parameter of the same family.
+		checkResolvable := func(polyTyp *types.T) {
+			if polyTyp.IsAnyCompatibleType() {
+				if !sawAnyCompatibleInParam {
+					panic(errors.WithDetailf(
+						pgerror.New(pgcode.InvalidFunctionDefinition, "cannot determine result data type"),
+						"A result of type %s requires at least one input of type anycompatible, "+
+							"anycompatiblearray, anycompatiblenonarray, anycompatiblerange, or anycompatiblemultirange.",
+						polyTyp.Name(),
+					))
+				}
+			} else if !sawPolymorphicInParam {
 				panic(errors.WithDetailf(
 					pgerror.New(pgcode.InvalidFunctionDefinition, "cannot determine result data type"),
 					"A result of type %s requires at least one input of type "+
@@ -326,13 +379,13 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 					polyTyp.Name(),
 				))
 			}
-			if funcReturnType.IsPolymorphicType() {
-				makeErr(funcReturnType)
-			} else {
-				for _, tc := range funcReturnType.TupleContents() {
-					if tc.IsPolymorphicType() {
-						makeErr(tc)
-					}
+		}
+		if funcReturnType.IsPolymorphicType() {
+			checkResolvable(funcReturnType)
+		} else {
+			for _, tc := range funcReturnType.TupleContents() {
+				if tc.IsPolymorphicType() {
+					checkResolvable(tc)
 				}
 			}
 		}
@@ -400,13 +453,6 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			afterBuildStmt()
 		}
 	case tree.RoutineLangPLpgSQL:
-		if cf.ReturnType != nil && cf.ReturnType.SetOf {
-			panic(unimplemented.NewWithIssueDetail(105240,
-				"set-returning PL/pgSQL functions",
-				"set-returning PL/pgSQL functions are not yet supported",
-			))
-		}
-
 		// Parse the function body.
 		stmt, err := plpgsqlparser.Parse(funcBodyStr)
 		if err != nil {
@@ -450,13 +496,21 @@ func (b *Builder) buildCreateFunction(cf *tree.CreateRoutine, inScope *scope) (o
 			buildSQL = false
 		}
 
+		// A set-returning function adds its rows to a result buffer. The buffer is
+		// only used to validate RETURN NEXT and RETURN QUERY statements here.
+		var resultBuffer *tree.RoutineResultBuffer
+		if cf.ReturnType != nil && cf.ReturnType.SetOf {
+			resultBuffer = &tree.RoutineResultBuffer{}
+		}
+
 		// We need to disable stable function folding because we want to catch the
 		// volatility of stable functions. If folded, we only get a scalar and lose
 		// the volatility.
 		b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
 			plBuilder := newPLpgSQLBuilder(
 				b, cf.Name.Object(), stmt.AST.Label, nil /* colRefs */, routineParams,
-				funcReturnType, cf.IsProcedure, buildSQL, nil, /* outScope */
+				funcReturnType, cf.IsProcedure, buildSQL, resultBuffer, false, /* multiColOutput */
+				nil, /* outScope */
 			)
 			stmtScope = plBuilder.buildRootBlock(stmt.AST, bodyScope, routineParams)
 		})
@@ -667,6 +721,18 @@ func checkStmtVolatility(
 	}
 }
 
+// checkVariadicAndAnyCompatibleSupported panics if VARIADIC parameters and
+// the anycompatible pseudo-types cannot be used yet. Nodes running older
+// versions neither persist the variadic flag of an overload nor know the OIDs
+// of the anycompatible types, so they would resolve calls to such routines
+// incorrectly.
+func (b *Builder) checkVariadicAndAnyCompatibleSupported(feature string) {
+	if !b.evalCtx.Settings.Version.ActiveVersion(b.ctx).IsActive(clusterversion.V25_1) {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported until upgrade to v25.1 is finalized", feature))
+	}
+}
+
 func checkUnsupportedType(ctx context.Context, semaCtx *tree.SemaContext, typ *types.T) {
 	if err := tree.CheckUnsupportedType(ctx, semaCtx, typ); err != nil {
 		panic(err)
@@ -237,7 +237,8 @@ func (b *Builder) buildFunctionForTrigger(
 	b.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
 		plBuilder := newPLpgSQLBuilder(
 			b, ct.FuncName.String(), stmt.AST.Label, nil /* colRefs */, triggerFuncParams, tableTyp,
-			false /* isProcedure */, true /* buildSQL */, nil, /* outScope */
+			false /* isProcedure */, true /* buildSQL */, nil, /* resultBuffer */
+			false /* multiColOutput */, nil, /* outScope */
 		)
 		funcScope = plBuilder.buildRootBlock(stmt.AST, funcScope, triggerFuncParams)
 	})
@@ -331,15 +332,6 @@ const triggerColNew = "new"
 const triggerColOld = "old"
 
 func checkUnsupportedCreateTrigger(ct *tree.CreateTrigger, ds cat.DataSource) {
-	if ct.ForEach == tree.TriggerForEachStatement {
-		panic(unimplementedStatementLevelErr)
-	}
-	if ct.ActionTime == tree.TriggerActionTimeInsteadOf {
-		panic(unimplementedInsteadOfErr)
-	}
-	if len(ct.Transitions) > 0 {
-		panic(unimplementedReferencingErr)
-	}
 	for _, event := range ct.Events {
 		if event.EventType == tree.TriggerEventTruncate {
 			panic(unimplementedTruncateErr)
@@ -348,22 +340,18 @@ func checkUnsupportedCreateTrigger(ct *tree.CreateTrigger, ds cat.DataSource) {
 			panic(unimplementedColumnListErr)
 		}
 	}
-	if _, ok := ds.(cat.View); ok {
+	if _, ok := ds.(cat.View); ok && ct.ActionTime != tree.TriggerActionTimeInsteadOf {
 		panic(unimplementedViewTriggerErr)
 	}
 }
 
 var (
-	unimplementedStatementLevelErr = unimplemented.NewWithIssue(126362,
-		"statement-level triggers are not yet supported")
-	unimplementedInsteadOfErr = unimplemented.NewWithIssue(126363,
-		"INSTEAD OF triggers are not yet supported")
-	unimplementedReferencingErr = unimplemented.NewWithIssue(135655,
-		"REFERENCING clause is not yet supported for triggers")
+	unimplementedTransitionUpsertErr = unimplemented.NewWithIssue(135655,
+		"transition tables are not yet supported for UPSERT and INSERT ... ON CONFLICT")
 	unimplementedTruncateErr = unimplemented.NewWithIssue(135657,
 		"TRUNCATE triggers are not yet supported")
 	unimplementedColumnListErr = unimplemented.NewWithIssue(135656,
 		"column lists are not yet supported for triggers")
 	unimplementedViewTriggerErr = unimplemented.NewWithIssue(135658,
-		"triggers on views are not yet supported")
+		"statement-level triggers on views are not yet supported")
 )
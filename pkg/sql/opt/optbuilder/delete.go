@@ -56,6 +56,11 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 			"DELETE BATCH not implemented"))
 	}
 
+	// Route the DELETE to the INSTEAD OF triggers of a view, if applicable.
+	if target := b.resolveInsteadOfTarget(del.Table, privilege.DELETE, tree.TriggerEventDelete); target != nil {
+		return b.buildInsteadOfDelete(del, inScope, target)
+	}
+
 	// Find which table we're working on, check the permissions.
 	tab, depName, alias, refColumns := b.resolveTableForMutation(del.Table, privilege.DELETE)
 
@@ -88,7 +93,8 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	// All columns from the delete table will be projected.
 	mb.buildInputForDelete(inScope, del.Table, del.Where, del.Using, del.Limit, del.OrderBy)
 
-	// Project row-level BEFORE triggers for DELETE.
+	// Project statement-level and row-level BEFORE triggers for DELETE.
+	mb.buildStatementLevelBeforeTriggers(false /* cascade */, tree.TriggerEventDelete)
 	mb.buildRowLevelBeforeTriggers(tree.TriggerEventDelete, false /* cascade */)
 
 	// Build the final delete statement, including any returned expressions.
@@ -106,7 +112,7 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 func (mb *mutationBuilder) buildDelete(returning *tree.ReturningExprs) {
 	mb.buildFKChecksAndCascadesForDelete()
 
-	mb.buildRowLevelAfterTriggers(opt.DeleteOp)
+	mb.buildAfterTriggers(opt.DeleteOp)
 
 	// Project partial index DEL boolean columns.
 	mb.projectPartialIndexDelCols()
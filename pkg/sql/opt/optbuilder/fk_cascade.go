@@ -114,6 +114,7 @@ func (cb *onDeleteCascadeBuilder) Build(
 		mb.setFetchColIDs(mb.outScope.cols)
 
 		// Cascades can fire triggers on the child table.
+		mb.buildStatementLevelBeforeTriggers(true /* cascade */, tree.TriggerEventDelete)
 		mb.buildRowLevelBeforeTriggers(tree.TriggerEventDelete, true /* cascade */)
 
 		mb.buildDelete(nil /* returning */)
@@ -360,6 +361,7 @@ func (cb *onDeleteFastCascadeBuilder) Build(
 		mb.setFetchColIDs(mb.outScope.cols)
 
 		// Cascades can fire triggers on the child table.
+		mb.buildStatementLevelBeforeTriggers(true /* cascade */, tree.TriggerEventDelete)
 		mb.buildRowLevelBeforeTriggers(tree.TriggerEventDelete, true /* cascade */)
 
 		mb.buildDelete(nil /* returning */)
@@ -496,6 +498,7 @@ func (cb *onDeleteSetBuilder) Build(
 		mb.addUpdateCols(updateExprs)
 
 		// Cascades can fire triggers on the child table.
+		mb.buildStatementLevelBeforeTriggers(true /* cascade */, tree.TriggerEventUpdate)
 		mb.buildRowLevelBeforeTriggers(tree.TriggerEventUpdate, true /* cascade */)
 
 		// TODO(radu): consider plumbing a flag to prevent building the FK check
@@ -741,6 +744,7 @@ func (cb *onUpdateCascadeBuilder) Build(
 		mb.addUpdateCols(updateExprs)
 
 		// Cascades can fire triggers on the child table.
+		mb.buildStatementLevelBeforeTriggers(true /* cascade */, tree.TriggerEventUpdate)
 		mb.buildRowLevelBeforeTriggers(tree.TriggerEventUpdate, true /* cascade */)
 
 		mb.buildUpdate(nil /* returning */)
@@ -245,6 +245,11 @@ func (a aggregateInfo) isOrderingSensitive() bool {
 	if a.isOrderedSetAggregate() {
 		return true
 	}
+	if a.def.Overload.UDFAggregate != nil {
+		// The state transition function of a user-defined aggregate may depend
+		// on the order of its input.
+		return true
+	}
 	switch a.def.Name {
 	case "array_agg", "array_cat_agg", "concat_agg", "string_agg", "json_agg",
 		"jsonb_agg", "json_object_agg", "jsonb_object_agg", "st_makeline",
@@ -380,7 +385,11 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 
 		// Construct the aggregate function from its name and arguments and store
 		// it in the corresponding scope column.
-		aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		if agg.def.Overload.UDFAggregate != nil {
+			aggCols[i].scalar = b.constructUDFAggregate(agg.FuncExpr, args[0])
+		} else {
+			aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		}
 
 		// Wrap the aggregate function with an AggDistinct operator if DISTINCT
 		// was specified in the query.
@@ -694,11 +703,12 @@ func (b *Builder) buildAggregateFunction(
 ) *aggregateInfo {
 	tempScopeColsBefore := len(tempScope.cols)
 
+	argExprs := aggregateArgExprs(f)
 	info := aggregateInfo{
 		FuncExpr: f,
 		def:      *def,
 		distinct: (f.Type == tree.DistinctFuncType),
-		args:     make(memo.ScalarListExpr, len(f.Exprs)),
+		args:     make(memo.ScalarListExpr, len(argExprs)),
 	}
 
 	// Temporarily set b.subquery to nil so we don't add outer columns to the
@@ -707,7 +717,7 @@ func (b *Builder) buildAggregateFunction(
 	b.subquery = nil
 	defer func() { b.subquery = subq }()
 
-	for i, pexpr := range f.Exprs {
+	for i, pexpr := range argExprs {
 		info.args[i] = b.buildAggArg(pexpr.(tree.TypedExpr), &info, tempScope, fromScope)
 	}
 
@@ -176,6 +176,11 @@ func init() {
 // ON CONFLICT clause is present, since it joins a new set of rows to the input
 // and thereby scrambles the input ordering.
 func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope) {
+	// Route the INSERT to the INSTEAD OF triggers of a view, if applicable.
+	if target := b.resolveInsteadOfTarget(ins.Table, privilege.INSERT, tree.TriggerEventInsert); target != nil {
+		return b.buildInsteadOfInsert(ins, inScope, target)
+	}
+
 	// Find which table we're working on, check the permissions.
 	tab, depName, alias, refColumns := b.resolveTableForMutation(ins.Table, privilege.INSERT)
 
@@ -246,7 +251,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 		// Target columns are explicitly specified by name.
 		mb.addTargetNamedColsForInsert(ins.Columns)
 	} else {
-		values := mb.extractValuesInput(ins.Rows)
+		values := extractValuesInput(ins.Rows)
 		if values != nil && len(values.Rows) > 0 {
 			// Target columns are implicitly targeted by VALUES expression in the
 			// same order they appear in the target table schema.
@@ -286,6 +291,16 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	// See mutationBuilder.buildCheckInputScan.
 	mb.insertExpr = mb.outScope.expr
 
+	// Project statement-level BEFORE triggers. UPSERT and INSERT..ON CONFLICT DO
+	// UPDATE statements fire both INSERT and UPDATE triggers.
+	if ins.OnConflict == nil || ins.OnConflict.DoNothing {
+		mb.buildStatementLevelBeforeTriggers(false /* cascade */, tree.TriggerEventInsert)
+	} else {
+		mb.buildStatementLevelBeforeTriggers(
+			false /* cascade */, tree.TriggerEventInsert, tree.TriggerEventUpdate,
+		)
+	}
+
 	var returning *tree.ReturningExprs
 	if resultsNeeded(ins.Returning) {
 		returning = ins.Returning.(*tree.ReturningExprs)
@@ -752,7 +767,7 @@ func (mb *mutationBuilder) buildInsert(returning *tree.ReturningExprs) {
 
 	mb.buildFKChecksForInsert()
 
-	mb.buildRowLevelAfterTriggers(opt.InsertOp)
+	mb.buildAfterTriggers(opt.InsertOp)
 
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
@@ -969,7 +984,7 @@ func (mb *mutationBuilder) buildUpsert(returning *tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpsert()
 
-	mb.buildRowLevelAfterTriggers(opt.InsertOp)
+	mb.buildAfterTriggers(opt.InsertOp)
 
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
@@ -57,7 +57,7 @@ func (b *Builder) buildJoin(
 	b.validateJoinTableNames(leftScope, rightScope)
 
 	var flags memo.JoinFlags
-	switch join.Hint {
+	switch hint := b.hintedJoinAlgorithm(join.Hint, joinType); hint {
 	case "":
 	case tree.AstHash:
 		telemetry.Inc(sqltelemetry.HashJoinHintUseCounter)
@@ -95,7 +95,7 @@ func (b *Builder) buildJoin(
 
 	default:
 		panic(pgerror.Newf(
-			pgcode.FeatureNotSupported, "join hint %s not supported", join.Hint,
+			pgcode.FeatureNotSupported, "join hint %s not supported", hint,
 		))
 	}
 
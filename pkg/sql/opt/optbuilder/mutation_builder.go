@@ -196,7 +196,7 @@ type mutationBuilder struct {
 	// cascades contains foreign key check cascades; see buildFK* methods.
 	cascades memo.FKCascades
 
-	// afterTriggers contains AFTER triggers; see buildRowLevelAfterTriggers.
+	// afterTriggers contains AFTER triggers; see buildAfterTriggers.
 	afterTriggers *memo.AfterTriggers
 
 	// withID is nonzero if we need to buffer the input for FK or uniqueness
@@ -322,7 +322,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 			includeSystem:    true,
 			includeInverted:  false,
 		}),
-		indexFlags,
+		mb.b.hintedIndexFlags(mb.tab, indexFlags),
 		noRowLocking,
 		inScope,
 		false, /* disableNotVisibleIndex */
@@ -433,7 +433,7 @@ func (mb *mutationBuilder) buildInputForDelete(
 			includeSystem:    true,
 			includeInverted:  false,
 		}),
-		indexFlags,
+		mb.b.hintedIndexFlags(mb.tab, indexFlags),
 		noRowLocking,
 		inScope,
 		false, /* disableNotVisibleIndex */
@@ -557,7 +557,7 @@ func (mb *mutationBuilder) addTargetCol(ord int) {
 // extractValuesInput tests whether the given input is a VALUES clause with no
 // WITH, ORDER BY, or LIMIT modifier. If so, it's returned, otherwise nil is
 // returned.
-func (mb *mutationBuilder) extractValuesInput(inputRows *tree.Select) *tree.ValuesClause {
+func extractValuesInput(inputRows *tree.Select) *tree.ValuesClause {
 	if inputRows == nil {
 		return nil
 	}
@@ -569,7 +569,7 @@ func (mb *mutationBuilder) extractValuesInput(inputRows *tree.Select) *tree.Valu
 
 	// Discard parentheses.
 	if parens, ok := inputRows.Select.(*tree.ParenSelect); ok {
-		return mb.extractValuesInput(parens.Select)
+		return extractValuesInput(parens.Select)
 	}
 
 	if values, ok := inputRows.Select.(*tree.ValuesClause); ok {
@@ -592,7 +592,7 @@ func (mb *mutationBuilder) extractValuesInput(inputRows *tree.Select) *tree.Valu
 // replaceDefaultExprs returns a VALUES expression with replaced DEFAULT values,
 // or just the unchanged input expression if there are no DEFAULT values.
 func (mb *mutationBuilder) replaceDefaultExprs(inRows *tree.Select) (outRows *tree.Select) {
-	values := mb.extractValuesInput(inRows)
+	values := extractValuesInput(inRows)
 	if values == nil || len(values.Rows) == 0 {
 		return inRows
 	}
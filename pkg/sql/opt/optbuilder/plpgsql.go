@@ -188,6 +188,21 @@ type plpgsqlBuilder struct {
 	// building their body statements.
 	outScope *scope
 
+	// resultBuffer is set for a set-returning routine. RETURN NEXT and RETURN
+	// QUERY statements add rows to the buffer, which make up the result of the
+	// routine.
+	resultBuffer *tree.RoutineResultBuffer
+
+	// multiColOutput is true if the rows added to the result buffer should have
+	// one column for each element of the (composite) return type, rather than a
+	// single column. This is the case for a set-returning routine used as a data
+	// source.
+	multiColOutput bool
+
+	// hasNestedTxnControl is true if the routine contains a CALL statement that
+	// invokes a procedure which may COMMIT or ROLLBACK the current transaction.
+	hasNestedTxnControl bool
+
 	routineName  string
 	isProcedure  bool
 	buildSQL     bool
@@ -209,18 +224,22 @@ func newPLpgSQLBuilder(
 	returnType *types.T,
 	isProcedure bool,
 	buildSQL bool,
+	resultBuffer *tree.RoutineResultBuffer,
+	multiColOutput bool,
 	outScope *scope,
 ) *plpgsqlBuilder {
 	const initialBlocksCap = 2
 	b := &plpgsqlBuilder{
-		ob:          ob,
-		colRefs:     colRefs,
-		returnType:  returnType,
-		blocks:      make([]plBlock, 0, initialBlocksCap),
-		routineName: routineName,
-		isProcedure: isProcedure,
-		buildSQL:    buildSQL,
-		outScope:    outScope,
+		ob:             ob,
+		colRefs:        colRefs,
+		returnType:     returnType,
+		blocks:         make([]plBlock, 0, initialBlocksCap),
+		routineName:    routineName,
+		isProcedure:    isProcedure,
+		buildSQL:       buildSQL,
+		resultBuffer:   resultBuffer,
+		multiColOutput: multiColOutput,
+		outScope:       outScope,
 	}
 	// Build the initial block for the routine parameters, which are considered
 	// PL/pgSQL variables.
@@ -309,7 +328,7 @@ func (b *plpgsqlBuilder) buildRootBlock(
 	// Initialize OUT parameters to NULL. Note that the initial block for
 	// parameters was already created in newPLpgSQLBuilder().
 	for _, param := range routineParams {
-		if param.class != tree.RoutineParamOut || param.name == "" {
+		if !tree.IsOutOnlyParamClass(param.class) || param.name == "" {
 			continue
 		}
 		s = b.addPLpgSQLAssign(
@@ -319,21 +338,18 @@ func (b *plpgsqlBuilder) buildRootBlock(
 	if b.isProcedure {
 		var tc transactionControlVisitor
 		ast.Walk(&tc, astBlock)
-		if tc.foundTxnControlStatement {
-			if b.ob.insideNestedPLpgSQLCall {
-				// Disallow transaction control statements in nested routines for now.
-				// TODO(#122266): once we support this, make sure to validate that
-				// transaction control statements are only allowed in a nested procedure
-				// when all ancestors are procedures or DO blocks.
-				panic(unimplemented.NewWithIssue(122266,
-					"transaction control statements in nested routines",
-				))
-			}
+		if tc.foundTxnControlStatement || tc.foundCallStatement {
 			// Disable stable folding, since different parts of the routine can be run
-			// in different transactions.
+			// in different transactions. Note that this is also possible when a
+			// nested CALL statement invokes a procedure that uses COMMIT or ROLLBACK.
 			b.ob.factory.FoldingControl().TemporarilyDisallowStableFolds(func() {
 				s = b.buildBlock(astBlock, s)
 			})
+			if b.ob.insideNestedPLpgSQLCall && (tc.foundTxnControlStatement || b.hasNestedTxnControl) {
+				// Notify the calling procedure that it must be able to resume
+				// execution after this procedure commits or aborts the transaction.
+				b.ob.nestedCallHasTxnControl = true
+			}
 			return s
 		}
 	}
@@ -359,7 +375,7 @@ func (b *plpgsqlBuilder) pushNewBlock(astBlock *ast.Block) *plBlock {
 		// If the current block or some ancestor block has an exception handler, it
 		// is necessary to maintain the BlockState with a reference to the parent
 		// BlockState (if any).
-		block.state = &tree.BlockState{}
+		block.state = &tree.BlockState{InProcedure: b.isProcedure}
 		if parent := b.parentBlock(); parent != nil {
 			block.state.Parent = parent.state
 		}
@@ -432,6 +448,11 @@ func (b *plpgsqlBuilder) buildBlock(astBlock *ast.Block, s *scope) *scope {
 	// RETURN statements. This has to happen after building the declaration
 	// block because RETURN statements can reference declared variables.
 	if b.returnType.Identical(types.AnyTuple) {
+		if b.resultBuffer != nil {
+			// The type of the rows added by RETURN NEXT and RETURN QUERY statements
+			// is not inferred.
+			panic(wildcardReturnTypeErr)
+		}
 		recordVisitor := newRecordTypeVisitor(b.ob.ctx, b.ob.semaCtx, s, astBlock)
 		ast.Walk(recordVisitor, astBlock)
 		if rtyp := recordVisitor.typ; rtyp == nil || rtyp.Identical(types.AnyTuple) {
@@ -499,11 +520,24 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			return b.buildBlock(t, s)
 
 		case *ast.Return:
+			// RETURN from within a loop over a cursor must close the cursor of the
+			// loop before returning.
+			if b.hasLoopCursorsAbove(0) {
+				return b.closeLoopCursors(s, 0 /* minIdx */, stmts[i:i+1])
+			}
 			// If the routine has OUT-parameters or a VOID return type, the RETURN
 			// statement must have no expression. Otherwise, the RETURN statement must
 			// have a non-empty expression.
 			expr := t.Expr
-			if b.hasOutParam() {
+			if b.resultBuffer != nil {
+				// The result of a set-returning routine is built by RETURN NEXT and
+				// RETURN QUERY statements, so RETURN only ends execution. The NULL
+				// return value is discarded.
+				if expr != nil {
+					panic(returnWithSetOfErr)
+				}
+				expr = tree.DNull
+			} else if b.hasOutParam() {
 				if expr != nil {
 					panic(returnWithOUTParameterErr)
 				}
@@ -531,6 +565,78 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			b.ob.constructProjectForScope(s, returnScope)
 			return returnScope
 
+		case *ast.ReturnNext:
+			// RETURN NEXT adds a row to the result of a set-returning routine, and
+			// then continues execution. The row is added by a volatile continuation
+			// whose first body statement is directed into the result buffer.
+			if b.resultBuffer == nil {
+				panic(returnNextWithoutSetOfErr)
+			}
+			expr := t.Expr
+			if b.hasOutParam() {
+				if expr != nil {
+					panic(returnNextWithOUTParameterErr)
+				}
+				expr = b.makeReturnForOutParams()
+			}
+			if expr == nil {
+				panic(emptyReturnNextErr)
+			}
+			nextCon := b.makeContinuation("_stmt_return_next")
+			nextCon.def.Volatility = volatility.Volatile
+			nextCon.def.AppendToResultBuffer = b.resultBuffer
+			nextScope := nextCon.s.push()
+			nextScalar := b.buildSQLExpr(expr, b.returnType, nextCon.s)
+			nextColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_return_next"))
+			nextCol := b.ob.synthesizeColumn(
+				nextScope, nextColName, b.returnType, nil /* expr */, nextScalar,
+			)
+			b.ob.constructProjectForScope(nextCon.s, nextScope)
+			if b.multiColOutput {
+				// Expand the composite value into one column for each element.
+				nextScope = b.expandTupleColumn(nextScope, nextCol)
+			}
+			b.appendBodyStmt(&nextCon, nextScope)
+			b.appendPlpgSQLStmts(&nextCon, stmts[i+1:])
+			return b.callContinuation(&nextCon, s)
+
+		case *ast.ReturnQuery:
+			// RETURN QUERY adds the rows of a query to the result of a set-returning
+			// routine, and then continues execution. It is handled similarly to
+			// RETURN NEXT, except that the rows are streamed from the query into the
+			// result buffer.
+			if b.resultBuffer == nil {
+				panic(returnQueryWithoutSetOfErr)
+			}
+			if t.DynamicQuery != nil {
+				// RETURN QUERY EXECUTE adds the rows of a dynamic query, which is
+				// handled by a loop over the rows of the query.
+				exitCon := b.makeContinuationWithTyp("loop_exit", "" /* label */, continuationLoopExit)
+				b.appendPlpgSQLStmts(&exitCon, stmts[i+1:])
+				b.pushContinuation(exitCon)
+				defer b.popContinuation()
+				return b.handleReturnQueryExecute(s, t, &exitCon)
+			}
+			queryCon := b.makeContinuation("_stmt_return_query")
+			queryCon.def.Volatility = volatility.Volatile
+			queryCon.def.AppendToResultBuffer = b.resultBuffer
+			queryScope := b.buildSQLStatement(t.SqlStmt, queryCon.s)
+			b.appendBodyStmt(&queryCon, b.shapeReturnQuery(queryScope))
+			b.appendPlpgSQLStmts(&queryCon, stmts[i+1:])
+			return b.callContinuation(&queryCon, s)
+
+		case *ast.Perform:
+			// PERFORM executes a query and discards its result. It is equivalent to
+			// a SQL statement without an INTO clause, so it is handled by a simple
+			// rewrite:
+			//
+			//   PERFORM [query];
+			//   =>
+			//   SELECT [query];
+			//
+			execStmt := &ast.Execute{SqlStmt: t.SqlStmt}
+			return b.buildPLpgSQLStatements(b.prependStmt(execStmt, stmts[i+1:]), s)
+
 		case *ast.Assignment:
 			// Assignment (:=) is handled by projecting a new column with the same
 			// name as the variable being assigned.
@@ -669,12 +775,23 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			case *ast.IntForLoopControl:
 				// FOR target IN [ REVERSE ] expr .. expr [ BY expr ] LOOP ...
 				return b.handleIntForLoop(s, t, c)
+			case *ast.QueryForLoopControl, *ast.CursorForLoopControl, *ast.DynamicForLoopControl:
+				// FOR target IN query LOOP ...
+				// FOR recordvar IN bound_cursorvar [ ( arguments ) ] LOOP ...
+				// FOR target IN EXECUTE text_expression [ USING expression [, ...] ] LOOP ...
+				return b.handleQueryForLoop(s, t, &exitCon)
 			default:
-				panic(errors.WithDetail(unsupportedPLStmtErr,
-					"query and cursor FOR loops are not yet supported",
-				))
+				panic(errors.AssertionFailedf("unexpected FOR loop control: %T", c))
 			}
 
+		case *ast.ForEachArray:
+			// FOREACH target [ SLICE number ] IN ARRAY expression LOOP ...
+			exitCon := b.makeContinuationWithTyp("loop_exit", t.Label, continuationLoopExit)
+			b.appendPlpgSQLStmts(&exitCon, stmts[i+1:])
+			b.pushContinuation(exitCon)
+			defer b.popContinuation()
+			return b.handleForEachLoop(s, t)
+
 		case *ast.Exit:
 			if t.Condition != nil {
 				// EXIT with a condition is syntactic sugar for EXIT inside an IF stmt.
@@ -692,6 +809,11 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 				conTypes |= continuationBlockExit
 			}
 			if con := b.getContinuation(conTypes, t.Label); con != nil {
+				// Close the cursors of any loops nested within the target that are
+				// exited along the way.
+				if idx := b.continuationIdx(con); b.hasLoopCursorsAbove(idx + 1) {
+					return b.closeLoopCursors(s, idx+1, stmts[i:i+1])
+				}
 				return b.callContinuation(con, s)
 			}
 			if t.Label == unspecifiedLabel {
@@ -700,6 +822,9 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			if t.Label == b.rootBlock().label {
 				// An EXIT from the root block has the same handling as when the routine
 				// ends with no RETURN statement.
+				if b.hasLoopCursorsAbove(0) {
+					return b.closeLoopCursors(s, 0 /* minIdx */, stmts[i:i+1])
+				}
 				return b.handleEndOfFunction(s)
 			}
 			if t.Label == b.routineName {
@@ -735,6 +860,11 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 						"block label \"%s\" cannot be used in CONTINUE", t.Label,
 					))
 				}
+				// Close the cursors of any loops nested within the target that are
+				// exited along the way.
+				if idx := b.continuationIdx(con); b.hasLoopCursorsAbove(idx + 1) {
+					return b.closeLoopCursors(s, idx+1, stmts[i:i+1])
+				}
 				return b.callContinuation(con, s)
 			}
 			if t.Label == unspecifiedLabel {
@@ -859,22 +989,38 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 					"variable \"%s\" must be of type cursor or refcursor", t.CurVar,
 				))
 			}
-			// Initialize the routine with the information needed to pipe the first
-			// body statement into a cursor.
-			query := b.resolveOpenQuery(t)
-			fmtCtx := b.ob.evalCtx.FmtCtx(tree.FmtSimple)
-			fmtCtx.FormatNode(query)
-			openCon.def.CursorDeclaration = &tree.RoutineOpenCursor{
-				NameArgIdx: source.(*scopeColumn).getParamOrd(),
-				Scroll:     t.Scroll,
-				CursorSQL:  fmtCtx.CloseAndGetString(),
-			}
-			openScope := b.buildSQLStatement(query, openCon.s)
-			if openScope.expr.Relational().CanMutate {
-				// Cursors with mutations are invalid.
-				panic(cursorMutationErr)
-			}
-			b.appendBodyStmt(&openCon, openScope)
+			if t.DynamicQuery != nil {
+				// OPEN ... FOR EXECUTE opens the cursor over a dynamic query, which is
+				// executed by the crdb_internal.plpgsql_open_dynamic_cursor builtin
+				// function.
+				if b.isBoundCursor(t.CurVar) {
+					panic(errors.WithHintf(
+						pgerror.New(pgcode.Syntax, "syntax error at or near \"FOR\""),
+						"cannot specify a query during OPEN for bound cursor \"%s\"", t.CurVar,
+					))
+				}
+				openScope := b.buildOpenDynamicCursor(
+					openCon.s, source.(*scopeColumn), t.DynamicQuery, t.Params, false, /* withMarker */
+				)
+				b.appendBodyStmt(&openCon, openScope)
+			} else {
+				// Initialize the routine with the information needed to pipe the first
+				// body statement into a cursor.
+				query := b.resolveOpenQuery(t)
+				fmtCtx := b.ob.evalCtx.FmtCtx(tree.FmtSimple)
+				fmtCtx.FormatNode(query)
+				openCon.def.CursorDeclaration = &tree.RoutineOpenCursor{
+					NameArgIdx: source.(*scopeColumn).getParamOrd(),
+					Scroll:     t.Scroll,
+					CursorSQL:  fmtCtx.CloseAndGetString(),
+				}
+				openScope := b.buildSQLStatement(query, openCon.s)
+				if openScope.expr.Relational().CanMutate {
+					// Cursors with mutations are invalid.
+					panic(cursorMutationErr)
+				}
+				b.appendBodyStmt(&openCon, openScope)
+			}
 			b.appendPlpgSQLStmts(&openCon, stmts[i+1:])
 
 			// Build a statement to generate a unique name for the cursor if one
@@ -883,7 +1029,8 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			// resulting projected column as input to the OPEN continuation.
 			nameCon := b.makeContinuation("_gen_cursor_name")
 			nameCon.def.Volatility = volatility.Volatile
-			nameScope := b.buildCursorNameGen(&nameCon, t.CurVar)
+			_, nameSource, _, _ := nameCon.s.FindSourceProvidingColumn(b.ob.ctx, t.CurVar)
+			nameScope := b.buildCursorNameGen(&nameCon, nameSource.(*scopeColumn))
 			b.appendBodyStmt(&nameCon, b.callContinuation(&openCon, nameScope))
 			return b.callContinuation(&nameCon, s)
 
@@ -894,11 +1041,6 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			// that calls the builtin function.
 			closeCon := b.makeContinuation("_stmt_close")
 			closeCon.def.Volatility = volatility.Volatile
-			const closeFnName = "crdb_internal.plpgsql_close"
-			props, overloads := builtinsregistry.GetBuiltinProperties(closeFnName)
-			if len(overloads) != 1 {
-				panic(errors.AssertionFailedf("expected one overload for %s", closeFnName))
-			}
 			_, source, _, err := closeCon.s.FindSourceProvidingColumn(b.ob.ctx, t.CurVar)
 			if err != nil {
 				if pgerror.GetPGCode(err) == pgcode.UndefinedColumn {
@@ -911,15 +1053,7 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 					"variable \"%s\" must be of type cursor or refcursor", t.CurVar,
 				))
 			}
-			closeCall := b.ob.factory.ConstructFunction(
-				memo.ScalarListExpr{b.ob.factory.ConstructVariable(source.(*scopeColumn).id)},
-				&memo.FunctionPrivate{
-					Name:       closeFnName,
-					Typ:        types.Int,
-					Properties: props,
-					Overload:   &overloads[0],
-				},
-			)
+			closeCall := b.makeCloseCall(b.ob.factory.ConstructVariable(source.(*scopeColumn).id))
 			closeColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_close"))
 			closeScope := closeCon.s.push()
 			b.ob.synthesizeColumn(closeScope, closeColName, types.Int, nil /* expr */, closeCall)
@@ -974,6 +1108,36 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			b.appendBodyStmt(&fetchCon, intoScope)
 			return b.callContinuation(&fetchCon, s)
 
+		case *ast.DynamicExecute:
+			// EXECUTE statements execute a dynamic SQL command, which is a string
+			// that is only known at runtime. The command cannot be planned along with
+			// the rest of the routine, so it is executed by the
+			// crdb_internal.plpgsql_execute builtin function in the routine's
+			// transaction. If there is an INTO target, the first row of the result is
+			// assigned to the target variables, similar to a FETCH statement.
+			b.checkDuplicateTargets(t.Target, "INTO")
+			execCon := b.makeContinuation("_stmt_dyn_exec")
+			execCon.def.Volatility = volatility.Volatile
+			execScope := b.buildDynamicExecute(execCon.s, t)
+			if len(t.Target) == 0 {
+				b.appendBodyStmt(&execCon, execScope)
+				b.appendPlpgSQLStmts(&execCon, stmts[i+1:])
+				return b.callContinuation(&execCon, s)
+			}
+			intoScope := b.projectTupleAsIntoTarget(execScope, t.Target)
+
+			// Add a barrier in case the projected variables are never referenced
+			// again, to prevent column-pruning rules from removing the EXECUTE.
+			b.ob.addBarrier(intoScope)
+
+			// Call a continuation for the remaining PLpgSQL statements from the newly
+			// built statement that has updated variables.
+			retCon := b.makeContinuation("_stmt_dyn_exec_ret")
+			b.appendPlpgSQLStmts(&retCon, stmts[i+1:])
+			intoScope = b.callContinuation(&retCon, intoScope)
+			b.appendBodyStmt(&execCon, intoScope)
+			return b.callContinuation(&execCon, s)
+
 		case *ast.Null:
 			// PL/pgSQL NULL statements are a no-op.
 			continue
@@ -984,13 +1148,10 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			// During execution, a TxnControlExpr directs the session to commit or
 			// rollback the transaction, and supplies a plan for the continuation to
 			// run in the new transaction.
-			if t.Chain {
-				panic(txnControlWithChainErr)
-			}
 			// NOTE: postgres doesn't make the following checks until runtime (see
-			// also #119750).
-			// TODO(#88198): check the calling context, since transaction control
-			// statements are only allowed through a stack of SPs and DO blocks.
+			// also #119750). The calling context is checked when building a nested
+			// CALL statement, since transaction control statements are only allowed
+			// through a stack of SPs and DO blocks.
 			if b.hasExceptionHandler() {
 				panic(txnControlWithExceptionErr)
 			}
@@ -1025,7 +1186,7 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			con := b.makeContinuation(name)
 			con.def.Volatility = volatility.Volatile
 			b.appendPlpgSQLStmts(&con, stmts)
-			return b.callContinuationWithTxnOp(&con, s, txnOpType, txnModes)
+			return b.callContinuationWithTxnOp(&con, s, txnOpType, txnModes, t.Chain)
 
 		case *ast.Call:
 			// Build a continuation that will execute the procedure, and then the
@@ -1041,10 +1202,24 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 			procTyp := proc.ResolvedType()
 			colName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_call"))
 			col := b.ob.synthesizeColumn(callScope, colName, procTyp, nil /* expr */, nil /* scalar */)
-			b.ob.withinNestedPLpgSQLCall(func() {
+			hasTxnControl := b.ob.withinNestedPLpgSQLCall(func() {
 				col.scalar = b.ob.buildRoutine(proc, def, callCon.s, callScope, b.colRefs)
 			})
 			b.ob.constructProjectForScope(callCon.s, callScope)
+			if hasTxnControl {
+				// The called procedure may COMMIT or ROLLBACK. This is only allowed if
+				// every routine in the call stack is a procedure or DO block without
+				// an exception handler.
+				// NOTE: postgres doesn't make these checks until runtime (see also
+				// #119750).
+				if b.hasExceptionHandler() {
+					panic(txnControlWithExceptionErr)
+				}
+				if !b.isProcedure {
+					panic(txnInUDFErr)
+				}
+				b.hasNestedTxnControl = true
+			}
 
 			// Collect any target variables in OUT-parameter position. The result of
 			// the procedure will be assigned to these variables, if any.
@@ -1067,6 +1242,43 @@ func (b *plpgsqlBuilder) buildPLpgSQLStatements(stmts []ast.Statement, s *scope)
 				}
 			}
 			b.checkDuplicateTargets(target, "CALL")
+			if hasTxnControl {
+				// Build a continuation for the remaining PL/pgSQL statements that
+				// receives the result of the nested procedure call as its last
+				// argument. If the nested procedure pauses execution to COMMIT or
+				// ROLLBACK, this continuation is used to resume execution of the
+				// calling procedure in the new transaction after the nested procedure
+				// finishes (see memo.TxnControlResume).
+				resumeCon := b.makeContinuation("_stmt_call_resume")
+				resumeCon.def.Volatility = volatility.Volatile
+				resultColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_call_result"))
+				resultCol := b.ob.synthesizeColumn(
+					resumeCon.s, resultColName, procTyp, nil /* expr */, nil, /* scalar */
+				)
+				resultCol.setParamOrd(len(resumeCon.def.Params))
+				resumeCon.def.Params = append(resumeCon.def.Params, resultCol.id)
+				props, outCols := b.makeTxnControlProps()
+				resumeCon.def.TxnControlResume = &memo.TxnControlResume{Props: props, OutCols: outCols}
+				if len(target) == 0 {
+					b.appendPlpgSQLStmts(&resumeCon, stmts[i+1:])
+				} else {
+					// Assign the result of the nested procedure to the target variables,
+					// and then call a continuation for the remaining statements.
+					resultScope := resumeCon.s.push()
+					b.ob.synthesizeColumn(
+						resultScope, resultColName, procTyp, nil /* expr */, b.ob.factory.ConstructVariable(resultCol.id),
+					)
+					b.ob.constructProjectForScope(resumeCon.s, resultScope)
+					intoScope := b.projectTupleAsIntoTarget(resultScope, target)
+					retCon := b.makeContinuation("_stmt_call_ret")
+					b.appendPlpgSQLStmts(&retCon, stmts[i+1:])
+					intoScope = b.callContinuation(&retCon, intoScope)
+					b.appendBodyStmt(&resumeCon, intoScope)
+				}
+				resumeScope := b.callContinuationWithCallResult(&resumeCon, callScope, col.id)
+				b.appendBodyStmt(&callCon, resumeScope)
+				return b.callContinuation(&callCon, s)
+			}
 			if len(target) == 0 {
 				// When there is no INTO target, build the nested procedure call into a
 				// body statement that is only executed for its side effects.
@@ -1247,6 +1459,556 @@ func (b *plpgsqlBuilder) handleIntForLoop(
 	return b.callContinuation(&loopCon, s)
 }
 
+// handleQueryForLoop constructs the plan for a FOR loop over the rows of a
+// query, a bound cursor, or a dynamic query. The rows are read through a
+// cursor; see buildCursorLoop for details.
+func (b *plpgsqlBuilder) handleQueryForLoop(
+	s *scope, forLoop *ast.ForLoop, exitCon *continuation,
+) *scope {
+	// Resolve the query before pushing the implicit block for the loop.
+	loop := cursorLoop{label: forLoop.Label}
+	switch c := forLoop.Control.(type) {
+	case *ast.QueryForLoopControl:
+		loop.query = c.Query
+		if _, ok := loop.query.(*tree.Select); !ok {
+			panic(errors.WithDetailf(unsupportedPLStmtErr,
+				"FOR loops over %s statements are not yet supported", loop.query.StatementTag(),
+			))
+		}
+	case *ast.CursorForLoopControl:
+		if len(forLoop.Target) != 1 {
+			panic(cursorForLoopTargetErr)
+		}
+		if !b.isBoundCursor(c.CursorVar) {
+			panic(cursorForLoopUnboundErr)
+		}
+		loop.cursorVar = c.CursorVar
+		loop.query = b.resolveOpenQuery(&ast.Open{CurVar: loop.cursorVar})
+	case *ast.DynamicForLoopControl:
+		loop.dynamicQuery, loop.dynamicParams = c.Query, c.Params
+	}
+
+	// Build an implicit block for the loop. For a cursor FOR loop, it declares
+	// the target variable, which is implicitly declared with the type of the
+	// cursor's rows.
+	b.pushNewBlock(&ast.Block{Label: forLoop.Label})
+	defer b.popBlock()
+	if loop.cursorVar != "" {
+		recordScope := b.buildSQLStatement(loop.query, s.push())
+		contents := make([]*types.T, len(recordScope.cols))
+		labels := make([]string, len(recordScope.cols))
+		for i := range recordScope.cols {
+			contents[i] = recordScope.cols[i].typ
+			labels[i] = string(recordScope.cols[i].name.ReferenceName())
+		}
+		targetTyp := types.MakeLabeledTuple(contents, labels)
+		b.addVariable(forLoop.Target[0], targetTyp)
+		s = b.addPLpgSQLAssign(
+			s, forLoop.Target[0], &tree.CastExpr{Expr: tree.DNull, Type: targetTyp}, noIndirection,
+		)
+	}
+	// The target variables determine the types of the fetched row. Rows from the
+	// cursor are cast to these types, and padded with NULLs if necessary.
+	recordTarget := b.targetIsRecordVar(forLoop.Target)
+	if recordTarget {
+		loop.rowTypes = b.resolveVariableForAssign(forLoop.Target[0]).TupleContents()
+	} else {
+		loop.rowTypes = make([]*types.T, len(forLoop.Target))
+		for i := range forLoop.Target {
+			loop.rowTypes[i] = b.resolveVariableForAssign(forLoop.Target[i])
+		}
+	}
+
+	// On each iteration, assign the current row to the target variables, and
+	// then execute the loop body.
+	loop.buildBody = func(s *scope, rowElem func(i int) opt.ScalarExpr) *scope {
+		assignScope := s.push()
+		if recordTarget {
+			typ := b.resolveVariableForAssign(forLoop.Target[0])
+			elems := make(memo.ScalarListExpr, len(loop.rowTypes))
+			for i := range elems {
+				elems[i] = rowElem(i)
+			}
+			b.ob.synthesizeColumn(
+				assignScope, scopeColName(forLoop.Target[0]), typ, nil, /* expr */
+				b.ob.factory.ConstructTuple(elems, typ),
+			)
+		} else {
+			for i := range forLoop.Target {
+				b.ob.synthesizeColumn(
+					assignScope, scopeColName(forLoop.Target[i]), loop.rowTypes[i], nil /* expr */, rowElem(i),
+				)
+			}
+		}
+		b.ob.constructProjectForScope(s, assignScope)
+		return b.buildPLpgSQLStatements(forLoop.Body, assignScope)
+	}
+	return b.buildCursorLoop(s, &loop, exitCon)
+}
+
+// handleReturnQueryExecute constructs the plan for a RETURN QUERY EXECUTE
+// statement. Since the query is not known until runtime, it is executed as a
+// loop over the rows of the dynamic query, which adds each row to the result
+// buffer of the routine.
+func (b *plpgsqlBuilder) handleReturnQueryExecute(
+	s *scope, returnQuery *ast.ReturnQuery, exitCon *continuation,
+) *scope {
+	b.pushNewBlock(&ast.Block{})
+	defer b.popBlock()
+	loop := cursorLoop{
+		dynamicQuery:  returnQuery.DynamicQuery,
+		dynamicParams: returnQuery.Params,
+	}
+	if b.returnType.Family() == types.TupleFamily {
+		loop.rowTypes = b.returnType.TupleContents()
+	} else {
+		loop.rowTypes = []*types.T{b.returnType}
+	}
+	loop.buildBody = func(s *scope, _ func(i int) opt.ScalarExpr) *scope {
+		// Add the current row to the result buffer in a separate continuation,
+		// which then calls back into the loop continuation. The current row is
+		// passed to the continuation through the hidden row variable.
+		nextCon := b.makeContinuation("_stmt_return_query")
+		nextCon.def.Volatility = volatility.Volatile
+		nextCon.def.AppendToResultBuffer = b.resultBuffer
+		rowCol := nextCon.s.findAnonymousColumnWithMetadataName(loop.rowName)
+		elems := make(memo.ScalarListExpr, len(loop.rowTypes))
+		for i := range elems {
+			// Skip the marker element.
+			elems[i] = b.ob.factory.ConstructColumnAccess(
+				b.ob.factory.ConstructVariable(rowCol.id), memo.TupleOrdinal(i+1),
+			)
+		}
+		var nextScalar opt.ScalarExpr
+		if b.returnType.Family() == types.TupleFamily {
+			nextScalar = b.ob.factory.ConstructTuple(elems, b.returnType)
+		} else {
+			nextScalar = elems[0]
+		}
+		nextScope := nextCon.s.push()
+		nextColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_return_query"))
+		nextCol := b.ob.synthesizeColumn(
+			nextScope, nextColName, b.returnType, nil /* expr */, nextScalar,
+		)
+		b.ob.constructProjectForScope(nextCon.s, nextScope)
+		if b.multiColOutput {
+			// Expand the composite value into one column for each element.
+			nextScope = b.expandTupleColumn(nextScope, nextCol)
+		}
+		b.appendBodyStmt(&nextCon, nextScope)
+		b.appendPlpgSQLStmts(&nextCon, nil /* stmts */)
+		return b.callContinuation(&nextCon, s)
+	}
+	return b.buildCursorLoop(s, &loop, exitCon)
+}
+
+// cursorLoop describes a loop over the rows of a query, which are read through
+// a cursor. See buildCursorLoop.
+type cursorLoop struct {
+	label string
+
+	// query is the statement that is used to open the cursor. It is unset for a
+	// loop over a dynamic query.
+	query tree.Statement
+
+	// dynamicQuery and dynamicParams are the query string expression and USING
+	// parameters for a loop over a dynamic query.
+	dynamicQuery  ast.Expr
+	dynamicParams []ast.Expr
+
+	// cursorVar is the bound cursor variable for a cursor FOR loop. If it is
+	// unset, a hidden variable is declared for the cursor.
+	cursorVar ast.Variable
+
+	// rowTypes are the types that the rows of the cursor are cast to.
+	rowTypes []*types.T
+
+	// cursorName and rowName are the names of the hidden variables for the
+	// cursor and the current row. They are set by buildCursorLoop.
+	cursorName, rowName string
+
+	// buildBody builds the body of the loop within the given scope. rowElem
+	// returns the i-th element of the current row.
+	buildBody func(s *scope, rowElem func(i int) opt.ScalarExpr) *scope
+}
+
+// buildCursorLoop constructs the plan for a loop over the rows of a query. The
+// rows are read through a cursor, which is opened when the loop starts and
+// closed when the loop exits. This avoids materializing the full result of the
+// query, since only the current row is held in a PL/pgSQL variable. The caller
+// is responsible for pushing the implicit block for the loop, in which the
+// hidden variables are declared.
+//
+// If control flow leaves the loop through a RETURN statement, or an EXIT or
+// CONTINUE statement that targets an enclosing loop or block, the cursor is
+// closed by closeLoopCursors. If an error is caught by an exception handler
+// outside the loop, the cursor is closed along with the other cursors opened
+// within the block.
+func (b *plpgsqlBuilder) buildCursorLoop(
+	s *scope, loop *cursorLoop, exitCon *continuation,
+) *scope {
+	// Declare the hidden variables for the loop:
+	//  * A hidden variable for the cursor through which the rows are read. For a
+	//    cursor FOR loop, the bound cursor variable is used instead.
+	//  * A hidden variable for the current row. The first element of the row is
+	//    a marker that is NULL once the cursor is exhausted, and the remaining
+	//    elements are the columns of the row.
+	//
+	// The names of the hidden variables are unique, so that they are resolved
+	// correctly within nested loops.
+	loop.cursorName = b.makeIdentifier("_loop_cursor")
+	loop.rowName = b.makeIdentifier("_loop_row")
+	if loop.cursorVar == "" {
+		b.addHiddenVariable(loop.cursorName, types.RefCursor)
+	}
+	rowTypes := make([]*types.T, 0, len(loop.rowTypes)+1)
+	rowTypes = append(rowTypes, types.Bool)
+	rowTypes = append(rowTypes, loop.rowTypes...)
+	rowType := types.MakeTuple(rowTypes)
+	b.addHiddenVariable(loop.rowName, rowType)
+
+	// Initialize the new variables to NULL.
+	if loop.cursorVar == "" {
+		s = b.assignToHiddenVariable(s, loop.cursorName, tree.DNull)
+	}
+	s = b.assignToHiddenVariable(s, loop.rowName, &tree.CastExpr{Expr: tree.DNull, Type: rowType})
+
+	// When referencing a hidden variable, make sure to check the correct scope,
+	// as different columns can represent the variable depending on context.
+	refCursor := func(s *scope) *scopeColumn {
+		if loop.cursorVar == "" {
+			return s.findAnonymousColumnWithMetadataName(loop.cursorName)
+		}
+		_, source, _, err := s.FindSourceProvidingColumn(b.ob.ctx, loop.cursorVar)
+		if err != nil {
+			panic(err)
+		}
+		return source.(*scopeColumn)
+	}
+
+	// The looping will be implemented by three continuations: one that fetches
+	// the next row and executes the loop body, one that closes the cursor and
+	// exits the loop, and one that opens the cursor before the first iteration.
+	//
+	// First, build the continuation that closes the cursor before calling the
+	// continuation that resumes execution after the loop. EXIT statements within
+	// the loop body will call into this continuation.
+	closeCon := b.makeContinuationWithTyp("loop_exit_close", loop.label, continuationLoopExit)
+	closeCon.def.Volatility = volatility.Volatile
+	closeCon.loopCursor = refCursor
+	closeCall := b.makeCloseCall(b.ob.factory.ConstructVariable(refCursor(closeCon.s).id))
+	closeColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_close"))
+	closeScope := closeCon.s.push()
+	b.ob.synthesizeColumn(closeScope, closeColName, types.Int, nil /* expr */, closeCall)
+	b.ob.constructProjectForScope(closeCon.s, closeScope)
+	b.appendBodyStmt(&closeCon, closeScope)
+	exitScope := closeCon.s.push()
+	b.ensureScopeHasExpr(exitScope)
+	b.appendBodyStmt(&closeCon, b.callContinuation(exitCon, exitScope))
+
+	// Next, build the loop continuation. It fetches the next row from the cursor
+	// and checks the marker element. If it is NULL, the loop exits. Otherwise,
+	// the loop body is executed with the current row. Reaching the end of the
+	// loop body or a CONTINUE statement calls recursively into the loop
+	// continuation.
+	b.pushContinuation(closeCon)
+	loopCon := b.makeContinuationWithTyp("stmt_loop", loop.label, continuationLoopContinue)
+	loopCon.def.IsRecursive = true
+	loopCon.def.Volatility = volatility.Volatile
+	b.pushContinuation(loopCon)
+	fetchScope := loopCon.s.push()
+	b.ensureScopeHasExpr(fetchScope)
+	fetchCall := b.makeFetchCall(
+		b.ob.factory.ConstructVariable(refCursor(fetchScope).id),
+		&tree.CursorStmt{FetchType: tree.FetchNormal, Count: 1},
+		rowType,
+	)
+	b.addBarrierIfVolatile(fetchScope, fetchCall)
+	rowScope := fetchScope.push()
+	rowColName := scopeColName("").WithMetadataName(loop.rowName)
+	rowCol := b.ob.synthesizeColumn(rowScope, rowColName, rowType, nil /* expr */, fetchCall)
+	rowColID := rowCol.id
+	b.ob.constructProjectForScope(fetchScope, rowScope)
+	b.ob.addBarrier(rowScope)
+
+	// Build the branch that exits the loop.
+	thenScope := b.buildPLpgSQLStatements([]ast.Statement{&ast.Exit{}}, rowScope.push())
+
+	// Build the branch that executes the loop body.
+	elseScope := rowScope.push()
+	b.ensureScopeHasExpr(elseScope)
+	rowElem := func(i int) opt.ScalarExpr {
+		// Skip the marker element.
+		return b.ob.factory.ConstructColumnAccess(
+			b.ob.factory.ConstructVariable(rowColID), memo.TupleOrdinal(i+1),
+		)
+	}
+	elseScope = loop.buildBody(elseScope, rowElem)
+	b.popContinuation()
+	b.popContinuation()
+
+	// Build a scalar CASE statement that conditionally executes each branch as a
+	// subquery, similar to an IF statement.
+	cond := b.ob.factory.ConstructIs(
+		b.ob.factory.ConstructColumnAccess(
+			b.ob.factory.ConstructVariable(rowColID), memo.TupleOrdinal(0),
+		),
+		memo.NullSingleton,
+	)
+	thenScalar := b.ob.factory.ConstructSubquery(thenScope.expr, &memo.SubqueryPrivate{})
+	elseScalar := b.ob.factory.ConstructSubquery(elseScope.expr, &memo.SubqueryPrivate{})
+	scalar := b.ob.factory.ConstructCase(
+		memo.TrueSingleton, memo.ScalarListExpr{b.ob.factory.ConstructWhen(cond, thenScalar)}, elseScalar,
+	)
+	returnColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_loop"))
+	returnScope := rowScope.push()
+	scalar = b.coerceType(scalar, b.returnType)
+	b.addBarrierIfVolatile(rowScope, scalar)
+	b.ob.synthesizeColumn(returnScope, returnColName, b.returnType, nil /* expr */, scalar)
+	b.ob.constructProjectForScope(rowScope, returnScope)
+	b.appendBodyStmt(&loopCon, returnScope)
+
+	// Build the continuation that opens the cursor, and then calls the loop
+	// continuation.
+	openCon := b.makeContinuation("_stmt_open")
+	openCon.def.Volatility = volatility.Volatile
+	if loop.dynamicQuery != nil {
+		// The dynamic query is executed by a builtin function, which also adds
+		// the marker column to each row.
+		openScope := b.buildOpenDynamicCursor(
+			openCon.s, refCursor(openCon.s), loop.dynamicQuery, loop.dynamicParams, true, /* withMarker */
+		)
+		b.appendBodyStmt(&openCon, openScope)
+	} else {
+		// The marker column is projected before the columns of the query.
+		fmtCtx := b.ob.evalCtx.FmtCtx(tree.FmtSimple)
+		fmtCtx.FormatNode(loop.query)
+		openCon.def.CursorDeclaration = &tree.RoutineOpenCursor{
+			NameArgIdx: refCursor(openCon.s).getParamOrd(),
+			CursorSQL:  fmtCtx.CloseAndGetString(),
+		}
+		queryScope := b.buildSQLStatement(loop.query, openCon.s)
+		if queryScope.expr.Relational().CanMutate {
+			panic(queryForLoopMutationErr)
+		}
+		openScope := queryScope.push()
+		markerColName := scopeColName("").WithMetadataName(b.makeIdentifier("loop_marker"))
+		b.ob.synthesizeColumn(openScope, markerColName, types.Bool, nil /* expr */, memo.TrueSingleton)
+		for i := range queryScope.cols {
+			openScope.appendColumn(&queryScope.cols[i])
+		}
+		openScope.copyOrdering(queryScope)
+		b.ob.constructProjectForScope(queryScope, openScope)
+		b.appendBodyStmt(&openCon, openScope)
+	}
+	loopScope := openCon.s.push()
+	b.ensureScopeHasExpr(loopScope)
+	b.appendBodyStmt(&openCon, b.callContinuation(&loopCon, loopScope))
+
+	// Finally, generate a unique name for the cursor if necessary, and then
+	// call the OPEN continuation. See the handling for OPEN statements.
+	nameCon := b.makeContinuation("_gen_cursor_name")
+	nameCon.def.Volatility = volatility.Volatile
+	nameScope := b.buildCursorNameGen(&nameCon, refCursor(nameCon.s))
+	b.appendBodyStmt(&nameCon, b.callContinuation(&openCon, nameScope))
+	return b.callContinuation(&nameCon, s)
+}
+
+// handleForEachLoop constructs the plan for a FOREACH loop, which iterates
+// over the elements of an array. It is handled similarly to an integer FOR
+// loop, with a hidden counter variable that indexes into the array.
+func (b *plpgsqlBuilder) handleForEachLoop(s *scope, forEach *ast.ForEachArray) *scope {
+	if forEach.Slice != 0 {
+		panic(errors.WithDetail(unsupportedPLStmtErr,
+			"FOREACH with SLICE is not yet supported",
+		))
+	}
+	if len(forEach.Target) != 1 {
+		panic(errors.WithDetail(unsupportedPLStmtErr,
+			"FOREACH with more than one target variable is not yet supported",
+		))
+	}
+	// Determine the type of the array expression, which is needed to declare
+	// the hidden variable that holds the array.
+	arrTyp := types.AnyArray
+	if b.buildSQL {
+		expr, _ := tree.WalkExpr(s, forEach.Expr)
+		typedExpr, err := expr.TypeCheck(b.ob.ctx, b.ob.semaCtx, types.AnyArray)
+		if err != nil {
+			panic(err)
+		}
+		arrTyp = typedExpr.ResolvedType()
+		if arrTyp.Family() != types.ArrayFamily {
+			panic(pgerror.Newf(pgcode.DatatypeMismatch,
+				"FOREACH expression must yield an array, not type %s", arrTyp.SQLString(),
+			))
+		}
+	}
+
+	// Build an implicit block declaring hidden variables for the array, the
+	// upper bound of the array, and an internal counter that is incremented on
+	// each iteration. Unlike an integer FOR loop, the target variable must
+	// already be declared.
+	b.pushNewBlock(&ast.Block{Label: forEach.Label})
+	defer b.popBlock()
+	const (
+		arrayName   = "_loop_array"
+		upperName   = "_loop_upper"
+		counterName = "_loop_counter"
+	)
+	b.addHiddenVariable(arrayName, arrTyp)
+	b.addHiddenVariable(upperName, types.Int)
+	b.addHiddenVariable(counterName, types.Int)
+	s = b.assignToHiddenVariable(s, arrayName, forEach.Expr)
+
+	// When referencing a hidden variable, make sure to check the correct scope,
+	// as different columns can represent the variable depending on context.
+	refHiddenVar := func(s *scope, name string) *scopeColumn {
+		return s.findAnonymousColumnWithMetadataName(name)
+	}
+
+	// Add a runtime check for a NULL array.
+	const severity, detail, hint = "ERROR", "", ""
+	b.addRuntimeCheck(s,
+		memo.ScalarListExpr{
+			b.buildSQLExpr(&tree.IsNullExpr{Expr: refHiddenVar(s, arrayName)}, types.Bool, s),
+		},
+		[]memo.ScalarListExpr{b.ob.makeConstRaiseArgs(
+			severity, "FOREACH expression must not be null", detail, hint,
+			pgcode.NullValueNotAllowed.String(),
+		)},
+	)
+
+	// Initialize the counter and upper bound using the bounds of the array. An
+	// empty array has NULL bounds, in which case the loop body is never executed.
+	arrayBound := func(fnName string, ifNull tree.Datum) tree.Expr {
+		return &tree.CoalesceExpr{Name: "COALESCE", Exprs: tree.Exprs{
+			&tree.FuncExpr{
+				Func:  tree.WrapFunction(fnName),
+				Exprs: tree.Exprs{refHiddenVar(s, arrayName), tree.NewDInt(1)},
+			},
+			ifNull,
+		}}
+	}
+	s = b.assignToHiddenVariable(s, counterName, arrayBound("array_lower", tree.NewDInt(1)))
+	s = b.assignToHiddenVariable(s, upperName, arrayBound("array_upper", tree.DZero))
+
+	// The looping will be implemented by two continuations: one to execute the
+	// loop body, and one to increment the counter variable. The loop body and
+	// increment continuations will call each other recursively.
+	loopCon := b.makeContinuation("stmt_loop")
+	loopCon.def.IsRecursive = true
+	incrementCon := b.makeContinuationWithTyp("stmt_loop_inc", forEach.Label, continuationLoopContinue)
+	incrementCon.def.IsRecursive = true
+	b.pushContinuation(incrementCon)
+
+	// Build the loop body continuation. Build an IF statement that checks whether
+	// the counter variable has exceeded the upper bound, and if not, assigns the
+	// current element to the target variable and executes the loop body.
+	cond := &tree.ComparisonExpr{
+		Operator: treecmp.MakeComparisonOperator(treecmp.LE),
+		Left:     refHiddenVar(loopCon.s, counterName),
+		Right:    refHiddenVar(loopCon.s, upperName),
+	}
+	elem := &tree.IndirectionExpr{
+		Expr: refHiddenVar(loopCon.s, arrayName),
+		Indirection: tree.ArraySubscripts{
+			&tree.ArraySubscript{Begin: refHiddenVar(loopCon.s, counterName)},
+		},
+	}
+	thenBody := b.prependStmt(&ast.Assignment{Var: forEach.Target[0], Value: elem}, forEach.Body)
+	ifStmt := &ast.If{Condition: cond, ThenBody: thenBody, ElseBody: []ast.Statement{&ast.Exit{}}}
+	b.appendPlpgSQLStmts(&loopCon, []ast.Statement{ifStmt})
+	b.popContinuation()
+
+	// Build the increment continuation, which increments the counter and then
+	// calls recursively into the loop body continuation.
+	incScope := incrementCon.s.push()
+	b.ensureScopeHasExpr(incScope)
+	inc := &tree.BinaryExpr{
+		Operator: treebin.MakeBinaryOperator(treebin.Plus),
+		Left:     refHiddenVar(incScope, counterName),
+		Right:    tree.NewDInt(1),
+	}
+	incScope = b.assignToHiddenVariable(incScope, counterName, inc)
+	incScope = b.callContinuation(&loopCon, incScope)
+	b.appendBodyStmt(&incrementCon, incScope)
+	return b.callContinuation(&loopCon, s)
+}
+
+// expandTupleColumn projects each element of the given tuple column as a
+// separate column. It is used to match the rows added to the result buffer of
+// a set-returning routine that is used as a data source.
+func (b *plpgsqlBuilder) expandTupleColumn(inScope *scope, tupleCol *scopeColumn) *scope {
+	outScope := inScope.push()
+	tupleColID := tupleCol.id
+	contents := tupleCol.typ.TupleContents()
+	for i := range contents {
+		colName := scopeColName("").WithMetadataName(b.makeIdentifier("return_elem"))
+		scalar := b.ob.factory.ConstructColumnAccess(
+			b.ob.factory.ConstructVariable(tupleColID), memo.TupleOrdinal(i),
+		)
+		b.ob.synthesizeColumn(outScope, colName, contents[i], nil /* expr */, scalar)
+	}
+	b.ob.constructProjectForScope(inScope, outScope)
+	return outScope
+}
+
+// shapeReturnQuery projects the result columns of the query for a RETURN QUERY
+// statement to match the rows of the routine's result buffer. The columns are
+// coerced to the expected types, and combined into a single tuple column if the
+// routine returns a composite type and is not used as a data source.
+func (b *plpgsqlBuilder) shapeReturnQuery(queryScope *scope) *scope {
+	if !b.buildSQL {
+		return queryScope
+	}
+	var expectedTypes []*types.T
+	var combineIntoTuple bool
+	if b.multiColOutput {
+		expectedTypes = b.returnType.TupleContents()
+	} else if b.returnType.Family() == types.TupleFamily &&
+		(len(queryScope.cols) != 1 || queryScope.cols[0].typ.Family() != types.TupleFamily) {
+		expectedTypes = b.returnType.TupleContents()
+		combineIntoTuple = true
+	} else {
+		expectedTypes = []*types.T{b.returnType}
+	}
+	if len(queryScope.cols) != len(expectedTypes) {
+		panic(errors.WithDetailf(returnQueryStructureErr,
+			"Number of returned columns (%d) does not match expected column count (%d).",
+			len(queryScope.cols), len(expectedTypes),
+		))
+	}
+	outScope := queryScope.push()
+	if combineIntoTuple {
+		elems := make(memo.ScalarListExpr, len(expectedTypes))
+		for i := range elems {
+			elems[i] = b.coerceType(
+				b.ob.factory.ConstructVariable(queryScope.cols[i].id), expectedTypes[i],
+			)
+		}
+		colName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_return_query"))
+		b.ob.synthesizeColumn(
+			outScope, colName, b.returnType, nil /* expr */, b.ob.factory.ConstructTuple(elems, b.returnType),
+		)
+	} else {
+		for i := range queryScope.cols {
+			col := &queryScope.cols[i]
+			if col.typ.Identical(expectedTypes[i]) {
+				outScope.appendColumn(col)
+				continue
+			}
+			scalar := b.coerceType(b.ob.factory.ConstructVariable(col.id), expectedTypes[i])
+			b.ob.synthesizeColumn(outScope, col.name, expectedTypes[i], nil /* expr */, scalar)
+		}
+	}
+	// Preserve the ordering of the query, since the rows are added to the result
+	// buffer in order.
+	outScope.copyOrdering(queryScope)
+	b.ob.constructProjectForScope(queryScope, outScope)
+	return outScope
+}
+
 // resolveOpenQuery finds and validates the query that is bound to cursor for
 // the given OPEN statement.
 func (b *plpgsqlBuilder) resolveOpenQuery(open *ast.Open) tree.Statement {
@@ -1293,16 +2055,16 @@ func (b *plpgsqlBuilder) resolveOpenQuery(open *ast.Open) tree.Statement {
 // buildCursorNameGen builds a statement that generates a unique name for the
 // cursor if the variable containing the name is unset. The unique name
 // generation is implemented by the crdb_internal.plpgsql_gen_cursor_name
-// builtin function.
-func (b *plpgsqlBuilder) buildCursorNameGen(nameCon *continuation, nameVar ast.Variable) *scope {
-	_, source, _, _ := nameCon.s.FindSourceProvidingColumn(b.ob.ctx, nameVar)
+// builtin function. The given column, which may be a hidden variable, is the
+// current value of the cursor variable.
+func (b *plpgsqlBuilder) buildCursorNameGen(nameCon *continuation, nameCol *scopeColumn) *scope {
 	const nameFnName = "crdb_internal.plpgsql_gen_cursor_name"
 	props, overloads := builtinsregistry.GetBuiltinProperties(nameFnName)
 	if len(overloads) != 1 {
 		panic(errors.AssertionFailedf("expected one overload for %s", nameFnName))
 	}
 	nameCall := b.ob.factory.ConstructFunction(
-		memo.ScalarListExpr{b.ob.factory.ConstructVariable(source.(*scopeColumn).id)},
+		memo.ScalarListExpr{b.ob.factory.ConstructVariable(nameCol.id)},
 		&memo.FunctionPrivate{
 			Name:       nameFnName,
 			Typ:        types.RefCursor,
@@ -1311,7 +2073,7 @@ func (b *plpgsqlBuilder) buildCursorNameGen(nameCon *continuation, nameVar ast.V
 		},
 	)
 	nameScope := nameCon.s.push()
-	b.ob.synthesizeColumn(nameScope, scopeColName(nameVar), types.RefCursor, nil /* expr */, nameCall)
+	b.ob.synthesizeColumn(nameScope, nameCol.name, types.RefCursor, nil /* expr */, nameCall)
 	b.ob.constructProjectForScope(nameCon.s, nameScope)
 	return nameScope
 }
@@ -1641,6 +2403,16 @@ func (b *plpgsqlBuilder) makeRaiseFormatMessage(
 // routine; this is because the declaration block is not within the scope of the
 // exception block.
 //
+// Transaction retry errors (e.g. serialization_failure) can be matched like any
+// other error. At execution time, they are only caught by the blocks of a
+// procedure, so that the new read snapshot of the retried transaction is only
+// observed at statement boundaries. Under read committed isolation, the block's
+// savepoint is rolled back to and the transaction is partially retried. Under
+// other isolation levels, the transaction must restart from the beginning, so
+// the error is only caught if the block's savepoint was taken before any other
+// activity in the transaction. Otherwise, the error is propagated so that the
+// transaction can be retried as a whole.
+//
 // The exception handler must observe up-to-date values for the PLpgSQL
 // variables, so a new continuation routine must be created for all body
 // statements following an assignment statement. This works because if an error
@@ -1711,12 +2483,6 @@ func (b *plpgsqlBuilder) buildExceptions(block *ast.Block) *memo.ExceptionBlock
 	handlers := make([]*memo.UDFDefinition, 0, len(block.Exceptions))
 	addHandler := func(codeStr string, handler *memo.UDFDefinition) {
 		code := pgcode.MakeCode(strings.ToUpper(codeStr))
-		switch code {
-		case pgcode.TransactionRollback, pgcode.TransactionIntegrityConstraintViolation,
-			pgcode.SerializationFailure, pgcode.StatementCompletionUnknown,
-			pgcode.DeadlockDetected:
-			panic(retryableErrErr)
-		}
 		codes = append(codes, code)
 		handlers = append(handlers, handler)
 	}
@@ -1758,11 +2524,13 @@ func (b *plpgsqlBuilder) buildExceptions(block *ast.Block) *memo.ExceptionBlock
 // handleEndOfFunction handles the case when control flow reaches the end of a
 // PL/pgSQL routine without reaching a RETURN statement.
 func (b *plpgsqlBuilder) handleEndOfFunction(inScope *scope) *scope {
-	if b.hasOutParam() || b.returnType.Family() == types.VoidFamily {
-		// Routines with OUT-parameters and VOID return types need not explicitly
-		// specify a RETURN statement.
+	if b.resultBuffer != nil || b.hasOutParam() || b.returnType.Family() == types.VoidFamily {
+		// Set-returning routines, and routines with OUT-parameters and VOID return
+		// types need not explicitly specify a RETURN statement. The result of a
+		// set-returning routine is built by RETURN NEXT and RETURN QUERY
+		// statements, so it returns NULL.
 		var returnExpr tree.Expr = tree.DNull
-		if b.hasOutParam() {
+		if b.resultBuffer == nil && b.hasOutParam() {
 			returnExpr = b.makeReturnForOutParams()
 		}
 		returnScope := inScope.push()
@@ -1879,11 +2647,6 @@ func (b *plpgsqlBuilder) addRuntimeCheck(
 // buildFetch projects a call to the crdb_internal.plpgsql_fetch builtin
 // function, which handles cursors for the PLpgSQL FETCH and MOVE statements.
 func (b *plpgsqlBuilder) buildFetch(s *scope, fetch *ast.Fetch) *scope {
-	const fetchFnName = "crdb_internal.plpgsql_fetch"
-	props, overloads := builtinsregistry.GetBuiltinProperties(fetchFnName)
-	if len(overloads) != 1 {
-		panic(errors.AssertionFailedf("expected one overload for %s", fetchFnName))
-	}
 	_, source, _, err := s.FindSourceProvidingColumn(b.ob.ctx, fetch.Cursor.Name)
 	if err != nil {
 		if pgerror.GetPGCode(err) == pgcode.UndefinedColumn {
@@ -1896,9 +2659,6 @@ func (b *plpgsqlBuilder) buildFetch(s *scope, fetch *ast.Fetch) *scope {
 			"variable \"%s\" must be of type cursor or refcursor", fetch.Cursor.Name,
 		))
 	}
-	makeConst := func(val tree.Datum, typ *types.T) opt.ScalarExpr {
-		return b.ob.factory.ConstructConstVal(val, typ)
-	}
 	// For a FETCH statement, we have to pass the expected result types.
 	var typs []*types.T
 	if !fetch.IsMove {
@@ -1916,6 +2676,36 @@ func (b *plpgsqlBuilder) buildFetch(s *scope, fetch *ast.Fetch) *scope {
 		}
 	}
 	returnType := types.MakeTuple(typs)
+	fetchCall := b.makeFetchCall(
+		b.ob.factory.ConstructVariable(source.(*scopeColumn).id), &fetch.Cursor, returnType,
+	)
+	b.addBarrierIfVolatile(s, fetchCall)
+	fetchColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_fetch"))
+	fetchScope := s.push()
+	b.ob.synthesizeColumn(fetchScope, fetchColName, returnType, nil /* expr */, fetchCall)
+	b.ob.constructProjectForScope(s, fetchScope)
+	if !fetch.IsMove && b.targetIsRecordVar(fetch.Target) {
+		// Handle a single record-type variable (see projectRecordVar for details).
+		fetchScope = b.projectRecordVar(fetchScope, fetch.Target[0])
+	}
+	return fetchScope
+}
+
+// makeFetchCall constructs a call to the crdb_internal.plpgsql_fetch builtin
+// function, which fetches a row from the cursor with the given name, and
+// returns it as a tuple of the given type.
+func (b *plpgsqlBuilder) makeFetchCall(
+	cursorName opt.ScalarExpr, cursor *tree.CursorStmt, returnType *types.T,
+) opt.ScalarExpr {
+	const fetchFnName = "crdb_internal.plpgsql_fetch"
+	props, overloads := builtinsregistry.GetBuiltinProperties(fetchFnName)
+	if len(overloads) != 1 {
+		panic(errors.AssertionFailedf("expected one overload for %s", fetchFnName))
+	}
+	makeConst := func(val tree.Datum, typ *types.T) opt.ScalarExpr {
+		return b.ob.factory.ConstructConstVal(val, typ)
+	}
+	typs := returnType.TupleContents()
 	elems := make(memo.ScalarListExpr, len(typs))
 	for i := range elems {
 		elems[i] = b.ob.factory.ConstructConstVal(tree.DNull, typs[i])
@@ -1927,11 +2717,11 @@ func (b *plpgsqlBuilder) buildFetch(s *scope, fetch *ast.Fetch) *scope {
 	//   3. The count of the cursor direction (FORWARD 1, RELATIVE 5).
 	//   4. The types of the columns to return (can be empty).
 	// The result of the fetch will be cast to strings and returned as an array.
-	fetchCall := b.ob.factory.ConstructFunction(
+	return b.ob.factory.ConstructFunction(
 		memo.ScalarListExpr{
-			b.ob.factory.ConstructVariable(source.(*scopeColumn).id),
-			makeConst(tree.NewDInt(tree.DInt(fetch.Cursor.FetchType)), types.Int),
-			makeConst(tree.NewDInt(tree.DInt(fetch.Cursor.Count)), types.Int),
+			cursorName,
+			makeConst(tree.NewDInt(tree.DInt(cursor.FetchType)), types.Int),
+			makeConst(tree.NewDInt(tree.DInt(cursor.Count)), types.Int),
 			b.ob.factory.ConstructTuple(elems, returnType),
 		},
 		&memo.FunctionPrivate{
@@ -1941,16 +2731,170 @@ func (b *plpgsqlBuilder) buildFetch(s *scope, fetch *ast.Fetch) *scope {
 			Overload:   &overloads[0],
 		},
 	)
-	b.addBarrierIfVolatile(s, fetchCall)
-	fetchColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_fetch"))
-	fetchScope := s.push()
-	b.ob.synthesizeColumn(fetchScope, fetchColName, returnType, nil /* expr */, fetchCall)
-	b.ob.constructProjectForScope(s, fetchScope)
-	if !fetch.IsMove && b.targetIsRecordVar(fetch.Target) {
+}
+
+// makeCloseCall constructs a call to the crdb_internal.plpgsql_close builtin
+// function, which closes the cursor with the given name.
+func (b *plpgsqlBuilder) makeCloseCall(cursorName opt.ScalarExpr) opt.ScalarExpr {
+	const closeFnName = "crdb_internal.plpgsql_close"
+	props, overloads := builtinsregistry.GetBuiltinProperties(closeFnName)
+	if len(overloads) != 1 {
+		panic(errors.AssertionFailedf("expected one overload for %s", closeFnName))
+	}
+	return b.ob.factory.ConstructFunction(
+		memo.ScalarListExpr{cursorName},
+		&memo.FunctionPrivate{
+			Name:       closeFnName,
+			Typ:        types.Int,
+			Properties: props,
+			Overload:   &overloads[0],
+		},
+	)
+}
+
+// buildDynamicExecute projects a call to the crdb_internal.plpgsql_execute
+// builtin function, which executes the dynamic SQL command for a PLpgSQL
+// EXECUTE statement.
+func (b *plpgsqlBuilder) buildDynamicExecute(s *scope, execute *ast.DynamicExecute) *scope {
+	// If there is an INTO target, we have to pass the expected result types.
+	var typs []*types.T
+	if b.targetIsRecordVar(execute.Target) {
+		// If the target is a single record-type variable, the columns of the
+		// result are assigned as its *elements*, rather than directly to the
+		// variable.
+		typs = b.resolveVariableForAssign(execute.Target[0]).TupleContents()
+	} else {
+		typs = make([]*types.T, len(execute.Target))
+		for i := range execute.Target {
+			typs[i] = b.resolveVariableForAssign(execute.Target[i])
+		}
+	}
+	// STRICT only applies when there is an INTO target.
+	strict := len(execute.Target) > 0 &&
+		(execute.Strict || b.ob.evalCtx.SessionData().PLpgSQLUseStrictInto)
+	returnType := types.MakeTuple(typs)
+	query, params := b.buildDynamicQueryArgs(s, execute.Query, execute.Params)
+	execCall := b.makeDynamicExecuteCall(query, params, strict, returnType)
+	b.addBarrierIfVolatile(s, execCall)
+	execColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_dyn_exec"))
+	execScope := s.push()
+	b.ob.synthesizeColumn(execScope, execColName, returnType, nil /* expr */, execCall)
+	b.ob.constructProjectForScope(s, execScope)
+	if b.targetIsRecordVar(execute.Target) {
 		// Handle a single record-type variable (see projectRecordVar for details).
-		fetchScope = b.projectRecordVar(fetchScope, fetch.Target[0])
+		execScope = b.projectRecordVar(execScope, execute.Target[0])
 	}
-	return fetchScope
+	return execScope
+}
+
+// buildOpenDynamicCursor projects a call to the
+// crdb_internal.plpgsql_open_dynamic_cursor builtin function, which opens a
+// cursor with the name given by the cursor variable over the rows of a dynamic
+// query.
+func (b *plpgsqlBuilder) buildOpenDynamicCursor(
+	s *scope, cursorVar *scopeColumn, query ast.Expr, params []ast.Expr, withMarker bool,
+) *scope {
+	queryArg, paramsArg := b.buildDynamicQueryArgs(s, query, params)
+	const openFnName = "crdb_internal.plpgsql_open_dynamic_cursor"
+	props, overloads := builtinsregistry.GetBuiltinProperties(openFnName)
+	if len(overloads) != 1 {
+		panic(errors.AssertionFailedf("expected one overload for %s", openFnName))
+	}
+	openCall := b.ob.factory.ConstructFunction(
+		memo.ScalarListExpr{
+			b.ob.factory.ConstructVariable(cursorVar.id),
+			queryArg,
+			paramsArg,
+			b.ob.factory.ConstructConstVal(tree.MakeDBool(tree.DBool(withMarker)), types.Bool),
+		},
+		&memo.FunctionPrivate{
+			Name:       openFnName,
+			Typ:        types.Int,
+			Properties: props,
+			Overload:   &overloads[0],
+		},
+	)
+	openColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_open"))
+	openScope := s.push()
+	b.ob.synthesizeColumn(openScope, openColName, types.Int, nil /* expr */, openCall)
+	b.ob.constructProjectForScope(s, openScope)
+	return openScope
+}
+
+// buildDynamicQueryArgs builds the query string expression and the USING
+// parameters of a dynamic query. The parameters are combined into a single
+// tuple, which is passed to the builtin function that executes the query. The
+// parameters keep their own types, since they determine the types of the
+// placeholders in the query.
+func (b *plpgsqlBuilder) buildDynamicQueryArgs(
+	s *scope, query ast.Expr, params []ast.Expr,
+) (queryArg, paramsArg opt.ScalarExpr) {
+	queryArg = b.buildSQLExpr(query, types.String, s)
+	elems := make(memo.ScalarListExpr, len(params))
+	typs := make([]*types.T, len(params))
+	for i := range params {
+		if !b.buildSQL {
+			elems[i], typs[i] = memo.NullSingleton, types.Unknown
+			continue
+		}
+		expr, _ := tree.WalkExpr(s, params[i])
+		typedExpr, err := expr.TypeCheck(b.ob.ctx, b.ob.semaCtx, types.Any)
+		if err != nil {
+			panic(err)
+		}
+		elems[i] = b.ob.buildScalar(typedExpr, s, nil, nil, b.colRefs)
+		typs[i] = elems[i].DataType()
+	}
+	return queryArg, b.ob.factory.ConstructTuple(elems, types.MakeTuple(typs))
+}
+
+// makeDynamicExecuteCall constructs a call to the crdb_internal.plpgsql_execute
+// builtin function, which executes a dynamic SQL command and returns the first
+// row of its result as a tuple of the given type.
+func (b *plpgsqlBuilder) makeDynamicExecuteCall(
+	query, params opt.ScalarExpr, strict bool, returnType *types.T,
+) opt.ScalarExpr {
+	const execFnName = "crdb_internal.plpgsql_execute"
+	props, overloads := builtinsregistry.GetBuiltinProperties(execFnName)
+	if len(overloads) != 1 {
+		panic(errors.AssertionFailedf("expected one overload for %s", execFnName))
+	}
+	typs := returnType.TupleContents()
+	elems := make(memo.ScalarListExpr, len(typs))
+	for i := range elems {
+		elems[i] = b.ob.factory.ConstructConstVal(tree.DNull, typs[i])
+	}
+
+	// The arguments are:
+	//   1. The query string (resolved at runtime).
+	//   2. The parameters for the query, as a tuple.
+	//   3. Whether the query must return exactly one row.
+	//   4. The types of the columns to return (can be empty).
+	return b.ob.factory.ConstructFunction(
+		memo.ScalarListExpr{
+			query,
+			params,
+			b.ob.factory.ConstructConstVal(tree.MakeDBool(tree.DBool(strict)), types.Bool),
+			b.ob.factory.ConstructTuple(elems, returnType),
+		},
+		&memo.FunctionPrivate{
+			Name:       execFnName,
+			Typ:        returnType,
+			Properties: props,
+			Overload:   &overloads[0],
+		},
+	)
+}
+
+// isBoundCursor returns true if the given variable is a cursor that was
+// declared with a query.
+func (b *plpgsqlBuilder) isBoundCursor(name ast.Variable) bool {
+	for i := range b.blocks {
+		if _, ok := b.blocks[i].cursors[name]; ok {
+			return true
+		}
+	}
+	return false
 }
 
 // targetIsSingleCompositeVar returns true if the given INTO target is a single
@@ -2081,7 +3025,11 @@ func (b *plpgsqlBuilder) callContinuation(con *continuation, s *scope) *scope {
 // continuation in a TxnControlExpr that will commit or abort the current
 // transaction before resuming execution with the continuation.
 func (b *plpgsqlBuilder) callContinuationWithTxnOp(
-	con *continuation, s *scope, txnOp tree.StoredProcTxnOp, txnModes tree.TransactionModes,
+	con *continuation,
+	s *scope,
+	txnOp tree.StoredProcTxnOp,
+	txnModes tree.TransactionModes,
+	chain bool,
 ) *scope {
 	if con == nil {
 		panic(errors.AssertionFailedf("nil continuation with transaction control"))
@@ -2092,16 +3040,10 @@ func (b *plpgsqlBuilder) callContinuationWithTxnOp(
 	b.ob.addBarrier(s)
 	returnScope := s.push()
 	args := b.makeContinuationArgs(con, s)
-	txnPrivate := &memo.TxnControlPrivate{TxnOp: txnOp, TxnModes: txnModes, Def: con.def}
-	if b.outScope != nil {
-		txnPrivate.Props = b.outScope.makePhysicalProps()
-		txnPrivate.OutCols = b.outScope.colList()
-	} else {
-		// outScope may be nil if we're in the context of function creation.
-		// It's fine to not fully initialize the TxnControl expression in this
-		// case.
-		txnPrivate.Props = &physical.Required{}
+	txnPrivate := &memo.TxnControlPrivate{
+		TxnOp: txnOp, TxnModes: txnModes, Chain: chain, Def: con.def,
 	}
+	txnPrivate.Props, txnPrivate.OutCols = b.makeTxnControlProps()
 	txnControlExpr := b.ob.factory.ConstructTxnControl(args, txnPrivate)
 	returnColName := scopeColName("").WithMetadataName(con.def.Name)
 	b.ob.synthesizeColumn(returnScope, returnColName, b.returnType, nil /* expr */, txnControlExpr)
@@ -2109,6 +3051,44 @@ func (b *plpgsqlBuilder) callContinuationWithTxnOp(
 	return returnScope
 }
 
+// callContinuationWithCallResult is similar to callContinuation, but passes
+// the given column, which holds the result of a nested CALL statement, as the
+// last argument of the continuation. It is used to resume execution after a
+// nested CALL to a procedure that may COMMIT or ROLLBACK (see
+// memo.TxnControlResume).
+func (b *plpgsqlBuilder) callContinuationWithCallResult(
+	con *continuation, s *scope, resultCol opt.ColumnID,
+) *scope {
+	args := b.makeContinuationArgs(con, s)
+	args = append(args, b.ob.factory.ConstructVariable(resultCol))
+	if len(args) != len(con.def.Params) {
+		panic(errors.AssertionFailedf("expected %d continuation arguments, found %d",
+			len(con.def.Params), len(args),
+		))
+	}
+	call := b.ob.factory.ConstructUDFCall(args, &memo.UDFCallPrivate{Def: con.def})
+	b.addBarrierIfVolatile(s, call)
+
+	returnColName := scopeColName("").WithMetadataName(con.def.Name)
+	returnScope := s.push()
+	b.ob.synthesizeColumn(returnScope, returnColName, b.returnType, nil /* expr */, call)
+	b.ob.constructProjectForScope(s, returnScope)
+	return returnScope
+}
+
+// makeTxnControlProps returns the physical properties and output columns used
+// to build the plan for a continuation stored procedure that resumes execution
+// in a new transaction.
+func (b *plpgsqlBuilder) makeTxnControlProps() (*physical.Required, opt.ColList) {
+	if b.outScope == nil {
+		// outScope may be nil if we're in the context of function creation.
+		// It's fine to not fully initialize the TxnControl expression in this
+		// case.
+		return &physical.Required{}, nil
+	}
+	return b.outScope.makePhysicalProps(), b.outScope.colList()
+}
+
 func (b *plpgsqlBuilder) makeContinuationArgs(con *continuation, s *scope) memo.ScalarListExpr {
 	args := make(memo.ScalarListExpr, 0, len(con.def.Params))
 	for i := range b.blocks {
@@ -2377,6 +3357,13 @@ type continuation struct {
 
 	// typ defines the context of the continuation.
 	typ continuationType
+
+	// loopCursor is set for the continuation that closes the cursor of a loop
+	// over the rows of a query and then exits the loop (see buildCursorLoop). It
+	// returns the column of the cursor variable in the given scope. It is used to
+	// close the cursor when control flow leaves the loop without calling the
+	// continuation, e.g. through a RETURN statement.
+	loopCursor func(s *scope) *scopeColumn
 }
 
 const unspecifiedLabel = ""
@@ -2416,6 +3403,60 @@ func (b *plpgsqlBuilder) popContinuation() {
 	}
 }
 
+// continuationIdx returns the index in the continuation stack of the given
+// continuation, which must have been returned by getContinuation.
+func (b *plpgsqlBuilder) continuationIdx(con *continuation) int {
+	for i := range b.continuations {
+		if &b.continuations[i] == con {
+			return i
+		}
+	}
+	panic(errors.AssertionFailedf("continuation %s is not on the stack", con.def.Name))
+}
+
+// hasLoopCursorsAbove returns true if any continuation in the stack at or after
+// the given index closes the cursor of a loop. See continuation.loopCursor.
+func (b *plpgsqlBuilder) hasLoopCursorsAbove(minIdx int) bool {
+	for i := minIdx; i < len(b.continuations); i++ {
+		if b.continuations[i].loopCursor != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// closeLoopCursors builds the given RETURN, EXIT or CONTINUE statement, which
+// leaves the loops over the rows of a query whose continuations are at or after
+// minIdx in the continuation stack, without passing through the continuations
+// that close their cursors. A volatile continuation closes the cursors of the
+// loops, innermost first, and then executes the statement. Otherwise, the
+// cursors would remain open until the end of the transaction.
+func (b *plpgsqlBuilder) closeLoopCursors(
+	s *scope, minIdx int, stmts []ast.Statement,
+) *scope {
+	con := b.makeContinuation("_loop_close")
+	con.def.Volatility = volatility.Volatile
+	for i := len(b.continuations) - 1; i >= minIdx; i-- {
+		loopCursor := b.continuations[i].loopCursor
+		if loopCursor == nil {
+			continue
+		}
+		closeCall := b.makeCloseCall(b.ob.factory.ConstructVariable(loopCursor(con.s).id))
+		closeColName := scopeColName("").WithMetadataName(b.makeIdentifier("stmt_close"))
+		closeScope := con.s.push()
+		b.ob.synthesizeColumn(closeScope, closeColName, types.Int, nil /* expr */, closeCall)
+		b.ob.constructProjectForScope(con.s, closeScope)
+		b.appendBodyStmt(&con, closeScope)
+
+		// The statement is built as if the loop had already been exited, since
+		// its cursor is closed by this point.
+		b.continuations[i].loopCursor = nil
+		defer func(i int) { b.continuations[i].loopCursor = loopCursor }(i)
+	}
+	b.appendPlpgSQLStmts(&con, stmts)
+	return b.callContinuation(&con, s)
+}
+
 // getContinuation attempts to retrieve the most recent continuation from the
 // stack with the given required type and label.
 //
@@ -2590,9 +3631,12 @@ func (r *recordTypeVisitor) Visit(stmt ast.Statement) (newStmt ast.Statement, re
 }
 
 // transactionControlVisitor is used to check for COMMIT or ROLLBACK statements
-// for a PL/pgSQL stored procedure, so that stable folding can be disabled.
+// for a PL/pgSQL stored procedure, so that stable folding can be disabled. It
+// also checks for CALL statements, since a nested procedure may COMMIT or
+// ROLLBACK.
 type transactionControlVisitor struct {
 	foundTxnControlStatement bool
+	foundCallStatement       bool
 }
 
 var _ ast.StatementVisitor = &transactionControlVisitor{}
@@ -2600,11 +3644,15 @@ var _ ast.StatementVisitor = &transactionControlVisitor{}
 func (tc *transactionControlVisitor) Visit(
 	stmt ast.Statement,
 ) (newStmt ast.Statement, recurse bool) {
-	if _, ok := stmt.(*ast.TransactionControl); ok {
+	switch stmt.(type) {
+	case *ast.TransactionControl:
 		tc.foundTxnControlStatement = true
 		return stmt, false
+	case *ast.Call:
+		tc.foundCallStatement = true
+		return stmt, false
 	}
-	return stmt, !tc.foundTxnControlStatement
+	return stmt, !tc.foundTxnControlStatement || !tc.foundCallStatement
 }
 
 var (
@@ -2623,9 +3671,6 @@ var (
 	scrollableCursorErr = unimplemented.NewWithIssue(77102,
 		"DECLARE SCROLL CURSOR",
 	)
-	retryableErrErr = unimplemented.NewWithIssue(111446,
-		"catching a Transaction Retry error in a PLpgSQL EXCEPTION block is not yet implemented",
-	)
 	recordReturnErr = errors.WithHint(
 		unimplemented.NewWithIssue(115384,
 			"returning different types from a RECORD-returning function is not yet supported",
@@ -2673,9 +3718,6 @@ var (
 	txnInUDFErr = errors.WithDetail(
 		pgerror.Newf(pgcode.InvalidTransactionTermination, "invalid transaction termination"),
 		"PL/pgSQL COMMIT/ROLLBACK is not allowed inside a user-defined function")
-	txnControlWithChainErr = unimplemented.NewWithIssue(119646,
-		"COMMIT or ROLLBACK with AND CHAIN syntax is not yet implemented",
-	)
 	setTxnNotAfterControlStmtErr = errors.WithHint(
 		pgerror.New(pgcode.ActiveSQLTransaction, "SET TRANSACTION must be called before any query"),
 		"PL/pgSQL SET TRANSACTION statements must immediately follow COMMIT or ROLLBACK",
@@ -2683,4 +3725,32 @@ var (
 	intForLoopTargetErr = pgerror.New(pgcode.Syntax,
 		"integer FOR loop must have only one target variable",
 	)
+	cursorForLoopTargetErr = pgerror.New(pgcode.Syntax,
+		"cursor FOR loop must have only one target variable",
+	)
+	cursorForLoopUnboundErr = pgerror.New(pgcode.Syntax,
+		"cursor FOR loop must use a bound cursor variable",
+	)
+	queryForLoopMutationErr = unimplemented.New("FOR loop over data-modifying query",
+		"FOR loops over queries with data-modifying statements are not yet supported",
+	)
+	returnWithSetOfErr = errors.WithHint(
+		pgerror.New(pgcode.Syntax, "RETURN cannot have a parameter in function returning set"),
+		"Use RETURN NEXT or RETURN QUERY.",
+	)
+	returnNextWithoutSetOfErr = pgerror.New(pgcode.Syntax,
+		"cannot use RETURN NEXT in a non-SETOF function",
+	)
+	returnNextWithOUTParameterErr = pgerror.New(pgcode.DatatypeMismatch,
+		"RETURN NEXT cannot have a parameter in function with OUT parameters",
+	)
+	emptyReturnNextErr = pgerror.New(pgcode.Syntax,
+		"RETURN NEXT must have a parameter",
+	)
+	returnQueryWithoutSetOfErr = pgerror.New(pgcode.Syntax,
+		"cannot use RETURN QUERY in a non-SETOF function",
+	)
+	returnQueryStructureErr = pgerror.New(pgcode.DatatypeMismatch,
+		"structure of query does not match function result type",
+	)
 )
@@ -19,9 +19,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	plpgsql "github.com/cockroachdb/cockroach/pkg/sql/plpgsql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	ast "github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -270,7 +270,7 @@ func (b *Builder) buildRoutine(
 	// CTEs that mutate and are not at the top-level.
 	bodyScope := b.allocScope()
 	var params opt.ColList
-	var polyArgTyp *types.T
+	var polyTypes polymorphicTypes
 	if o.Types.Length() > 0 {
 		// If necessary, add DEFAULT arguments.
 		args, argTypes = b.addDefaultArgs(f, args, argTypes, bodyScope, colRefs)
@@ -278,8 +278,12 @@ func (b *Builder) buildRoutine(
 		// Add all input parameters to the scope.
 		paramTypes, ok := o.Types.(tree.ParamTypes)
 		if !ok {
-			panic(unimplemented.NewWithIssue(88947,
-				"variadiac user-defined functions are not yet supported"))
+			panic(errors.AssertionFailedf("expected ParamTypes for routine, found %T", o.Types))
+		}
+		// Unless the VARIADIC keyword was used in the invocation, collect the
+		// arguments for a VARIADIC parameter into an array.
+		if o.Variadic && !f.Variadic {
+			args, argTypes = b.packVariadicArgs(paramTypes, args, argTypes)
 		}
 		if len(paramTypes) != len(args) {
 			panic(errors.AssertionFailedf(
@@ -290,12 +294,16 @@ func (b *Builder) buildRoutine(
 		// Check the parameters for polymorphic types, and resolve to a concrete
 		// type if any exist.
 		if b.evalCtx.SessionData().OptimizerUsePolymorphicParameterFix {
-			var numPolyParams int
-			_, numPolyParams, polyArgTyp = tree.ResolvePolymorphicArgTypes(
+			var numPolyParams, numAnyCompatibleParams int
+			_, numPolyParams, polyTypes.anyElement = tree.ResolvePolymorphicArgTypes(
 				paramTypes, argTypes, nil /* anyElemTyp */, true, /* enforceConsistency */
 			)
-			if numPolyParams > 0 {
-				if polyArgTyp == nil {
+			_, numAnyCompatibleParams, polyTypes.anyCompatible = tree.ResolveAnyCompatibleArgType(
+				paramTypes, argTypes, true, /* enforceConsistency */
+			)
+			if numPolyParams > 0 || numAnyCompatibleParams > 0 {
+				if (numPolyParams > 0 && polyTypes.anyElement == nil) ||
+					(numAnyCompatibleParams > 0 && polyTypes.anyCompatible == nil) {
 					// All supplied arguments were NULL, so a type could not be resolved
 					// for the polymorphic parameters.
 					panic(pgerror.New(pgcode.DatatypeMismatch,
@@ -303,8 +311,8 @@ func (b *Builder) buildRoutine(
 					))
 				}
 				// If the routine returns a polymorphic type, use the resolved
-				// polymorphic argument type to determine the concrete return type.
-				b.maybeResolvePolymorphicReturnType(f, polyArgTyp)
+				// polymorphic argument types to determine the concrete return type.
+				b.maybeResolvePolymorphicReturnType(f, polyTypes)
 			}
 		}
 
@@ -313,7 +321,7 @@ func (b *Builder) buildRoutine(
 		params = make(opt.ColList, len(paramTypes))
 		for i := range paramTypes {
 			argTyp := argTypes[i]
-			desiredTyp := maybeReplacePolymorphicType(paramTypes[i].Typ, polyArgTyp)
+			desiredTyp := maybeReplacePolymorphicType(paramTypes[i].Typ, polyTypes)
 			if desiredTyp.Identical(types.AnyTuple) {
 				// This is a RECORD-typed parameter. Use the actual argument type.
 				desiredTyp = argTyp
@@ -362,6 +370,7 @@ func (b *Builder) buildRoutine(
 	b.insideUDF = true
 	b.insideSQLRoutine = o.Language == tree.RoutineLangSQL
 	isSetReturning := o.Class == tree.GeneratorClass
+	multiColDataSource := len(f.ResolvedType().TupleContents()) > 0 && oldInsideDataSource
 	// If this is a user-defined routine that has a security mode of DEFINER, we
 	// need to override our checkPrivilegeUser to be the owner of the routine.
 	if o.Type != tree.BuiltinRoutine && o.SecurityMode == tree.RoutineDefiner {
@@ -376,6 +385,7 @@ func (b *Builder) buildRoutine(
 	var body []memo.RelExpr
 	var bodyProps []*physical.Required
 	var bodyStmts []string
+	var resultBuffer *tree.RoutineResultBuffer
 	switch o.Language {
 	case tree.RoutineLangSQL:
 		// Parse the function body.
@@ -436,17 +446,31 @@ func (b *Builder) buildRoutine(
 			}
 			routineParams = append(routineParams, routineParam{
 				name:  param.Name,
-				typ:   maybeReplacePolymorphicType(typ, polyArgTyp),
+				typ:   maybeReplacePolymorphicType(typ, polyTypes),
 				class: param.Class,
 			})
 		}
+		if isSetReturning {
+			// The rows returned by a set-returning PL/pgSQL routine are added to a
+			// result buffer by RETURN NEXT and RETURN QUERY statements.
+			resultBuffer = &tree.RoutineResultBuffer{}
+		}
 		var expr memo.RelExpr
 		var physProps *physical.Required
 		plBuilder := newPLpgSQLBuilder(
 			b, def.Name, stmt.AST.Label, colRefs, routineParams, f.ResolvedType(),
-			isProc, true /* buildSQL */, outScope,
+			isProc, true /* buildSQL */, resultBuffer, multiColDataSource, outScope,
 		)
 		stmtScope := plBuilder.buildRootBlock(stmt.AST, bodyScope, routineParams)
+		if isProc && len(o.SessionVars) > 0 {
+			// The session variables set by the procedure could not be restored if
+			// it committed or aborted the transaction.
+			var tc transactionControlVisitor
+			ast.Walk(&tc, stmt.AST)
+			if tc.foundTxnControlStatement || plBuilder.hasNestedTxnControl {
+				panic(pgerror.New(pgcode.InvalidTransactionTermination, "invalid transaction termination"))
+			}
+		}
 		expr, physProps = b.finishBuildLastStmt(
 			stmtScope, bodyScope, inScope, isSetReturning, oldInsideDataSource, f,
 		)
@@ -459,7 +483,6 @@ func (b *Builder) buildRoutine(
 		panic(errors.AssertionFailedf("unexpected language: %v", o.Language))
 	}
 
-	multiColDataSource := len(f.ResolvedType().TupleContents()) > 0 && oldInsideDataSource
 	routine := b.factory.ConstructUDFCall(
 		args,
 		&memo.UDFCallPrivate{
@@ -476,6 +499,10 @@ func (b *Builder) buildRoutine(
 				BodyProps:          bodyProps,
 				BodyStmts:          bodyStmts,
 				Params:             params,
+				ResultBuffer:       resultBuffer,
+				Cost:               o.Cost,
+				Rows:               o.Rows,
+				SessionVars:        o.SessionVars,
 			},
 		},
 	)
@@ -750,13 +777,75 @@ func (b *Builder) addDefaultArgs(
 	return args, argTypes
 }
 
+// packVariadicArgs collects the arguments supplied for the trailing VARIADIC
+// parameter of a routine into a single array argument. The elements are cast
+// to the element type of the parameter, which is resolved from the arguments if
+// it is polymorphic.
+func (b *Builder) packVariadicArgs(
+	paramTypes tree.ParamTypes, args memo.ScalarListExpr, argTypes []*types.T,
+) (memo.ScalarListExpr, []*types.T) {
+	expandedParams, ok := paramTypes.ExpandVariadic(len(args))
+	if !ok {
+		panic(errors.AssertionFailedf(
+			"expected at least %d arguments for VARIADIC routine, found %d", len(paramTypes), len(args),
+		))
+	}
+	variadicOrd := len(paramTypes) - 1
+	elemTyp := expandedParams[variadicOrd].Typ
+	if elemTyp.IsPolymorphicType() {
+		var polyArgTyp *types.T
+		if elemTyp.IsAnyCompatibleType() {
+			_, _, polyArgTyp = tree.ResolveAnyCompatibleArgType(
+				expandedParams, argTypes, true, /* enforceConsistency */
+			)
+		} else {
+			_, _, polyArgTyp = tree.ResolvePolymorphicArgTypes(
+				expandedParams, argTypes, nil /* anyElemTyp */, true, /* enforceConsistency */
+			)
+		}
+		if polyArgTyp == nil {
+			panic(pgerror.New(pgcode.DatatypeMismatch,
+				"could not determine polymorphic type because input has type unknown",
+			))
+		}
+		if polyArgTyp.Family() == types.ArrayFamily {
+			panic(pgerror.Newf(pgcode.UndefinedObject,
+				"could not find array type for data type %s", polyArgTyp.Name(),
+			))
+		}
+		elemTyp = polyArgTyp
+	}
+	elems := make(memo.ScalarListExpr, len(args)-variadicOrd)
+	for i := range elems {
+		elem, elemArgTyp := args[variadicOrd+i], argTypes[variadicOrd+i]
+		if !elemArgTyp.Identical(elemTyp) {
+			elem = b.factory.ConstructCast(elem, elemTyp)
+		}
+		elems[i] = elem
+	}
+	arrayTyp := types.MakeArray(elemTyp)
+	args = append(args[:variadicOrd], b.factory.ConstructArray(elems, arrayTyp))
+	argTypes = append(argTypes[:variadicOrd], arrayTyp)
+	return args, argTypes
+}
+
+// polymorphicTypes contains the concrete types resolved from the arguments of
+// a routine invocation for its polymorphic parameters.
+type polymorphicTypes struct {
+	// anyElement is the type resolved for ANYELEMENT and ANYARRAY parameters.
+	anyElement *types.T
+	// anyCompatible is the common type resolved for ANYCOMPATIBLE and
+	// ANYCOMPATIBLEARRAY parameters.
+	anyCompatible *types.T
+}
+
 // maybeResolvePolymorphicReturnType checks whether the return type of the
-// routine is polymorphic and if so, uses the resolved polymorphic argument type
-// to determine the concrete return type.
-func (b *Builder) maybeResolvePolymorphicReturnType(f *tree.FuncExpr, polyArgTyp *types.T) {
+// routine is polymorphic and if so, uses the resolved polymorphic argument
+// types to determine the concrete return type.
+func (b *Builder) maybeResolvePolymorphicReturnType(f *tree.FuncExpr, polyTypes polymorphicTypes) {
 	originalRTyp := f.ResolvedType()
 	if originalRTyp.IsPolymorphicType() {
-		f.SetTypeAnnotation(maybeReplacePolymorphicType(originalRTyp, polyArgTyp))
+		f.SetTypeAnnotation(maybeReplacePolymorphicType(originalRTyp, polyTypes))
 	} else if originalRTyp.Family() == types.TupleFamily && !f.ResolvedOverload().ReturnsRecordType {
 		var hasPolymorphicOutParam bool
 		for _, typ := range originalRTyp.TupleContents() {
@@ -768,7 +857,7 @@ func (b *Builder) maybeResolvePolymorphicReturnType(f *tree.FuncExpr, polyArgTyp
 		if hasPolymorphicOutParam {
 			outParamTypes := make([]*types.T, len(originalRTyp.TupleContents()))
 			for i, outParamTyp := range originalRTyp.TupleContents() {
-				outParamTypes[i] = maybeReplacePolymorphicType(outParamTyp, polyArgTyp)
+				outParamTypes[i] = maybeReplacePolymorphicType(outParamTyp, polyTypes)
 			}
 			f.SetTypeAnnotation(types.MakeLabeledTuple(outParamTypes, originalRTyp.TupleLabels()))
 		}
@@ -776,10 +865,17 @@ func (b *Builder) maybeResolvePolymorphicReturnType(f *tree.FuncExpr, polyArgTyp
 }
 
 // maybeReplacePolymorphicType checks whether the given type is polymorphic and
-// if so, replaces it with the given polymorphic argument type. It returns the
-// original type if it is not polymorphic.
-func maybeReplacePolymorphicType(originalTyp, polyArgTyp *types.T) *types.T {
-	if !originalTyp.IsPolymorphicType() || polyArgTyp == nil {
+// if so, replaces it with the corresponding resolved polymorphic argument type.
+// It returns the original type if it is not polymorphic.
+func maybeReplacePolymorphicType(originalTyp *types.T, polyTypes polymorphicTypes) *types.T {
+	if !originalTyp.IsPolymorphicType() {
+		return originalTyp
+	}
+	polyArgTyp := polyTypes.anyElement
+	if originalTyp.IsAnyCompatibleType() {
+		polyArgTyp = polyTypes.anyCompatible
+	}
+	if polyArgTyp == nil {
 		return originalTyp
 	}
 	switch originalTyp.Family() {
@@ -795,10 +891,16 @@ func maybeReplacePolymorphicType(originalTyp, polyArgTyp *types.T) *types.T {
 	}
 }
 
-func (b *Builder) withinNestedPLpgSQLCall(fn func()) {
-	defer func(origValue bool) {
+// withinNestedPLpgSQLCall calls the given function, which builds a nested
+// PLpgSQL CALL statement. It returns true if the called procedure may COMMIT or
+// ROLLBACK the current transaction.
+func (b *Builder) withinNestedPLpgSQLCall(fn func()) (hasTxnControl bool) {
+	defer func(origValue, origHasTxnControl bool) {
 		b.insideNestedPLpgSQLCall = origValue
-	}(b.insideNestedPLpgSQLCall)
+		b.nestedCallHasTxnControl = origHasTxnControl
+	}(b.insideNestedPLpgSQLCall, b.nestedCallHasTxnControl)
 	b.insideNestedPLpgSQLCall = true
+	b.nestedCallHasTxnControl = false
 	fn()
+	return b.nestedCallHasTxnControl
 }
@@ -1595,9 +1595,10 @@ func (s *scope) replaceCount(
 	}
 	f.Exprs[0] = vn
 
-	// It is ok to use string equality here, even if there is a UDF named
-	// "count" because UDFs cannot be aggregate functions. This code path is
-	// only executed for aggregate functions.
+	// It is ok to use string equality here, even if there is a user-defined
+	// aggregate named "count", because user-defined aggregates cannot be called
+	// with a star argument. This code path is only executed for aggregate
+	// functions.
 	if strings.EqualFold(def.Name, "count") && f.Type == 0 {
 		if _, ok := vn.(tree.UnqualifiedStar); ok {
 			if f.Filter != nil {
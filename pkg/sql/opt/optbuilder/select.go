@@ -163,7 +163,7 @@ func (b *Builder) buildDataSource(
 					includeSystem:    true,
 					includeInverted:  false,
 				}),
-				indexFlags, locking, inScope,
+				b.hintedIndexFlags(t, indexFlags), locking, inScope,
 				false, /* disableNotVisibleIndex */
 			)
 
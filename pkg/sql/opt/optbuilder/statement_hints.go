@@ -0,0 +1,88 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// StatementHints are hints that were specified externally for the statement
+// being built (see CREATE STATEMENT HINT), rather than inline in its SQL. They
+// only apply to table references and joins that don't have an inline hint.
+type StatementHints struct {
+	// IndexFlags maps unqualified table names to the index flags that are
+	// applied to references to the table.
+	IndexFlags map[tree.Name]*tree.IndexFlags
+	// JoinHint is the join algorithm (e.g. tree.AstHash) that is applied to
+	// joins in the statement. It is empty if there is no join hint.
+	JoinHint string
+}
+
+// statementHintsApply returns true if external statement hints should be
+// applied to the expressions that are currently being built. Hints are not
+// applied to the bodies of routines, triggers and views that are being
+// defined, since those are not part of the hinted statement.
+func (b *Builder) statementHintsApply() bool {
+	return b.StatementHints != nil &&
+		!b.insideViewDef && !b.insideFuncDef && !b.insideTriggerDef && !b.insideUDF
+}
+
+// hintedIndexFlags returns the given index flags if they are not nil.
+// Otherwise, it returns the index flags of the external statement hint for the
+// given table, if there is one. A hint that refers to an index that no longer
+// exists is ignored.
+func (b *Builder) hintedIndexFlags(tab cat.Table, indexFlags *tree.IndexFlags) *tree.IndexFlags {
+	if indexFlags != nil || !b.statementHintsApply() {
+		return indexFlags
+	}
+	hint, ok := b.StatementHints.IndexFlags[tab.Name()]
+	if !ok {
+		return nil
+	}
+	indexExists := func(name tree.UnrestrictedName, id tree.IndexID) bool {
+		for i, n := 0, tab.IndexCount(); i < n; i++ {
+			if (name != "" && tab.Index(i).Name() == tree.Name(name)) ||
+				(id != 0 && tab.Index(i).ID() == cat.StableID(id)) {
+				return true
+			}
+		}
+		return false
+	}
+	if (hint.Index != "" || hint.IndexID != 0) && !indexExists(hint.Index, hint.IndexID) {
+		return nil
+	}
+	for _, name := range hint.ZigzagIndexes {
+		if !indexExists(name, 0 /* id */) {
+			return nil
+		}
+	}
+	for _, id := range hint.ZigzagIndexIDs {
+		if !indexExists("" /* name */, id) {
+			return nil
+		}
+	}
+	return hint
+}
+
+// hintedJoinAlgorithm returns the given join hint if it is not empty.
+// Otherwise, it returns the join hint of the external statement hints, if it
+// can be applied to a join of the given type.
+func (b *Builder) hintedJoinAlgorithm(hint string, joinType descpb.JoinType) string {
+	if hint != "" || !b.statementHintsApply() {
+		return hint
+	}
+	switch b.StatementHints.JoinHint {
+	case tree.AstLookup, tree.AstInverted:
+		// Unlike inline hints, which cause an error, an external hint that can't
+		// be applied to this join is ignored.
+		if joinType != descpb.InnerJoin && joinType != descpb.LeftOuterJoin {
+			return ""
+		}
+	}
+	return b.StatementHints.JoinHint
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -18,7 +19,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	plpgsql "github.com/cockroachdb/cockroach/pkg/sql/plpgsql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -83,8 +86,12 @@ func (mb *mutationBuilder) buildRowLevelBeforeTriggers(
 		triggerScope.expr = f.ConstructBarrier(triggerScope.expr)
 
 		// Resolve the trigger function and build the invocation.
-		args := mb.buildTriggerFunctionArgs(trigger, eventType, oldColID, newColID)
-		triggerFn, def := mb.b.buildTriggerFunction(triggers[i], mb.tab.ID(), tableTyp, args)
+		args := mb.b.buildTriggerFunctionArgs(
+			mb.tab, trigger, tree.TriggerActionTimeBefore, eventType, oldColID, newColID,
+		)
+		triggerFn, def := mb.b.buildTriggerFunction(
+			triggers[i], mb.tab.ID(), tableTyp, args, nil, /* transitions */
+		)
 
 		// If there is a WHEN condition, wrap the trigger function invocation in a
 		// CASE WHEN statement that checks the WHEN condition.
@@ -201,11 +208,16 @@ func (mb *mutationBuilder) buildOldAndNewCols(
 }
 
 // buildTriggerFunctionArgs builds the set of arguments that should be passed to
-// the trigger function.
-func (mb *mutationBuilder) buildTriggerFunctionArgs(
-	trigger cat.Trigger, eventType tree.TriggerEventType, oldColID, newColID opt.ColumnID,
+// the trigger function. The NEW and OLD arguments are NULL if the corresponding
+// column is zero, as is always the case for statement-level triggers.
+func (b *Builder) buildTriggerFunctionArgs(
+	tab cat.DataSource,
+	trigger cat.Trigger,
+	actionTime tree.TriggerActionTime,
+	eventType tree.TriggerEventType,
+	oldColID, newColID opt.ColumnID,
 ) memo.ScalarListExpr {
-	f := mb.b.factory
+	f := b.factory
 	tgNew := opt.ScalarExpr(memo.NullSingleton)
 	if newColID != 0 {
 		tgNew = f.ConstructVariable(newColID)
@@ -216,11 +228,20 @@ func (mb *mutationBuilder) buildTriggerFunctionArgs(
 	}
 	tgName := tree.NewDName(string(trigger.Name()))
 	tgWhen := tree.NewDString("BEFORE")
+	switch actionTime {
+	case tree.TriggerActionTimeAfter:
+		tgWhen = tree.NewDString("AFTER")
+	case tree.TriggerActionTimeInsteadOf:
+		tgWhen = tree.NewDString("INSTEAD OF")
+	}
 	tgLevel := tree.NewDString("ROW")
+	if !trigger.ForEachRow() {
+		tgLevel = tree.NewDString("STATEMENT")
+	}
 	tgOp := tree.NewDString(eventType.String())
-	tgRelID := tree.NewDOid(oid.Oid(mb.tab.ID()))
-	tgTableName := tree.NewDString(string(mb.tab.Name()))
-	fqName, err := mb.b.catalog.FullyQualifiedName(mb.b.ctx, mb.tab)
+	tgRelID := tree.NewDOid(oid.Oid(tab.ID()))
+	tgTableName := tree.NewDString(string(tab.Name()))
+	fqName, err := b.catalog.FullyQualifiedName(b.ctx, tab)
 	if err != nil {
 		panic(err)
 	}
@@ -375,23 +396,133 @@ func (mb *mutationBuilder) recomputeComputedColsForTrigger(eventType tree.Trigge
 }
 
 // ============================================================================
-// Row-level AFTER triggers
+// Statement-level BEFORE triggers
+// ============================================================================
+
+// buildStatementLevelBeforeTriggers builds any applicable statement-level
+// BEFORE triggers for the given events, in order. The triggers fire exactly
+// once, even if the mutation does not modify any rows.
+//
+// For a top-level statement, the trigger functions are invoked by an
+// uncorrelated subquery which is projected onto the mutation input, and which
+// is executed before the main query. Subqueries are evaluated lazily when the
+// mutation is planned within a routine, and cannot be used at all in cascades,
+// so in those cases the trigger functions are instead invoked by a single-row
+// expression that is left-joined with the mutation input (see
+// joinStatementLevelBeforeTriggers).
+func (mb *mutationBuilder) buildStatementLevelBeforeTriggers(
+	cascade bool, events ...tree.TriggerEventType,
+) {
+	f := mb.b.factory
+	var stmtScope *scope
+	var tableTyp *types.T
+	for _, eventType := range events {
+		var eventsToMatch tree.TriggerEventTypeSet
+		eventsToMatch.Add(eventType)
+		triggers := cat.GetStatementLevelTriggers(mb.tab, tree.TriggerActionTimeBefore, eventsToMatch)
+		if len(triggers) == 0 {
+			continue
+		}
+		if stmtScope == nil {
+			typeID := typedesc.TableIDToImplicitTypeOID(descpb.ID(mb.tab.ID()))
+			var err error
+			tableTyp, err = mb.b.semaCtx.TypeResolver.ResolveTypeByOID(mb.b.ctx, typeID)
+			if err != nil {
+				panic(err)
+			}
+			stmtScope = mb.b.allocScope()
+			stmtScope.expr = f.ConstructNoColsRow()
+		}
+		mb.b.buildStatementLevelTriggerCalls(
+			stmtScope, mb.tab, tableTyp, triggers, tree.TriggerActionTimeBefore, eventType,
+			nil, /* transitions */
+		)
+	}
+	if stmtScope == nil {
+		return
+	}
+	// The barrier prevents the trigger invocations from being pruned.
+	stmtScope.expr = f.ConstructBarrier(stmtScope.expr)
+	if cascade || mb.b.insideUDF {
+		mb.joinStatementLevelBeforeTriggers(stmtScope)
+		return
+	}
+
+	// Wrap the trigger invocations in a subquery that returns the result of the
+	// last trigger function.
+	lastColID := stmtScope.cols[len(stmtScope.cols)-1].id
+	subqueryInput := f.ConstructProject(
+		stmtScope.expr, memo.ProjectionsExpr{}, opt.MakeColSet(lastColID),
+	)
+	subquery := f.ConstructSubquery(subqueryInput, &memo.SubqueryPrivate{})
+
+	// Project the subquery onto the mutation input, and add a barrier to ensure
+	// that it is not pruned.
+	triggerScope := mb.outScope.push()
+	triggerScope.expr = mb.outScope.expr
+	triggerScope.appendColumnsFromScope(mb.outScope)
+	mb.b.projectColWithMetadataName(triggerScope, "statement-triggers", tableTyp, subquery)
+	triggerScope.expr = f.ConstructBarrier(triggerScope.expr)
+	mb.outScope = triggerScope
+}
+
+// joinStatementLevelBeforeTriggers invokes the statement-level BEFORE triggers
+// built in stmtScope, which produces a single row, by left-joining it with the
+// mutation input:
+//
+//	SELECT input.* FROM (SELECT <trigger calls>) LEFT JOIN
+//	  (SELECT *, true AS canary FROM input) ON true
+//	WHERE canary IS NOT NULL
+//
+// The left side of a left join is always read, so the triggers fire even if
+// the input is empty. The filter removes the null-extended row produced in
+// that case. The join is wrapped in a barrier so that the filter cannot be
+// used to simplify it into an inner join.
+func (mb *mutationBuilder) joinStatementLevelBeforeTriggers(stmtScope *scope) {
+	f := mb.b.factory
+	inputScope := mb.outScope.push()
+	inputScope.expr = mb.outScope.expr
+	inputScope.appendColumnsFromScope(mb.outScope)
+	mb.b.projectColWithMetadataName(
+		inputScope, "statement-triggers-canary", types.Bool, memo.TrueSingleton,
+	)
+	canaryColID := inputScope.cols[len(inputScope.cols)-1].id
+
+	triggerScope := inputScope.push()
+	triggerScope.appendColumnsFromScope(inputScope)
+	triggerScope.expr = f.ConstructBarrier(f.ConstructLeftJoin(
+		stmtScope.expr, inputScope.expr, memo.TrueFilter, memo.EmptyJoinPrivate,
+	))
+	triggerScope.expr = f.ConstructSelect(triggerScope.expr, memo.FiltersExpr{
+		f.ConstructFiltersItem(f.ConstructIsNot(f.ConstructVariable(canaryColID), memo.NullSingleton)),
+	})
+	mb.outScope = triggerScope
+}
+
+// ============================================================================
+// AFTER triggers
 // ============================================================================
 
-// buildRowLevelAfterTriggers builds any applicable row-level AFTER triggers
-// based on the mutation operator. Since AFTER triggers are a form of
+// buildAfterTriggers builds any applicable row-level and statement-level AFTER
+// triggers based on the mutation operator. Since AFTER triggers are a form of
 // post-query, they are stored on mutationBuilder instead of being projected as
 // part of the mutation input.
 //
-// NOTE: buildRowLevelAfterTriggers doesn't actually build the expression that
-// calls the trigger functions. Instead, it stores the information needed to do
-// so after the mutation executes.
-func (mb *mutationBuilder) buildRowLevelAfterTriggers(mutation opt.Operator) {
+// NOTE: buildAfterTriggers doesn't actually build the expression that calls the
+// trigger functions. Instead, it stores the information needed to do so after
+// the mutation executes.
+func (mb *mutationBuilder) buildAfterTriggers(mutation opt.Operator) {
 	eventsToMatch := mb.getEventsToMatchForMutation(mutation)
 	triggers := cat.GetRowLevelTriggers(mb.tab, tree.TriggerActionTimeAfter, eventsToMatch)
-	if len(triggers) == 0 {
+	stmtTriggers := cat.GetStatementLevelTriggers(mb.tab, tree.TriggerActionTimeAfter, eventsToMatch)
+	if len(triggers) == 0 && len(stmtTriggers) == 0 {
 		return
 	}
+	if mb.canaryColID != 0 && (hasTransitionTables(triggers) || hasTransitionTables(stmtTriggers)) {
+		// TODO(#135655): for UPSERT and INSERT ON CONFLICT, the transition
+		// relations must be filtered using the canary column.
+		panic(unimplementedTransitionUpsertErr)
+	}
 	mb.ensureWithID()
 
 	var visibleColOrds intsets.Fast
@@ -445,13 +576,30 @@ func (mb *mutationBuilder) buildRowLevelAfterTriggers(mutation opt.Operator) {
 	if mb.afterTriggers != nil {
 		panic(errors.AssertionFailedf("afterTriggers already set"))
 	}
-	mb.afterTriggers = &memo.AfterTriggers{
-		Triggers: triggers,
-		Builder: newRowLevelAfterTriggerBuilder(
+	mb.afterTriggers = &memo.AfterTriggers{WithID: mb.withID}
+	if len(triggers) > 0 {
+		mb.afterTriggers.Triggers = triggers
+		mb.afterTriggers.Builder = newRowLevelAfterTriggerBuilder(
 			mutation, mb.tab, triggers, fetchCols, updateCols, insertCols, mb.canaryColID,
-		),
-		WithID: mb.withID,
+		)
+	}
+	if len(stmtTriggers) > 0 {
+		mb.afterTriggers.StatementTriggers = stmtTriggers
+		mb.afterTriggers.StatementBuilder = newStatementLevelAfterTriggerBuilder(
+			mutation, mb.tab, stmtTriggers, eventsToMatch, fetchCols, updateCols, insertCols,
+		)
+	}
+}
+
+// hasTransitionTables returns true if any of the given triggers reference the
+// OLD or NEW transition relations.
+func hasTransitionTables(triggers []cat.Trigger) bool {
+	for _, trigger := range triggers {
+		if trigger.OldTransitionAlias() != "" || trigger.NewTransitionAlias() != "" {
+			return true
+		}
 	}
+	return false
 }
 
 // getEventsToMatchForMutation returns the set of trigger events that should be
@@ -599,6 +747,9 @@ func (tb *rowLevelAfterTriggerBuilder) Build(
 		md.AddWithBinding(binding, b.factory.ConstructFakeRel(&memo.FakeRelPrivate{
 			Props: bindingProps,
 		}))
+		transitions := makeTriggerTransitions(
+			tb.mutation, binding, tableTyp, inFetchCols, inUpdateCols, inInsertCols,
+		)
 		triggerScope.expr = f.ConstructWithScan(&memo.WithScanPrivate{
 			With:    binding,
 			InCols:  inCols,
@@ -710,7 +861,9 @@ func (tb *rowLevelAfterTriggerBuilder) Build(
 			}
 
 			// Resolve the trigger function and build the invocation.
-			triggerFn, def := b.buildTriggerFunction(trigger, tb.mutatedTable.ID(), tableTyp, args)
+			triggerFn, def := b.buildTriggerFunction(
+				trigger, tb.mutatedTable.ID(), tableTyp, args, transitions,
+			)
 
 			// If there is a WHEN condition, wrap the trigger function invocation in a
 			// CASE WHEN statement that checks the WHEN condition.
@@ -757,10 +910,643 @@ func (tb *rowLevelAfterTriggerBuilder) Build(
 	})
 }
 
+// statementLevelAfterTriggerBuilder is a memo.PostQueryBuilder implementation
+// for statement-level AFTER triggers.
+//
+// It provides a method to build the trigger-function invocations, which are
+// executed once after the mutation, regardless of how many rows it modified.
+// The columns of the buffered mutation input are only used to provide the
+// transition relations, if any.
+type statementLevelAfterTriggerBuilder struct {
+	mutation     opt.Operator
+	mutatedTable cat.Table
+	triggers     []cat.Trigger
+
+	// eventsToMatch are the trigger events performed by the mutation. Triggers
+	// are only fired for these events, even if they also match others.
+	eventsToMatch tree.TriggerEventTypeSet
+
+	// fetchCols, updateCols, and insertCols are the columns from the mutation
+	// input that correspond to the old and new values of the modified rows. See
+	// the rowLevelAfterTriggerBuilder fields with the same names.
+	fetchCols  opt.ColList
+	updateCols opt.ColList
+	insertCols opt.ColList
+}
+
+var _ memo.PostQueryBuilder = &statementLevelAfterTriggerBuilder{}
+
+func newStatementLevelAfterTriggerBuilder(
+	mutation opt.Operator,
+	mutatedTable cat.Table,
+	triggers []cat.Trigger,
+	eventsToMatch tree.TriggerEventTypeSet,
+	fetchCols, updateCols, insertCols opt.ColList,
+) *statementLevelAfterTriggerBuilder {
+	return &statementLevelAfterTriggerBuilder{
+		mutation:      mutation,
+		mutatedTable:  mutatedTable,
+		triggers:      triggers,
+		eventsToMatch: eventsToMatch,
+		fetchCols:     fetchCols,
+		updateCols:    updateCols,
+		insertCols:    insertCols,
+	}
+}
+
+// Build is part of the memo.PostQueryBuilder interface.
+func (tb *statementLevelAfterTriggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *eval.Context,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	colMap opt.ColMap,
+) (_ memo.RelExpr, err error) {
+	return buildTriggerCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		f := b.factory
+
+		typeID := typedesc.TableIDToImplicitTypeOID(descpb.ID(tb.mutatedTable.ID()))
+		tableTyp, err := semaCtx.TypeResolver.ResolveTypeByOID(ctx, typeID)
+		if err != nil {
+			panic(err)
+		}
+
+		// Make the buffered mutation input available to the trigger functions as
+		// the transition relations.
+		var transitions *triggerTransitions
+		if binding != 0 {
+			f.Metadata().AddWithBinding(binding, f.ConstructFakeRel(&memo.FakeRelPrivate{
+				Props: bindingProps,
+			}))
+			transitions = makeTriggerTransitions(
+				tb.mutation, binding, tableTyp, tb.fetchCols.RemapColumns(colMap),
+				tb.updateCols.RemapColumns(colMap), tb.insertCols.RemapColumns(colMap),
+			)
+		}
+
+		// Invoke the trigger functions for each event performed by the mutation in
+		// turn, starting from a single row with no columns.
+		triggerScope := b.allocScope()
+		triggerScope.expr = f.ConstructNoColsRow()
+		for _, eventType := range []tree.TriggerEventType{
+			tree.TriggerEventInsert, tree.TriggerEventUpdate, tree.TriggerEventDelete,
+		} {
+			if !tb.eventsToMatch.Contains(eventType) {
+				continue
+			}
+			var triggers []cat.Trigger
+			for _, trigger := range tb.triggers {
+				for i := 0; i < trigger.EventCount(); i++ {
+					if trigger.Event(i).EventType == eventType {
+						triggers = append(triggers, trigger)
+						break
+					}
+				}
+			}
+			b.buildStatementLevelTriggerCalls(
+				triggerScope, tb.mutatedTable, tableTyp, triggers, tree.TriggerActionTimeAfter,
+				eventType, transitions,
+			)
+		}
+		// Always wrap the expression in a barrier, or else the projections will be
+		// pruned and the triggers will not be executed.
+		return f.ConstructBarrier(triggerScope.expr)
+	})
+}
+
+// ============================================================================
+// INSTEAD OF triggers
+// ============================================================================
+
+// insteadOfTarget is a view targeted by an INSERT, UPDATE, or DELETE statement
+// that is routed to the view's row-level INSTEAD OF triggers. No mutation
+// operator is built for such a statement; instead, the trigger functions are
+// invoked once for each row that would have been inserted, updated, or deleted.
+type insteadOfTarget struct {
+	view     cat.View
+	viewName tree.TableName
+
+	// alias is the name used to reference the columns of the view in the WHERE
+	// and RETURNING clauses.
+	alias tree.TableName
+
+	// viewTyp is the implicit record type of the view, which is the type of the
+	// OLD and NEW rows passed to the trigger functions.
+	viewTyp *types.T
+
+	eventType tree.TriggerEventType
+	triggers  []cat.Trigger
+}
+
+// resolveInsteadOfTarget returns the target of a mutation if it is a view with
+// INSTEAD OF triggers for the given event, after checking that the current user
+// has the given privilege on the view. Otherwise, it returns nil and the
+// mutation should be built as usual. Note that views without INSTEAD OF
+// triggers are still rejected by resolveTableForMutation.
+func (b *Builder) resolveInsteadOfTarget(
+	n tree.TableExpr, priv privilege.Kind, eventType tree.TriggerEventType,
+) *insteadOfTarget {
+	var alias *tree.TableName
+	if ate, ok := n.(*tree.AliasedTableExpr); ok {
+		n = ate.Expr
+		if ate.As.Alias != "" {
+			alias = tree.NewUnqualifiedTableName(ate.As.Alias)
+		}
+	}
+	tn, ok := n.(*tree.TableName)
+	if !ok {
+		return nil
+	}
+	var flags cat.Flags
+	if b.insideViewDef || b.insideFuncDef || b.insideTriggerDef {
+		flags.AvoidDescriptorCaches = true
+	}
+	ds, _, err := b.catalog.ResolveDataSource(b.ctx, flags, tn)
+	if err != nil {
+		// Leave it to resolveTableForMutation to report the error.
+		return nil
+	}
+	if view, ok := ds.(cat.View); !ok || view.TriggerCount() == 0 {
+		return nil
+	}
+
+	// Resolve the view again in order to check privileges and add the view as a
+	// dependency of the query.
+	ds, depName, viewName := b.resolveDataSource(tn, priv)
+	view := ds.(cat.View)
+	var eventsToMatch tree.TriggerEventTypeSet
+	eventsToMatch.Add(eventType)
+	triggers := cat.GetInsteadOfTriggers(view, eventsToMatch)
+	if len(triggers) == 0 {
+		return nil
+	}
+	if eventType != tree.TriggerEventInsert {
+		// Existing rows must be read from the view.
+		b.checkPrivilege(depName, view, privilege.SELECT)
+	}
+	typeID := typedesc.TableIDToImplicitTypeOID(descpb.ID(view.ID()))
+	viewTyp, err := b.semaCtx.TypeResolver.ResolveTypeByOID(b.ctx, typeID)
+	if err != nil {
+		panic(err)
+	}
+	target := &insteadOfTarget{
+		view:      view,
+		viewName:  viewName,
+		alias:     viewName,
+		viewTyp:   viewTyp,
+		eventType: eventType,
+		triggers:  triggers,
+	}
+	if alias != nil {
+		target.alias = *alias
+	}
+	return target
+}
+
+// columnOrdinal returns the ordinal of the view column with the given name.
+func (t *insteadOfTarget) columnOrdinal(name tree.Name) int {
+	for i, label := range t.viewTyp.TupleLabels() {
+		if label == string(name) {
+			return i
+		}
+	}
+	panic(colinfo.NewUndefinedColumnError(string(name)))
+}
+
+// buildInsteadOfInsert builds an INSERT into a view with INSTEAD OF INSERT
+// triggers. The NEW row passed to the triggers is built from the input rows.
+// Columns that are not targeted by the input are NULL, since views do not have
+// column defaults.
+func (b *Builder) buildInsteadOfInsert(
+	ins *tree.Insert, inScope *scope, target *insteadOfTarget,
+) (outScope *scope) {
+	if ins.OnConflict != nil {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"UPSERT and INSERT ... ON CONFLICT are not supported on view %q", target.view.Name()))
+	}
+	colTypes := target.viewTyp.TupleContents()
+
+	// Determine the view columns that are targeted by the input, either
+	// explicitly by name or implicitly in the order of the view columns.
+	var targetOrds []int
+	if len(ins.Columns) != 0 {
+		var targeted intsets.Fast
+		for _, name := range ins.Columns {
+			ord := target.columnOrdinal(name)
+			if targeted.Contains(ord) {
+				panic(pgerror.Newf(pgcode.Syntax, "multiple assignments to the same column %q", name))
+			}
+			targeted.Add(ord)
+			targetOrds = append(targetOrds, ord)
+		}
+	} else {
+		targetOrds = make([]int, len(colTypes))
+		for i := range targetOrds {
+			targetOrds[i] = i
+		}
+	}
+
+	// Build the input rows, or a single empty row for DEFAULT VALUES.
+	var rowScope *scope
+	if ins.DefaultValues() {
+		rowScope = inScope.push()
+		rowScope.expr = b.factory.ConstructNoColsRow()
+	} else {
+		desiredTypes := make([]*types.T, len(targetOrds))
+		for i, ord := range targetOrds {
+			desiredTypes[i] = colTypes[ord]
+		}
+		rowScope = b.buildStmt(replaceDefaultsWithNull(ins.Rows), desiredTypes, inScope)
+		expected, actual := len(targetOrds), len(rowScope.cols)
+		if actual > expected || (len(ins.Columns) != 0 && actual < expected) {
+			more, less := "expressions", "target columns"
+			if actual < expected {
+				more, less = less, more
+			}
+			panic(pgerror.Newf(pgcode.Syntax,
+				"INSERT has more %s than %s, %d expressions for %d targets", more, less, actual, expected))
+		}
+	}
+
+	// Project the NEW row and invoke the triggers.
+	newCols := make(opt.OptionalColList, len(colTypes))
+	for i := range rowScope.cols {
+		newCols[targetOrds[i]] = rowScope.cols[i].id
+	}
+	newColID := b.projectInsteadOfRow(rowScope, target, newCols, triggerColNew)
+	resultColID := b.buildInsteadOfTriggerCalls(rowScope, target, 0 /* oldColID */, newColID)
+	return b.buildInsteadOfOutput(rowScope, target, resultColID, ins.Returning)
+}
+
+// buildInsteadOfUpdate builds an UPDATE of a view with INSTEAD OF UPDATE
+// triggers. The OLD row passed to the triggers is the existing row of the view,
+// and the NEW row is the result of applying the SET expressions to it.
+func (b *Builder) buildInsteadOfUpdate(
+	upd *tree.Update, inScope *scope, target *insteadOfTarget,
+) (outScope *scope) {
+	if len(upd.From) > 0 {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"UPDATE ... FROM is not supported on view %q", target.view.Name()))
+	}
+	triggerScope, viewCols := b.buildInputForInsteadOf(
+		inScope, target, upd.Where, upd.Limit, upd.OrderBy,
+	)
+
+	// SET expressions should reject aggregates, generators, etc.
+	scalarProps := &b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	b.semaCtx.Properties.Require("UPDATE SET", tree.RejectSpecial)
+
+	// Build each of the SET expressions, which can reference the columns of the
+	// view.
+	colTypes := target.viewTyp.TupleContents()
+	newCols := make(opt.OptionalColList, len(viewCols))
+	copy(newCols, viewCols)
+	projectionsScope := triggerScope.replace()
+	projectionsScope.appendColumnsFromScope(triggerScope)
+	var updated intsets.Fast
+	addCol := func(name tree.Name, expr tree.Expr) {
+		ord := target.columnOrdinal(name)
+		if updated.Contains(ord) {
+			panic(pgerror.Newf(pgcode.Syntax, "multiple assignments to the same column %q", name))
+		}
+		updated.Add(ord)
+		if _, ok := expr.(tree.DefaultVal); ok {
+			expr = tree.DNull
+		}
+		texpr := triggerScope.resolveType(expr, colTypes[ord])
+		colName := scopeColName(name).WithMetadataName(string(name) + "_new")
+		scopeCol := projectionsScope.addColumn(colName, texpr)
+		b.buildScalar(texpr, triggerScope, projectionsScope, scopeCol, nil /* colRefs */)
+		newCols[ord] = scopeCol.id
+	}
+	for _, set := range upd.Exprs {
+		if !set.Tuple {
+			addCol(set.Names[0], set.Expr)
+			continue
+		}
+		tuple, ok := set.Expr.(*tree.Tuple)
+		if !ok {
+			panic(pgerror.Newf(pgcode.FeatureNotSupported,
+				"subqueries in SET are not supported on view %q", target.view.Name()))
+		}
+		if len(tuple.Exprs) != len(set.Names) {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"number of columns (%d) does not match number of values (%d)",
+				len(set.Names), len(tuple.Exprs)))
+		}
+		for i := range set.Names {
+			addCol(set.Names[i], tuple.Exprs[i])
+		}
+	}
+	b.constructProjectForScope(triggerScope, projectionsScope)
+	triggerScope = projectionsScope
+
+	// Project the OLD and NEW rows and invoke the triggers.
+	oldColID := b.projectInsteadOfRow(triggerScope, target, viewCols, triggerColOld)
+	newColID := b.projectInsteadOfRow(triggerScope, target, newCols, triggerColNew)
+	resultColID := b.buildInsteadOfTriggerCalls(triggerScope, target, oldColID, newColID)
+	return b.buildInsteadOfOutput(triggerScope, target, resultColID, upd.Returning)
+}
+
+// buildInsteadOfDelete builds a DELETE from a view with INSTEAD OF DELETE
+// triggers. The OLD row passed to the triggers is the existing row of the view.
+func (b *Builder) buildInsteadOfDelete(
+	del *tree.Delete, inScope *scope, target *insteadOfTarget,
+) (outScope *scope) {
+	if len(del.Using) > 0 {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported,
+			"DELETE ... USING is not supported on view %q", target.view.Name()))
+	}
+	triggerScope, viewCols := b.buildInputForInsteadOf(
+		inScope, target, del.Where, del.Limit, del.OrderBy,
+	)
+
+	// Project the OLD row and invoke the triggers. The return value of a DELETE
+	// trigger is only used to determine whether the row was processed, so
+	// RETURNING uses the OLD row.
+	oldColID := b.projectInsteadOfRow(triggerScope, target, viewCols, triggerColOld)
+	b.buildInsteadOfTriggerCalls(triggerScope, target, oldColID, 0 /* newColID */)
+	return b.buildInsteadOfOutput(triggerScope, target, oldColID, del.Returning)
+}
+
+// buildInputForInsteadOf builds the rows of the view that are targeted by an
+// UPDATE or DELETE statement, similar to this:
+//
+//	SELECT <cols> FROM <view> WHERE <where>
+//	ORDER BY <order-by> LIMIT <limit>
+//
+// It returns the IDs of the view columns, in order.
+func (b *Builder) buildInputForInsteadOf(
+	inScope *scope,
+	target *insteadOfTarget,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
+) (outScope *scope, viewCols opt.OptionalColList) {
+	viewScope := b.buildView(target.view, &target.viewName, noLocking, inScope)
+	if len(viewScope.cols) != len(target.viewTyp.TupleContents()) {
+		panic(errors.AssertionFailedf("unexpected number of view columns"))
+	}
+	viewCols = make(opt.OptionalColList, len(viewScope.cols))
+	for i := range viewScope.cols {
+		viewScope.cols[i].table = target.alias
+		viewCols[i] = viewScope.cols[i].id
+	}
+
+	// WHERE
+	b.buildWhere(where, viewScope)
+
+	// SELECT + ORDER BY (which may add projected expressions)
+	orderByKind := exprKindOrderByUpdate
+	if target.eventType == tree.TriggerEventDelete {
+		orderByKind = exprKindOrderByDelete
+	}
+	projectionsScope := viewScope.replace()
+	projectionsScope.appendColumnsFromScope(viewScope)
+	orderByScope := b.analyzeOrderBy(orderBy, viewScope, projectionsScope,
+		orderByKind, tree.RejectGenerators|tree.RejectAggregates)
+	b.buildOrderBy(viewScope, projectionsScope, orderByScope)
+	b.constructProjectForScope(viewScope, projectionsScope)
+
+	// LIMIT
+	if limit != nil {
+		b.buildLimit(limit, inScope, projectionsScope)
+	}
+	return projectionsScope, viewCols
+}
+
+// projectInsteadOfRow projects a tuple of the view's implicit record type onto
+// the given scope, with elements taken from the given columns. Elements with
+// no corresponding column are NULL.
+func (b *Builder) projectInsteadOfRow(
+	s *scope, target *insteadOfTarget, cols opt.OptionalColList, name string,
+) opt.ColumnID {
+	f := b.factory
+	elems := make(memo.ScalarListExpr, len(cols))
+	for i, colTyp := range target.viewTyp.TupleContents() {
+		if cols[i] == 0 {
+			elems[i] = f.ConstructNull(colTyp)
+			continue
+		}
+		elem := opt.ScalarExpr(f.ConstructVariable(cols[i]))
+		if !f.Metadata().ColumnMeta(cols[i]).Type.Identical(colTyp) {
+			elem = f.ConstructAssignmentCast(elem, colTyp)
+		}
+		elems[i] = elem
+	}
+	tup := f.ConstructTuple(elems, target.viewTyp)
+	return b.projectColWithMetadataName(s, name, target.viewTyp, tup)
+}
+
+// buildInsteadOfTriggerCalls projects a column onto triggerScope for each of
+// the target's INSTEAD OF triggers, which invokes the trigger function. As with
+// row-level BEFORE triggers, a row for which a trigger function returns NULL is
+// skipped, and the result of each INSERT or UPDATE trigger function is passed
+// as the NEW row to the next trigger. buildInsteadOfTriggerCalls returns the
+// column that holds the result of the last trigger function.
+func (b *Builder) buildInsteadOfTriggerCalls(
+	triggerScope *scope, target *insteadOfTarget, oldColID, newColID opt.ColumnID,
+) (resultColID opt.ColumnID) {
+	f := b.factory
+	for _, trigger := range target.triggers {
+		triggerScope.expr = f.ConstructBarrier(triggerScope.expr)
+		args := b.buildTriggerFunctionArgs(
+			target.view, trigger, tree.TriggerActionTimeInsteadOf, target.eventType, oldColID, newColID,
+		)
+		triggerFn, def := b.buildTriggerFunction(
+			trigger, target.view.ID(), target.viewTyp, args, nil, /* transitions */
+		)
+		resultColID = b.projectColWithMetadataName(triggerScope, def.Name, target.viewTyp, triggerFn)
+		filter := f.ConstructIsNot(f.ConstructVariable(resultColID), memo.NullSingleton)
+		triggerScope.expr = f.ConstructSelect(
+			triggerScope.expr, memo.FiltersExpr{f.ConstructFiltersItem(filter)},
+		)
+		if target.eventType != tree.TriggerEventDelete {
+			newColID = resultColID
+		}
+	}
+	// Wrap the expression in a barrier, or else the trigger invocations could be
+	// pruned.
+	triggerScope.expr = f.ConstructBarrier(triggerScope.expr)
+	return resultColID
+}
+
+// buildInsteadOfOutput builds the output of a statement that was routed to
+// INSTEAD OF triggers. If there is a RETURNING clause, it is evaluated over the
+// columns of the row in rowColID. Otherwise, the output is a single row with
+// the number of rows processed by the triggers, which is reported as the number
+// of rows affected by the statement.
+func (b *Builder) buildInsteadOfOutput(
+	triggerScope *scope,
+	target *insteadOfTarget,
+	rowColID opt.ColumnID,
+	returning tree.ReturningClause,
+) (outScope *scope) {
+	f := b.factory
+	if !resultsNeeded(returning) {
+		outScope = triggerScope.replace()
+		countCol := b.synthesizeColumn(
+			outScope, scopeColName("count"), types.Int, nil /* expr */, nil, /* scalar */
+		)
+		aggs := memo.AggregationsExpr{f.ConstructAggregationsItem(f.ConstructCountRows(), countCol.id)}
+		outScope.expr = f.ConstructScalarGroupBy(triggerScope.expr, aggs, &memo.GroupingPrivate{})
+		return outScope
+	}
+
+	// Construct a scope containing one column for each column of the view, with
+	// the same names. These columns can be referenced by the RETURNING clause.
+	inScope := triggerScope.replace()
+	labels := target.viewTyp.TupleLabels()
+	projections := make(memo.ProjectionsExpr, len(labels))
+	for i, colTyp := range target.viewTyp.TupleContents() {
+		elem := f.ConstructColumnAccess(f.ConstructVariable(rowColID), memo.TupleOrdinal(i))
+		col := b.synthesizeColumn(inScope, scopeColName(tree.Name(labels[i])), colTyp, nil /* expr */, elem)
+		col.table = target.alias
+		projections[i] = f.ConstructProjectionsItem(elem, col.id)
+	}
+	inScope.expr = f.ConstructProject(triggerScope.expr, projections, opt.ColSet{})
+
+	// Construct the Project operator that projects the RETURNING expressions.
+	outScope = inScope.replace()
+	b.analyzeReturningList(returning.(*tree.ReturningExprs), nil /* desiredTypes */, inScope, outScope)
+	b.buildProjectionList(inScope, outScope)
+	b.constructProjectForScope(inScope, outScope)
+	return outScope
+}
+
+// replaceDefaultsWithNull replaces DEFAULT specifiers in the input of an INSERT
+// into a view with NULL, since views do not have column defaults. This is only
+// possible when the input is a VALUES clause.
+func replaceDefaultsWithNull(inRows *tree.Select) (outRows *tree.Select) {
+	values := extractValuesInput(inRows)
+	if values == nil {
+		return inRows
+	}
+	newRows := make([]tree.Exprs, len(values.Rows))
+	for i, tuple := range values.Rows {
+		newRows[i] = make(tree.Exprs, len(tuple))
+		for j, val := range tuple {
+			if _, ok := val.(tree.DefaultVal); ok {
+				val = tree.DNull
+			}
+			newRows[i][j] = val
+		}
+	}
+	return &tree.Select{Select: &tree.ValuesClause{Rows: newRows}}
+}
+
 // ============================================================================
 // Shared logic
 // ============================================================================
 
+// buildStatementLevelTriggerCalls projects a column onto triggerScope for each
+// of the given statement-level triggers, which invokes the trigger function.
+// Barriers ensure that the triggers are executed in order. The results of the
+// trigger functions are ignored.
+func (b *Builder) buildStatementLevelTriggerCalls(
+	triggerScope *scope,
+	tab cat.Table,
+	tableTyp *types.T,
+	triggers []cat.Trigger,
+	actionTime tree.TriggerActionTime,
+	eventType tree.TriggerEventType,
+	transitions *triggerTransitions,
+) {
+	f := b.factory
+	for _, trigger := range triggers {
+		triggerScope.expr = f.ConstructBarrier(triggerScope.expr)
+		args := b.buildTriggerFunctionArgs(
+			tab, trigger, actionTime, eventType, 0 /* oldColID */, 0, /* newColID */
+		)
+		triggerFn, def := b.buildTriggerFunction(trigger, tab.ID(), tableTyp, args, transitions)
+
+		// The WHEN condition of a statement-level trigger cannot reference the OLD
+		// or NEW columns.
+		if trigger.WhenExpr() != "" {
+			triggerFn = b.buildTriggerWhen(
+				trigger, triggerScope, 0 /* oldColID */, 0 /* newColID */, triggerFn,
+				f.ConstructNull(tableTyp),
+			)
+		}
+		b.projectColWithMetadataName(triggerScope, def.Name, tableTyp, triggerFn)
+	}
+}
+
+// triggerTransitions describes the OLD and NEW transition relations that can be
+// referenced by the function of an AFTER trigger. Both are scanned from the
+// buffered mutation input, identified by binding. Each presentation has one
+// column per visible column in the table, and is nil if the corresponding
+// relation is not available for the triggering mutation.
+type triggerTransitions struct {
+	binding opt.WithID
+	oldCols physical.Presentation
+	newCols physical.Presentation
+}
+
+// makeTriggerTransitions builds the transition relations for the given mutation
+// from the (remapped) columns of the buffered mutation input. It returns nil
+// if there is no buffered input.
+func makeTriggerTransitions(
+	mutation opt.Operator,
+	binding opt.WithID,
+	tableTyp *types.T,
+	fetchCols, updateCols, insertCols opt.ColList,
+) *triggerTransitions {
+	if binding == 0 {
+		return nil
+	}
+	makePresentation := func(cols opt.ColList) physical.Presentation {
+		if len(cols) == 0 {
+			return nil
+		}
+		if len(cols) != len(tableTyp.TupleLabels()) {
+			panic(errors.AssertionFailedf("unexpected number of transition columns"))
+		}
+		presentation := make(physical.Presentation, len(cols))
+		for i, col := range cols {
+			presentation[i] = opt.AliasedColumn{Alias: tableTyp.TupleLabels()[i], ID: col}
+		}
+		return presentation
+	}
+	transitions := &triggerTransitions{binding: binding}
+	switch mutation {
+	case opt.InsertOp:
+		transitions.newCols = makePresentation(insertCols)
+	case opt.UpdateOp:
+		transitions.oldCols = makePresentation(fetchCols)
+		transitions.newCols = makePresentation(updateCols)
+	case opt.DeleteOp:
+		transitions.oldCols = makePresentation(fetchCols)
+	default:
+		panic(errors.AssertionFailedf("unexpected mutation type: %v", mutation))
+	}
+	return transitions
+}
+
+// addTransitionRelation makes the transition relation with the given alias
+// available to SQL statements in the given trigger function scope.
+func (b *Builder) addTransitionRelation(
+	triggerFuncScope *scope, alias tree.Name, binding opt.WithID, cols physical.Presentation,
+) {
+	if cols == nil {
+		panic(errors.AssertionFailedf("transition relation %s is not available", alias))
+	}
+	if triggerFuncScope.ctes == nil {
+		triggerFuncScope.ctes = make(map[string]*cteSource)
+	}
+	triggerFuncScope.ctes[string(alias)] = &cteSource{
+		name: tree.AliasClause{Alias: alias},
+		cols: cols,
+		expr: b.factory.Metadata().WithBinding(binding).(memo.RelExpr),
+		id:   binding,
+		mtr:  tree.CTEMaterializeAlways,
+	}
+}
+
 type cachedTriggerFunc struct {
 	triggerName tree.Name
 	funDef      *memo.UDFDefinition
@@ -768,9 +1554,15 @@ type cachedTriggerFunc struct {
 }
 
 // buildTriggerFunction resolves and builds a trigger function invocation for
-// the given trigger, using the given arguments.
+// the given trigger, using the given arguments. transitions provides the
+// transition relations referenced by the trigger, if any; it is nil for BEFORE
+// triggers.
 func (b *Builder) buildTriggerFunction(
-	trigger cat.Trigger, tableID cat.StableID, tableTyp *types.T, args memo.ScalarListExpr,
+	trigger cat.Trigger,
+	tableID cat.StableID,
+	tableTyp *types.T,
+	args memo.ScalarListExpr,
+	transitions *triggerTransitions,
 ) (opt.ScalarExpr, *tree.ResolvedFunctionDefinition) {
 	cached := b.builtTriggerFuncs[tableID]
 	for _, cachedFunc := range cached {
@@ -803,6 +1595,21 @@ func (b *Builder) buildTriggerFunction(
 		paramCols[colOrd] = col.id
 	}
 
+	// The trigger function can reference the OLD and NEW transition relations
+	// using the aliases from the trigger definition.
+	oldAlias, newAlias := trigger.OldTransitionAlias(), trigger.NewTransitionAlias()
+	if oldAlias != "" || newAlias != "" {
+		if transitions == nil {
+			panic(errors.AssertionFailedf("missing transition relations for trigger %s", trigger.Name()))
+		}
+		if oldAlias != "" {
+			b.addTransitionRelation(triggerFuncScope, oldAlias, transitions.binding, transitions.oldCols)
+		}
+		if newAlias != "" {
+			b.addTransitionRelation(triggerFuncScope, newAlias, transitions.binding, transitions.newCols)
+		}
+	}
+
 	// Initialize and cache the UDF definition before building the function body.
 	// This is necessary to handle recursive triggers.
 	//
@@ -837,7 +1644,8 @@ func (b *Builder) buildTriggerFunction(
 	}
 	plBuilder := newPLpgSQLBuilder(
 		b, resolvedDef.Name, stmt.AST.Label, nil /* colRefs */, params, tableTyp,
-		false /* isProc */, true /* buildSQL */, nil, /* outScope */
+		false /* isProc */, true /* buildSQL */, nil, /* resultBuffer */
+		false /* multiColOutput */, nil, /* outScope */
 	)
 	stmtScope := plBuilder.buildRootBlock(stmt.AST, triggerFuncScope, params)
 	udfDef.Body = []memo.RelExpr{stmtScope.expr}
@@ -0,0 +1,164 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package optbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	plpgsql "github.com/cockroachdb/cockroach/pkg/sql/plpgsql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// User-defined aggregates are evaluated incrementally by the aggregator and
+// the windower, in the same way as builtin aggregates. The arguments of each
+// call are packed into a single tuple, which is the only input column of the
+// UserDefinedAgg operator. For each row, the aggregate invokes a routine that
+// calls the state transition function with the current state and the unpacked
+// arguments. Once all rows of a group have been added, it invokes a routine
+// that calls the final function, if any.
+//
+// Plans that call a user-defined aggregate are not distributed, and local plans
+// never split an aggregation into partial and final stages. For this reason,
+// user-defined aggregates can't have a combine function.
+
+const udfAggStateName = "agg_state"
+
+// aggregateArgExprs returns the arguments of the given aggregate or window
+// function call as they are projected for the GroupBy or Window operator. The
+// arguments of a user-defined aggregate are packed into a single tuple.
+func aggregateArgExprs(f *tree.FuncExpr) tree.Exprs {
+	if f.ResolvedOverload().UDFAggregate == nil {
+		return f.Exprs
+	}
+	argTypes := make([]*types.T, len(f.Exprs))
+	for i := range f.Exprs {
+		argTypes[i] = f.Exprs[i].(tree.TypedExpr).ResolvedType()
+	}
+	return tree.Exprs{tree.NewTypedTuple(types.MakeTuple(argTypes), f.Exprs)}
+}
+
+// constructUDFAggregate constructs a UserDefinedAgg operator for the given
+// call to a user-defined aggregate. input is the tuple of the arguments built by
+// aggregateArgExprs.
+func (b *Builder) constructUDFAggregate(f *tree.FuncExpr, input opt.ScalarExpr) opt.ScalarExpr {
+	o := f.ResolvedOverload()
+	agg := o.UDFAggregate
+	argTypes := make([]*types.T, len(f.Exprs))
+	for i := range f.Exprs {
+		argTypes[i] = f.Exprs[i].(tree.TypedExpr).ResolvedType()
+	}
+	b.factory.Metadata().AddUserDefinedRoutine(o, argTypes, f.Func.ReferenceByName)
+	if b.trackSchemaDeps {
+		b.schemaFunctionDeps.Add(int(o.Oid))
+	}
+
+	_, stateFunc, err := b.semaCtx.FunctionResolver.ResolveFunctionByOID(b.ctx, agg.StateFunc)
+	if err != nil {
+		panic(err)
+	}
+
+	name := f.Func.String()
+	def := &memo.UDFAggregateDefinition{
+		Name:             name,
+		Typ:              f.ResolvedType(),
+		Volatility:       o.Volatility,
+		StrictTransition: !stateFunc.CalledOnNullInput,
+	}
+
+	// The transition routine is only invoked by the aggregate once it has
+	// handled NULL arguments and a NULL state according to StrictTransition.
+	params := make([]routineParam, len(argTypes)+1)
+	params[0] = routineParam{name: udfAggStateName, typ: agg.StateType, class: tree.RoutineParamIn}
+	for i, typ := range argTypes {
+		params[i+1] = routineParam{
+			name:  tree.Name(fmt.Sprintf("agg_arg_%d", i+1)),
+			typ:   typ,
+			class: tree.RoutineParamIn,
+		}
+	}
+	def.Transition = b.buildUDFAggregateRoutine(name, agg.StateFunc, params, agg.StateType, o)
+
+	if agg.FinalFunc != 0 {
+		def.Final = b.buildUDFAggregateRoutine(name, agg.FinalFunc, params[:1], f.ResolvedType(), o)
+	}
+
+	if agg.InitCond != nil {
+		// The initial condition is cast to the state type when the aggregate is
+		// initialized, since the cast may depend on session settings.
+		def.InitCond = tree.NewTypedCastExpr(tree.NewDString(*agg.InitCond), agg.StateType)
+	}
+
+	return b.factory.ConstructUserDefinedAgg(input, &memo.UserDefinedAggPrivate{Def: def})
+}
+
+// buildUDFAggregateRoutine builds a routine that returns the result of calling
+// the support function of a user-defined aggregate with the given OID, passing
+// the routine parameters through as arguments.
+func (b *Builder) buildUDFAggregateRoutine(
+	name string, fnOID oid.Oid, params []routineParam, returnType *types.T, o *tree.Overload,
+) *memo.UDFDefinition {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "BEGIN\nRETURN [FUNCTION %d](", fnOID)
+	for i := range params {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(params[i].name.String())
+	}
+	sb.WriteString(");\nEND\n")
+	stmt, err := plpgsql.Parse(sb.String())
+	if err != nil {
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "failed to parse aggregate support routine"))
+	}
+
+	// The support functions are resolved by OID, so they are not tracked as
+	// schema dependencies. See buildRoutine.
+	defer func(trackSchemaDeps, insideUDF, insideDataSource, insideSQLRoutine bool) {
+		b.trackSchemaDeps = trackSchemaDeps
+		b.insideUDF = insideUDF
+		b.insideDataSource = insideDataSource
+		b.insideSQLRoutine = insideSQLRoutine
+	}(b.trackSchemaDeps, b.insideUDF, b.insideDataSource, b.insideSQLRoutine)
+	b.trackSchemaDeps = false
+	b.insideUDF = true
+	b.insideDataSource = false
+	b.insideSQLRoutine = false
+
+	bodyScope := b.allocScope()
+	paramCols := make(opt.ColList, len(params))
+	for i := range params {
+		col := b.synthesizeColumn(
+			bodyScope, funcParamColName(params[i].name, i), params[i].typ, nil /* expr */, nil, /* scalar */
+		)
+		col.setParamOrd(i)
+		paramCols[i] = col.id
+	}
+	plBuilder := newPLpgSQLBuilder(
+		b, name, stmt.AST.Label, nil /* colRefs */, params, returnType,
+		false /* isProcedure */, true /* buildSQL */, nil, /* resultBuffer */
+		false /* multiColOutput */, nil, /* outScope */
+	)
+	stmtScope := plBuilder.buildRootBlock(stmt.AST, bodyScope, params)
+
+	return &memo.UDFDefinition{
+		Name:              name,
+		Typ:               returnType,
+		Volatility:        o.Volatility,
+		CalledOnNullInput: true,
+		RoutineType:       o.Type,
+		RoutineLang:       tree.RoutineLangPLpgSQL,
+		Params:            paramCols,
+		Body:              []memo.RelExpr{stmtScope.expr},
+		BodyProps:         []*physical.Required{stmtScope.makePhysicalProps()},
+	}
+}
@@ -69,6 +69,11 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 		panic(pgerror.DangerousStatementf("UPDATE without WHERE or LIMIT clause"))
 	}
 
+	// Route the UPDATE to the INSTEAD OF triggers of a view, if applicable.
+	if target := b.resolveInsteadOfTarget(upd.Table, privilege.UPDATE, tree.TriggerEventUpdate); target != nil {
+		return b.buildInsteadOfUpdate(upd, inScope, target)
+	}
+
 	// Find which table we're working on, check the permissions.
 	tab, depName, alias, refColumns := b.resolveTableForMutation(upd.Table, privilege.UPDATE)
 
@@ -107,7 +112,8 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 	// Build each of the SET expressions.
 	mb.addUpdateCols(upd.Exprs)
 
-	// Project row-level BEFORE triggers for UPDATE.
+	// Project statement-level and row-level BEFORE triggers for UPDATE.
+	mb.buildStatementLevelBeforeTriggers(false /* cascade */, tree.TriggerEventUpdate)
 	mb.buildRowLevelBeforeTriggers(tree.TriggerEventUpdate, false /* cascade */)
 
 	// Build the final update statement, including any returned expressions.
@@ -350,7 +356,7 @@ func (mb *mutationBuilder) buildUpdate(returning *tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpdate()
 
-	mb.buildRowLevelAfterTriggers(opt.UpdateOp)
+	mb.buildAfterTriggers(opt.UpdateOp)
 
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
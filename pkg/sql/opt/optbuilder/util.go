@@ -613,8 +613,9 @@ func (b *Builder) resolveTableForMutation(
 		alias = *outerAlias
 	}
 
-	// We can't mutate materialized views.
-	if tab.IsMaterializedView() {
+	// We can't mutate materialized views, unless the changes of an incremental
+	// refresh are being applied to the view.
+	if tab.IsMaterializedView() && !b.evalCtx.SessionData().AllowMaterializedViewMutations {
 		panic(pgerror.Newf(pgcode.WrongObjectType, "cannot mutate materialized view %q", tab.Name()))
 	}
 
@@ -54,6 +54,12 @@ type Statistics struct {
 	// expressions with Cardinality.Max > 0, RowCount will be >= epsilon.
 	RowCount float64
 
+	// RowCountBeforeFeedback is set when RowCount was replaced by a row count
+	// observed during a previous execution of the statement. It is the row
+	// count that was estimated before the replacement. See
+	// memo.CardinalityFeedbackKeyBuilder.
+	RowCountBeforeFeedback float64
+
 	// VirtualCols is the set of virtual computed columns produced by our input
 	// that we have statistics on. Any of these could appear in ColStats. This set
 	// is maintained separately from OutputCols to allow lookup of statistics on
@@ -103,6 +103,7 @@ func (t TableID) index() int {
 // Currently, the following annotations are in use:
 //   - FuncDeps: functional dependencies derived from the base table
 //   - Stats: statistics derived from the base table
+//   - MultiColHistograms: multi-column histograms derived from the base table
 //   - NotNullCols: not null columns derived from the base table
 //
 // To add an additional annotation, increase the value of maxTableAnnIDCount and
@@ -116,7 +117,7 @@ var tableAnnIDCount TableAnnID
 // called. Calling more than this number of times results in a panic. Having
 // a maximum enables a static annotation array to be inlined into the metadata
 // table struct.
-const maxTableAnnIDCount = 4
+const maxTableAnnIDCount = 5
 
 // NotNullAnnID is the annotation ID for table not null columns.
 var NotNullAnnID = NewTableAnnID()
@@ -108,7 +108,7 @@ func (tc *Catalog) CreateRoutine(c *tree.CreateRoutine) {
 				Typ:  typ,
 			})
 		}
-		if param.Class == tree.RoutineParamOut {
+		if tree.IsOutOnlyParamClass(param.Class) {
 			outParamOrdinals = append(outParamOrdinals, int32(i))
 			outParams = append(outParams, tree.ParamType{Typ: typ})
 		}
@@ -188,6 +188,14 @@ func (tc *Catalog) CreateRoutine(c *tree.CreateRoutine) {
 		OutParamTypes:     outParams,
 		DefaultExprs:      defaultExprs,
 	}
+	for _, option := range c.Options {
+		switch t := option.(type) {
+		case tree.RoutineCost:
+			overload.Cost = float64(t)
+		case tree.RoutineRows:
+			overload.Rows = float64(t)
+		}
+	}
 	overload.ReturnsRecordType = !c.IsProcedure && retType.Identical(types.AnyTuple)
 	if c.ReturnType != nil && c.ReturnType.SetOf {
 		overload.Class = tree.GeneratorClass
@@ -266,6 +274,9 @@ func collectFuncOptions(
 			}
 			language = t
 
+		case tree.RoutineCost, tree.RoutineRows:
+			// These options are handled by CreateRoutine.
+
 		default:
 			ctx := tree.NewFmtCtx(tree.FmtSimple)
 			option.Format(ctx)
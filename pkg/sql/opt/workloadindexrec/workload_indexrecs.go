@@ -26,8 +26,7 @@ func FindWorkloadRecs(
 		return nil, err
 	}
 
-	trieMap := buildTrieForIndexRecs(cis)
-	newCis, err := extractIndexCovering(trieMap)
+	newCis, err := MergeCreateIndexes(cis)
 	if err != nil {
 		return nil, err
 	}
@@ -72,15 +71,10 @@ func collectIndexRecs(
 		return nil, nil, err
 	}
 
-	var p parser.Parser
 	var cis []tree.CreateIndex
 	var dis []tree.DropIndex
 	var ok bool
 
-	// The index recommendation starts with "creation", "replacement" or
-	// "alteration".
-	var r = regexp.MustCompile(`\s*(creation|replacement|alteration)\s*:\s*(.*)`)
-
 	for ok, err = indexRecs.Next(ctx); ; ok, err = indexRecs.Next(ctx) {
 		if err != nil {
 			err = errors.CombineErrors(err, indexRecs.Close())
@@ -101,43 +95,74 @@ func collectIndexRecs(
 				return cis, dis, err
 			}
 
-			indexStrArr := r.FindStringSubmatch(string(*indexStr))
-			if indexStrArr == nil {
-				err = errors.CombineErrors(errors.Newf("%s is not a valid index recommendation!", string(*indexStr)), indexRecs.Close())
+			recCis, recDis, err := ParseIndexRec(string(*indexStr))
+			if err != nil {
+				err = errors.CombineErrors(err, indexRecs.Close())
 				indexRecs = nil
 				return cis, dis, err
 			}
+			cis = append(cis, recCis...)
+			dis = append(dis, recDis...)
+		}
+	}
 
-			// Since Alter index recommendation only makes invisible indexes visible,
-			// so we skip it for now.
-			if indexStrArr[1] == "alteration" {
-				continue
-			}
+	return cis, dis, nil
+}
 
-			stmts, err := p.Parse(indexStrArr[2])
-			if err != nil {
-				err = errors.CombineErrors(errors.Newf("%s is not a valid index operation!", indexStrArr[2]), indexRecs.Close())
-				indexRecs = nil
-				return cis, dis, err
-			}
+// indexRecRegex matches an index recommendation as it is stored in
+// system.statement_statistics. The index recommendation starts with
+// "creation", "replacement" or "alteration".
+var indexRecRegex = regexp.MustCompile(`\s*(creation|replacement|alteration)\s*:\s*(.*)`)
 
-			for _, stmt := range stmts {
-				switch stmt := stmt.AST.(type) {
-				case *tree.CreateIndex:
-					// Ignore all the inverted, partial and sharded indexes right now.
-					if !stmt.Inverted && stmt.Predicate == nil && stmt.Sharded == nil {
-						cis = append(cis, *stmt)
-					}
-				case *tree.DropIndex:
-					dis = append(dis, *stmt)
-				}
+// ParseIndexRec parses an index recommendation as it is stored in
+// system.statement_statistics, e.g.
+//
+//	replacement : CREATE INDEX ON t.public.t1 (k) STORING (i); DROP INDEX t.public.t1@t1_k;
+//
+// It returns the CREATE INDEX and DROP INDEX statements of the recommendation.
+// Inverted, partial and sharded indexes and alteration recommendations are
+// ignored.
+func ParseIndexRec(rec string) ([]tree.CreateIndex, []tree.DropIndex, error) {
+	indexStrArr := indexRecRegex.FindStringSubmatch(rec)
+	if indexStrArr == nil {
+		return nil, nil, errors.Newf("%s is not a valid index recommendation!", rec)
+	}
+
+	// Since Alter index recommendation only makes invisible indexes visible,
+	// so we skip it for now.
+	if indexStrArr[1] == "alteration" {
+		return nil, nil, nil
+	}
+
+	stmts, err := parser.Parse(indexStrArr[2])
+	if err != nil {
+		return nil, nil, errors.Newf("%s is not a valid index operation!", indexStrArr[2])
+	}
+
+	var cis []tree.CreateIndex
+	var dis []tree.DropIndex
+	for _, stmt := range stmts {
+		switch stmt := stmt.AST.(type) {
+		case *tree.CreateIndex:
+			// Ignore all the inverted, partial and sharded indexes right now.
+			if !stmt.Inverted && stmt.Predicate == nil && stmt.Sharded == nil {
+				cis = append(cis, *stmt)
 			}
+		case *tree.DropIndex:
+			dis = append(dis, *stmt)
 		}
 	}
-
 	return cis, dis, nil
 }
 
+// MergeCreateIndexes merges the given indexes into a set of indexes that
+// covers all of them: the key columns of every given index are a prefix of the
+// key columns of one of the returned indexes on the same table, and its
+// STORING columns are among the columns of that index.
+func MergeCreateIndexes(cis []tree.CreateIndex) ([]tree.CreateIndex, error) {
+	return extractIndexCovering(buildTrieForIndexRecs(cis))
+}
+
 // buildTrieForIndexRecs builds the relation among all the indexRecs by a trie tree.
 func buildTrieForIndexRecs(cis []tree.CreateIndex) map[tree.TableName]*indexTrie {
 	trieMap := make(map[tree.TableName]*indexTrie)
@@ -89,6 +89,10 @@ type coster struct {
 	// rng is used for deterministic perturbation.
 	rng *rand.Rand
 
+	// planBaseline, if set, is the shape of a pinned plan. Expressions that
+	// deviate from it are assigned a huge cost. See SetPlanBaseline.
+	planBaseline *PlanBaseline
+
 	o *Optimizer
 }
 
@@ -606,6 +610,11 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 		// default behavior.
 	}
 
+	if c.planBaseline != nil && c.deviatesFromPlanBaseline(candidate) {
+		// Avoid expressions that deviate from the pinned plan.
+		cost += hugeCost
+	}
+
 	// Add a one-time cost for any operator, meant to reflect the cost of setting
 	// up execution for the operator. This makes plans with fewer operators
 	// preferable, all else being equal.
@@ -935,6 +944,11 @@ func (c *coster) computeProjectCost(prj *memo.ProjectExpr) memo.Cost {
 	synthesizedColCount := len(prj.Projections)
 	cost := memo.Cost(rowCount) * memo.Cost(synthesizedColCount) * cpuCostFactor
 
+	// Add the cost of evaluating any routines with a COST clause.
+	for i := range prj.Projections {
+		cost += memo.Cost(rowCount) * c.computeUDFCost(prj.Projections[i].Element)
+	}
+
 	// Add the CPU cost of emitting the rows.
 	cost += memo.Cost(rowCount) * cpuCostFactor
 	return cost
@@ -1244,10 +1258,15 @@ func (c *coster) computeInvertedJoinCost(
 // It finds every embedded spatial function and add its cost.
 func (c *coster) computeExprCost(expr opt.Expr) memo.Cost {
 	perRowCost := memo.Cost(0)
-	if expr.Op() == opt.FunctionOp {
+	switch expr.Op() {
+	case opt.FunctionOp:
 		// We are ok with the zero value here for functions not in the map.
 		function := expr.(*memo.FunctionExpr)
 		perRowCost += fnCost[function.Name]
+	case opt.UDFCallOp:
+		// Routines without a COST clause have zero cost.
+		udf := expr.(*memo.UDFCallExpr)
+		perRowCost += memo.Cost(udf.Def.Cost) * cpuCostFactor
 	}
 	// recurse into the children of the current expression
 	for i := 0; i < expr.ChildCount(); i++ {
@@ -1256,6 +1275,24 @@ func (c *coster) computeExprCost(expr opt.Expr) memo.Cost {
 	return perRowCost
 }
 
+// computeUDFCost returns the per-row cost of evaluating the user-defined
+// routines in the given scalar expression, based on the COST clause of each
+// routine. Unlike computeExprCost, it does not include the cost of builtin
+// functions, so the cost of expressions without such routines is zero.
+// Relational expressions nested within expr are not traversed.
+func (c *coster) computeUDFCost(expr opt.Expr) memo.Cost {
+	perRowCost := memo.Cost(0)
+	if udf, ok := expr.(*memo.UDFCallExpr); ok {
+		perRowCost += memo.Cost(udf.Def.Cost) * cpuCostFactor
+	}
+	for i := 0; i < expr.ChildCount(); i++ {
+		if _, ok := expr.Child(i).(memo.RelExpr); !ok {
+			perRowCost += c.computeUDFCost(expr.Child(i))
+		}
+	}
+	return perRowCost
+}
+
 // computeFiltersCost returns the setup and per-row cost of executing
 // a filter. Callers of this function should add setupCost and multiply
 // perRowCost by the number of rows expected to be filtered.
@@ -1481,6 +1518,13 @@ func (c *coster) computeOrdinalityCost(ord *memo.OrdinalityExpr) memo.Cost {
 func (c *coster) computeProjectSetCost(projectSet *memo.ProjectSetExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
 	cost := memo.Cost(projectSet.Relational().Statistics().RowCount) * cpuCostFactor
+
+	// Add the cost of evaluating any routines with a COST clause. Each function
+	// in the zip is evaluated once per input row.
+	inputRowCount := projectSet.Input.Relational().Statistics().RowCount
+	for i := range projectSet.Zip {
+		cost += memo.Cost(inputRowCount) * c.computeUDFCost(projectSet.Zip[i].Fn)
+	}
 	return cost
 }
 
@@ -0,0 +1,102 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package xform
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+)
+
+// PlanBaseline describes the shape of a pinned plan. When a PlanBaseline is
+// set, the coster assigns a huge cost to expressions that deviate from it, so
+// that the optimizer reproduces the pinned plan as closely as possible.
+//
+// Only the indexes used to access each table and the set of join algorithms
+// are constrained. The join order, and which algorithm is used for which join,
+// are not, so the optimizer may still choose a plan whose gist differs from the
+// pinned one. Such executions are detected and logged by the plan baseline
+// registry (see planbaseline.Registry.RecordExecution).
+type PlanBaseline struct {
+	// Indexes maps the ID of each table accessed by the pinned plan to the IDs
+	// of the indexes used to access it. Tables that are not in the map are not
+	// constrained.
+	Indexes map[cat.StableID][]cat.StableID
+	// The following fields are set if the pinned plan contains a join that uses
+	// the respective algorithm. Join algorithms are only constrained if the
+	// pinned plan contains at least one join.
+	HashJoin     bool
+	MergeJoin    bool
+	LookupJoin   bool
+	InvertedJoin bool
+	ZigzagJoin   bool
+}
+
+// hasJoins returns true if the pinned plan contains at least one join.
+func (b *PlanBaseline) hasJoins() bool {
+	return b.HashJoin || b.MergeJoin || b.LookupJoin || b.InvertedJoin || b.ZigzagJoin
+}
+
+// SetPlanBaseline constrains the default coster to prefer expressions that
+// match the given pinned plan. It must be called after Init, and has no effect
+// if SetCoster is used to override the default coster.
+func (o *Optimizer) SetPlanBaseline(b *PlanBaseline) {
+	o.defaultCoster.planBaseline = b
+}
+
+// deviatesFromPlanBaseline returns true if the given candidate expression
+// accesses a table through an index that is not used by the pinned plan, or
+// uses a join algorithm that is not used by the pinned plan.
+func (c *coster) deviatesFromPlanBaseline(candidate memo.RelExpr) bool {
+	b := c.planBaseline
+	md := c.mem.Metadata()
+	indexAllowed := func(tabID opt.TableID, idx cat.IndexOrdinal) bool {
+		tab := md.Table(tabID)
+		allowed, ok := b.Indexes[tab.ID()]
+		if !ok {
+			return true
+		}
+		id := tab.Index(idx).ID()
+		for i := range allowed {
+			if allowed[i] == id {
+				return true
+			}
+		}
+		return false
+	}
+	joinAllowed := func(used bool) bool {
+		return used || !b.hasJoins()
+	}
+
+	switch t := candidate.(type) {
+	case *memo.ScanExpr:
+		return !indexAllowed(t.Table, t.Index)
+
+	case *memo.IndexJoinExpr:
+		return !indexAllowed(t.Table, cat.PrimaryIndex)
+
+	case *memo.LookupJoinExpr:
+		return !joinAllowed(b.LookupJoin) || !indexAllowed(t.Table, t.Index)
+
+	case *memo.InvertedJoinExpr:
+		return !joinAllowed(b.InvertedJoin) || !indexAllowed(t.Table, t.Index)
+
+	case *memo.ZigzagJoinExpr:
+		return !joinAllowed(b.ZigzagJoin) ||
+			!indexAllowed(t.LeftTable, t.LeftIndex) || !indexAllowed(t.RightTable, t.RightIndex)
+
+	case *memo.MergeJoinExpr:
+		return !joinAllowed(b.MergeJoin)
+	}
+
+	switch candidate.Op() {
+	case opt.InnerJoinOp, opt.LeftJoinOp, opt.RightJoinOp, opt.FullJoinOp,
+		opt.SemiJoinOp, opt.AntiJoinOp:
+		// These join operators are executed as hash joins.
+		return !joinAllowed(b.HashJoin)
+	}
+	return false
+}
@@ -8,6 +8,7 @@ package sql
 import (
 	"context"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/config"
@@ -1852,26 +1853,34 @@ func (os *optTableStat) init(
 		}
 	}
 
-	// Verify that histogram column type matches table column type.
-	// TODO(49698): When we support multi-column histograms this check will need
-	// adjustment.
-	if len(os.columnOrdinals) == 1 {
-		col := tab.getCol(os.columnOrdinals[0])
-		if err := stat.HistogramData.TypeCheck(
-			col.GetType(), string(tab.Name()), col.GetName(), stats.TSFromTime(stat.CreatedAt),
-		); err != nil {
-			// Column type in the histogram differs from column type in the
-			// table. This is only possible if we somehow re-used the same column ID
-			// during an ALTER TABLE statement, which we shouldn't.
-			if buildutil.CrdbTestBuild {
-				return false, errors.NewAssertionErrorWithWrappedErrf(
-					err, "type check failed while initializing stat %d", stat.StatisticID,
-				)
-			}
-			// For release builds, skip over the stat and log a warning.
-			log.Warningf(ctx, "skipping stat %d due to failed type check: %v", stat.StatisticID, err)
-			return false, nil
+	// Verify that histogram column type matches table column type. The type of
+	// a multi-column histogram is a tuple of the column types.
+	col := tab.getCol(os.columnOrdinals[0])
+	colType, colName := col.GetType(), col.GetName()
+	if len(os.columnOrdinals) > 1 {
+		colTypes := make([]*types.T, len(os.columnOrdinals))
+		colNames := make([]string, len(os.columnOrdinals))
+		for i, ord := range os.columnOrdinals {
+			col = tab.getCol(ord)
+			colTypes[i], colNames[i] = col.GetType(), col.GetName()
+		}
+		colType = types.MakeTuple(colTypes)
+		colName = "(" + strings.Join(colNames, ", ") + ")"
+	}
+	if err := stat.HistogramData.TypeCheck(
+		colType, string(tab.Name()), colName, stats.TSFromTime(stat.CreatedAt),
+	); err != nil {
+		// Column type in the histogram differs from column type in the
+		// table. This is only possible if we somehow re-used the same column ID
+		// during an ALTER TABLE statement, which we shouldn't.
+		if buildutil.CrdbTestBuild {
+			return false, errors.NewAssertionErrorWithWrappedErrf(
+				err, "type check failed while initializing stat %d", stat.StatisticID,
+			)
 		}
+		// For release builds, skip over the stat and log a warning.
+		log.Warningf(ctx, "skipping stat %d due to failed type check: %v", stat.StatisticID, err)
+		return false, nil
 	}
 
 	return true, nil
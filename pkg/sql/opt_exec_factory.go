@@ -152,6 +152,7 @@ func (ef *execFactory) ConstructScan(
 
 	scan.reverse = params.Reverse
 	scan.parallelize = params.Parallelize
+	scan.splitWithinRanges = canSplitScanWithinRanges(&ef.planner.ExecCfg().Settings.SV, idx, params)
 	var err error
 	scan.spans, err = generateScanSpans(ef.ctx, ef.planner.EvalContext(), ef.planner.ExecCfg().Codec, tabDesc, idx, params)
 	if err != nil {
@@ -415,6 +416,7 @@ func (ef *execFactory) ConstructHashJoin(
 	leftEqCols, rightEqCols []exec.NodeColumnOrdinal,
 	leftEqColsAreKey, rightEqColsAreKey bool,
 	extraOnCond tree.TypedExpr,
+	rightEstimate exec.CardinalityEstimate,
 ) (exec.Node, error) {
 	p := ef.planner
 	leftSrc := asDataSource(left)
@@ -435,7 +437,9 @@ func (ef *execFactory) ConstructHashJoin(
 	pred.leftEqKey = leftEqColsAreKey
 	pred.rightEqKey = rightEqColsAreKey
 
-	return p.makeJoinNode(leftSrc, rightSrc, pred), nil
+	n := p.makeJoinNode(leftSrc, rightSrc, pred)
+	n.rightEstimate = rightEstimate
+	return n, nil
 }
 
 // ConstructApplyJoin is part of the exec.Factory interface.
@@ -563,6 +567,7 @@ func (ef *execFactory) addAggregations(n *groupNode, aggregations []exec.AggInfo
 			agg.DistsqlBlocklist,
 		)
 		f.filterRenderIdx = int(agg.Filter)
+		f.userDefined = agg.UserDefined
 
 		n.funcs = append(n.funcs, f)
 	}
@@ -1166,6 +1171,9 @@ func (ef *execFactory) ConstructWindow(root exec.Node, wi exec.WindowInfo) (exec
 			columnOrdering: wi.Ordering,
 			frame:          wi.Exprs[i].WindowDef.Frame,
 		}
+		if wi.UserDefined != nil {
+			p.funcs[i].userDefined = wi.UserDefined[i]
+		}
 		if len(wi.Ordering) == 0 {
 			frame := p.funcs[i].frame
 			if frame.Mode == treewindow.RANGE && frame.Bounds.HasOffset() {
@@ -863,6 +863,12 @@ func (u *sqlSymUnion) functionObj() tree.RoutineObj {
 func (u *sqlSymUnion) routineObjs() tree.RoutineObjs {
     return u.val.(tree.RoutineObjs)
 }
+func (u *sqlSymUnion) aggregateOption() tree.AggregateOption {
+    return u.val.(tree.AggregateOption)
+}
+func (u *sqlSymUnion) aggregateOptions() []tree.AggregateOption {
+    return u.val.([]tree.AggregateOption)
+}
 func (u *sqlSymUnion) tenantReplicationOptions() *tree.TenantReplicationOptions {
   return u.val.(*tree.TenantReplicationOptions)
 }
@@ -967,15 +973,15 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %token <str> FORCE_NOT_NULL FORCE_NULL FORCE_QUOTE FORCE_ZIGZAG
 %token <str> FOREIGN FORMAT FORWARD FREEZE FROM FULL FUNCTION FUNCTIONS
 
-%token <str> GENERATED GEOGRAPHY GEOMETRY GEOMETRYM GEOMETRYZ GEOMETRYZM
+%token <str> GC GENERATED GEOGRAPHY GEOMETRY GEOMETRYM GEOMETRYZ GEOMETRYZM
 %token <str> GEOMETRYCOLLECTION GEOMETRYCOLLECTIONM GEOMETRYCOLLECTIONZ GEOMETRYCOLLECTIONZM
 %token <str> GLOBAL GOAL GRANT GRANTEE GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
+%token <str> HAVING HASH HEADER HIGH HINT HISTOGRAM HOLD HOUR
 
 %token <str> IDENTITY
 %token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMEDIATELY IMMUTABLE IMPORT IN INCLUDE
-%token <str> INCLUDING INCLUDE_ALL_SECONDARY_TENANTS INCLUDE_ALL_VIRTUAL_CLUSTERS INCREMENT INCREMENTAL INCREMENTAL_LOCATION
+%token <str> INCLUDING INCLUDE_ALL_SECONDARY_TENANTS INCLUDE_ALL_VIRTUAL_CLUSTERS INCREMENT INCREMENTAL INCREMENTALLY INCREMENTAL_LOCATION
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INITIALLY
 %token <str> INDEX_BEFORE_PAREN INDEX_BEFORE_NAME_THEN_PAREN INDEX_AFTER_ORDER_BY_BEFORE_AT
@@ -1001,7 +1007,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %token <str> NOSQLLOGIN NO_INDEX_JOIN NO_ZIGZAG_JOIN NO_FULL_SCAN NONE NONVOTERS NORMAL NOT
 %token <str> NOTHING NOTHING_AFTER_RETURNING
 %token <str> NOTNULL
-%token <str> NOVIEWACTIVITY NOVIEWACTIVITYREDACTED NOVIEWCLUSTERSETTING NOWAIT NULL NULLIF NULLS NUMERIC
+%token <str> NOVIEWACTIVITY NOVIEWACTIVITYREDACTED NOVIEWCLUSTERSETTING NOW NOWAIT NULL NULLIF NULLS NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR OLD OLD_KMS ON ONLY OPT OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OWNER OPERATOR
@@ -1013,7 +1019,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 
 %token <str> QUERIES QUERY QUOTE
 
-%token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REDACT REF REFERENCES REFERENCING REFRESH
+%token <str> RANGE RANGES READ REAL REASON REASSIGN RECOMMENDATIONS RECURSIVE RECURRING REDACT REF REFERENCES REFERENCING REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH REMOVE_REGIONS RENAME REPEATABLE REPLACE REPLICATION
 %token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETENTION RETURNING RETURN RETURNS RETRY REVISION_HISTORY
@@ -1098,10 +1104,12 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> alter_unsupported_stmt
 %type <tree.Statement> alter_func_stmt
 %type <tree.Statement> alter_proc_stmt
+%type <tree.Statement> alter_aggregate_stmt
 
 // ALTER RANGE
 %type <tree.Statement> alter_zone_range_stmt
 %type <tree.Statement> alter_range_relocate_stmt
+%type <tree.Statement> alter_range_gc_stmt
 
 // ALTER TABLE
 %type <tree.Statement> alter_onetable_stmt
@@ -1109,6 +1117,7 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> alter_unsplit_stmt
 %type <tree.Statement> alter_rename_table_stmt
 %type <tree.Statement> alter_scatter_stmt
+%type <tree.Statement> alter_gc_stmt
 %type <tree.Statement> alter_relocate_stmt
 %type <tree.Statement> alter_zone_table_stmt
 %type <tree.Statement> alter_table_set_schema_stmt
@@ -1233,11 +1242,13 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> create_sequence_stmt
 %type <tree.Statement> create_func_stmt
 %type <tree.Statement> create_proc_stmt
+%type <tree.Statement> create_aggregate_stmt
 %type <tree.Statement> create_trigger_stmt
 
 %type <tree.LogicalReplicationResources> logical_replication_resources, logical_replication_resources_list
 %type <*tree.LogicalReplicationOptions> opt_logical_replication_options logical_replication_options logical_replication_options_list
 
+%type <tree.Statement> create_statement_hint_stmt
 %type <tree.Statement> create_stats_stmt
 %type <*tree.CreateStatsOptions> opt_create_stats_options
 %type <*tree.CreateStatsOptions> create_stats_option_list
@@ -1258,8 +1269,10 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <tree.Statement> drop_type_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
+%type <tree.Statement> drop_statement_hint_stmt
 %type <tree.Statement> drop_func_stmt
 %type <tree.Statement> drop_proc_stmt
+%type <tree.Statement> drop_aggregate_stmt
 %type <tree.Statement> drop_trigger_stmt
 %type <tree.Statement> drop_virtual_cluster_stmt
 %type <bool>           opt_immediate
@@ -1722,14 +1735,17 @@ func (u *sqlSymUnion) triggerForEach() tree.TriggerForEach {
 %type <privilege.TargetObjectType> target_object_type
 
 // Routine (UDF/SP) relevant components.
-%type <bool> opt_or_replace opt_return_table opt_return_set opt_no
+%type <bool> opt_or_replace opt_return_set opt_no
 %type <str> param_name routine_as
 %type <tree.RoutineParams> opt_routine_param_with_default_list routine_param_with_default_list func_params func_params_list
-%type <tree.RoutineParam> routine_param_with_default routine_param
+%type <tree.RoutineParams> table_func_column_list
+%type <tree.RoutineParam> routine_param_with_default routine_param table_func_column
 %type <tree.ResolvableTypeReference> routine_return_type routine_param_type
 %type <tree.RoutineOptions> opt_create_routine_opt_list create_routine_opt_list alter_func_opt_list
 %type <tree.RoutineOption> create_routine_opt_item common_routine_opt_item
 %type <tree.RoutineParamClass> routine_param_class
+%type <[]tree.AggregateOption> aggregate_opt_list
+%type <tree.AggregateOption> aggregate_opt_item
 %type <*tree.UnresolvedObjectName> routine_create_name
 %type <tree.Statement> routine_return_stmt routine_body_stmt
 %type <tree.Statements> routine_body_stmt_list
@@ -1891,6 +1907,7 @@ alter_ddl_stmt:
 | alter_backup_stmt             // EXTEND WITH HELP: ALTER BACKUP
 | alter_func_stmt               // EXTEND WITH HELP: ALTER FUNCTION
 | alter_proc_stmt               // EXTEND WITH HELP: ALTER PROCEDURE
+| alter_aggregate_stmt          // EXTEND WITH HELP: ALTER AGGREGATE
 | alter_backup_schedule  // EXTEND WITH HELP: ALTER BACKUP SCHEDULE
 
 // %Help: ALTER TABLE - change the definition of a table
@@ -1921,6 +1938,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... UNSPLIT AT <selectclause>
 //   ALTER TABLE ... UNSPLIT ALL
 //   ALTER TABLE ... SCATTER [ FROM ( <exprs...> ) TO ( <exprs...> ) ]
+//   ALTER TABLE ... GC NOW
 //   ALTER TABLE ... INJECT STATISTICS ...  (experimental)
 //   ALTER TABLE ... RELOCATE [ LEASE | VOTERS | NONVOTERS ] <selectclause>  (experimental)
 //   ALTER TABLE ... PARTITION BY RANGE ( <name...> ) ( <rangespec> )
@@ -1949,6 +1967,7 @@ alter_table_stmt:
 | alter_split_stmt
 | alter_unsplit_stmt
 | alter_scatter_stmt
+| alter_gc_stmt
 | alter_zone_table_stmt
 | alter_rename_table_stmt
 | alter_table_set_schema_stmt
@@ -2081,6 +2100,13 @@ alter_database_stmt:
 //    IMMUTABLE | STABLE | VOLATILE
 //    [ NOT ] LEAKPROOF
 //    [ EXTERNAL ] SECURITY { INVOKER | DEFINER }
+//    PARALLEL { UNSAFE | RESTRICTED | SAFE }
+//    COST execution_cost
+//    ROWS result_rows
+//    SET configuration_parameter { TO | = } { value | DEFAULT }
+//    SET configuration_parameter FROM CURRENT
+//    RESET configuration_parameter
+//    RESET ALL
 // %SeeAlso: WEBDOCS/alter-function.html
 alter_func_stmt:
   alter_func_options_stmt
@@ -2107,6 +2133,42 @@ alter_proc_stmt:
 | alter_proc_set_schema_stmt
 | ALTER PROCEDURE error // SHOW HELP: ALTER PROCEDURE
 
+// %Help: ALTER AGGREGATE - change the definition of an aggregate function
+// %Category: DDL
+// %Text:
+// ALTER AGGREGATE name ( argtype [, ...] ) RENAME TO new_name
+// ALTER AGGREGATE name ( argtype [, ...] )
+//    OWNER TO { new_owner | CURRENT_USER | SESSION_USER }
+// ALTER AGGREGATE name ( argtype [, ...] ) SET SCHEMA new_schema
+//
+// %SeeAlso: CREATE AGGREGATE, DROP AGGREGATE
+alter_aggregate_stmt:
+  ALTER AGGREGATE function_with_paramtypes RENAME TO name
+  {
+    $$.val = &tree.AlterRoutineRename{
+      Function: $3.functionObj(),
+      NewName: tree.Name($6),
+      Aggregate: true,
+    }
+  }
+| ALTER AGGREGATE function_with_paramtypes OWNER TO role_spec
+  {
+    $$.val = &tree.AlterRoutineSetOwner{
+      Function: $3.functionObj(),
+      NewOwner: $6.roleSpec(),
+      Aggregate: true,
+    }
+  }
+| ALTER AGGREGATE function_with_paramtypes SET SCHEMA schema_name
+  {
+    $$.val = &tree.AlterRoutineSetSchema{
+      Function: $3.functionObj(),
+      NewSchemaName: tree.Name($6),
+      Aggregate: true,
+    }
+  }
+| ALTER AGGREGATE error // SHOW HELP: ALTER AGGREGATE
+
 // ALTER DATABASE has its error help token here because the ALTER DATABASE
 // prefix is spread over multiple non-terminals.
 | ALTER DATABASE error // SHOW HELP: ALTER DATABASE
@@ -2305,6 +2367,7 @@ alter_database_set_zone_config_extension_stmt:
 //   ALTER RANGE r RELOCATE { VOTERS | NONVOTERS } FROM <store_id> TO <store_id>
 //   ALTER RANGE   RELOCATE LEASE                                  TO <store_id> FOR <selectclause>
 //   ALTER RANGE r RELOCATE LEASE                                  TO <store_id>
+//   ALTER RANGE r GC
 //
 // Zone configurations:
 //   DISCARD
@@ -2316,6 +2379,7 @@ alter_database_set_zone_config_extension_stmt:
 alter_range_stmt:
   alter_zone_range_stmt
 | alter_range_relocate_stmt
+| alter_range_gc_stmt
 | ALTER RANGE error // SHOW HELP: ALTER RANGE
 
 // %Help: ALTER INDEX - change the definition of an index
@@ -2594,6 +2658,12 @@ alter_range_relocate_stmt:
     }
   }
 
+alter_range_gc_stmt:
+  ALTER RANGE a_expr GC
+  {
+    $$.val = &tree.RunGC{RangeID: $3.expr()}
+  }
+
 set_zone_config:
   CONFIGURE ZONE to_or_eq a_expr
   {
@@ -2738,6 +2808,13 @@ alter_scatter_stmt:
     }
   }
 
+alter_gc_stmt:
+  ALTER TABLE table_name GC NOW
+  {
+    name := $3.unresolvedObjectName().ToTableName()
+    $$.val = &tree.RunGC{TableOrIndex: tree.TableIndexName{Table: name}}
+  }
+
 alter_scatter_index_stmt:
   ALTER INDEX table_index_name SCATTER
   {
@@ -3290,6 +3367,7 @@ alter_attribute_action:
 // %Category: Misc
 // %Text:
 // REFRESH MATERIALIZED VIEW [CONCURRENTLY] view_name [WITH [NO] DATA]
+// REFRESH MATERIALIZED VIEW [CONCURRENTLY] view_name INCREMENTALLY
 refresh_stmt:
   REFRESH MATERIALIZED VIEW opt_concurrently view_name opt_clear_data
   {
@@ -3299,6 +3377,14 @@ refresh_stmt:
       RefreshDataOption: $6.refreshDataOption(),
     }
   }
+| REFRESH MATERIALIZED VIEW opt_concurrently view_name INCREMENTALLY
+  {
+    $$.val = &tree.RefreshMaterializedView{
+      Name: $5.unresolvedObjectName(),
+      Concurrently: $4.bool(),
+      Incrementally: true,
+    }
+  }
 | REFRESH error // SHOW HELP: REFRESH
 
 opt_clear_data:
@@ -3998,10 +4084,6 @@ alter_unsupported_stmt:
   {
     return unimplemented(sqllex, "alter domain")
   }
-| ALTER AGGREGATE error
-  {
-    return unimplementedWithIssueDetail(sqllex, 74775, "alter aggregate")
-  }
 
 // %Help: IMPORT - load data from file in a distributed manner
 // %Category: CCL
@@ -4584,6 +4666,7 @@ create_stmt:
   create_role_stmt       // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt        // help texts in sub-rule
 | create_stats_stmt      // EXTEND WITH HELP: CREATE STATISTICS
+| create_statement_hint_stmt // EXTEND WITH HELP: CREATE STATEMENT HINT
 | create_changefeed_stmt // EXTEND WITH HELP: CREATE CHANGEFEED
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
 | create_external_connection_stmt // EXTEND WITH HELP: CREATE EXTERNAL CONNECTION
@@ -4845,18 +4928,22 @@ create_extension_stmt:
 // %Text:
 // CREATE [ OR REPLACE ] FUNCTION
 //    name ( [ [ argmode ] [ argname ] argtype [, ...] ] )
-//    [ RETURNS rettype ]
+//    [ RETURNS rettype | RETURNS TABLE ( column_name column_type [, ...] ) ]
 //  { LANGUAGE lang_name
 //    | { IMMUTABLE | STABLE | VOLATILE }
 //    | [ NOT ] LEAKPROOF
 //    | { CALLED ON NULL INPUT | RETURNS NULL ON NULL INPUT | STRICT }
 //    | AS 'definition'
 //    | { [ EXTERNAL ] SECURITY { INVOKER | DEFINER } }
+//    | PARALLEL { UNSAFE | RESTRICTED | SAFE }
+//    | COST execution_cost
+//    | ROWS result_rows
+//    | SET configuration_parameter { TO value | = value | FROM CURRENT }
 //  } ...
 // %SeeAlso: WEBDOCS/create-function.html
 create_func_stmt:
   CREATE opt_or_replace FUNCTION routine_create_name '(' opt_routine_param_with_default_list ')'
-  RETURNS opt_return_table opt_return_set routine_return_type
+  RETURNS opt_return_set routine_return_type
   opt_create_routine_opt_list opt_routine_body
   {
     name := $4.unresolvedObjectName().ToRoutineName()
@@ -4866,11 +4953,37 @@ create_func_stmt:
       Name: name,
       Params: $6.routineParams(),
       ReturnType: &tree.RoutineReturnType{
-        Type: $11.typeReference(),
-        SetOf: $10.bool(),
+        Type: $10.typeReference(),
+        SetOf: $9.bool(),
+      },
+      Options: $11.routineOptions(),
+      RoutineBody: $12.routineBody(),
+    }
+  }
+| CREATE opt_or_replace FUNCTION routine_create_name '(' opt_routine_param_with_default_list ')'
+  RETURNS TABLE '(' table_func_column_list ')'
+  opt_create_routine_opt_list opt_routine_body
+  {
+    name := $4.unresolvedObjectName().ToRoutineName()
+    // The columns of a RETURNS TABLE clause are treated as output parameters.
+    // Like in Postgres, the function returns a set of the column type if there
+    // is only one column, and a set of records otherwise.
+    columns := $11.routineParams()
+    var retType tree.ResolvableTypeReference = types.AnyTuple
+    if len(columns) == 1 {
+      retType = columns[0].Type
+    }
+    $$.val = &tree.CreateRoutine{
+      IsProcedure: false,
+      Replace: $2.bool(),
+      Name: name,
+      Params: append($6.routineParams(), columns...),
+      ReturnType: &tree.RoutineReturnType{
+        Type: retType,
+        SetOf: true,
       },
-      Options: $12.routineOptions(),
-      RoutineBody: $13.routineBody(),
+      Options: $13.routineOptions(),
+      RoutineBody: $14.routineBody(),
     }
   }
 | CREATE opt_or_replace FUNCTION routine_create_name '(' opt_routine_param_with_default_list ')'
@@ -4895,6 +5008,8 @@ create_func_stmt:
 // CREATE [ OR REPLACE ] PROCEDURE
 //    name ( [ [ argmode ] [ argname ] argtype [, ...] ] )
 //  { LANGUAGE lang_name
+//    | { [ EXTERNAL ] SECURITY { INVOKER | DEFINER } }
+//    | SET configuration_parameter { TO value | = value | FROM CURRENT }
 //    | AS 'definition'
 //  } ...
 // %SeeAlso: WEBDOCS/create-procedure.html
@@ -4915,14 +5030,63 @@ create_proc_stmt:
   }
 | CREATE opt_or_replace PROCEDURE error // SHOW HELP: CREATE PROCEDURE
 
+// %Help: CREATE AGGREGATE - define a new aggregate function
+// %Category: DDL
+// %Text:
+// CREATE [ OR REPLACE ] AGGREGATE name ( [ argname ] argtype [, ...] ) (
+//     SFUNC = sfunc,
+//     STYPE = state_data_type
+//     [ , FINALFUNC = ffunc ]
+//     [ , COMBINEFUNC = combinefunc ]
+//     [ , INITCOND = initial_condition ]
+// )
+// %SeeAlso: CREATE FUNCTION, DROP AGGREGATE, ALTER AGGREGATE
+create_aggregate_stmt:
+  CREATE opt_or_replace AGGREGATE routine_create_name '(' func_params_list ')' '(' aggregate_opt_list ')'
+  {
+    opts, err := tree.MakeAggregateOptions($9.aggregateOptions())
+    if err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = &tree.CreateAggregate{
+      Replace: $2.bool(),
+      Name: $4.unresolvedObjectName().ToRoutineName(),
+      Params: $6.routineParams(),
+      Options: opts,
+    }
+  }
+| CREATE opt_or_replace AGGREGATE error // SHOW HELP: CREATE AGGREGATE
+
+aggregate_opt_list:
+  aggregate_opt_item
+  {
+    $$.val = []tree.AggregateOption{$1.aggregateOption()}
+  }
+| aggregate_opt_list ',' aggregate_opt_item
+  {
+    $$.val = append($1.aggregateOptions(), $3.aggregateOption())
+  }
+
+aggregate_opt_item:
+  name '=' typename
+  {
+    $$.val = tree.AggregateOption{Name: $1, Type: $3.typeReference()}
+  }
+| name '=' SCONST
+  {
+    val := $3
+    $$.val = tree.AggregateOption{Name: $1, Value: &val}
+  }
+| name '=' numeric_only
+  {
+    val := tree.AsString($3.expr())
+    $$.val = tree.AggregateOption{Name: $1, Value: &val}
+  }
+
 opt_or_replace:
   OR REPLACE { $$.val = true }
 | /* EMPTY */ { $$.val = false }
 
-opt_return_table:
-  TABLE { return unimplementedWithIssueDetail(sqllex, 100226, "UDF returning TABLE") }
-| /* EMPTY */ { $$.val = false }
-
 opt_return_set:
   SETOF { $$.val = true}
 | /* EMPTY */ { $$.val = false }
@@ -4996,12 +5160,29 @@ routine_param:
     }
   }
 
+table_func_column_list:
+  table_func_column { $$.val = tree.RoutineParams{$1.routineParam()} }
+| table_func_column_list ',' table_func_column
+  {
+    $$.val = append($1.routineParams(), $3.routineParam())
+  }
+
+table_func_column:
+  param_name routine_param_type
+  {
+    $$.val = tree.RoutineParam{
+      Name: tree.Name($1),
+      Type: $2.typeReference(),
+      Class: tree.RoutineParamTable,
+    }
+  }
+
 routine_param_class:
   IN { $$.val = tree.RoutineParamIn }
 | OUT { $$.val = tree.RoutineParamOut }
 | INOUT { $$.val = tree.RoutineParamInOut }
 | IN OUT { $$.val = tree.RoutineParamInOut }
-| VARIADIC { return unimplementedWithIssueDetail(sqllex, 88947, "variadic user-defined functions") }
+| VARIADIC { $$.val = tree.RoutineParamVariadic }
 
 routine_param_type:
   typename
@@ -5091,22 +5272,42 @@ common_routine_opt_item:
   }
 | COST numeric_only
   {
-    return unimplemented(sqllex, "create function/procedure ... cost")
+    cost, _ := constant.Float64Val($2.numVal().AsConstantValue())
+    $$.val = tree.RoutineCost(cost)
   }
 | ROWS numeric_only
   {
-    return unimplemented(sqllex, "create function/procedure ... rows")
+    rows, _ := constant.Float64Val($2.numVal().AsConstantValue())
+    $$.val = tree.RoutineRows(rows)
   }
 | SUPPORT name
   {
     return unimplemented(sqllex, "create function/procedure ... support")
   }
-
-// In theory we should parse the a whole set/reset statement here. But it's fine
-// to just return fast on SET/RESET keyword for now since it's not supported
-// yet.
-| SET { return unimplemented(sqllex, "create function/procedure ... set") }
-| PARALLEL { return unimplemented(sqllex, "create function/procedure ... parallel") }
+| PARALLEL name
+  {
+    parallel, err := tree.AsRoutineParallel($2)
+    if err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = parallel
+  }
+| SET var_name to_or_eq var_list
+  {
+    $$.val = &tree.RoutineSetVar{Name: strings.Join($2.strs(), "."), Values: $4.exprs()}
+  }
+| SET var_name FROM CURRENT
+  {
+    $$.val = &tree.RoutineSetVar{Name: strings.Join($2.strs(), "."), FromCurrent: true}
+  }
+| RESET var_name
+  {
+    $$.val = &tree.RoutineSetVar{Name: strings.Join($2.strs(), "."), Reset: true}
+  }
+| RESET_ALL ALL
+  {
+    $$.val = &tree.RoutineSetVar{Reset: true, ResetAll: true}
+  }
 
 routine_as:
   SCONST
@@ -5209,6 +5410,32 @@ drop_proc_stmt:
   }
 | DROP PROCEDURE error // SHOW HELP: DROP PROCEDURE
 
+// %Help: DROP AGGREGATE - remove an aggregate function
+// %Category: DDL
+// %Text:
+// DROP AGGREGATE [ IF EXISTS ] name ( [ argname ] argtype [, ...] ) [, ...]
+//    [ CASCADE | RESTRICT ]
+// %SeeAlso: CREATE AGGREGATE
+drop_aggregate_stmt:
+  DROP AGGREGATE function_with_paramtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropRoutine{
+      Aggregate: true,
+      Routines: $3.routineObjs(),
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP AGGREGATE IF EXISTS function_with_paramtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropRoutine{
+      IfExists: true,
+      Aggregate: true,
+      Routines: $5.routineObjs(),
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP AGGREGATE error // SHOW HELP: DROP AGGREGATE
+
 function_with_paramtypes_list:
   function_with_paramtypes
   {
@@ -5567,7 +5794,6 @@ drop_trigger_stmt:
 
 create_unsupported:
   CREATE ACCESS METHOD error { return unimplemented(sqllex, "create access method") }
-| CREATE AGGREGATE error { return unimplementedWithIssueDetail(sqllex, 74775, "create aggregate") }
 | CREATE CAST error { return unimplemented(sqllex, "create cast") }
 | CREATE CONSTRAINT TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create constraint") }
 | CREATE CONVERSION error { return unimplemented(sqllex, "create conversion") }
@@ -5593,7 +5819,6 @@ opt_procedural:
 
 drop_unsupported:
   DROP ACCESS METHOD error { return unimplemented(sqllex, "drop access method") }
-| DROP AGGREGATE error { return unimplementedWithIssueDetail(sqllex, 74775, "drop aggregate") }
 | DROP CAST error { return unimplemented(sqllex, "drop cast") }
 | DROP COLLATION error { return unimplemented(sqllex, "drop collation") }
 | DROP CONVERSION error { return unimplemented(sqllex, "drop conversion") }
@@ -5623,8 +5848,29 @@ create_ddl_stmt:
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 | create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
 | create_proc_stmt     // EXTEND WITH HELP: CREATE PROCEDURE
+| create_aggregate_stmt // EXTEND WITH HELP: CREATE AGGREGATE
 | create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
 
+// %Help: CREATE STATEMENT HINT - attach a hint to a statement fingerprint
+// %Category: Misc
+// %Text:
+// CREATE STATEMENT HINT FOR <fingerprint> USING <hint>
+//
+// Fingerprint:
+//   The fingerprint of the statement, as shown in SQL statistics.
+//
+// Hint:
+//   <tablename>@<indexname> | <tablename>@{<index flags>}
+//   <HASH | MERGE | LOOKUP | INVERTED | STRAIGHT> JOIN
+//   SET <var> = <value>
+// %SeeAlso: DROP STATEMENT HINT
+create_statement_hint_stmt:
+  CREATE STATEMENT HINT FOR SCONST USING SCONST
+  {
+    $$.val = &tree.CreateStatementHint{Fingerprint: $5, Hint: $7}
+  }
+| CREATE STATEMENT HINT error // SHOW HELP: CREATE STATEMENT HINT
+
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
 // %Text:
@@ -5997,6 +6243,7 @@ drop_stmt:
 | drop_schedule_stmt            // EXTEND WITH HELP: DROP SCHEDULES
 | drop_external_connection_stmt // EXTEND WITH HELP: DROP EXTERNAL CONNECTION
 | drop_virtual_cluster_stmt     // EXTEND WITH HELP: DROP VIRTUAL CLUSTER
+| drop_statement_hint_stmt      // EXTEND WITH HELP: DROP STATEMENT HINT
 | drop_unsupported   {}
 | DROP error                    // SHOW HELP: DROP
 
@@ -6010,8 +6257,25 @@ drop_ddl_stmt:
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
 | drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
 | drop_proc_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_aggregate_stmt // EXTEND WITH HELP: DROP AGGREGATE
 | drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
 
+// %Help: DROP STATEMENT HINT - remove hints from a statement fingerprint
+// %Category: Misc
+// %Text:
+// DROP STATEMENT HINT FOR <fingerprint> [USING <hint>]
+// %SeeAlso: CREATE STATEMENT HINT
+drop_statement_hint_stmt:
+  DROP STATEMENT HINT FOR SCONST
+  {
+    $$.val = &tree.DropStatementHint{Fingerprint: $5}
+  }
+| DROP STATEMENT HINT FOR SCONST USING SCONST
+  {
+    $$.val = &tree.DropStatementHint{Fingerprint: $5, Hint: $7}
+  }
+| DROP STATEMENT HINT error // SHOW HELP: DROP STATEMENT HINT
+
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
 // %Text: DROP [MATERIALIZED] VIEW [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
@@ -8622,13 +8886,27 @@ show_grants_stmt:
 
 // %Help: SHOW INDEXES - list indexes
 // %Category: DDL
-// %Text: SHOW INDEXES FROM { <tablename> | DATABASE <database_name> } [WITH COMMENT]
+// %Text:
+// SHOW INDEXES FROM { <tablename> | DATABASE <database_name> } [WITH COMMENT]
+// SHOW INDEX RECOMMENDATIONS [FROM { <tablename> | DATABASE <database_name> }]
 // %SeeAlso: WEBDOCS/show-index.html
 show_indexes_stmt:
   SHOW INDEX FROM table_name with_comment
   {
     $$.val = &tree.ShowIndexes{Table: $4.unresolvedObjectName(), WithComment: $5.bool()}
   }
+| SHOW INDEX RECOMMENDATIONS
+  {
+    $$.val = &tree.ShowIndexRecommendations{}
+  }
+| SHOW INDEX RECOMMENDATIONS FROM table_name
+  {
+    $$.val = &tree.ShowIndexRecommendations{Table: $5.unresolvedObjectName()}
+  }
+| SHOW INDEX RECOMMENDATIONS FROM DATABASE database_name
+  {
+    $$.val = &tree.ShowIndexRecommendations{Database: tree.Name($6)}
+  }
 | SHOW INDEX error // SHOW HELP: SHOW INDEXES
 | SHOW INDEX FROM DATABASE database_name with_comment
   {
@@ -15874,8 +16152,14 @@ func_application:
   {
     $$.val = &tree.FuncExpr{Func: $1.resolvableFuncRef(), Exprs: $3.exprs(), OrderBy: $4.orderBy(), AggType: tree.GeneralAgg}
   }
-| func_application_name '(' VARIADIC a_expr opt_sort_clause_no_index ')' { return unimplemented(sqllex, "variadic") }
-| func_application_name '(' expr_list ',' VARIADIC a_expr opt_sort_clause_no_index ')' { return unimplemented(sqllex, "variadic") }
+| func_application_name '(' VARIADIC a_expr opt_sort_clause_no_index ')'
+  {
+    $$.val = &tree.FuncExpr{Func: $1.resolvableFuncRef(), Exprs: tree.Exprs{$4.expr()}, OrderBy: $5.orderBy(), AggType: tree.GeneralAgg, Variadic: true}
+  }
+| func_application_name '(' expr_list ',' VARIADIC a_expr opt_sort_clause_no_index ')'
+  {
+    $$.val = &tree.FuncExpr{Func: $1.resolvableFuncRef(), Exprs: append($3.exprs(), $6.expr()), OrderBy: $7.orderBy(), AggType: tree.GeneralAgg, Variadic: true}
+  }
 | func_application_name '(' ALL expr_list opt_sort_clause_no_index ')'
   {
     $$.val = &tree.FuncExpr{Func: $1.resolvableFuncRef(), Type: tree.AllFuncType, Exprs: $4.exprs(), OrderBy: $5.orderBy(), AggType: tree.GeneralAgg}
@@ -17590,6 +17874,7 @@ unreserved_keyword:
 | FREEZE
 | FUNCTION
 | FUNCTIONS
+| GC
 | GENERATED
 | GEOMETRYM
 | GEOMETRYZ
@@ -17606,6 +17891,7 @@ unreserved_keyword:
 | HASH
 | HEADER
 | HIGH
+| HINT
 | HISTOGRAM
 | HOLD
 | HOUR
@@ -17620,6 +17906,7 @@ unreserved_keyword:
 | INCLUDE_ALL_VIRTUAL_CLUSTERS
 | INCREMENT
 | INCREMENTAL
+| INCREMENTALLY
 | INCREMENTAL_LOCATION
 | INDEX
 | INDEXES
@@ -17714,6 +18001,7 @@ unreserved_keyword:
 | NOVIEWACTIVITY
 | NOVIEWACTIVITYREDACTED
 | NOVIEWCLUSTERSETTING
+| NOW
 | NOWAIT
 | NULLS
 | IGNORE_FOREIGN_KEYS
@@ -17769,6 +18057,7 @@ unreserved_keyword:
 | READ
 | REASON
 | REASSIGN
+| RECOMMENDATIONS
 | RECURRING
 | RECURSIVE
 | REDACT
@@ -18118,6 +18407,7 @@ bare_label_keywords:
 | FULL
 | FUNCTION
 | FUNCTIONS
+| GC
 | GENERATED
 | GEOGRAPHY
 | GEOMETRY
@@ -18138,6 +18428,7 @@ bare_label_keywords:
 | HASH
 | HEADER
 | HIGH
+| HINT
 | HISTOGRAM
 | HOLD
 | IDENTITY
@@ -18157,6 +18448,7 @@ bare_label_keywords:
 | INCLUDING
 | INCREMENT
 | INCREMENTAL
+| INCREMENTALLY
 | INCREMENTAL_LOCATION
 | INDEX
 | INDEXES
@@ -18270,6 +18562,7 @@ bare_label_keywords:
 | NOVIEWACTIVITY
 | NOVIEWACTIVITYREDACTED
 | NOVIEWCLUSTERSETTING
+| NOW
 | NOWAIT
 | NO_FULL_SCAN
 | NO_INDEX_JOIN
@@ -18339,6 +18632,7 @@ bare_label_keywords:
 | REAL
 | REASON
 | REASSIGN
+| RECOMMENDATIONS
 | RECURRING
 | RECURSIVE
 | REDACT
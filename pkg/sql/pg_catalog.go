@@ -2621,6 +2621,11 @@ var (
 	proArgModeOut      = tree.NewDString("o")
 	proArgModeInOut    = tree.NewDString("b")
 	proArgModeVariadic = tree.NewDString("v")
+	proArgModeTable    = tree.NewDString("t")
+
+	proParallelUnsafe     = tree.NewDString("u")
+	proParallelRestricted = tree.NewDString("r")
+	proParallelSafe       = tree.NewDString("s")
 )
 
 func addPgProcUDFRow(
@@ -2632,6 +2637,8 @@ func addPgProcUDFRow(
 	kind := proKindFunction
 	if fnDesc.IsProcedure() {
 		kind = proKindProcedure
+	} else if fnDesc.IsAggregate() {
+		kind = proKindAggregate
 	}
 
 	lang := languageInternalOid
@@ -2647,8 +2654,13 @@ func addPgProcUDFRow(
 	var foundAnyArgNames bool
 	var nArgs, nArgDefaults int
 	var argDefaultsBuilder strings.Builder
+	variadicType := oidZero
 	for _, param := range fnDesc.GetParams() {
 		class := funcdesc.ToTreeRoutineParamClass(param.Class)
+		if class == tree.RoutineParamVariadic {
+			// provariadic is the element type of the VARIADIC parameter.
+			variadicType = tree.NewDOid(param.Type.ArrayContents().Oid())
+		}
 		if tree.IsInParamClass(class) {
 			// nArgs tracks only the number of input arguments.
 			nArgs++
@@ -2685,6 +2697,8 @@ func addPgProcUDFRow(
 			argMode = proArgModeInOut
 		case tree.RoutineParamVariadic:
 			argMode = proArgModeVariadic
+		case tree.RoutineParamTable:
+			argMode = proArgModeTable
 		default:
 			return errors.AssertionFailedf("unknown parameter class %d", class)
 		}
@@ -2712,23 +2726,50 @@ func addPgProcUDFRow(
 	if nArgDefaults > 0 {
 		argDefaults = tree.NewDString("(" + argDefaultsBuilder.String() + ")")
 	}
+	// As in Postgres, the default cost of a user-defined function is 100 units
+	// of cpu_operator_cost, and the default number of rows returned by a
+	// set-returning function is 1000.
+	cost, rows := fnDesc.GetCost(), fnDesc.GetRows()
+	if cost == 0 {
+		cost = 100
+	}
+	if rows == 0 && fnDesc.GetReturnType().ReturnSet {
+		rows = 1000
+	}
+	parallel := proParallelUnsafe
+	switch fnDesc.GetParallel() {
+	case catpb.Function_RESTRICTED:
+		parallel = proParallelRestricted
+	case catpb.Function_SAFE:
+		parallel = proParallelSafe
+	}
+	config := tree.DNull
+	if sessionVars := fnDesc.GetConfig(); len(sessionVars) > 0 {
+		configArray := tree.NewDArray(types.String)
+		for _, sv := range sessionVars {
+			if err := configArray.Append(tree.NewDString(sv.Name + "=" + sv.Value)); err != nil {
+				return err
+			}
+		}
+		config = configArray
+	}
 	return addRow(
 		tree.NewDOid(catid.FuncIDToOID(fnDesc.GetID())), // oid
 		tree.NewDName(fnDesc.GetName()),                 // proname
 		schemaOid(scDesc.GetID()),                       // pronamespace
 		h.UserOid(fnDesc.GetPrivileges().Owner()),       // proowner
-		lang,            // prolang
-		tree.DNull,      // procost
-		tree.DNull,      // prorows
-		oidZero,         // provariadic // TODO(88947): this might need an adjustment.
-		tree.DNull,      // prosupport
-		kind,            // prokind
-		tree.DBoolFalse, // prosecdef
+		lang,                              // prolang
+		tree.NewDFloat(tree.DFloat(cost)), // procost
+		tree.NewDFloat(tree.DFloat(rows)), // prorows
+		variadicType,                      // provariadic
+		tree.DNull,                        // prosupport
+		kind,                              // prokind
+		tree.DBoolFalse,                   // prosecdef
 		tree.MakeDBool(tree.DBool(fnDesc.GetLeakProof())),                                    // proleakproof
 		tree.MakeDBool(fnDesc.GetNullInputBehavior() != catpb.Function_CALLED_ON_NULL_INPUT), // proisstrict
 		tree.MakeDBool(tree.DBool(fnDesc.GetReturnType().ReturnSet)),                         // proretset
 		tree.NewDString(funcVolatility(fnDesc.GetVolatility())),                              // provolatile
-		tree.DNull,                                      // proparallel
+		parallel,                                        // proparallel
 		tree.NewDInt(tree.DInt(nArgs)),                  // pronargs
 		tree.NewDInt(tree.DInt(nArgDefaults)),           // pronargdefaults
 		tree.NewDOid(fnDesc.GetReturnType().Type.Oid()), // prorettype
@@ -2741,7 +2782,7 @@ func addPgProcUDFRow(
 		tree.NewDString(fnDesc.GetFunctionBody()),       // prosrc
 		tree.DNull,                                      // probin
 		tree.DNull,                                      // prosqlbody
-		tree.DNull,                                      // proconfig
+		config,                                          // proconfig
 		tree.DNull,                                      // proacl
 	)
 }
@@ -355,6 +355,13 @@ var (
 	// gateway.
 	ScalarOperationCannotRunWithoutFullSessionContext = MakeCode("22C01")
 
+	// Class 40C - Transaction Rollback (Cockroach extension)
+
+	// SpanRateLimitExceeded signals that a request was rejected because it
+	// exceeded a rate limit configured in the zone configuration of the data it
+	// accessed. The transaction can be retried after backing off.
+	SpanRateLimitExceeded = MakeCode("40C01")
+
 	// Class 55C - Object Not In Prerequisite State (Cockroach extension)
 
 	// SchemaChangeOccurred signals that a DDL change to the targets of a
@@ -7,6 +7,7 @@ package pgerror_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -95,6 +96,15 @@ func TestFlatten(t *testing.T) {
 				t.CheckEqual(pgcode.MakeCode(e.Code), pgcode.StatementCompletionUnknown)
 			},
 		},
+		{
+			errors.Wrap(kvpb.NewSpanRateLimitExceededError(1, "rate_limit.requests_per_second", time.Second), ""),
+			func(t testutils.T, e *pgerror.Error) {
+				t.CheckRegexpEqual(e.Message, "r1: span rate limit rate_limit.requests_per_second exceeded")
+				t.CheckEqual(pgcode.MakeCode(e.Code), pgcode.SpanRateLimitExceeded)
+				t.CheckRegexpEqual(e.Detail, "rejected by the rate_limit.requests_per_second limit")
+				t.CheckRegexpEqual(e.Hint, "retry the transaction after 1s")
+			},
+		},
 		{
 			errors.Wrap(
 				kvpb.NewTransactionRetryWithProtoRefreshError(
@@ -81,6 +81,7 @@ func GetPGCodeInternal(
 // - the existing code for Error instances
 // - SerializationFailure for roachpb retry errors that can be reported to clients
 // - StatementCompletionUnknown for ambiguous commit errors
+// - SpanRateLimitExceeded for roachpb rate limit errors
 // - InternalError for assertion failures
 // - FeatureNotSupportedError for unimplemented errors.
 //
@@ -96,6 +97,8 @@ func ComputeDefaultCode(err error) pgcode.Code {
 		return pgcode.SerializationFailure
 	case ClientVisibleAmbiguousError:
 		return pgcode.StatementCompletionUnknown
+	case ClientVisibleRateLimitError:
+		return pgcode.SpanRateLimitExceeded
 	}
 
 	if errors.IsAssertionFailure(err) {
@@ -120,6 +123,12 @@ type ClientVisibleAmbiguousError interface {
 	ClientVisibleAmbiguousError()
 }
 
+// ClientVisibleRateLimitError mirrors kvpb.ClientVisibleRateLimitError but
+// is defined here to avoid an import cycle.
+type ClientVisibleRateLimitError interface {
+	ClientVisibleRateLimitError()
+}
+
 // combineCodes combines the inner and outer codes.
 func combineCodes(innerCode, outerCode pgcode.Code) pgcode.Code {
 	if outerCode == pgcode.Uncategorized {
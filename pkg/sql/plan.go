@@ -155,10 +155,12 @@ var _ planNode = &cancelQueriesNode{}
 var _ planNode = &cancelSessionsNode{}
 var _ planNode = &changeDescriptorBackedPrivilegesNode{}
 var _ planNode = &completionsNode{}
+var _ planNode = &createAggregateNode{}
 var _ planNode = &createDatabaseNode{}
 var _ planNode = &createFunctionNode{}
 var _ planNode = &createIndexNode{}
 var _ planNode = &createSequenceNode{}
+var _ planNode = &createStatementHintNode{}
 var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
 var _ planNode = &createTypeNode{}
@@ -172,6 +174,7 @@ var _ planNode = &dropDatabaseNode{}
 var _ planNode = &dropIndexNode{}
 var _ planNode = &dropSchemaNode{}
 var _ planNode = &dropSequenceNode{}
+var _ planNode = &dropStatementHintNode{}
 var _ planNode = &dropTableNode{}
 var _ planNode = &dropTypeNode{}
 var _ planNode = &DropRoleNode{}
@@ -202,6 +205,7 @@ var _ planNode = &renameTableNode{}
 var _ planNode = &renderNode{}
 var _ planNode = &RevokeRoleNode{}
 var _ planNode = &rowCountNode{}
+var _ planNode = &runGCNode{}
 var _ planNode = &scanBufferNode{}
 var _ planNode = &scanNode{}
 var _ planNode = &scatterNode{}
@@ -236,6 +240,7 @@ var _ planNodeReadingOwnWrites = &alterSchemaNode{}
 var _ planNodeReadingOwnWrites = &alterSequenceNode{}
 var _ planNodeReadingOwnWrites = &alterTableNode{}
 var _ planNodeReadingOwnWrites = &alterTypeNode{}
+var _ planNodeReadingOwnWrites = &createAggregateNode{}
 var _ planNodeReadingOwnWrites = &createFunctionNode{}
 var _ planNodeReadingOwnWrites = &createIndexNode{}
 var _ planNodeReadingOwnWrites = &createSequenceNode{}
@@ -650,6 +655,14 @@ const (
 	// planFlagDistributedExecution is set if execution of any part of the plan
 	// was distributed.
 	planFlagDistributedExecution
+
+	// planFlagPlanBaseline is set if the optimizer was constrained to the
+	// pinned plan of the statement's fingerprint.
+	planFlagPlanBaseline
+
+	// planFlagContainsVolatile is set if the plan contains a volatile
+	// expression, such as a sequence function or a call to a volatile routine.
+	planFlagContainsVolatile
 )
 
 // IsSet returns true if the receiver has all of the given flags set.
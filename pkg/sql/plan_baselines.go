@@ -0,0 +1,53 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/explain"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// PinStatementPlan is part of the eval.Planner interface.
+func (p *planner) PinStatementPlan(ctx context.Context, fingerprint string, planGist string) error {
+	if err := p.checkPlanBaselinesSupported(ctx); err != nil {
+		return err
+	}
+	// Make sure that the gist can be decoded, and that it refers to tables that
+	// exist, so that it can be enforced.
+	shape, err := explain.DecodePlanGistToShape(planGist, p.optPlanningCtx.catalog)
+	if err != nil {
+		return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid plan gist %q", planGist)
+	}
+	if len(shape.Indexes) == 0 {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"plan gist %q does not access any existing table", planGist)
+	}
+	return p.ExecCfg().PlanBaselineRegistry.Pin(ctx, fingerprint, planGist)
+}
+
+// UnpinStatementPlan is part of the eval.Planner interface.
+func (p *planner) UnpinStatementPlan(ctx context.Context, fingerprint string) (bool, error) {
+	if err := p.checkPlanBaselinesSupported(ctx); err != nil {
+		return false, err
+	}
+	return p.ExecCfg().PlanBaselineRegistry.Unpin(ctx, fingerprint)
+}
+
+func (p *planner) checkPlanBaselinesSupported(ctx context.Context) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V25_1_AddStatementPlanBaselinesTable) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"pinning statement plans is not supported until upgrade to v25.1 is finalized")
+	}
+	if p.ExecCfg().PlanBaselineRegistry == nil {
+		return errors.AssertionFailedf("plan baseline registry is not initialized")
+	}
+	return nil
+}
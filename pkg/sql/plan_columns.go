@@ -111,6 +111,8 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 		return n.getColumns(mut, colinfo.AlterTableRelocateColumns)
 	case *relocateRange:
 		return n.getColumns(mut, colinfo.AlterRangeRelocateColumns)
+	case *runGCNode:
+		return n.getColumns(mut, colinfo.AlterRunGCColumns)
 	case *scatterNode:
 		return n.getColumns(mut, colinfo.AlterTableScatterColumns)
 	case *splitNode:
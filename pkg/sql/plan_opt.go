@@ -11,6 +11,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/cardfeedback"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -139,7 +140,7 @@ func (p *planner) prepareUsingOptimizer(
 			if !pm.TypeHints.Identical(p.semaCtx.Placeholders.TypeHints) {
 				opc.log(ctx, "query cache hit but type hints don't match")
 			} else {
-				isStale, err := cachedData.Memo.IsStale(ctx, p.EvalContext(), opc.catalog)
+				isStale, err := opc.isStale(ctx, cachedData.Memo)
 				if err != nil {
 					return 0, err
 				}
@@ -249,11 +250,23 @@ func (p *planner) prepareUsingOptimizer(
 
 // makeOptimizerPlan generates a plan using the cost-based optimizer.
 // On success, it populates p.curPlan.
-func (p *planner) makeOptimizerPlan(ctx context.Context) error {
+func (p *planner) makeOptimizerPlan(ctx context.Context) (retErr error) {
 	ctx, sp := tracing.ChildSpan(ctx, "optimizer")
 	defer sp.Finish()
 	p.curPlan.init(&p.stmt, &p.instrumentation)
 
+	// Apply the session variable overrides of the statement's hints, if any.
+	// This must happen before the optimizer is initialized, since the memo
+	// captures the values of the session variables that affect planning.
+	if hints := p.lookupStatementHints(); hints != nil && len(hints.SessionVars) > 0 {
+		if err := p.pushSessionVars(ctx, hints.SessionVars); err != nil {
+			return err
+		}
+		defer func() {
+			retErr = errors.CombineErrors(retErr, p.EvalContext().SessionDataStack.Pop())
+		}()
+	}
+
 	opc := &p.optPlanningCtx
 	opc.reset(ctx)
 
@@ -359,6 +372,19 @@ type optPlanningCtx struct {
 	// allowMemoReuse is false.
 	useCache bool
 
+	// planBaseline is the shape of the pinned plan for the statement's
+	// fingerprint, if any. See maybeApplyPlanBaseline.
+	planBaseline *xform.PlanBaseline
+
+	// statementHints are the external hints for the statement's fingerprint,
+	// if any. See maybeApplyStatementHints.
+	statementHints *optbuilder.StatementHints
+
+	// cardinalityFeedback contains the row counts observed during previous
+	// executions of the statement's fingerprint, if any. See
+	// maybeApplyCardinalityFeedback.
+	cardinalityFeedback map[string]float64
+
 	flags planFlags
 }
 
@@ -407,6 +433,121 @@ func (opc *optPlanningCtx) reset(ctx context.Context) {
 		opc.allowMemoReuse = false
 		opc.useCache = false
 	}
+
+	opc.planBaseline = nil
+	opc.maybeApplyPlanBaseline(ctx)
+	opc.statementHints = nil
+	opc.maybeApplyStatementHints(ctx)
+	opc.cardinalityFeedback = nil
+	opc.maybeApplyCardinalityFeedback(ctx)
+}
+
+// planningFingerprint returns the fingerprint that is used to look up the
+// pinned plan and the hints of the current statement. EXPLAIN statements use
+// the fingerprint of the explained statement, so that they show the plan that
+// would be executed.
+func (p *planner) planningFingerprint() string {
+	if e, ok := p.stmt.AST.(*tree.Explain); ok {
+		return formatStatementHideConstants(
+			e.Statement, tree.FmtFlags(queryFormattingForFingerprintsMask.Get(&p.execCfg.Settings.SV)),
+		)
+	}
+	return p.stmt.StmtNoConstants
+}
+
+// maybeApplyPlanBaseline constrains the optimizer to the pinned plan of the
+// statement's fingerprint, if there is one. EXPLAIN statements use the pinned
+// plan of the explained statement, so that they show the plan that would be
+// executed. When candidate verification is enabled, a sample of executions
+// are planned without the pinned plan.
+func (opc *optPlanningCtx) maybeApplyPlanBaseline(ctx context.Context) {
+	p := opc.p
+	registry := p.execCfg.PlanBaselineRegistry
+	if registry == nil {
+		return
+	}
+	gist, ok := registry.PinnedPlan(p.planningFingerprint())
+	if !ok || registry.ShouldVerifyCandidate() {
+		return
+	}
+	shape, err := explain.DecodePlanGistToShape(gist, opc.catalog)
+	if err != nil {
+		log.VEventf(ctx, 1, "unable to decode pinned plan %s: %v", gist, err)
+		return
+	}
+	opc.planBaseline = &xform.PlanBaseline{
+		Indexes:      shape.Indexes,
+		HashJoin:     shape.HashJoin,
+		MergeJoin:    shape.MergeJoin,
+		LookupJoin:   shape.LookupJoin,
+		InvertedJoin: shape.InvertedJoin,
+		ZigzagJoin:   shape.ZigzagJoin,
+	}
+	opc.optimizer.SetPlanBaseline(opc.planBaseline)
+	opc.flags.Set(planFlagPlanBaseline)
+	// Cached and prepared memos may have been optimized without the pinned
+	// plan, so they cannot be reused.
+	opc.allowMemoReuse = false
+	opc.useCache = false
+	opc.log(ctx, "using pinned plan")
+}
+
+// maybeApplyStatementHints looks up the external hints of the statement's
+// fingerprint. The index and join hints are applied by optbuilder; session
+// variable overrides are applied by makeOptimizerPlan.
+func (opc *optPlanningCtx) maybeApplyStatementHints(ctx context.Context) {
+	hints := opc.p.lookupStatementHints()
+	if hints == nil {
+		return
+	}
+	opc.statementHints = &optbuilder.StatementHints{
+		IndexFlags: hints.IndexFlags,
+		JoinHint:   hints.JoinHint,
+	}
+	// Cached and prepared memos may have been built without the hints, so they
+	// cannot be reused.
+	opc.allowMemoReuse = false
+	opc.useCache = false
+	opc.log(ctx, "using statement hints")
+}
+
+// maybeApplyCardinalityFeedback makes the optimizer use the row counts
+// observed during previous executions of the statement's fingerprint in place
+// of its estimates. See cardfeedback.
+func (opc *optPlanningCtx) maybeApplyCardinalityFeedback(ctx context.Context) {
+	p := opc.p
+	if !cardfeedback.Enabled.Get(&p.execCfg.Settings.SV) {
+		return
+	}
+	feedback := p.execCfg.CardinalityFeedbackCache.Lookup(p.planningFingerprint())
+	if len(feedback) == 0 {
+		return
+	}
+	opc.cardinalityFeedback = feedback
+	opc.optimizer.Memo().SetCardinalityFeedback(feedback)
+	// Cached and prepared memos that were built with other observed
+	// cardinalities are considered stale; see isStale.
+	opc.log(ctx, "using observed cardinalities")
+}
+
+// isStale returns true if the given cached or prepared memo cannot be reused,
+// either because its dependencies have changed, or because it was built with
+// other observed cardinalities than are currently known for the statement.
+func (opc *optPlanningCtx) isStale(ctx context.Context, m *memo.Memo) (bool, error) {
+	if !m.CardinalityFeedbackMatches(opc.cardinalityFeedback) {
+		return true, nil
+	}
+	return m.IsStale(ctx, opc.p.EvalContext(), opc.catalog)
+}
+
+// detachMemo detaches the memo from the optimizer, which is re-initialized
+// with an empty memo that uses the same observed cardinalities, if any.
+func (opc *optPlanningCtx) detachMemo(ctx context.Context) *memo.Memo {
+	m := opc.optimizer.DetachMemo(ctx)
+	if opc.cardinalityFeedback != nil {
+		opc.optimizer.Memo().SetCardinalityFeedback(opc.cardinalityFeedback)
+	}
+	return m
 }
 
 func (opc *optPlanningCtx) log(ctx context.Context, msg redact.SafeString) {
@@ -473,6 +614,7 @@ func (opc *optPlanningCtx) buildReusableMemo(
 	f := opc.optimizer.Factory()
 	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), opc.catalog, f, opc.p.stmt.AST)
 	bld.KeepPlaceholders = true
+	bld.StatementHints = opc.statementHints
 	if opc.flags.IsSet(planFlagSessionMigration) {
 		bld.SkipAOST = true
 	}
@@ -496,7 +638,7 @@ func (opc *optPlanningCtx) buildReusableMemo(
 		}
 		// With a canned plan, we don't want to optimize the memo. Since we
 		// won't optimize it, we consider it an ideal generic plan.
-		return opc.optimizer.DetachMemo(ctx), memoTypeIdealGeneric, nil
+		return opc.detachMemo(ctx), memoTypeIdealGeneric, nil
 	}
 
 	// If the memo doesn't have placeholders and did not encounter any stable
@@ -508,7 +650,7 @@ func (opc *optPlanningCtx) buildReusableMemo(
 			return nil, memoTypeUnknown, err
 		}
 		opc.flags.Set(planFlagOptimized)
-		return opc.optimizer.DetachMemo(ctx), memoTypeIdealGeneric, nil
+		return opc.detachMemo(ctx), memoTypeIdealGeneric, nil
 	}
 
 	// If the memo has placeholders, first try the placeholder fast path.
@@ -519,7 +661,7 @@ func (opc *optPlanningCtx) buildReusableMemo(
 	if ok {
 		opc.log(ctx, "placeholder fast path")
 		opc.flags.Set(planFlagOptimized)
-		return opc.optimizer.DetachMemo(ctx), memoTypeIdealGeneric, nil
+		return opc.detachMemo(ctx), memoTypeIdealGeneric, nil
 	} else if allowNonIdealGeneric {
 		// Build a generic query plan if the placeholder fast path failed and a
 		// generic plan was requested.
@@ -528,13 +670,13 @@ func (opc *optPlanningCtx) buildReusableMemo(
 			return nil, memoTypeUnknown, err
 		}
 		opc.flags.Set(planFlagOptimized)
-		return opc.optimizer.DetachMemo(ctx), memoTypeGeneric, nil
+		return opc.detachMemo(ctx), memoTypeGeneric, nil
 	}
 
 	// Detach the prepared memo from the factory and transfer its ownership
 	// to the prepared statement. DetachMemo will re-initialize the optimizer
 	// to an empty memo.
-	return opc.optimizer.DetachMemo(ctx), memoTypeCustom, nil
+	return opc.detachMemo(ctx), memoTypeCustom, nil
 }
 
 // reuseMemo returns an optimized memo using a cached memo as a starting point.
@@ -652,7 +794,7 @@ func (opc *optPlanningCtx) chooseValidPreparedMemo(ctx context.Context) (*memo.M
 			// A generic plan does not yet exist.
 			return nil, nil
 		}
-		isStale, err := prep.GenericMemo.IsStale(ctx, opc.p.EvalContext(), opc.catalog)
+		isStale, err := opc.isStale(ctx, prep.GenericMemo)
 		if err != nil {
 			return nil, err
 		} else if !isStale {
@@ -666,7 +808,7 @@ func (opc *optPlanningCtx) chooseValidPreparedMemo(ctx context.Context) (*memo.M
 	}
 
 	if prep.BaseMemo != nil {
-		isStale, err := prep.BaseMemo.IsStale(ctx, opc.p.EvalContext(), opc.catalog)
+		isStale, err := opc.isStale(ctx, prep.BaseMemo)
 		if err != nil {
 			return nil, err
 		} else if !isStale {
@@ -791,7 +933,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 		// Consult the query cache.
 		cachedData, ok := p.execCfg.QueryCache.Find(&p.queryCacheSession, opc.p.stmt.SQL)
 		if ok {
-			if isStale, err := cachedData.Memo.IsStale(ctx, p.EvalContext(), opc.catalog); err != nil {
+			if isStale, err := opc.isStale(ctx, cachedData.Memo); err != nil {
 				return nil, err
 			} else if isStale {
 				opc.log(ctx, "query cache hit but needed update")
@@ -821,6 +963,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 	f := opc.optimizer.Factory()
 	f.FoldingControl().AllowStableFolds()
 	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), opc.catalog, f, opc.p.stmt.AST)
+	bld.StatementHints = opc.statementHints
 	if err := bld.Build(); err != nil {
 		return nil, err
 	}
@@ -854,7 +997,7 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 	if opc.useCache && !bld.HadPlaceholders && !bld.DisableMemoReuse &&
 		!f.FoldingControl().PermittedStableFold() {
 		opc.log(ctx, "query cache add")
-		memo := opc.optimizer.DetachMemo(ctx)
+		memo := opc.detachMemo(ctx)
 		cachedData := querycache.CachedData{
 			SQL:  opc.p.stmt.SQL,
 			Memo: memo,
@@ -891,6 +1034,7 @@ func (opc *optPlanningCtx) runExecBuilder(
 			ctx, f, &opc.optimizer, mem, opc.catalog, mem.RootExpr(),
 			semaCtx, evalCtx, allowAutoCommit, statements.IsANSIDML(stmt.AST),
 		)
+		bld.BuildCardinalityEstimates = cardfeedback.Enabled.Get(&opc.p.execCfg.Settings.SV)
 		plan, err := bld.Build()
 		if err != nil {
 			return err
@@ -903,6 +1047,7 @@ func (opc *optPlanningCtx) runExecBuilder(
 			ctx, explainFactory, &opc.optimizer, mem, opc.catalog, mem.RootExpr(),
 			semaCtx, evalCtx, allowAutoCommit, statements.IsANSIDML(stmt.AST),
 		)
+		bld.BuildCardinalityEstimates = cardfeedback.Enabled.Get(&opc.p.execCfg.Settings.SV)
 		plan, err := bld.Build()
 		if err != nil {
 			return err
@@ -925,6 +1070,9 @@ func (opc *optPlanningCtx) runExecBuilder(
 		planTop.instrumentation.planGist = gf.PlanGist()
 	}
 	planTop.instrumentation.costEstimate = float64(mem.RootExpr().(memo.RelExpr).Cost())
+	if mem.RootExpr().(memo.RelExpr).Relational().VolatilitySet.HasVolatile() {
+		opc.flags.Set(planFlagContainsVolatile)
+	}
 	available := mem.RootExpr().(memo.RelExpr).Relational().Statistics().Available
 	planTop.instrumentation.statsAvailable = available
 	if available {
@@ -1047,6 +1195,12 @@ func (opc *optPlanningCtx) makeQueryIndexRecommendation(
 	// context after this function ends, and we don't want "use of Span after
 	// Finish" errors.
 	opc.optimizer.Init(origCtx, f.EvalContext(), opc.catalog)
+	if opc.planBaseline != nil {
+		opc.optimizer.SetPlanBaseline(opc.planBaseline)
+	}
+	if opc.cardinalityFeedback != nil {
+		opc.optimizer.Memo().SetCardinalityFeedback(opc.cardinalityFeedback)
+	}
 	savedMemo.Metadata().UpdateTableMeta(origCtx, f.EvalContext(), optTables)
 	f.CopyAndReplace(
 		savedMemo.RootExpr().(memo.RelExpr),
@@ -0,0 +1,535 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+// Package planbaseline implements persistent plan management. An operator can
+// pin a known-good plan gist for a statement fingerprint; the pinned plans are
+// stored in system.statement_plan_baselines and cached on every node by the
+// Registry, which the optimizer consults while planning. Optionally, a small
+// fraction of executions of a pinned fingerprint are planned without the
+// baseline so that new candidate plans can be verified against the pinned plan
+// and promoted if they are faster by a sufficient margin.
+package planbaseline
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// Status is the status of a plan in system.statement_plan_baselines.
+type Status string
+
+const (
+	// StatusPinned is the status of the plan that the optimizer is constrained
+	// to use for its fingerprint. There is at most one pinned plan per
+	// fingerprint.
+	StatusPinned Status = "pinned"
+	// StatusCandidate is the status of a plan that was produced by the
+	// unconstrained optimizer for a pinned fingerprint and is being verified.
+	StatusCandidate Status = "candidate"
+	// StatusRejected is the status of a candidate plan that was found to be
+	// slower than the pinned plan.
+	StatusRejected Status = "rejected"
+	// StatusRetired is the status of a previously pinned plan that was replaced,
+	// either by an operator or by a faster candidate plan.
+	StatusRetired Status = "retired"
+)
+
+// Enabled controls whether pinned plans are enforced by the optimizer.
+var Enabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.plan_baselines.enabled",
+	"if enabled, the optimizer is constrained to use the pinned plan of a "+
+		"statement fingerprint stored in system.statement_plan_baselines",
+	true,
+	settings.WithPublic)
+
+var pollingInterval = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.plan_baselines.poll_interval",
+	"rate at which the planbaseline.Registry polls for pinned plans, set to zero to disable",
+	10*time.Second,
+	settings.NonNegativeDuration,
+)
+
+var verifyCandidates = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.plan_baselines.verify_candidates.enabled",
+	"if enabled, a sample of executions of fingerprints with a pinned plan are "+
+		"planned without the pinned plan, and the resulting candidate plan "+
+		"replaces the pinned plan if it is faster by at least "+
+		"sql.plan_baselines.verify_candidates.min_improvement",
+	false,
+	settings.WithPublic)
+
+var verifySampleRate = settings.RegisterFloatSetting(
+	settings.ApplicationLevel,
+	"sql.plan_baselines.verify_candidates.sample_rate",
+	"fraction of executions of fingerprints with a pinned plan that are planned "+
+		"without the pinned plan in order to verify candidate plans",
+	0.05,
+	settings.FloatInRange(0, 1),
+)
+
+var verifyMinExecutions = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.plan_baselines.verify_candidates.min_executions",
+	"minimum number of executions of both the pinned plan and a candidate plan "+
+		"before the candidate plan is accepted or rejected",
+	10,
+	settings.PositiveInt,
+)
+
+var verifyMinImprovement = settings.RegisterFloatSetting(
+	settings.ApplicationLevel,
+	"sql.plan_baselines.verify_candidates.min_improvement",
+	"minimum fraction by which the mean latency of a candidate plan must be lower "+
+		"than that of the pinned plan for the candidate plan to be promoted; since "+
+		"the latencies are only observed on a single node, this guards against "+
+		"promoting a plan because of noise",
+	0.2,
+	settings.FloatInRangeUpperExclusive(0, 1),
+)
+
+// deviationLogEvery rate limits the logging of executions whose plan differs
+// from the pinned plan they were constrained to.
+var deviationLogEvery = log.Every(time.Minute)
+
+// Registry maintains a view of the pinned plans in
+// system.statement_plan_baselines and verifies candidate plans.
+type Registry struct {
+	mu struct {
+		// NOTE: This lock can't be held while the registry runs any statements
+		// internally; it'd deadlock.
+		syncutil.Mutex
+		// pinned maps statement fingerprints to the gist of their pinned plan.
+		pinned map[string]string
+		// rejected maps statement fingerprints to the gists of their rejected
+		// plans. Rejected plans are not verified again.
+		rejected map[string]map[string]struct{}
+		// verifying maps statement fingerprints to the latencies observed on
+		// this node for the pinned plan and its candidate plans.
+		verifying map[string]*fingerprintLatencies
+
+		// epoch is observed before reading system.statement_plan_baselines, and
+		// then checked again before loading the table's contents. If the value
+		// changed in between, then the table contents might be stale.
+		epoch int
+
+		rand *rand.Rand
+	}
+	st      *cluster.Settings
+	db      isql.DB
+	stopper *stop.Stopper
+}
+
+// latencies accumulates the service latencies of executions of a single plan.
+type latencies struct {
+	count int64
+	total time.Duration
+}
+
+func (l *latencies) add(latency time.Duration) {
+	l.count++
+	l.total += latency
+}
+
+func (l *latencies) mean() time.Duration {
+	if l.count == 0 {
+		return 0
+	}
+	return l.total / time.Duration(l.count)
+}
+
+// fingerprintLatencies tracks the latencies of the pinned plan of a
+// fingerprint along with those of its candidate plans, keyed by gist.
+type fingerprintLatencies struct {
+	pinnedGist string
+	pinned     latencies
+	candidates map[string]*latencies
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(db isql.DB, st *cluster.Settings) *Registry {
+	r := &Registry{
+		db: db,
+		st: st,
+	}
+	r.mu.rand = rand.New(rand.NewSource(timeutil.Now().UnixNano()))
+	return r
+}
+
+// Start will start the polling loop for the Registry.
+func (r *Registry) Start(ctx context.Context, stopper *stop.Stopper) {
+	r.stopper = stopper
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+
+	// Since background plan baseline maintenance is not under user control,
+	// exclude it from cost accounting and control.
+	ctx = multitenant.WithTenantCostControlExemption(ctx)
+
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = stopper.RunAsyncTask(ctx, "plan-baseline-poll", r.poll)
+}
+
+func (r *Registry) poll(ctx context.Context) {
+	var (
+		timer               timeutil.Timer
+		lastPoll            time.Time
+		deadline            time.Time
+		pollIntervalChanged = make(chan struct{}, 1)
+		maybeResetTimer     = func() {
+			if interval := pollingInterval.Get(&r.st.SV); interval == 0 {
+				// Setting the interval to zero stops the polling.
+				timer.Stop()
+			} else {
+				newDeadline := lastPoll.Add(interval)
+				if deadline.IsZero() || !deadline.Equal(newDeadline) {
+					deadline = newDeadline
+					timer.Reset(timeutil.Until(deadline))
+				}
+			}
+		}
+		poll = func() {
+			if err := r.pollBaselines(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Warningf(ctx, "error polling for plan baselines: %s", err)
+			}
+			lastPoll = timeutil.Now()
+		}
+	)
+	pollingInterval.SetOnChange(&r.st.SV, func(ctx context.Context) {
+		select {
+		case pollIntervalChanged <- struct{}{}:
+		default:
+		}
+	})
+	for {
+		maybeResetTimer()
+		select {
+		case <-pollIntervalChanged:
+			continue // go back around and maybe reset the timer
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return
+		}
+		poll()
+	}
+}
+
+func (r *Registry) pollBaselines(ctx context.Context) error {
+	var rows []tree.Datums
+
+	// Loop until we run the query without straddling an epoch increment.
+	for {
+		r.mu.Lock()
+		epoch := r.mu.epoch
+		r.mu.Unlock()
+
+		it, err := r.db.Executor().QueryIteratorEx(ctx, "plan-baseline-poll", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			`SELECT fingerprint, plan_gist, status FROM system.statement_plan_baselines
+				WHERE status IN ($1, $2)`,
+			string(StatusPinned), string(StatusRejected),
+		)
+		if err != nil {
+			return err
+		}
+		rows = rows[:0]
+		var ok bool
+		for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+			rows = append(rows, it.Cur())
+		}
+		if err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		// If the epoch changed it means that a plan was pinned or unpinned
+		// manually while the query was running. In that case, if we were to
+		// process the query results normally, we might undo that change.
+		if r.mu.epoch != epoch {
+			r.mu.Unlock()
+			continue
+		}
+		break
+	}
+	defer r.mu.Unlock()
+
+	pinned := make(map[string]string)
+	rejected := make(map[string]map[string]struct{})
+	for _, row := range rows {
+		fingerprint := string(tree.MustBeDString(row[0]))
+		gist := string(tree.MustBeDString(row[1]))
+		switch Status(tree.MustBeDString(row[2])) {
+		case StatusPinned:
+			pinned[fingerprint] = gist
+		case StatusRejected:
+			if rejected[fingerprint] == nil {
+				rejected[fingerprint] = make(map[string]struct{})
+			}
+			rejected[fingerprint][gist] = struct{}{}
+		}
+	}
+	r.mu.pinned = pinned
+	r.mu.rejected = rejected
+
+	// Discard the latencies of fingerprints that are no longer pinned, or
+	// whose pinned plan changed.
+	for fingerprint, fl := range r.mu.verifying {
+		if gist, ok := pinned[fingerprint]; !ok || gist != fl.pinnedGist {
+			delete(r.mu.verifying, fingerprint)
+		}
+	}
+	return nil
+}
+
+// PinnedPlan returns the gist of the pinned plan for the given statement
+// fingerprint, if there is one.
+func (r *Registry) PinnedPlan(fingerprint string) (gist string, ok bool) {
+	if !Enabled.Get(&r.st.SV) {
+		return "", false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	gist, ok = r.mu.pinned[fingerprint]
+	return gist, ok
+}
+
+// ShouldVerifyCandidate returns true if the current execution of a
+// fingerprint with a pinned plan should be planned without the pinned plan,
+// in order to verify the resulting candidate plan.
+func (r *Registry) ShouldVerifyCandidate() bool {
+	if !verifyCandidates.Get(&r.st.SV) {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mu.rand.Float64() < verifySampleRate.Get(&r.st.SV)
+}
+
+// Pin makes the plan with the given gist the pinned plan for the given
+// statement fingerprint. Any previously pinned plan for the fingerprint is
+// retired.
+func (r *Registry) Pin(ctx context.Context, fingerprint, gist string) error {
+	if err := r.db.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		txn.KV().SetDebugName("plan-baseline-pin")
+		if _, err := txn.ExecEx(ctx, "plan-baseline-retire", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride,
+			`UPDATE system.statement_plan_baselines SET status = $3
+				WHERE fingerprint = $1 AND status = $2 AND plan_gist != $4`,
+			fingerprint, string(StatusPinned), string(StatusRetired), gist,
+		); err != nil {
+			return err
+		}
+		_, err := txn.ExecEx(ctx, "plan-baseline-pin", txn.KV(),
+			sessiondata.NodeUserSessionDataOverride,
+			`UPSERT INTO system.statement_plan_baselines
+				(fingerprint, plan_gist, status, created_at, last_verified_at)
+				VALUES ($1, $2, $3, now(), NULL)`,
+			fingerprint, gist, string(StatusPinned),
+		)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Manually update the (local) registry. This lets this node use the pinned
+	// plan right away, without waiting for the poller.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.epoch++
+	if r.mu.pinned == nil {
+		r.mu.pinned = make(map[string]string)
+	}
+	r.mu.pinned[fingerprint] = gist
+	delete(r.mu.verifying, fingerprint)
+	return nil
+}
+
+// Unpin retires the pinned plan of the given statement fingerprint, if there
+// is one. It returns true if a pinned plan was found.
+func (r *Registry) Unpin(ctx context.Context, fingerprint string) (bool, error) {
+	rowsAffected, err := r.db.Executor().ExecEx(ctx, "plan-baseline-unpin", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		`UPDATE system.statement_plan_baselines SET status = $3
+			WHERE fingerprint = $1 AND status = $2`,
+		fingerprint, string(StatusPinned), string(StatusRetired),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.epoch++
+	delete(r.mu.pinned, fingerprint)
+	delete(r.mu.verifying, fingerprint)
+	return rowsAffected > 0, nil
+}
+
+// RecordExecution records the service latency of an execution of the plan
+// with the given gist for the given statement fingerprint. usedPinnedPlan is
+// true if the optimizer was constrained to the pinned plan when planning the
+// execution, and false if it was sampled for verification (see
+// ShouldVerifyCandidate).
+//
+// The optimizer only constrains the indexes and join algorithms of a plan to
+// those of the pinned plan, so a constrained execution may still differ from
+// the pinned plan, e.g. in its join order. Such executions are logged and are
+// not used for verification, since their latencies are neither those of the
+// pinned plan nor those of a plan chosen by the unconstrained optimizer.
+//
+// Verification is a no-op unless it is enabled and the fingerprint has a
+// pinned plan. Once both the pinned plan and a candidate plan have been
+// executed enough times on this node, the candidate plan is promoted to the
+// pinned plan if its mean latency is lower by at least the configured margin,
+// and rejected otherwise.
+func (r *Registry) RecordExecution(
+	ctx context.Context, fingerprint, gist string, usedPinnedPlan bool, latency time.Duration,
+) {
+	if gist == "" || !Enabled.Get(&r.st.SV) {
+		return
+	}
+	if usedPinnedPlan {
+		if pinnedGist, ok := r.PinnedPlan(fingerprint); ok && gist != pinnedGist &&
+			deviationLogEvery.ShouldLog() {
+			log.Warningf(ctx, "plan %s of fingerprint %q differs from its pinned plan %s",
+				gist, fingerprint, pinnedGist)
+		}
+	}
+	if !verifyCandidates.Get(&r.st.SV) {
+		return
+	}
+	minExecutions := verifyMinExecutions.Get(&r.st.SV)
+	minImprovement := verifyMinImprovement.Get(&r.st.SV)
+
+	var newCandidate, promote, reject bool
+	func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		pinnedGist, ok := r.mu.pinned[fingerprint]
+		if !ok {
+			return
+		}
+		if _, ok := r.mu.rejected[fingerprint][gist]; ok {
+			return
+		}
+		if r.mu.verifying == nil {
+			r.mu.verifying = make(map[string]*fingerprintLatencies)
+		}
+		fl, ok := r.mu.verifying[fingerprint]
+		if !ok {
+			fl = &fingerprintLatencies{
+				pinnedGist: pinnedGist,
+				candidates: make(map[string]*latencies),
+			}
+			r.mu.verifying[fingerprint] = fl
+		}
+		if gist == pinnedGist {
+			fl.pinned.add(latency)
+			return
+		}
+		if usedPinnedPlan {
+			// The constrained optimizer did not reproduce the pinned plan. See the
+			// function comment.
+			return
+		}
+		candidate, ok := fl.candidates[gist]
+		if !ok {
+			candidate = &latencies{}
+			fl.candidates[gist] = candidate
+			newCandidate = true
+		}
+		candidate.add(latency)
+		if fl.pinned.count < minExecutions || candidate.count < minExecutions {
+			return
+		}
+		if float64(candidate.mean()) < float64(fl.pinned.mean())*(1-minImprovement) {
+			promote = true
+			r.mu.pinned[fingerprint] = gist
+			delete(r.mu.verifying, fingerprint)
+		} else {
+			reject = true
+			if r.mu.rejected == nil {
+				r.mu.rejected = make(map[string]map[string]struct{})
+			}
+			if r.mu.rejected[fingerprint] == nil {
+				r.mu.rejected[fingerprint] = make(map[string]struct{})
+			}
+			r.mu.rejected[fingerprint][gist] = struct{}{}
+			delete(fl.candidates, gist)
+		}
+		r.mu.epoch++
+	}()
+
+	if !newCandidate && !promote && !reject {
+		return
+	}
+	r.updateAsync(ctx, fingerprint, gist, newCandidate, promote)
+}
+
+// updateAsync persists the outcome of recording a candidate plan execution
+// without blocking the statement that produced it.
+func (r *Registry) updateAsync(
+	ctx context.Context, fingerprint, gist string, newCandidate, promote bool,
+) {
+	if r.stopper == nil {
+		return
+	}
+	// The statement's context may be canceled as soon as it finishes, so the
+	// update runs with a fresh context.
+	taskCtx := multitenant.WithTenantCostControlExemption(context.Background())
+	if err := r.stopper.RunAsyncTask(taskCtx, "plan-baseline-update", func(ctx context.Context) {
+		var err error
+		switch {
+		case promote:
+			err = r.Pin(ctx, fingerprint, gist)
+			if err == nil {
+				_, err = r.db.Executor().ExecEx(ctx, "plan-baseline-verified", nil, /* txn */
+					sessiondata.NodeUserSessionDataOverride,
+					`UPDATE system.statement_plan_baselines SET last_verified_at = now()
+						WHERE fingerprint = $1 AND plan_gist = $2`,
+					fingerprint, gist,
+				)
+			}
+		case newCandidate:
+			_, err = r.db.Executor().ExecEx(ctx, "plan-baseline-candidate", nil, /* txn */
+				sessiondata.NodeUserSessionDataOverride,
+				`INSERT INTO system.statement_plan_baselines (fingerprint, plan_gist, status)
+					VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+				fingerprint, gist, string(StatusCandidate),
+			)
+		default:
+			_, err = r.db.Executor().ExecEx(ctx, "plan-baseline-reject", nil, /* txn */
+				sessiondata.NodeUserSessionDataOverride,
+				`UPSERT INTO system.statement_plan_baselines
+					(fingerprint, plan_gist, status, last_verified_at)
+					VALUES ($1, $2, $3, now())`,
+				fingerprint, gist, string(StatusRejected),
+			)
+		}
+		if err != nil {
+			log.Warningf(ctx, "error updating plan baseline: %s", err)
+		}
+	}); err != nil {
+		log.VEventf(ctx, 1, "unable to update plan baseline: %s", err)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package planbaseline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordExecution(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	verifyCandidates.Override(ctx, &st.SV, true)
+	verifyMinExecutions.Override(ctx, &st.SV, 2)
+	verifyMinImprovement.Override(ctx, &st.SV, 0.2)
+
+	// The registry is not started, so the outcome of verification is only
+	// reflected in memory.
+	const fingerprint = "SELECT * FROM t WHERE a = _"
+	r := NewRegistry(nil /* db */, st)
+	r.mu.pinned = map[string]string{fingerprint: "pinned"}
+	pinned := func() string {
+		gist, ok := r.PinnedPlan(fingerprint)
+		require.True(t, ok)
+		return gist
+	}
+	rejected := func(gist string) bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		_, ok := r.mu.rejected[fingerprint][gist]
+		return ok
+	}
+
+	for i := 0; i < 2; i++ {
+		r.RecordExecution(ctx, fingerprint, "pinned", true /* usedPinnedPlan */, 100*time.Millisecond)
+	}
+
+	// A constrained execution that did not reproduce the pinned plan is not a
+	// candidate, no matter how fast it is.
+	for i := 0; i < 2; i++ {
+		r.RecordExecution(ctx, fingerprint, "approx", true /* usedPinnedPlan */, time.Millisecond)
+	}
+	require.Equal(t, "pinned", pinned())
+	require.False(t, rejected("approx"))
+
+	// A candidate that is faster, but not by the minimum margin, is rejected.
+	for i := 0; i < 2; i++ {
+		r.RecordExecution(ctx, fingerprint, "slightly-faster", false /* usedPinnedPlan */, 90*time.Millisecond)
+	}
+	require.Equal(t, "pinned", pinned())
+	require.True(t, rejected("slightly-faster"))
+
+	// A candidate that is faster by the minimum margin is promoted.
+	r.RecordExecution(ctx, fingerprint, "faster", false /* usedPinnedPlan */, 50*time.Millisecond)
+	require.Equal(t, "pinned", pinned())
+	r.RecordExecution(ctx, fingerprint, "faster", false /* usedPinnedPlan */, 50*time.Millisecond)
+	require.Equal(t, "faster", pinned())
+}
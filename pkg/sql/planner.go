@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schematelemetry/schematelemetrycontroller"
 	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
 	"github.com/cockroachdb/cockroach/pkg/sql/evalcatalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/exprutil"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -215,6 +216,10 @@ type planner struct {
 	// pausablePortal is set when the query is from a pausable portal.
 	pausablePortal *PreparedPortal
 
+	// replan is set when the main query may be re-planned if an operator
+	// detects a gross cardinality misestimate during execution.
+	replan *execinfra.ReplanCoordinator
+
 	instrumentation instrumentationHelper
 
 	// Contexts for different stages of planning and execution.
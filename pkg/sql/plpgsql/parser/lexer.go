@@ -188,59 +188,115 @@ func (l *lexer) MakeExecSqlStmt() (*plpgsqltree.Execute, error) {
 	}, nil
 }
 
+// MakeDynamicExecuteStmt makes a DynamicExecute node. The INTO and USING
+// clauses may be specified in either order. Syntax:
+//
+//	EXECUTE command_string [ INTO [STRICT] target ] [ USING expression [, ...] ];
 func (l *lexer) MakeDynamicExecuteStmt() (*plpgsqltree.DynamicExecute, error) {
-	cmdStr, _, err := l.ReadSqlStatement(INTO, USING, ';')
+	queryStr, terminator, err := l.ReadSqlExpr(INTO, USING, ';')
 	if err != nil {
 		return nil, err
 	}
-	ret := &plpgsqltree.DynamicExecute{
-		Query: cmdStr,
+	query, err := l.ParseExpr(queryStr)
+	if err != nil {
+		return nil, err
 	}
-
-	var lval plpgsqlSymType
-	l.Lex(&lval)
+	ret := &plpgsqltree.DynamicExecute{Query: query}
 	for {
-		if lval.id == INTO {
-			if ret.Into {
-				return nil, errors.New("multiple INTO keywords")
+		switch terminator {
+		case ';':
+			// Move past the semicolon.
+			l.lastPos++
+			return ret, nil
+		case INTO:
+			if ret.Target != nil {
+				return nil, errors.New("INTO specified more than once")
 			}
-			ret.Into = true
-			nextTok := l.Peek()
-			if nextTok.id == int32(STRICT) {
-				l.Lex(&lval)
+			// Move past the INTO keyword.
+			l.lastPos++
+			if l.Peek().id == STRICT {
 				ret.Strict = true
+				l.lastPos++
 			}
-			// TODO we need to read each "INTO" variable name instead of just a
-			// string.
-			_, _, err = l.ReadSqlExpr(USING, ';')
-			if err != nil {
+			if ret.Target, err = l.ReadTarget(); err != nil {
 				return nil, err
 			}
-			l.Lex(&lval)
-		} else if lval.id == USING {
+			if len(ret.Target) == 0 {
+				return nil, errors.New("missing INTO target")
+			}
+			terminator = int(l.Peek().id)
+			if terminator != USING && terminator != ';' {
+				tok := l.Peek()
+				return nil, pgerror.Newf(pgcode.Syntax, "syntax error at or near \"%s\"", tok.str)
+			}
+		case USING:
 			if ret.Params != nil {
-				return nil, errors.New("multiple USING keywords")
+				return nil, errors.New("USING specified more than once")
 			}
-			ret.Params = make([]plpgsqltree.Expr, 0)
-			for {
-				_, _, err = l.ReadSqlExpr(',', ';', INTO)
-				if err != nil {
-					return nil, err
-				}
-				ret.Params = append(ret.Params, nil)
-				l.Lex(&lval)
-				if lval.id == ';' {
-					break
-				}
+			// Move past the USING keyword.
+			l.lastPos++
+			if ret.Params, terminator, err = l.readUsingParams(INTO, ';'); err != nil {
+				return nil, err
 			}
-		} else if lval.id == ';' {
-			break
-		} else {
-			return nil, errors.Newf("unexpected token: %s", lval.id)
+		default:
+			return nil, errors.New("unterminated EXECUTE statement")
 		}
 	}
+}
 
-	return ret, nil
+// ReadDynamicQuery reads the query string expression and the optional USING
+// clause of a dynamic query, followed by the given terminator. Syntax:
+//
+//	query_string [ USING expression [, ...] ] terminator
+func (l *lexer) ReadDynamicQuery(
+	terminator int,
+) (query plpgsqltree.Expr, params []plpgsqltree.Expr, terminatorMet int, err error) {
+	var queryStr string
+	queryStr, terminatorMet, err = l.ReadSqlExpr(USING, terminator)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if query, err = l.ParseExpr(queryStr); err != nil {
+		return nil, nil, 0, err
+	}
+	if terminatorMet == USING {
+		// Move past the USING keyword.
+		l.lastPos++
+		if params, terminatorMet, err = l.readUsingParams(terminator); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	if terminatorMet != 0 {
+		// Move past the terminator.
+		l.lastPos++
+	}
+	return query, params, terminatorMet, nil
+}
+
+// readUsingParams reads the comma-separated list of expressions in the USING
+// clause of a dynamic query, up to one of the given terminators. The
+// terminator is not consumed.
+func (l *lexer) readUsingParams(
+	terminator1 int, terminators ...int,
+) (params []plpgsqltree.Expr, terminatorMet int, err error) {
+	terminators = append([]int{terminator1}, terminators...)
+	for {
+		var paramStr string
+		paramStr, terminatorMet, err = l.ReadSqlExpr(',', terminators...)
+		if err != nil {
+			return nil, 0, err
+		}
+		param, err := l.ParseExpr(paramStr)
+		if err != nil {
+			return nil, 0, err
+		}
+		params = append(params, param)
+		if terminatorMet != ',' {
+			return params, terminatorMet, nil
+		}
+		// Move past the comma.
+		l.lastPos++
+	}
 }
 
 func (l *lexer) MakeFetchOrMoveStmt(isMove bool) (plpgsqltree.Statement, error) {
@@ -360,6 +416,58 @@ func (l *lexer) ReadIntegerForLoopControl() (plpgsqltree.ForLoopControl, error)
 	}, err
 }
 
+// ReadQueryForLoopControl reads a loop control statement that iterates over
+// the rows of a query or a bound cursor. Syntax:
+//
+//	query LOOP
+//	cursor_variable LOOP
+func (l *lexer) ReadQueryForLoopControl() (plpgsqltree.ForLoopControl, error) {
+	startPos, endPos, terminator, err := l.readSQLConstruct(
+		false /* isExpr */, false /* allowEmpty */, LOOP,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if terminator == 0 {
+		return nil, errors.New("missing LOOP keyword")
+	}
+	// Move past the LOOP keyword.
+	l.lastPos++
+	if endPos-startPos == 1 && l.tokens[startPos].id == IDENT {
+		// A single identifier references a bound cursor variable.
+		return &plpgsqltree.CursorForLoopControl{
+			CursorVar: plpgsqltree.Variable(strings.TrimSpace(l.getStr(startPos, endPos))),
+		}, nil
+	}
+	sqlStmt, err := parser.ParseOne(l.getStr(startPos, endPos))
+	if err != nil {
+		return nil, err
+	}
+	if sqlStmt.AST.StatementReturnType() != tree.Rows {
+		return nil, pgerror.Newf(pgcode.Syntax,
+			"cannot iterate over %s statement in FOR loop", sqlStmt.AST.StatementTag(),
+		)
+	}
+	return &plpgsqltree.QueryForLoopControl{Query: sqlStmt.AST}, nil
+}
+
+// ReadDynamicForLoopControl reads a loop control statement that iterates over
+// the rows of a dynamic query. Syntax:
+//
+//	EXECUTE query_string [ USING expression [, ...] ] LOOP
+func (l *lexer) ReadDynamicForLoopControl() (plpgsqltree.ForLoopControl, error) {
+	// Move past the EXECUTE keyword.
+	l.lastPos++
+	query, params, terminator, err := l.ReadDynamicQuery(LOOP)
+	if err != nil {
+		return nil, err
+	}
+	if terminator == 0 {
+		return nil, errors.New("missing LOOP keyword")
+	}
+	return &plpgsqltree.DynamicForLoopControl{Query: query, Params: params}, nil
+}
+
 func (l *lexer) ReadSqlExpr(
 	terminator1 int, terminators ...int,
 ) (sqlStr string, terminatorMet int, err error) {
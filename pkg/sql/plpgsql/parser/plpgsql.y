@@ -337,7 +337,7 @@ func (u *plpgsqlSymUnion) forLoopControl() plpgsqltree.ForLoopControl {
 %type <*tree.NumVal> foreach_slice
 %type <plpgsqltree.ForLoopControl> for_control
 
-%type <str> any_identifier opt_block_label opt_loop_label opt_label query_options
+%type <str> any_identifier opt_block_label opt_loop_label opt_label
 %type <str> opt_error_level option_type
 
 %type <[]plpgsqltree.Statement> proc_sect
@@ -754,7 +754,12 @@ proc_stmt:pl_block ';'
 
 stmt_perform: PERFORM stmt_until_semi ';'
   {
-    return unimplemented(plpgsqllex, "perform")
+    // PERFORM is equivalent to a SELECT statement with its results discarded.
+    sqlStmt, err := parser.ParseOne("SELECT " + $2)
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    $$.val = &plpgsqltree.Perform{SqlStmt: sqlStmt.AST}
   }
 ;
 
@@ -1058,7 +1063,18 @@ for_control:
 	    }
 	    $$.val = forLoopControl
 	  case LOOP:
-	    return unimplemented(plpgsqllex, "for loop over query or cursor")
+	    // This is an iteration over the rows of a query or a bound cursor.
+	    var forLoopControl plpgsqltree.ForLoopControl
+	    var err error
+	    if plpgsqllex.(*lexer).Peek().id == EXECUTE {
+	      forLoopControl, err = plpgsqllex.(*lexer).ReadDynamicForLoopControl()
+	    } else {
+	      forLoopControl, err = plpgsqllex.(*lexer).ReadQueryForLoopControl()
+	    }
+	    if err != nil {
+	      return setErr(plpgsqllex, err)
+	    }
+	    $$.val = forLoopControl
 	  default:
 	    return setErr(plpgsqllex, errors.New("unterminated FOR loop definition"))
 	  }
@@ -1075,17 +1091,41 @@ for_target:
   }
 ;
 
-stmt_foreach_a: opt_loop_label FOREACH
+stmt_foreach_a: opt_loop_label FOREACH for_target foreach_slice IN ARRAY expr_until_loop LOOP loop_body opt_label ';'
   {
-    return unimplemented(plpgsqllex, "for each loop")
+    loopLabel, loopEndLabel := $1, $10
+    if err := checkLoopLabels(loopLabel, loopEndLabel); err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    var slice int64
+    if $4.numVal() != nil {
+      var err error
+      slice, err = $4.numVal().AsInt64()
+      if err != nil {
+        return setErr(plpgsqllex, err)
+      }
+    }
+    expr, err := plpgsqllex.(*lexer).ParseExpr($7)
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    $$.val = &plpgsqltree.ForEachArray{
+      Label: loopLabel,
+      Target: $3.variables(),
+      Slice: int(slice),
+      Expr: expr,
+      Body: $9.statements(),
+    }
   }
 ;
 
 foreach_slice:
   {
+    $$.val = (*tree.NumVal)(nil)
   }
 | SLICE ICONST
   {
+    $$.val = $2.numVal()
   }
 ;
 
@@ -1119,39 +1159,51 @@ stmt_return: RETURN return_expr ';'
     }
     $$.val = &plpgsqltree.Return{Expr: expr}
   }
-| RETURN_NEXT NEXT
+| RETURN_NEXT NEXT return_expr ';'
   {
-    return unimplemented(plpgsqllex, "return next")
+    var expr plpgsqltree.Expr
+    if $3 != "" {
+      var err error
+      expr, err = plpgsqllex.(*lexer).ParseExpr($3)
+      if err != nil {
+        return setErr(plpgsqllex, err)
+      }
+    }
+    $$.val = &plpgsqltree.ReturnNext{Expr: expr}
   }
-| RETURN_QUERY QUERY
- {
-   return unimplemented (plpgsqllex, "return query")
- }
-;
-
-return_expr:
+| RETURN_QUERY QUERY EXECUTE
   {
-    sqlStr, err := plpgsqllex.(*lexer).ReadReturnExpr()
+    query, params, terminator, err := plpgsqllex.(*lexer).ReadDynamicQuery(';')
     if err != nil {
       return setErr(plpgsqllex, err)
     }
-    $$ = sqlStr
+    if terminator == 0 {
+      return setErr(plpgsqllex, errors.New("unterminated RETURN QUERY EXECUTE statement"))
+    }
+    $$.val = &plpgsqltree.ReturnQuery{DynamicQuery: query, Params: params}
   }
-;
-
-query_options:
+| RETURN_QUERY QUERY stmt_until_semi ';'
   {
-    _, terminator, err := plpgsqllex.(*lexer).ReadSqlExpr(EXECUTE, ';')
+    sqlStmt, err := parser.ParseOne($3)
     if err != nil {
       return setErr(plpgsqllex, err)
     }
-    if terminator == EXECUTE {
-      return unimplemented (plpgsqllex, "return dynamic sql query")
+    if sqlStmt.AST.StatementReturnType() != tree.Rows {
+      return setErr(plpgsqllex,
+        errors.New("RETURN QUERY must be used with a query that returns rows"),
+      )
     }
-    _, _, err = plpgsqllex.(*lexer).ReadSqlExpr(';')
+    $$.val = &plpgsqltree.ReturnQuery{SqlStmt: sqlStmt.AST}
+  }
+;
+
+return_expr:
+  {
+    sqlStr, err := plpgsqllex.(*lexer).ReadReturnExpr()
     if err != nil {
       return setErr(plpgsqllex, err)
     }
+    $$ = sqlStr
   }
 ;
 
@@ -1358,9 +1410,21 @@ stmt_open: OPEN IDENT ';'
   {
     $$.val = &plpgsqltree.Open{CurVar: plpgsqltree.Variable($2)}
   }
-| OPEN IDENT opt_scrollable FOR EXECUTE 
+| OPEN IDENT opt_scrollable FOR EXECUTE
   {
-    return unimplemented(plpgsqllex, "cursor for execute")
+    query, params, terminator, err := plpgsqllex.(*lexer).ReadDynamicQuery(';')
+    if err != nil {
+      return setErr(plpgsqllex, err)
+    }
+    if terminator == 0 {
+      return setErr(plpgsqllex, errors.New("unterminated OPEN statement"))
+    }
+    $$.val = &plpgsqltree.Open{
+      CurVar: plpgsqltree.Variable($2),
+      Scroll: $3.cursorScrollOption(),
+      DynamicQuery: query,
+      Params: params,
+    }
   }
 | OPEN IDENT opt_scrollable FOR stmt_until_semi ';'
   {
@@ -0,0 +1,933 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// refreshIncrementally brings the data of the materialized view up to date by
+// applying the changes made to its base tables since the view was last
+// refreshed, instead of recomputing the view query from scratch.
+//
+// The changes to each base table are read with crdb_internal.table_diff, which
+// uses MVCC incremental iteration over the table's primary index. The change
+// to the result of the view query (the delta) is computed with the usual
+// incremental view maintenance rules, which join the changes to each table
+// with the other tables of the query: see delta. The scans of the other tables
+// are only restricted to the rows joining the changes if the optimizer can plan
+// lookup joins into them, so the cost of the refresh is only proportional to
+// the size of the changes if the join columns are indexed. The delta is
+// computed once, buffered as a JSON array, and then applied to the view with
+// regular DML statements, all in the transaction of the REFRESH statement.
+// Only a subset of queries is supported: see analyzeIncrementalViewQuery.
+func (n *refreshMaterializedViewNode) refreshIncrementally(params runParams) error {
+	ctx, p, desc := params.ctx, params.p, n.desc
+	telemetry.Inc(sqltelemetry.SchemaRefreshMaterializedViewIncrementally)
+
+	refreshedAsOf := desc.GetViewRefreshedAsOf()
+	if desc.IsRefreshViewRequired() || refreshedAsOf.IsEmpty() {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"materialized view %q has not been populated", desc.GetName()),
+			"use REFRESH MATERIALIZED VIEW without INCREMENTALLY to populate the view",
+		)
+	}
+	viewName, err := p.getQualifiedTableName(ctx, desc)
+	if err != nil {
+		return err
+	}
+	q, err := analyzeIncrementalViewQuery(ctx, p, desc, viewName)
+	if err != nil {
+		return err
+	}
+
+	// All the statements must observe the base tables as of the same
+	// timestamp, which becomes the new refresh timestamp of the view.
+	asOf := p.txn.ReadTimestamp()
+	override := sessiondata.InternalExecutorOverride{
+		User:                           p.User(),
+		AllowMaterializedViewMutations: true,
+	}
+	withHint := func(err error) error {
+		return errors.WithHint(err,
+			"use REFRESH MATERIALIZED VIEW without INCREMENTALLY to recompute the view")
+	}
+	// The delta is needed by each of the statements which apply it to the view,
+	// so it is only computed once.
+	row, err := p.InternalSQLTxn().QueryRowEx(
+		ctx, "compute-materialized-view-delta", p.txn, override, q.deltaQuery(refreshedAsOf),
+	)
+	if err != nil {
+		return withHint(err)
+	}
+	if delta := row[0]; delta != tree.DNull {
+		for _, stmt := range q.refreshStatements() {
+			if _, err := p.InternalSQLTxn().ExecEx(
+				ctx, "refresh-materialized-view-incrementally", p.txn, override, stmt, delta,
+			); err != nil {
+				return withHint(err)
+			}
+		}
+	}
+	if readTS := p.txn.ReadTimestamp(); readTS != asOf {
+		return p.txn.GenerateForcedRetryableErr(
+			ctx, "read timestamp changed during incremental materialized view refresh",
+		)
+	}
+
+	desc.ViewRefreshedAsOf = asOf
+	return p.writeSchemaChange(
+		ctx, desc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// incrementalViewTable is a reference to a base table in the FROM clause of a
+// view query which is maintained incrementally.
+type incrementalViewTable struct {
+	// expr is the table expression in the parsed view query. Its table name is
+	// replaced when generating each term of the delta of the view.
+	expr *tree.AliasedTableExpr
+	// name is the original name of the table in expr.
+	name *tree.TableName
+	desc catalog.TableDescriptor
+}
+
+// incrementalViewAggKind is the kind of an aggregate function in a view query
+// which is maintained incrementally.
+type incrementalViewAggKind int
+
+const (
+	// incrementalViewCountRows is count(*).
+	incrementalViewCountRows incrementalViewAggKind = iota
+	// incrementalViewCount is count(x).
+	incrementalViewCount
+	// incrementalViewSum is sum(x).
+	incrementalViewSum
+)
+
+// incrementalViewAgg is an aggregate function in the select list of a view
+// query which is maintained incrementally.
+type incrementalViewAgg struct {
+	kind incrementalViewAggKind
+	// col is the view column computed by the aggregate.
+	col tree.Name
+	// arg is the argument of the aggregate. It is nil for count(*).
+	arg tree.Expr
+	// countCol is, for sum(x), the view column computed by count(x). It is
+	// used to determine when the sum becomes NULL.
+	countCol tree.Name
+}
+
+// incrementalViewQuery is the analyzed query of a materialized view which is
+// maintained incrementally.
+type incrementalViewQuery struct {
+	viewName *tree.TableName
+	sel      *tree.SelectClause
+	tables   []incrementalViewTable
+	// cols are the visible columns of the view, and colTypes their types.
+	cols     tree.NameList
+	colTypes []*types.T
+	// rowIDCol is the hidden primary key column of the view.
+	rowIDCol tree.Name
+
+	// The following fields are only set if the query computes aggregates.
+	aggregate bool
+	// groupExprs are the grouping expressions of the query, which compute the
+	// view columns in groupCols.
+	groupExprs tree.Exprs
+	groupCols  tree.NameList
+	aggs       []incrementalViewAgg
+	// countRowsCol is the view column computed by count(*), if there is one.
+	countRowsCol tree.Name
+}
+
+// Names of the common table expressions and columns used by the statements
+// of an incremental refresh.
+const (
+	incrementalViewDiffCTE     = "__mv_diff"
+	incrementalViewInsertedCTE = "__mv_ins"
+	incrementalViewDeletedCTE  = "__mv_del"
+	incrementalViewDeltaCTE    = "__mv_delta"
+	incrementalViewRecordAlias = "__mv_r"
+	incrementalViewAlias       = "__mv_view"
+	incrementalViewSignCol     = "__mv_sign"
+	incrementalViewCountCol    = "__mv_count"
+	incrementalViewRowNumCol   = "__mv_rn"
+	incrementalViewArgCol      = "__mv_arg"
+	incrementalViewAggCol      = "__mv_agg"
+)
+
+// incrementalViewMaxTables is the maximum number of tables in the query of a
+// view which can be refreshed incrementally. The delta of a query over n
+// tables has 3^n-1 terms (see terms), so it grows too quickly to be useful
+// past a few tables.
+const incrementalViewMaxTables = 3
+
+// errIncrementalRefreshNotSupported returns the error for a view whose query
+// can't be maintained incrementally.
+func errIncrementalRefreshNotSupported(
+	viewName string, format string, args ...interface{},
+) error {
+	return errors.WithHint(
+		pgerror.Newf(pgcode.FeatureNotSupported,
+			"materialized view %q cannot be refreshed incrementally: %s",
+			viewName, fmt.Sprintf(format, args...)),
+		"views which only use filters, projections, inner joins and the count and sum "+
+			"aggregate functions over tables can be refreshed incrementally",
+	)
+}
+
+// analyzeIncrementalViewQuery parses the query of the given materialized view
+// and checks that it can be maintained incrementally. The supported queries
+// are a single SELECT clause over tables and inner joins of at most
+// incrementalViewMaxTables tables, with filters and projections using
+// immutable expressions. The query may compute
+// count(*), count(x) and sum(x) aggregates, optionally grouped by the other
+// columns of the view; with grouping, count(*) must be part of the view so
+// that emptied groups can be detected, and each sum(x) requires count(x) so
+// that sums over only NULL values can be detected.
+func analyzeIncrementalViewQuery(
+	ctx context.Context, p *planner, desc *tabledesc.Mutable, viewName *tree.TableName,
+) (*incrementalViewQuery, error) {
+	name := desc.GetName()
+	stmt, err := parser.ParseOne(desc.GetViewQuery())
+	if err != nil {
+		return nil, err
+	}
+	s, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, errors.AssertionFailedf("unexpected view query %s", stmt.AST)
+	}
+	if err := checkIncrementalViewVolatility(ctx, p, name, desc.GetViewQuery()); err != nil {
+		return nil, err
+	}
+	if s.With != nil || s.Limit != nil || len(s.Locking) > 0 {
+		return nil, errIncrementalRefreshNotSupported(name, "the query must be a simple SELECT")
+	}
+	sel, ok := s.Select.(*tree.SelectClause)
+	if !ok || sel.TableSelect {
+		return nil, errIncrementalRefreshNotSupported(name, "the query must be a simple SELECT")
+	}
+	switch {
+	case sel.Distinct || sel.DistinctOn != nil:
+		return nil, errIncrementalRefreshNotSupported(name, "DISTINCT is not supported")
+	case sel.Having != nil:
+		return nil, errIncrementalRefreshNotSupported(name, "HAVING is not supported")
+	case len(sel.Window) > 0:
+		return nil, errIncrementalRefreshNotSupported(name, "window functions are not supported")
+	case sel.From.AsOf.Expr != nil:
+		return nil, errIncrementalRefreshNotSupported(name, "AS OF SYSTEM TIME is not supported")
+	case len(sel.From.Tables) == 0:
+		return nil, errIncrementalRefreshNotSupported(name, "the query must read from a table")
+	}
+
+	q := &incrementalViewQuery{
+		viewName: viewName,
+		sel:      sel,
+		rowIDCol: tree.Name(desc.GetPrimaryIndex().GetKeyColumnName(0)),
+	}
+	for _, col := range desc.VisibleColumns() {
+		q.cols = append(q.cols, tree.Name(col.GetName()))
+		q.colTypes = append(q.colTypes, col.GetType())
+	}
+	for _, t := range sel.From.Tables {
+		if err := q.addTables(ctx, p, name, t); err != nil {
+			return nil, err
+		}
+	}
+	if len(q.tables) > incrementalViewMaxTables {
+		return nil, errIncrementalRefreshNotSupported(name,
+			"views over more than %d tables are not supported", incrementalViewMaxTables)
+	}
+	checkExpr := func(e tree.Expr, allowAgg bool) (isAgg bool, _ error) {
+		return checkIncrementalViewExpr(ctx, p, name, e, allowAgg)
+	}
+	if sel.Where != nil {
+		if _, err := checkExpr(sel.Where.Expr, false /* allowAgg */); err != nil {
+			return nil, err
+		}
+	}
+
+	// Determine whether the query computes aggregates.
+	isAgg := make([]bool, len(sel.Exprs))
+	for i := range sel.Exprs {
+		switch sel.Exprs[i].Expr.(type) {
+		case tree.UnqualifiedStar, *tree.AllColumnsSelector:
+			continue
+		}
+		if isAgg[i], err = checkExpr(sel.Exprs[i].Expr, true /* allowAgg */); err != nil {
+			return nil, err
+		}
+		q.aggregate = q.aggregate || isAgg[i]
+	}
+	if !q.aggregate && len(sel.GroupBy) == 0 {
+		return q, nil
+	}
+	if len(sel.Exprs) != len(q.cols) {
+		return nil, errIncrementalRefreshNotSupported(name, "* is not supported with aggregates")
+	}
+
+	// Each grouping expression must be one of the view columns, and each view
+	// column must be either a grouping expression or an aggregate.
+	isGroup := make([]bool, len(sel.Exprs))
+	for _, g := range sel.GroupBy {
+		i, err := q.findGroupByExpr(g)
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 {
+			return nil, errIncrementalRefreshNotSupported(name,
+				"GROUP BY expression %s must be part of the view", g)
+		}
+		isGroup[i] = true
+	}
+	for i := range sel.Exprs {
+		switch {
+		case isAgg[i]:
+			if err := q.addAgg(ctx, p, desc, name, i); err != nil {
+				return nil, err
+			}
+		case isGroup[i]:
+			q.groupExprs = append(q.groupExprs, sel.Exprs[i].Expr)
+			q.groupCols = append(q.groupCols, q.cols[i])
+		default:
+			return nil, errIncrementalRefreshNotSupported(name,
+				"column %s must be a GROUP BY expression", q.cols[i])
+		}
+	}
+	if len(sel.GroupBy) > 0 && q.countRowsCol == "" {
+		return nil, errIncrementalRefreshNotSupported(name,
+			"views with GROUP BY must include count(*)")
+	}
+	for i := range q.aggs {
+		agg := &q.aggs[i]
+		if agg.kind != incrementalViewSum {
+			continue
+		}
+		for j := range q.aggs {
+			other := &q.aggs[j]
+			if other.kind == incrementalViewCount && tree.AsString(other.arg) == tree.AsString(agg.arg) {
+				agg.countCol = other.col
+				break
+			}
+		}
+		if agg.countCol == "" {
+			return nil, errIncrementalRefreshNotSupported(name,
+				"sum(%[1]s) requires count(%[1]s) to be part of the view", agg.arg)
+		}
+	}
+	return q, nil
+}
+
+// addTables adds the base tables referenced by the given table expression.
+func (q *incrementalViewQuery) addTables(
+	ctx context.Context, p *planner, viewName string, expr tree.TableExpr,
+) error {
+	switch t := expr.(type) {
+	case *tree.ParenTableExpr:
+		return q.addTables(ctx, p, viewName, t.Expr)
+
+	case *tree.JoinTableExpr:
+		if t.JoinType != "" && t.JoinType != tree.AstInner && t.JoinType != tree.AstCross {
+			return errIncrementalRefreshNotSupported(viewName,
+				"%s JOIN is not supported", t.JoinType)
+		}
+		if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+			if _, err := checkIncrementalViewExpr(
+				ctx, p, viewName, on.Expr, false, /* allowAgg */
+			); err != nil {
+				return err
+			}
+		}
+		if err := q.addTables(ctx, p, viewName, t.Left); err != nil {
+			return err
+		}
+		return q.addTables(ctx, p, viewName, t.Right)
+
+	case *tree.AliasedTableExpr:
+		tn, ok := t.Expr.(*tree.TableName)
+		if !ok || t.Lateral || t.Ordinality || t.IndexFlags != nil {
+			return errIncrementalRefreshNotSupported(viewName,
+				"table expression %s is not supported", tree.AsString(t))
+		}
+		table, err := p.ResolveExistingObjectEx(
+			ctx, tn.ToUnresolvedObjectName(), true /* required */, tree.ResolveAnyTableKind,
+		)
+		if err != nil {
+			return err
+		}
+		if !table.IsPhysicalTable() || table.IsView() || table.IsSequence() {
+			return errIncrementalRefreshNotSupported(viewName,
+				"%s is not a table", tn)
+		}
+		for _, col := range table.VisibleColumns() {
+			if col.IsVirtual() {
+				return errIncrementalRefreshNotSupported(viewName,
+					"table %s has virtual computed columns", tn)
+			}
+		}
+		// The table is replaced by common table expressions in the terms of the
+		// delta, so make sure that the references to its columns don't depend
+		// on its name.
+		if t.As.Alias == "" {
+			t.As.Alias = tn.ObjectName
+		}
+		q.tables = append(q.tables, incrementalViewTable{expr: t, name: tn, desc: table})
+		return nil
+
+	default:
+		return errIncrementalRefreshNotSupported(viewName,
+			"table expression %s is not supported", tree.AsString(expr))
+	}
+}
+
+// findGroupByExpr returns the ordinal of the select expression matching the
+// given GROUP BY expression, or -1 if there isn't one.
+func (q *incrementalViewQuery) findGroupByExpr(g tree.Expr) (int, error) {
+	if num, ok := g.(*tree.NumVal); ok {
+		// GROUP BY <ordinal>.
+		ord, err := num.AsInt64()
+		if err != nil || ord < 1 || int(ord) > len(q.sel.Exprs) {
+			return -1, pgerror.Newf(pgcode.InvalidColumnReference,
+				"GROUP BY position %s is not in select list", num)
+		}
+		return int(ord - 1), nil
+	}
+	str := tree.AsString(g)
+	for i := range q.sel.Exprs {
+		if tree.AsString(q.sel.Exprs[i].Expr) == str {
+			return i, nil
+		}
+	}
+	if name, ok := g.(*tree.UnresolvedName); ok && name.NumParts == 1 {
+		// GROUP BY <alias>.
+		for i := range q.sel.Exprs {
+			if q.sel.Exprs[i].As == tree.UnrestrictedName(name.Parts[0]) {
+				return i, nil
+			}
+		}
+	}
+	return -1, nil
+}
+
+// addAgg adds the aggregate function computed by the ith select expression.
+func (q *incrementalViewQuery) addAgg(
+	ctx context.Context, p *planner, desc *tabledesc.Mutable, viewName string, i int,
+) error {
+	fn, ok := q.sel.Exprs[i].Expr.(*tree.FuncExpr)
+	if !ok {
+		return errIncrementalRefreshNotSupported(viewName,
+			"aggregate functions must not be nested in other expressions")
+	}
+	if fn.Type == tree.DistinctFuncType || fn.Filter != nil || len(fn.OrderBy) > 0 ||
+		len(fn.Exprs) != 1 {
+		return errIncrementalRefreshNotSupported(viewName,
+			"aggregate function %s is not supported", fn)
+	}
+	searchPath := p.CurrentSearchPath()
+	def, err := fn.Func.Resolve(ctx, &searchPath, p.semaCtx.FunctionResolver)
+	if err != nil {
+		return err
+	}
+	agg := incrementalViewAgg{col: q.cols[i], arg: fn.Exprs[0]}
+	switch def.Name {
+	case "count":
+		agg.kind = incrementalViewCount
+		if _, ok := agg.arg.(tree.UnqualifiedStar); ok {
+			agg.kind, agg.arg = incrementalViewCountRows, nil
+			q.countRowsCol = agg.col
+		}
+	case "sum":
+		agg.kind = incrementalViewSum
+		// Maintaining the sum of floating point values would accumulate
+		// rounding errors.
+		if desc.VisibleColumns()[i].GetType().Family() == types.FloatFamily {
+			return errIncrementalRefreshNotSupported(viewName,
+				"sum of floating point values is not supported")
+		}
+	default:
+		return errIncrementalRefreshNotSupported(viewName,
+			"aggregate function %s is not supported", def.Name)
+	}
+	q.aggs = append(q.aggs, agg)
+	return nil
+}
+
+// checkIncrementalViewVolatility checks that the given view query only uses
+// immutable expressions, since the result of a stable or volatile expression
+// could change without any change to the base tables. The check is made on
+// the expressions built by the optimizer, so that it accounts for casts and
+// operators as well as functions.
+func checkIncrementalViewVolatility(
+	ctx context.Context, p *planner, viewName string, query string,
+) error {
+	// Parse the query again, since the optimizer annotates the AST.
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return err
+	}
+	ctlg := &optCatalog{}
+	ctlg.init(p)
+	var f norm.Factory
+	f.Init(ctx, p.EvalContext(), ctlg)
+	// Stable expressions must not be folded into constants, or the check would
+	// miss them.
+	f.FoldingControl().TemporarilyDisallowStableFolds(func() {
+		err = optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), ctlg, &f, stmt.AST).Build()
+	})
+	if err != nil {
+		return err
+	}
+	vol := f.Memo().RootExpr().(memo.RelExpr).Relational().VolatilitySet.ToVolatility()
+	if vol > volatility.Immutable {
+		return errIncrementalRefreshNotSupported(viewName, "%s expressions are not supported", vol)
+	}
+	return nil
+}
+
+// checkIncrementalViewExpr checks that the given expression of a view query
+// can be maintained incrementally, and returns whether it is an aggregate
+// function. Aggregate functions are only allowed if allowAgg is true, and
+// can't be nested in other expressions.
+func checkIncrementalViewExpr(
+	ctx context.Context, p *planner, viewName string, expr tree.Expr, allowAgg bool,
+) (isAgg bool, _ error) {
+	searchPath := p.CurrentSearchPath()
+	_, err := tree.SimpleVisit(expr, func(e tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		switch t := e.(type) {
+		case *tree.Subquery:
+			return false, nil, errIncrementalRefreshNotSupported(viewName,
+				"subqueries are not supported")
+
+		case *tree.FuncExpr:
+			if t.WindowDef != nil {
+				return false, nil, errIncrementalRefreshNotSupported(viewName,
+					"window functions are not supported")
+			}
+			def, err := t.Func.Resolve(ctx, &searchPath, p.semaCtx.FunctionResolver)
+			if err != nil {
+				return false, nil, err
+			}
+			for i := range def.Overloads {
+				o := def.Overloads[i]
+				switch {
+				case o.Type != tree.BuiltinRoutine:
+					return false, nil, errIncrementalRefreshNotSupported(viewName,
+						"user-defined function %s is not supported", def.Name)
+				case o.Class == tree.AggregateClass:
+					if !allowAgg || t != expr {
+						return false, nil, errIncrementalRefreshNotSupported(viewName,
+							"aggregate function %s is not allowed here", def.Name)
+					}
+					isAgg = true
+				case o.Class != tree.NormalClass:
+					return false, nil, errIncrementalRefreshNotSupported(viewName,
+						"function %s is not supported", def.Name)
+				}
+			}
+		}
+		return true, e, nil
+	})
+	return isAgg, err
+}
+
+// deltaQuery returns the query computing the delta of the view (see delta)
+// since refreshedAsOf as a single JSON array, or NULL if the delta is empty.
+func (q *incrementalViewQuery) deltaQuery(refreshedAsOf hlc.Timestamp) string {
+	delta := tree.NameString(incrementalViewDeltaCTE)
+	return fmt.Sprintf("WITH %[1]s SELECT json_agg(%[2]s) FROM (%[3]s) AS %[2]s",
+		q.changesCTEs(refreshedAsOf), delta, q.delta())
+}
+
+// refreshStatements returns the statements which apply the delta of the view
+// to the view. The delta is passed to each statement as the JSON array
+// computed by deltaQuery, in the $1 placeholder.
+func (q *incrementalViewQuery) refreshStatements() []string {
+	with := fmt.Sprintf(
+		"WITH %[1]s AS MATERIALIZED (SELECT %[2]s.* FROM json_to_recordset($1::JSONB) AS %[2]s (%[3]s)) ",
+		incrementalViewDeltaCTE, incrementalViewRecordAlias, q.deltaColDefs(),
+	)
+	view := tree.AsString(q.viewName)
+	alias := tree.NameString(incrementalViewAlias)
+	delta := tree.NameString(incrementalViewDeltaCTE)
+
+	if !q.aggregate {
+		// Each row of the delta contains the net number of copies of a row of
+		// the view to insert (if positive) or delete (if negative).
+		count := tree.NameString(incrementalViewCountCol)
+		rowNum := tree.NameString(incrementalViewRowNumCol)
+		rowID := tree.NameString(string(q.rowIDCol))
+		del := with + fmt.Sprintf(
+			"DELETE FROM %[1]s WHERE %[2]s IN (SELECT %[2]s FROM ("+
+				"SELECT %[3]s.%[2]s, %[4]s.%[5]s, row_number() OVER (PARTITION BY %[6]s) AS %[7]s "+
+				"FROM %[1]s AS %[3]s JOIN %[4]s ON %[8]s WHERE %[4]s.%[5]s < 0"+
+				") WHERE %[7]s <= -%[5]s)",
+			view, rowID, alias, delta, count,
+			qualifiedNames(incrementalViewAlias, q.cols), rowNum, q.matchDelta(q.cols),
+		)
+		ins := with + fmt.Sprintf(
+			"INSERT INTO %[1]s (%[2]s) SELECT %[3]s FROM %[4]s, generate_series(1, %[4]s.%[5]s) "+
+				"WHERE %[4]s.%[5]s > 0",
+			view, tree.AsString(&q.cols), qualifiedNames(incrementalViewDeltaCTE, q.cols), delta, count,
+		)
+		return []string{del, ins}
+	}
+
+	// Update the existing groups of the view.
+	var set strings.Builder
+	for i := range q.aggs {
+		agg := &q.aggs[i]
+		if i > 0 {
+			set.WriteString(", ")
+		}
+		col := tree.NameString(string(agg.col))
+		aggDelta := fmt.Sprintf("%s.%s_%d", delta, incrementalViewAggCol, i)
+		switch agg.kind {
+		case incrementalViewCountRows, incrementalViewCount:
+			fmt.Fprintf(&set, "%[1]s = %[2]s.%[1]s + %[3]s", col, alias, aggDelta)
+		case incrementalViewSum:
+			fmt.Fprintf(&set,
+				"%[1]s = CASE WHEN %[2]s.%[3]s + %[4]s = 0 THEN NULL "+
+					"WHEN %[2]s.%[1]s IS NULL THEN %[5]s "+
+					"WHEN %[5]s IS NULL THEN %[2]s.%[1]s "+
+					"ELSE %[2]s.%[1]s + %[5]s END",
+				col, alias, tree.NameString(string(agg.countCol)),
+				q.countDelta(agg.countCol), aggDelta,
+			)
+		}
+	}
+	update := with + fmt.Sprintf("UPDATE %s AS %s SET %s FROM %s", view, alias, set.String(), delta)
+	if len(q.groupCols) == 0 {
+		// The view contains a single row for scalar aggregates.
+		return []string{update}
+	}
+	update += " WHERE " + q.matchDelta(q.groupCols)
+
+	// Insert the new groups.
+	var values strings.Builder
+	values.WriteString(qualifiedNames(incrementalViewDeltaCTE, q.groupCols))
+	for i := range q.aggs {
+		agg := &q.aggs[i]
+		aggDelta := fmt.Sprintf("%s.%s_%d", delta, incrementalViewAggCol, i)
+		if agg.kind == incrementalViewSum {
+			fmt.Fprintf(&values, ", CASE WHEN %s = 0 THEN NULL ELSE %s END",
+				q.countDelta(agg.countCol), aggDelta)
+		} else {
+			fmt.Fprintf(&values, ", %s", aggDelta)
+		}
+	}
+	cols := append(append(tree.NameList(nil), q.groupCols...), q.aggCols()...)
+	ins := with + fmt.Sprintf(
+		"INSERT INTO %[1]s (%[2]s) SELECT %[3]s FROM %[4]s WHERE %[5]s > 0 "+
+			"AND NOT EXISTS (SELECT 1 FROM %[1]s AS %[6]s WHERE %[7]s)",
+		view, tree.AsString(&cols), values.String(), delta, q.countDelta(q.countRowsCol),
+		alias, q.matchDelta(q.groupCols),
+	)
+
+	// Remove the groups which no longer contain any rows. Only the groups
+	// changed by the delta can have become empty.
+	del := with + fmt.Sprintf("DELETE FROM %s AS %s USING %s WHERE %s.%s = 0 AND %s",
+		view, alias, delta, alias, tree.NameString(string(q.countRowsCol)), q.matchDelta(q.groupCols))
+	return []string{update, ins, del}
+}
+
+// deltaColDefs returns the column definitions of the delta of the view, as
+// computed by delta.
+func (q *incrementalViewQuery) deltaColDefs() string {
+	var b strings.Builder
+	if !q.aggregate {
+		for i := range q.cols {
+			fmt.Fprintf(&b, "%s %s, ",
+				tree.NameString(string(q.cols[i])), incrementalViewTypeName(q.colTypes[i]))
+		}
+		fmt.Fprintf(&b, "%s INT8", tree.NameString(incrementalViewCountCol))
+		return b.String()
+	}
+	for i := range q.aggs {
+		agg := &q.aggs[i]
+		typName := "INT8"
+		if agg.kind == incrementalViewSum {
+			typName = incrementalViewTypeName(q.colType(agg.col))
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s_%d %s", incrementalViewAggCol, i, typName)
+	}
+	for _, col := range q.groupCols {
+		fmt.Fprintf(&b, ", %s %s", tree.NameString(string(col)), incrementalViewTypeName(q.colType(col)))
+	}
+	return b.String()
+}
+
+// colType returns the type of the given view column.
+func (q *incrementalViewQuery) colType(col tree.Name) *types.T {
+	for i := range q.cols {
+		if q.cols[i] == col {
+			return q.colTypes[i]
+		}
+	}
+	panic(errors.AssertionFailedf("view has no column %s", col))
+}
+
+// incrementalViewTypeName returns the name of the given type, for use in the
+// column definitions of json_to_record and json_to_recordset. User-defined
+// types are referenced by OID, since their names may not resolve in the
+// internal executor.
+func incrementalViewTypeName(typ *types.T) string {
+	if typ.UserDefined() {
+		return (&tree.OIDTypeReference{OID: typ.Oid()}).SQLString()
+	}
+	return typ.SQLString()
+}
+
+// changesCTEs returns the common table expressions with the rows inserted
+// into and deleted from each base table since refreshedAsOf. An updated row is
+// both deleted (the old version) and inserted (the new version).
+func (q *incrementalViewQuery) changesCTEs(refreshedAsOf hlc.Timestamp) string {
+	startTime := tree.AsStringWithFlags(eval.TimestampToDecimalDatum(refreshedAsOf), tree.FmtParsable)
+	var b strings.Builder
+	for i := range q.tables {
+		t := &q.tables[i]
+		var colDefs strings.Builder
+		for j, col := range t.desc.VisibleColumns() {
+			if j > 0 {
+				colDefs.WriteString(", ")
+			}
+			fmt.Fprintf(&colDefs, "%s %s",
+				tree.NameString(col.GetName()), incrementalViewTypeName(col.GetType()))
+		}
+		diff := cteName(incrementalViewDiffCTE, i)
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b,
+			"%[1]s AS MATERIALIZED (SELECT before, after FROM crdb_internal.table_diff(%[2]d::REGCLASS, %[3]s)), "+
+				"%[4]s AS (SELECT r.* FROM %[1]s, json_to_record(%[1]s.after) AS r (%[6]s) WHERE %[1]s.after IS NOT NULL), "+
+				"%[5]s AS (SELECT r.* FROM %[1]s, json_to_record(%[1]s.before) AS r (%[6]s) WHERE %[1]s.before IS NOT NULL)",
+			diff, t.desc.GetID(), startTime,
+			cteName(incrementalViewInsertedCTE, i), cteName(incrementalViewDeletedCTE, i), colDefs.String(),
+		)
+	}
+	return b.String()
+}
+
+// delta returns the query computing the change to the result of the view
+// query. For a query over tables T1, ..., Tn, the change is the sum over i of
+// the query evaluated with T1, ..., Ti-1 as of now, Ti replaced by its
+// inserted rows (counted positively) or its deleted rows (counted
+// negatively), and Ti+1, ..., Tn as of the last refresh. See terms.
+//
+// For queries without aggregates, the delta contains the net number of
+// copies of each row of the result to add or remove. For queries with
+// aggregates, it contains the change to each aggregate of every affected
+// group.
+func (q *incrementalViewQuery) delta() string {
+	var terms strings.Builder
+	for i := range q.tables {
+		for _, term := range q.terms(i) {
+			if terms.Len() > 0 {
+				terms.WriteString(" UNION ALL ")
+			}
+			terms.WriteString(term)
+		}
+	}
+	sign := tree.NameString(incrementalViewSignCol)
+	if !q.aggregate {
+		return fmt.Sprintf(
+			"SELECT %[1]s, sum(%[2]s)::INT8 AS %[3]s FROM (%[4]s) AS __mv_terms (%[1]s, %[2]s) GROUP BY %[1]s",
+			tree.AsString(&q.cols), sign, tree.NameString(incrementalViewCountCol), terms.String(),
+		)
+	}
+
+	var termCols tree.NameList
+	termCols = append(termCols, q.groupCols...)
+	var aggs strings.Builder
+	for i := range q.aggs {
+		agg := &q.aggs[i]
+		if i > 0 {
+			aggs.WriteString(", ")
+		}
+		arg := fmt.Sprintf("%s_%d", incrementalViewArgCol, i)
+		switch agg.kind {
+		case incrementalViewCountRows:
+			fmt.Fprintf(&aggs, "COALESCE(sum(%s), 0)::INT8", sign)
+		case incrementalViewCount:
+			fmt.Fprintf(&aggs, "COALESCE(sum(CASE WHEN %s IS NULL THEN 0 ELSE %s END), 0)::INT8", arg, sign)
+			termCols = append(termCols, tree.Name(arg))
+		case incrementalViewSum:
+			fmt.Fprintf(&aggs, "sum(%s * %s)", arg, sign)
+			termCols = append(termCols, tree.Name(arg))
+		}
+		fmt.Fprintf(&aggs, " AS %s_%d", incrementalViewAggCol, i)
+	}
+	termCols = append(termCols, incrementalViewSignCol)
+	var groupBy string
+	if len(q.groupCols) > 0 {
+		groupBy = fmt.Sprintf(" GROUP BY %s", tree.AsString(&q.groupCols))
+		aggs.WriteString(", ")
+		aggs.WriteString(tree.AsString(&q.groupCols))
+	}
+	return fmt.Sprintf("SELECT %s FROM (%s) AS __mv_terms (%s)%s",
+		aggs.String(), terms.String(), tree.AsString(&termCols), groupBy)
+}
+
+// terms returns the terms of the delta of the view query in which the ith
+// table is replaced by its changes. The tables before the ith one are used as
+// of now, and the tables after it as of the last refresh.
+//
+// The contents of a table as of the last refresh are not read directly, since
+// that would require scanning the whole table. Instead, they are expressed as
+// the current contents of the table, minus its inserted rows, plus its deleted
+// rows, so that each table after the ith one triples the number of terms, which
+// is why the number of tables is limited by incrementalViewMaxTables. In
+// each term, the changes to the ith table are joined with (the current
+// contents or the changes of) the other tables, so the optimizer can restrict
+// the scans of the other tables to the rows joining the changes.
+func (q *incrementalViewQuery) terms(i int) []string {
+	defer func() {
+		for j := range q.tables {
+			q.tables[j].expr.Expr = q.tables[j].name
+		}
+	}()
+	var terms []string
+	var build func(j int, sign int)
+	build = func(j int, sign int) {
+		if j == len(q.tables) {
+			terms = append(terms, q.term(sign))
+			return
+		}
+		t := &q.tables[j]
+		switch {
+		case j < i:
+			t.expr.Expr = t.name
+			build(j+1, sign)
+		case j == i:
+			t.expr.Expr = cteTableName(incrementalViewInsertedCTE, j)
+			build(j+1, sign)
+			t.expr.Expr = cteTableName(incrementalViewDeletedCTE, j)
+			build(j+1, -sign)
+		default:
+			t.expr.Expr = t.name
+			build(j+1, sign)
+			t.expr.Expr = cteTableName(incrementalViewInsertedCTE, j)
+			build(j+1, -sign)
+			t.expr.Expr = cteTableName(incrementalViewDeletedCTE, j)
+			build(j+1, sign)
+		}
+	}
+	build(0, 1 /* sign */)
+	return terms
+}
+
+// term returns one term of the delta of the view query, with the tables
+// replaced as set up by terms. The result contains the view columns (or, for
+// queries with aggregates, the grouping columns and the arguments of the
+// aggregates) followed by the sign of the term.
+func (q *incrementalViewQuery) term(sign int) string {
+	term := tree.SelectClause{From: q.sel.From, Where: q.sel.Where}
+	if !q.aggregate {
+		for _, e := range q.sel.Exprs {
+			term.Exprs = append(term.Exprs, tree.SelectExpr{Expr: e.Expr})
+		}
+	} else {
+		for _, e := range q.groupExprs {
+			term.Exprs = append(term.Exprs, tree.SelectExpr{Expr: e})
+		}
+		for _, agg := range q.aggs {
+			if agg.arg != nil {
+				term.Exprs = append(term.Exprs, tree.SelectExpr{Expr: agg.arg})
+			}
+		}
+	}
+	term.Exprs = append(term.Exprs, tree.SelectExpr{Expr: tree.NewDInt(tree.DInt(sign))})
+	return tree.AsStringWithFlags(&term, tree.FmtParsable)
+}
+
+// matchDelta returns the condition matching the rows of the view (aliased as
+// incrementalViewAlias) with the rows of the delta on the given columns.
+func (q *incrementalViewQuery) matchDelta(cols tree.NameList) string {
+	if len(cols) == 0 {
+		return "true"
+	}
+	var b strings.Builder
+	for i := range cols {
+		if i > 0 {
+			b.WriteString(" AND ")
+		}
+		col := tree.NameString(string(cols[i]))
+		fmt.Fprintf(&b, "%[1]s.%[3]s IS NOT DISTINCT FROM %[2]s.%[3]s",
+			tree.NameString(incrementalViewAlias), tree.NameString(incrementalViewDeltaCTE), col)
+	}
+	return b.String()
+}
+
+// countDelta returns the column of the delta containing the change to the
+// count aggregate computing the given view column.
+func (q *incrementalViewQuery) countDelta(col tree.Name) string {
+	for i := range q.aggs {
+		if q.aggs[i].col == col {
+			return fmt.Sprintf("%s.%s_%d", tree.NameString(incrementalViewDeltaCTE), incrementalViewAggCol, i)
+		}
+	}
+	panic(errors.AssertionFailedf("no aggregate computes column %s", col))
+}
+
+// aggCols returns the view columns computed by aggregates.
+func (q *incrementalViewQuery) aggCols() tree.NameList {
+	cols := make(tree.NameList, len(q.aggs))
+	for i := range q.aggs {
+		cols[i] = q.aggs[i].col
+	}
+	return cols
+}
+
+// qualifiedNames returns the given columns qualified with the given table
+// name, separated by commas.
+func qualifiedNames(table string, cols tree.NameList) string {
+	var b strings.Builder
+	for i := range cols {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s.%s", tree.NameString(table), tree.NameString(string(cols[i])))
+	}
+	return b.String()
+}
+
+// cteName returns the name of the common table expression of the given kind
+// for the ith table of the view query.
+func cteName(kind string, i int) string {
+	return fmt.Sprintf("%s_%d", kind, i)
+}
+
+// cteTableName is like cteName, but returns a table name.
+func cteTableName(kind string, i int) *tree.TableName {
+	tn := tree.MakeUnqualifiedTableName(tree.Name(cteName(kind, i)))
+	return &tn
+}
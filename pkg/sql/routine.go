@@ -10,14 +10,18 @@ import (
 	"strconv"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -108,6 +112,23 @@ func (d *callNode) getResultColumns() colinfo.ResultColumns {
 func (p *planner) EvalRoutineExpr(
 	ctx context.Context, expr *tree.RoutineExpr, args tree.Datums,
 ) (result tree.Datum, err error) {
+	if expr.ResumeAfterTxnControl != nil {
+		if p.storedProcTxnState.getTxnOp() != tree.StoredProcTxnNoOp {
+			// A nested CALL statement has paused execution in order to COMMIT or
+			// ROLLBACK the current transaction. Instead of continuing execution of
+			// the calling procedure, replace the "resume" plan with one that resumes
+			// execution of both the nested and the calling procedure.
+			resumeProc, err := expr.ResumeAfterTxnControl(
+				ctx, args, p.storedProcTxnState.getResumeProc(),
+			)
+			if err != nil {
+				return nil, err
+			}
+			p.storedProcTxnState.setResumeProc(resumeProc.(*memo.Memo))
+			return tree.DNull, nil
+		}
+	}
+
 	// Strict routines (CalledOnNullInput=false) should not be invoked and they
 	// should immediately return NULL if any of their arguments are NULL.
 	if !expr.CalledOnNullInput {
@@ -196,11 +217,140 @@ type triggerDepthKey struct{}
 func (p *planner) RoutineExprGenerator(
 	ctx context.Context, expr *tree.RoutineExpr, args tree.Datums,
 ) eval.ValueGenerator {
+	if expr.ResultBuffer != nil {
+		// This is a set-returning PL/pgSQL routine.
+		g := resultBufferGenerator{expr: expr}
+		g.routine.init(p, expr, args)
+		return &g
+	}
 	var g routineGenerator
 	g.init(p, expr, args)
 	return &g
 }
 
+// resultBufferGenerator is an eval.ValueGenerator that produces the result of a
+// set-returning PL/pgSQL routine. Rather than the result of the last body
+// statement, the output of the routine is the set of rows added to its
+// RoutineResultBuffer by RETURN NEXT and RETURN QUERY statements.
+//
+// The rows are accumulated in a row container that is owned by the
+// resultBufferGenerator rather than the wrapped routineGenerator, since the
+// latter is reset for tail calls and exception handlers. The container spills
+// to disk, so the result set need not fit in memory.
+//
+// Note that the rows are not streamed to the consumer as they are added: the
+// routine runs to completion in Start, and Next then reads the rows back from
+// the container. This is the same as the tuplestore used by Postgres. The
+// routine can't be suspended at a RETURN NEXT statement, since its body
+// statements are executed by nested calls that modify the transaction's
+// stepping mode, the session data stack and the open cursors, which the
+// consumer would observe in between rows.
+type resultBufferGenerator struct {
+	expr     *tree.RoutineExpr
+	routine  routineGenerator
+	rch      rowContainerHelper
+	rci      *rowContainerIterator
+	currVals tree.Datums
+}
+
+var _ eval.ValueGenerator = &resultBufferGenerator{}
+
+// ResolvedType is part of the eval.ValueGenerator interface.
+func (g *resultBufferGenerator) ResolvedType() *types.T {
+	return g.expr.ResolvedType()
+}
+
+// Start is part of the eval.ValueGenerator interface.
+func (g *resultBufferGenerator) Start(ctx context.Context, txn *kv.Txn) (err error) {
+	retTypes, err := routineResultTypes(g.expr)
+	if err != nil {
+		return err
+	}
+	g.rch.Init(ctx, retTypes, g.routine.p.ExtendedEvalContext(), "routine_result_buffer" /* opName */)
+
+	// Direct the rows added by the routine's sub-routines to the container. The
+	// previous writer is restored afterward in case this is a nested invocation
+	// of a recursive routine.
+	buf := g.expr.ResultBuffer
+	prevWriter := buf.Writer
+	buf.Writer = NewRowResultWriter(&g.rch)
+	defer func() { buf.Writer = prevWriter }()
+
+	// Execute the routine to completion. The result of its last body statement
+	// is not part of the output, so it is discarded.
+	err = g.routine.Start(ctx, txn)
+	g.routine.Close(ctx)
+	if err != nil {
+		return err
+	}
+	g.rci = newRowContainerIterator(ctx, g.rch)
+	return nil
+}
+
+// Next is part of the eval.ValueGenerator interface.
+func (g *resultBufferGenerator) Next(ctx context.Context) (bool, error) {
+	var err error
+	g.currVals, err = g.rci.Next()
+	if err != nil {
+		return false, err
+	}
+	return g.currVals != nil, nil
+}
+
+// Values is part of the eval.ValueGenerator interface.
+func (g *resultBufferGenerator) Values() (tree.Datums, error) {
+	return g.currVals, nil
+}
+
+// Close is part of the eval.ValueGenerator interface.
+func (g *resultBufferGenerator) Close(ctx context.Context) {
+	g.routine.Close(ctx)
+	if g.rci != nil {
+		g.rci.Close()
+	}
+	g.rch.Close(ctx)
+	*g = resultBufferGenerator{}
+}
+
+// routineResultTypes returns the types of the columns produced by the given
+// routine.
+func routineResultTypes(expr *tree.RoutineExpr) ([]*types.T, error) {
+	rt := expr.ResolvedType()
+	if expr.MultiColOutput {
+		// A routine with multiple output column should have its types in a tuple.
+		if rt.Family() != types.TupleFamily {
+			return nil, errors.AssertionFailedf("routine expected to return multiple columns")
+		}
+		return rt.TupleContents(), nil
+	}
+	return []*types.T{rt}, nil
+}
+
+// pushSessionVars pushes a copy of the current session data onto the session
+// data stack, and applies the given session variable settings, such as those
+// of a routine or a statement hint, to it. The caller is responsible for
+// popping the stack once the settings no longer apply, which restores the
+// previous values of the variables.
+func (p *planner) pushSessionVars(
+	ctx context.Context, vars []tree.RoutineSessionVar,
+) error {
+	p.EvalContext().SessionDataStack.PushTopClone()
+	m := p.sessionDataMutatorIterator.mutator(false /* applyCallbacks */, p.SessionData())
+	for _, sv := range vars {
+		_, v, err := getSessionVar(sv.Name, false /* missingOk */)
+		if err == nil && v.Set == nil {
+			err = newCannotChangeParameterError(sv.Name)
+		}
+		if err == nil {
+			err = v.Set(ctx, m, sv.Value)
+		}
+		if err != nil {
+			return errors.CombineErrors(err, p.EvalContext().SessionDataStack.Pop())
+		}
+	}
+	return nil
+}
+
 // routineGenerator is an eval.ValueGenerator that produces the result of a
 // routine.
 type routineGenerator struct {
@@ -246,6 +396,18 @@ func (g *routineGenerator) ResolvedType() *types.T {
 
 // Start is part of the eval.ValueGenerator interface.
 func (g *routineGenerator) Start(ctx context.Context, txn *kv.Txn) (err error) {
+	if len(g.expr.SessionVars) > 0 {
+		// Apply the session variables set by the routine for the duration of its
+		// execution. A routine with session variables is never deferred as a tail
+		// call, so the settings also apply to any nested routines that are.
+		p := g.p
+		if err = p.pushSessionVars(ctx, g.expr.SessionVars); err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.CombineErrors(err, p.EvalContext().SessionDataStack.Pop())
+		}()
+	}
 	enabledStepping := false
 	var prevSteppingMode kv.SteppingMode
 	var prevSeqNum enginepb.TxnSeq
@@ -280,16 +442,9 @@ func (g *routineGenerator) Start(ctx context.Context, txn *kv.Txn) (err error) {
 // is cache-able (i.e., there are no arguments to the routine and stepping is
 // disabled).
 func (g *routineGenerator) startInternal(ctx context.Context, txn *kv.Txn) (err error) {
-	rt := g.expr.ResolvedType()
-	var retTypes []*types.T
-	if g.expr.MultiColOutput {
-		// A routine with multiple output column should have its types in a tuple.
-		if rt.Family() != types.TupleFamily {
-			return errors.AssertionFailedf("routine expected to return multiple columns")
-		}
-		retTypes = rt.TupleContents()
-	} else {
-		retTypes = []*types.T{g.expr.ResolvedType()}
+	retTypes, err := routineResultTypes(g.expr)
+	if err != nil {
+		return err
 	}
 	g.rch.Init(ctx, retTypes, g.p.ExtendedEvalContext(), "routine" /* opName */)
 
@@ -313,9 +468,18 @@ func (g *routineGenerator) startInternal(ctx context.Context, txn *kv.Txn) (err
 
 		var w rowResultWriter
 		openCursor := stmtIdx == 1 && g.expr.CursorDeclaration != nil
+		appendToResultBuffer := stmtIdx == 1 && g.expr.AppendToResultBuffer != nil
 		if isFinalPlan {
 			// The result of this statement is the routine's output.
 			w = rrw
+		} else if appendToResultBuffer {
+			// The result of the first statement is added to the result of a
+			// set-returning ancestor routine.
+			bufWriter, ok := g.expr.AppendToResultBuffer.Writer.(rowResultWriter)
+			if !ok {
+				return errors.AssertionFailedf("expected an initialized routine result buffer")
+			}
+			w = bufWriter
 		} else if openCursor {
 			// The result of the first statement will be used to open a SQL cursor.
 			cursorHelper, err = g.newCursorHelper(plan.(*planComponents))
@@ -379,6 +543,8 @@ func (g *routineGenerator) handleException(ctx context.Context, err error) error
 		// It is not safe to catch an uncategorized error.
 		return err
 	}
+	var retryErr *kvpb.TransactionRetryWithProtoRefreshError
+	isRetryErr := errors.As(err, &retryErr)
 	// Attempt to catch the error, starting with the exception handler for the
 	// current block, and propagating the error up to ancestor exception handlers
 	// if necessary.
@@ -387,11 +553,36 @@ func (g *routineGenerator) handleException(ctx context.Context, err error) error
 			// This block has no exception handler.
 			continue
 		}
+		if isRetryErr && !blockState.InProcedure {
+			// Catching a retryable error moves the transaction to a new read
+			// snapshot. Outside a procedure, the rest of the enclosing statement
+			// would observe the new snapshot in addition to the old one, so the error
+			// is propagated instead. See tree.BlockState.InProcedure.
+			if blockState.ExceptionHandler.HasCode(caughtCode) {
+				// The block explicitly handles this error, but its handler cannot run.
+				// Make the reason visible rather than silently skipping the handler.
+				err = errors.WithHintf(err,
+					"the %s exception handler was not run because transaction retry "+
+						"errors can only be caught by the exception handlers of procedures",
+					caughtCode)
+			}
+			return err
+		}
 		if !g.p.Txn().CanUseSavepoint(ctx, blockState.SavepointTok.(kv.SavepointToken)) {
-			// The current transaction state does not allow roll-back.
-			// TODO(111446): some retryable errors allow the transaction to be rolled
-			// back partially (e.g. for read committed). We should be able to take
-			// advantage of that mechanism here as well.
+			// The current transaction state does not allow roll-back. Note that
+			// retryable errors that only require a partial retry of the transaction
+			// (e.g. for read committed) do allow roll-back. Those that require the
+			// transaction to restart from the beginning only allow roll-back to a
+			// savepoint taken before any activity in the transaction; otherwise,
+			// they are propagated so that the transaction can be retried as a whole.
+			if isRetryErr && blockState.ExceptionHandler.HasCode(caughtCode) {
+				// The block explicitly handles this error, but its handler cannot run.
+				// Make the reason visible rather than silently skipping the handler.
+				err = errors.WithHintf(err,
+					"the %s exception handler was not run because the transaction must be "+
+						"retried from the beginning, and it had already done work before "+
+						"the block was entered", caughtCode)
+			}
 			return err
 		}
 		// Unset the exception handler to indicate that it has already encountered an
@@ -423,6 +614,27 @@ func (g *routineGenerator) handleException(ctx context.Context, err error) error
 				// This error is unexpected, so return immediately.
 				return errors.CombineErrors(err, errors.WithAssertionFailure(spErr))
 			}
+			if isRetryErr {
+				// The error was a retryable error. Now that the transaction has been
+				// rolled back to the block's savepoint, clear the error and step the
+				// transaction so that the exception handler observes the new read
+				// snapshot. If the transaction must restart from the beginning, the
+				// savepoint was taken before any activity in the transaction (see
+				// CanUseSavepoint), so the restart loses no work done outside the
+				// block.
+				var prepErr error
+				if retryErr.TxnMustRestartFromBeginning() {
+					prepErr = g.p.Txn().PrepareForRetry(ctx)
+				} else {
+					prepErr = g.p.Txn().PrepareForPartialRetry(ctx)
+				}
+				if prepErr != nil {
+					return errors.CombineErrors(err, errors.WithAssertionFailure(prepErr))
+				}
+				if stepErr := g.p.Txn().Step(ctx, false /* allowReadTimestampStep */); stepErr != nil {
+					return errors.CombineErrors(err, errors.WithAssertionFailure(stepErr))
+				}
+			}
 			// Truncate the arguments using the number of variables in scope for the
 			// current block. This is necessary because the error may originate from
 			// a child block, but propagate up to a parent block. See the BlockState
@@ -532,6 +744,11 @@ var tailCallOptimizationEnabled = metamorphic.ConstantWithTestBool(
 )
 
 func (g *routineGenerator) CanOptimizeTailCall(nestedRoutine *tree.RoutineExpr) bool {
+	if len(nestedRoutine.SessionVars) > 0 {
+		// The session variables of the nested routine must only be applied while
+		// it is executing, so it cannot be deferred to the parent routine.
+		return false
+	}
 	// Tail-call optimization is allowed only if the current routine will not
 	// perform any work after its body statements finish executing.
 	//
@@ -567,6 +784,16 @@ func (g *routineGenerator) newCursorHelper(plan *planComponents) (*plpgsqlCursor
 		return nil, errors.AssertionFailedf("expected non-null cursor name")
 	}
 	cursorName := tree.Name(tree.MustBeDString(g.args[open.NameArgIdx]))
+	return newPLpgSQLCursorHelper(g.p, cursorName, open.CursorSQL, plan.main.planColumns())
+}
+
+// newPLpgSQLCursorHelper returns a plpgsqlCursorHelper with an initialized
+// row container for the rows of a PL/pgSQL cursor with the given name and
+// result columns. The caller is responsible for closing the helper if the
+// cursor is never created.
+func newPLpgSQLCursorHelper(
+	p *planner, cursorName tree.Name, cursorSQL string, resultCols colinfo.ResultColumns,
+) (*plpgsqlCursorHelper, error) {
 	if cursorName == "" {
 		// Specifying the empty string as a cursor name conflicts with the
 		// "unnamed" portal, which always exists.
@@ -574,26 +801,25 @@ func (g *routineGenerator) newCursorHelper(plan *planComponents) (*plpgsqlCursor
 	}
 	// Use context.Background(), since the cursor can outlive the context in which
 	// it was created.
-	planCols := plan.main.planColumns()
 	cursorHelper := &plpgsqlCursorHelper{
 		ctx:        context.Background(),
 		cursorName: cursorName,
-		resultCols: make(colinfo.ResultColumns, len(planCols)),
-		cursorSql:  open.CursorSQL,
+		resultCols: make(colinfo.ResultColumns, len(resultCols)),
+		cursorSql:  cursorSQL,
 	}
-	copy(cursorHelper.resultCols, planCols)
-	mon := g.p.Mon()
-	if !g.p.SessionData().CloseCursorsAtCommit {
-		mon = g.p.sessionMonitor
+	copy(cursorHelper.resultCols, resultCols)
+	mon := p.Mon()
+	if !p.SessionData().CloseCursorsAtCommit {
+		mon = p.sessionMonitor
 		if mon == nil {
 			return nil, errors.AssertionFailedf("cannot open cursor WITH HOLD without an active session")
 		}
 	}
 	cursorHelper.container.InitWithParentMon(
 		cursorHelper.ctx,
-		getTypesFromResultColumns(planCols),
+		getTypesFromResultColumns(resultCols),
 		mon,
-		g.p.ExtendedEvalContextCopy(),
+		p.ExtendedEvalContextCopy(),
 		"routine_open_cursor", /* opName */
 	)
 	return cursorHelper, nil
@@ -685,6 +911,71 @@ func (h *plpgsqlCursorHelper) HasResults() bool {
 	return h.lastRow != nil
 }
 
+// PLpgSQLExecuteDynamic executes the given query string, binding the given
+// parameters to its placeholders. It is used to implement the PLpgSQL EXECUTE
+// statement. The query is executed with the internal executor in the current
+// transaction. PLpgSQLExecuteDynamic returns the first row of the result (if
+// any), as well as the number of rows returned by the query.
+//
+// Transaction control statements are rejected, as in Postgres. SET statements
+// are also rejected, since the internal executor doesn't propagate changes to
+// session variables back to the session.
+func (p *planner) PLpgSQLExecuteDynamic(
+	ctx context.Context, query string, params tree.Datums,
+) (firstRow tree.Datums, rowCount int, err error) {
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	switch stmt.AST.(type) {
+	case *tree.BeginTransaction, *tree.CommitTransaction, *tree.RollbackTransaction,
+		*tree.Savepoint, *tree.ReleaseSavepoint, *tree.RollbackToSavepoint,
+		*tree.SetTransaction, *tree.SetSessionCharacteristics:
+		return nil, 0, pgerror.New(pgcode.FeatureNotSupported,
+			"EXECUTE of transaction commands is not implemented",
+		)
+	case *tree.SetVar, *tree.SetSessionAuthorizationDefault, *tree.SetTracing:
+		return nil, 0, pgerror.Newf(pgcode.FeatureNotSupported,
+			"EXECUTE of %s is not supported", stmt.AST.StatementTag(),
+		)
+	}
+	rows, err := p.InternalSQLTxn().QueryIteratorEx(
+		ctx, "plpgsql-execute", p.Txn(), sessiondata.NoSessionDataOverride,
+		query, datumsToQueryArgs(params)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		err = errors.CombineErrors(err, rows.Close())
+	}()
+	// Consume all rows, since the query may have side effects.
+	for {
+		ok, err := rows.Next(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			break
+		}
+		if rowCount == 0 {
+			firstRow = rows.Cur()
+		}
+		rowCount++
+	}
+	return firstRow, rowCount, nil
+}
+
+// datumsToQueryArgs converts the given datums into arguments for a query
+// executed by the internal executor.
+func datumsToQueryArgs(datums tree.Datums) []interface{} {
+	args := make([]interface{}, len(datums))
+	for i := range datums {
+		args[i] = datums[i]
+	}
+	return args
+}
+
 // storedProcTxnStateAccessor provides a method for stored procedures to request
 // that the current transaction be committed or aborted and supply a
 // continuation stored procedure to resume execution in the new transaction.
@@ -703,6 +994,13 @@ func (a *storedProcTxnStateAccessor) setStoredProcTxnState(
 	a.ex.extraTxnState.storedProcTxnState.resumeProc = resumeProc
 }
 
+func (a *storedProcTxnStateAccessor) setResumeProc(resumeProc *memo.Memo) {
+	if a.ex == nil {
+		panic(errors.AssertionFailedf("setResumeProc is not supported without connExecutor"))
+	}
+	a.ex.extraTxnState.storedProcTxnState.resumeProc = resumeProc
+}
+
 func (a *storedProcTxnStateAccessor) getTxnOp() tree.StoredProcTxnOp {
 	if a.ex == nil {
 		return tree.StoredProcTxnNoOp
@@ -746,6 +1044,39 @@ func (p *planner) EvalTxnControlExpr(
 	if err != nil {
 		return nil, err
 	}
-	p.storedProcTxnState.setStoredProcTxnState(expr.Op, &expr.Modes, resumeProc.(*memo.Memo))
+	txnModes := &expr.Modes
+	if expr.Chain {
+		txnModes = p.chainedTxnModes(txnModes)
+	}
+	p.storedProcTxnState.setStoredProcTxnState(expr.Op, txnModes, resumeProc.(*memo.Memo))
 	return tree.DNull, nil
 }
+
+// chainedTxnModes returns the transaction modes for the new transaction that
+// is started by a COMMIT AND CHAIN or ROLLBACK AND CHAIN statement. The new
+// transaction has the same characteristics as the current one, except for
+// those that were explicitly set by SET TRANSACTION statements.
+func (p *planner) chainedTxnModes(explicit *tree.TransactionModes) *tree.TransactionModes {
+	modes := *explicit
+	txn := p.Txn()
+	if modes.Isolation == tree.UnspecifiedIsolation {
+		modes.Isolation = tree.FromKVIsoLevel(txn.IsoLevel())
+	}
+	if modes.UserPriority == tree.UnspecifiedUserPriority {
+		switch txn.UserPriority() {
+		case roachpb.MinUserPriority:
+			modes.UserPriority = tree.Low
+		case roachpb.MaxUserPriority:
+			modes.UserPriority = tree.High
+		default:
+			modes.UserPriority = tree.Normal
+		}
+	}
+	if modes.ReadWriteMode == tree.UnspecifiedReadWriteMode && modes.AsOf.Expr == nil {
+		modes.ReadWriteMode = tree.ReadWrite
+		if p.EvalContext().TxnReadOnly {
+			modes.ReadWriteMode = tree.ReadOnly
+		}
+	}
+	return &modes
+}
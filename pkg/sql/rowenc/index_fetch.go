@@ -12,6 +12,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
 	"github.com/cockroachdb/cockroach/pkg/util/buildutil"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/intsets"
 	"github.com/cockroachdb/errors"
 )
 
@@ -81,11 +82,13 @@ func InitIndexFetchSpec(
 	} else {
 		s.FetchedColumns = make([]fetchpb.IndexFetchSpec_Column, len(fetchColumnIDs))
 	}
+	var neededColOrdinals intsets.Fast
 	for i, colID := range fetchColumnIDs {
 		col, err := catalog.MustFindColumnByID(table, colID)
 		if err != nil {
 			return err
 		}
+		neededColOrdinals.Add(col.Ordinal())
 		typ := col.GetType()
 		if colID == invertedColumnID {
 			typ = index.InvertedColumnKeyType()
@@ -98,6 +101,10 @@ func InitIndexFetchSpec(
 		}
 	}
 
+	if table.IsFamilyPerColumn() {
+		s.NeededFamilyIDs = neededFamilyIDsForFamilyPerColumn(table, index, neededColOrdinals)
+	}
+
 	// In test builds, verify that we aren't trying to fetch columns that are not
 	// available in the index.
 	if buildutil.CrdbTestBuild && s.IsSecondaryIndex {
@@ -113,3 +120,33 @@ func InitIndexFetchSpec(
 
 	return nil
 }
+
+// neededFamilyIDsForFamilyPerColumn returns the IDs of the column families that
+// must be read in order to fetch the given columns from an index of a table
+// with family_per_column set, or nil if all column families must be read. Such
+// a table stores each column in its own column family, so the returned
+// families correspond to the needed columns.
+func neededFamilyIDsForFamilyPerColumn(
+	table catalog.TableDescriptor, index catalog.Index, neededColOrdinals intsets.Fast,
+) []descpb.FamilyID {
+	if table.NumFamilies() == 1 {
+		return nil
+	}
+	// Only the values of primary indexes and of forward secondary indexes with
+	// a new enough encoding are split by column family.
+	if !index.Primary() && (index.GetType() != descpb.IndexDescriptor_FORWARD ||
+		index.GetVersion() < descpb.SecondaryIndexFamilyFormatVersion) {
+		return nil
+	}
+	familyIDs := NeededColumnFamilyIDs(neededColOrdinals, table, index, false /* forSideEffect */)
+	if familyIDs[0] != 0 {
+		// Column family 0 is the only family that has a KV for every row, so
+		// we always include it in order for the boundaries between rows to be
+		// easy to detect.
+		familyIDs = append([]descpb.FamilyID{0}, familyIDs...)
+	}
+	if len(familyIDs) == table.NumFamilies() {
+		return nil
+	}
+	return familyIDs
+}
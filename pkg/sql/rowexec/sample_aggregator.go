@@ -100,9 +100,6 @@ func newSampleAggregator(
 		if s.GenerateHistogram && s.HistogramMaxBuckets == 0 {
 			return nil, errors.Errorf("histogram max buckets not specified")
 		}
-		if s.GenerateHistogram && len(s.Columns) != 1 {
-			return nil, errors.Errorf("histograms require one column")
-		}
 	}
 
 	// Limit the memory use by creating a child monitor with a hard limit.
@@ -149,7 +146,9 @@ func newSampleAggregator(
 			numRows:  0,
 		}
 		if spec.Sketches[i].GenerateHistogram {
-			sampleCols.Add(int(spec.Sketches[i].Columns[0]))
+			for _, col := range spec.Sketches[i].Columns {
+				sampleCols.Add(int(col))
+			}
 		}
 	}
 
@@ -457,7 +456,13 @@ func (s *sampleAggregator) writeResults(ctx context.Context) error {
 	if err := s.FlowCtx.Cfg.DB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
 		for _, si := range s.sketches {
 			var histogram *stats.HistogramData
-			if si.spec.GenerateHistogram {
+			if si.spec.GenerateHistogram && len(si.spec.Columns) > 1 {
+				h, err := s.generateMultiColumnHistogram(ctx, s.FlowCtx.EvalCtx, &si)
+				if err != nil {
+					return err
+				}
+				histogram = &h
+			} else if si.spec.GenerateHistogram {
 				colIdx := int(si.spec.Columns[0])
 				typ := s.inTypes[colIdx]
 
@@ -675,6 +680,49 @@ func (s *sampleAggregator) generateHistogram(
 	return h, err
 }
 
+// generateMultiColumnHistogram returns a histogram on the tuples of values of
+// the columns in the given sketch from a set of samples. Rows that have a NULL
+// value in any of the columns are excluded from the histogram.
+func (s *sampleAggregator) generateMultiColumnHistogram(
+	ctx context.Context, evalCtx *eval.Context, si *sketchInfo,
+) (stats.HistogramData, error) {
+	colIdxs := make([]int, len(si.spec.Columns))
+	colTypes := make([]*types.T, len(si.spec.Columns))
+	for i, c := range si.spec.Columns {
+		colIdxs[i] = int(c)
+		colTypes[i] = s.inTypes[c]
+	}
+	typ := types.MakeTuple(colTypes)
+
+	prevCapacity := s.sr.Cap()
+	values, err := s.sr.GetNonNullTuples(ctx, &s.tempMemAcc, colIdxs, typ)
+	if err != nil {
+		return stats.HistogramData{}, err
+	}
+	if s.sr.Cap() != prevCapacity {
+		log.Infof(
+			ctx, "histogram samples reduced from %d to %d due to excessive memory utilization",
+			prevCapacity, s.sr.Cap(),
+		)
+	}
+
+	// Estimate the number of rows without NULL values in any of the columns
+	// based on the fraction of such rows in the samples.
+	var numRows int64
+	if numSamples := int64(len(s.sr.Get())); numSamples > 0 {
+		numRows = si.numRows * int64(len(values)) / numSamples
+	}
+	// The distinct count of the sketch includes tuples that have NULL values in
+	// some of the columns, so make sure it doesn't exceed the row count.
+	distinctCount := s.getDistinctCount(si, false /* includeNulls */)
+	distinctCount = max(min(distinctCount, numRows), 1)
+
+	h, _, err := stats.EquiDepthHistogram(
+		ctx, evalCtx, typ, values, numRows, distinctCount, int(si.spec.HistogramMaxBuckets), evalCtx.Settings,
+	)
+	return h, err
+}
+
 var _ execinfra.DoesNotUseTxn = &sampleAggregator{}
 
 // DoesNotUseTxn implements the DoesNotUseTxn interface.
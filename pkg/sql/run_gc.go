@@ -0,0 +1,132 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+type runGCNode struct {
+	optColumnsSlot
+
+	run runGCRun
+}
+
+// RunGC runs ranges through the MVCC GC queue on their leaseholders, bypassing
+// the queue's scoring heuristics
+// (`ALTER RANGE .. GC` and `ALTER TABLE .. GC NOW` statements).
+// Privileges: REPAIRCLUSTER for ranges, INSERT on table for tables.
+func (p *planner) RunGC(ctx context.Context, n *tree.RunGC) (planNode, error) {
+	if !p.ExecCfg().Codec.ForSystemTenant() {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is only supported on the system tenant", n.StatementTag())
+	}
+
+	if n.RangeID == nil {
+		_, tableDesc, _, err := p.getTableAndIndex(ctx, &n.TableOrIndex, privilege.INSERT, true /* skipCache */)
+		if err != nil {
+			return nil, err
+		}
+		return &runGCNode{
+			run: runGCRun{span: tableDesc.TableSpan(p.ExecCfg().Codec)},
+		}, nil
+	}
+
+	if err := p.CheckPrivilege(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.REPAIRCLUSTER,
+	); err != nil {
+		return nil, err
+	}
+	typedExpr, err := p.analyzeExpr(
+		ctx, n.RangeID, tree.IndexedVarHelper{}, types.Int, true /* requireType */, "GC",
+	)
+	if err != nil {
+		return nil, err
+	}
+	d, err := eval.Expr(ctx, p.EvalContext(), typedExpr)
+	if err != nil {
+		return nil, err
+	}
+	if d == tree.DNull {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "range ID must not be NULL")
+	}
+	return &runGCNode{
+		run: runGCRun{rangeID: roachpb.RangeID(tree.MustBeDInt(d))},
+	}, nil
+}
+
+// runGCRun contains the run-time state of runGCNode during local execution.
+type runGCRun struct {
+	// Exactly one of rangeID and span is set.
+	rangeID roachpb.RangeID
+	span    roachpb.Span
+
+	rangeIdx int
+	ranges   []kvpb.AdminRunGCResponse_Range
+}
+
+func (n *runGCNode) startExec(params runParams) error {
+	span := n.run.span
+	if n.run.rangeID != 0 {
+		execCfg := params.ExecCfg()
+		rangeDescIterator, err := execCfg.RangeDescIteratorFactory.NewIterator(
+			params.ctx, execCfg.Codec.TenantSpan(),
+		)
+		if err != nil {
+			return err
+		}
+		for ; rangeDescIterator.Valid(); rangeDescIterator.Next() {
+			if desc := rangeDescIterator.CurRangeDescriptor(); desc.RangeID == n.run.rangeID {
+				span = desc.KeySpan().AsRawSpanWithNoLocals()
+				break
+			}
+		}
+		if len(span.Key) == 0 {
+			return errors.Errorf("descriptor for range %d is not found", n.run.rangeID)
+		}
+	}
+
+	res, err := params.ExecCfg().DB.AdminRunGC(params.ctx, span)
+	if err != nil {
+		return err
+	}
+	n.run.rangeIdx = -1
+	n.run.ranges = res.Ranges
+	return nil
+}
+
+func (n *runGCNode) Next(params runParams) (bool, error) {
+	n.run.rangeIdx++
+	return n.run.rangeIdx < len(n.run.ranges), nil
+}
+
+func (n *runGCNode) Values() tree.Datums {
+	r := n.run.ranges[n.run.rangeIdx]
+	result := "ok"
+	if r.Error != "" {
+		result = r.Error
+	}
+	return tree.Datums{
+		tree.NewDInt(tree.DInt(r.RangeID)),
+		tree.NewDInt(tree.DInt(r.GarbageBytesBefore)),
+		tree.NewDInt(tree.DInt(r.GarbageBytesAfter)),
+		tree.NewDInt(tree.DInt(r.GarbageBytesBefore - r.GarbageBytesAfter)),
+		tree.NewDString(result),
+	}
+}
+
+func (*runGCNode) Close(ctx context.Context) {}
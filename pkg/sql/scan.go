@@ -70,6 +70,11 @@ type scanNode struct {
 	// See exec.Factory.ConstructScan.
 	parallelize bool
 
+	// splitWithinRanges indicates whether the scan might be split into
+	// sub-spans that are read concurrently when the plan is local. See
+	// canSplitScanWithinRanges.
+	splitWithinRanges bool
+
 	// Is this a full scan of an index?
 	isFull bool
 
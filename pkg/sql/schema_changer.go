@@ -556,6 +556,11 @@ func (sc *SchemaChanger) maybeMakeAddTablePublic(
 			return nil
 		}
 		mut.State = descpb.DescriptorState_PUBLIC
+		if mut.MaterializedView() && !mut.IsRefreshViewRequired() {
+			// The view was backfilled as of its creation time, so an incremental
+			// refresh can pick up the changes from there.
+			mut.ViewRefreshedAsOf = mut.CreateAsOfTime
+		}
 		return txn.Descriptors().WriteDesc(ctx, true /* kvTrace */, mut, txn.KV())
 	})
 }
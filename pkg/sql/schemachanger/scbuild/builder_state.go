@@ -440,6 +440,18 @@ func (b *builderState) NextColumnFamilyID(table *scpb.Table) (ret catid.FamilyID
 	return ret
 }
 
+// IsFamilyPerColumn implements the scbuildstmt.TableHelpers interface.
+func (b *builderState) IsFamilyPerColumn(table *scpb.Table) bool {
+	b.ensureDescriptor(table.TableID)
+	desc := b.descCache[table.TableID].desc
+	tbl, ok := desc.(catalog.TableDescriptor)
+	if !ok {
+		panic(errors.AssertionFailedf("Expected table descriptor for ID %d, instead got %s",
+			desc.GetID(), desc.DescriptorType()))
+	}
+	return tbl.IsFamilyPerColumn()
+}
+
 // NextTableIndexID implements the scbuildstmt.TableHelpers interface.
 func (b *builderState) NextTableIndexID(tableID catid.DescID) (ret catid.IndexID) {
 	return b.nextIndexID(tableID)
@@ -1410,6 +1422,11 @@ func (b *builderState) ResolveRoutine(
 		)
 	}
 
+	// Aggregate functions are only supported by the legacy schema changer.
+	if ol.Class == tree.AggregateClass {
+		panic(scerrors.NotImplementedErrorf(nil, "aggregate functions"))
+	}
+
 	fnID := funcdesc.UserDefinedFunctionOIDToID(ol.Oid)
 	if p.RequireOwnership {
 		b.mustOwn(fnID)
@@ -216,6 +216,15 @@ func alterTableAddColumn(
 		} else if d.Family.Create && !d.Family.IfNotExists {
 			panic(errors.Errorf("family %q already exists", d.Family.Name))
 		}
+	} else if !desc.Virtual && b.IsFamilyPerColumn(tbl) {
+		// Tables with family_per_column set store each column in its own family.
+		familyID := b.NextColumnFamilyID(tbl)
+		spec.fam = &scpb.ColumnFamily{
+			TableID:  tbl.TableID,
+			FamilyID: familyID,
+			Name:     tabledesc.GeneratedFamilyName(familyID, []string{string(d.Name)}),
+		}
+		spec.colType.FamilyID = familyID
 	}
 	if desc.HasDefault() {
 		expression := b.WrapExpression(tbl.TableID, cdd.DefaultExpr)
@@ -28,6 +28,14 @@ func CreateFunction(b BuildCtx, n *tree.CreateRoutine) {
 	if n.Replace {
 		panic(scerrors.NotImplementedError(n))
 	}
+	for _, option := range n.Options {
+		switch option.(type) {
+		case tree.RoutineCost, tree.RoutineRows, tree.RoutineParallel, *tree.RoutineSetVar:
+			// These options are not yet represented by schema changer elements, so
+			// fall back to the legacy schema changer.
+			panic(scerrors.NotImplementedErrorf(n, "routine option %s", tree.AsString(option)))
+		}
+	}
 	b.IncrementSchemaChangeCreateCounter("function")
 
 	dbElts, scElts := b.ResolveTargetObject(n.Name.ToUnresolvedObjectName(), privilege.CREATE)
@@ -7,14 +7,17 @@ package scbuildstmt
 
 import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
-// CreateTrigger creates a new trigger on a table in the declarative schema
+// CreateTrigger creates a new trigger on a table or view in the declarative schema
 // changer. It expects that the CREATE TRIGGER statement has already been
 // validated, except for cross-DB references.
 func CreateTrigger(b BuildCtx, n *tree.CreateTrigger) {
@@ -35,8 +38,8 @@ func CreateTrigger(b BuildCtx, n *tree.CreateTrigger) {
 	validateFunctionRelationReferences(b, refProvider, namespace.DatabaseID)
 	validateFunctionToFunctionReferences(b, refProvider, namespace.DatabaseID)
 
-	_, _, tbl := scpb.FindTable(relationElements)
-	tableID, triggerID := tbl.TableID, b.NextTableTriggerID(tbl.TableID)
+	tableID := triggerRelationID(relationElements, n.TableName)
+	triggerID := b.NextTableTriggerID(tableID)
 
 	b.Add(&scpb.Trigger{
 		TableID:   tableID,
@@ -145,3 +148,17 @@ func CreateTrigger(b BuildCtx, n *tree.CreateTrigger) {
 		UsesRoutineIDs:  refProvider.ReferencedRoutines().Ordered(),
 	})
 }
+
+// triggerRelationID returns the ID of the relation on which a trigger is
+// defined, which is either a table or, for INSTEAD OF triggers, a view.
+func triggerRelationID(
+	relationElements ElementResultSet, name *tree.UnresolvedObjectName,
+) catid.DescID {
+	if _, _, tbl := scpb.FindTable(relationElements); tbl != nil {
+		return tbl.TableID
+	}
+	if _, _, view := scpb.FindView(relationElements); view != nil {
+		return view.ViewID
+	}
+	panic(pgerror.Newf(pgcode.WrongObjectType, "%q is not a table or view", tree.ErrString(name)))
+}
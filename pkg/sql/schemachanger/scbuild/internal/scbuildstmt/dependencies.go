@@ -262,6 +262,10 @@ type TableHelpers interface {
 	// family added to this table.
 	NextColumnFamilyID(table *scpb.Table) catid.FamilyID
 
+	// IsFamilyPerColumn returns whether each new column of this table should be
+	// stored in its own column family.
+	IsFamilyPerColumn(table *scpb.Table) bool
+
 	// NextTableIndexID returns the ID that should be used for any new index added
 	// to this table.
 	NextTableIndexID(tableID catid.DescID) catid.IndexID
@@ -18,20 +18,21 @@ func DropTrigger(b BuildCtx, n *tree.DropTrigger) {
 		panic(unimplemented.NewWithIssue(128151, "cascade dropping triggers"))
 	}
 
-	// NOTE: DROP TRIGGER requires the user to have ownership of the table.
-	tableElems := b.ResolveTable(n.Table, ResolveParams{
+	// NOTE: DROP TRIGGER requires the user to have ownership of the table or
+	// view.
+	relationElems := b.ResolveRelation(n.Table, ResolveParams{
 		IsExistenceOptional: n.IfExists,
 		RequireOwnership:    true,
 	})
-	_, _, tbl := scpb.FindTable(tableElems)
-	if tbl == nil {
-		// IF EXISTS was true and the table was not found.
+	if relationElems == nil {
+		// IF EXISTS was true and the relation was not found.
 		noticeSender.BufferClientNotice(b,
 			pgnotice.Newf("relation \"%v\" does not exist, skipping", n.Table))
 		return
 	}
 
-	triggerElems := b.ResolveTrigger(tbl.TableID, n.Trigger, ResolveParams{
+	tableID := triggerRelationID(relationElems, n.Table)
+	triggerElems := b.ResolveTrigger(tableID, n.Trigger, ResolveParams{
 		IsExistenceOptional: n.IfExists,
 	})
 	_, _, trigger := scpb.FindTrigger(triggerElems)
@@ -1387,6 +1387,7 @@ const sizeOfStatementStatistics = int64(unsafe.Sizeof(aggStatementStatistics{}))
 const sizeOfAggStatementMetadata = int64(unsafe.Sizeof(aggStatementMetadata{}))
 const sizeOfTransactionStatistics = int64(unsafe.Sizeof(aggTransactionStatistics{}))
 const sizeOfAggregatedStmtMetadataAggregate = int64(unsafe.Sizeof(aggregatedStmtMetadataAggregate{}))
+const sizeOfUserDefinedAggregate = int64(unsafe.Sizeof(userDefinedAggregate{}))
 
 // aggregateWithIntermediateResult is a common interface for aggregate functions
 // which can return a result without loss of precision. This is useful when an
@@ -1578,6 +1579,117 @@ func (a *anyNotNullAggregate) Size() int64 {
 	return sizeOfAnyNotNullAggregate
 }
 
+// userDefinedAggregate evaluates an aggregate created with CREATE AGGREGATE.
+// The state is advanced by invoking the state transition routine for each
+// input row, and the final routine, if any, is applied to the state to produce
+// the result.
+type userDefinedAggregate struct {
+	singleDatumAggregateBase
+
+	evalCtx    *eval.Context
+	transition *tree.RoutineExpr
+	final      *tree.RoutineExpr
+	initCond   tree.Datum
+	strict     bool
+
+	state tree.Datum
+	// seeded is false if the state transition function is strict, there is no
+	// initial condition, and no row with non-NULL arguments has been added yet.
+	seeded bool
+	// args is reused to pass the state and the arguments of each row to the
+	// transition routine.
+	args tree.Datums
+}
+
+// NewUserDefinedAggregate returns a constructor of an aggregate that invokes
+// the given routines. transition is invoked with the current state followed by
+// the arguments of each row, and final, if not nil, is invoked with the state
+// to produce the result. The arguments of each row are passed to Add as a
+// single tuple.
+//
+// If strict is true, rows with a NULL argument are skipped, and if initCond is
+// NULL, the first argument of the first row that is not skipped becomes the
+// initial state. This matches the semantics of aggregates with a STRICT state
+// transition function in Postgres.
+func NewUserDefinedAggregate(
+	transition, final *tree.RoutineExpr, initCond tree.Datum, strict bool,
+) func(*eval.Context, tree.Datums) eval.AggregateFunc {
+	return func(evalCtx *eval.Context, _ tree.Datums) eval.AggregateFunc {
+		return &userDefinedAggregate{
+			singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+			evalCtx:                  evalCtx,
+			transition:               transition,
+			final:                    final,
+			initCond:                 initCond,
+			strict:                   strict,
+			state:                    initCond,
+			seeded:                   !strict || initCond != tree.DNull,
+		}
+	}
+}
+
+// Add advances the state with the arguments in the given tuple.
+func (a *userDefinedAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	tuple, ok := tree.AsDTuple(datum)
+	if !ok {
+		return errors.AssertionFailedf("expected tuple of user-defined aggregate arguments, found %s", datum)
+	}
+	if a.strict {
+		for _, d := range tuple.D {
+			if d == tree.DNull {
+				return nil
+			}
+		}
+		if !a.seeded {
+			a.seeded = true
+			if len(tuple.D) > 0 {
+				a.state = tuple.D[0]
+			}
+			return a.updateMemoryUsage(ctx, int64(a.state.Size()))
+		}
+		if a.state == tree.DNull {
+			// A strict transition function returns NULL for a NULL state.
+			return nil
+		}
+	}
+	a.args = append(a.args[:0], a.state)
+	a.args = append(a.args, tuple.D...)
+	state, err := a.evalCtx.Planner.EvalRoutineExpr(ctx, a.transition, a.args)
+	if err != nil {
+		return err
+	}
+	a.state = state
+	return a.updateMemoryUsage(ctx, int64(a.state.Size()))
+}
+
+// Result returns the result of the final routine applied to the state, or the
+// state itself if there is no final routine.
+func (a *userDefinedAggregate) Result() (tree.Datum, error) {
+	if a.final == nil {
+		return a.state, nil
+	}
+	// Result is not passed a context. See stMakeLineAgg.Result.
+	ctx := context.Background()
+	return a.evalCtx.Planner.EvalRoutineExpr(ctx, a.final, tree.Datums{a.state})
+}
+
+// Reset implements eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Reset(ctx context.Context) {
+	a.state = a.initCond
+	a.seeded = !a.strict || a.initCond != tree.DNull
+	a.reset(ctx)
+}
+
+// Close is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Close(ctx context.Context) {
+	a.close(ctx)
+}
+
+// Size is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Size() int64 {
+	return sizeOfUserDefinedAggregate
+}
+
 type arrayAggregate struct {
 	arr *tree.DArray
 	// Note that we do not embed singleDatumAggregateBase struct to help with
@@ -8096,6 +8096,98 @@ specified store on the node it's run from. One of 'mvccGC', 'merge', 'split',
 		makeRequestStatementBundleBuiltinOverload(true /* withPlanGist */, true /* withAntiPlanGist */, true /* redacted */),
 	),
 
+	"crdb_internal.pin_statement_plan": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         builtinconstants.CategorySystemInfo,
+			DistsqlBlocklist: true, // applicable only on the gateway
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "fingerprint", Typ: types.String},
+				{Name: "plan_gist", Typ: types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if err := evalCtx.SessionAccessor.CheckPrivilege(
+					ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.MODIFYCLUSTERSETTING,
+				); err != nil {
+					return nil, err
+				}
+				fingerprint := string(tree.MustBeDString(args[0]))
+				planGist := string(tree.MustBeDString(args[1]))
+				if err := evalCtx.Planner.PinStatementPlan(ctx, fingerprint, planGist); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "Pins the plan with the given gist for the given statement fingerprint. " +
+				"The optimizer is constrained to use the indexes and join algorithms of the " +
+				"pinned plan when planning statements with the fingerprint. Any previously " +
+				"pinned plan for the fingerprint is retired.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.unpin_statement_plan": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         builtinconstants.CategorySystemInfo,
+			DistsqlBlocklist: true, // applicable only on the gateway
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "fingerprint", Typ: types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if err := evalCtx.SessionAccessor.CheckPrivilege(
+					ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.MODIFYCLUSTERSETTING,
+				); err != nil {
+					return nil, err
+				}
+				fingerprint := string(tree.MustBeDString(args[0]))
+				unpinned, err := evalCtx.Planner.UnpinStatementPlan(ctx, fingerprint)
+				if err != nil {
+					return nil, err
+				}
+				return tree.MakeDBool(tree.DBool(unpinned)), nil
+			},
+			Info: "Retires the pinned plan of the given statement fingerprint. Returns " +
+				"false if the fingerprint did not have a pinned plan.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
+	"crdb_internal.schedule_index_drop": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         builtinconstants.CategorySystemInfo,
+			DistsqlBlocklist: true, // applicable only on the gateway
+		},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "table_id", Typ: types.Int},
+				{Name: "index_id", Typ: types.Int},
+				{Name: "observation_period", Typ: types.Interval},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				tableID := int64(tree.MustBeDInt(args[0]))
+				indexID := int64(tree.MustBeDInt(args[1]))
+				observationPeriod := time.Duration(tree.MustBeDInterval(args[2]).Nanos())
+				scheduleID, err := evalCtx.Planner.ScheduleIndexDrop(ctx, tableID, indexID, observationPeriod)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDInt(tree.DInt(scheduleID)), nil
+			},
+			Info: "Schedules the drop of the given secondary index. The index is made " +
+				"not visible right away, and is dropped after the observation period " +
+				"unless it was made visible again or was read in the meantime. Dropping " +
+				"the returned schedule before the end of the observation period makes " +
+				"the index visible again. Requires the CREATE privilege on the table.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	"crdb_internal.set_compaction_concurrency": makeBuiltin(
 		tree.FunctionProperties{
 			Category:         builtinconstants.CategorySystemRepair,
@@ -8820,6 +8912,98 @@ specified store on the node it's run from. One of 'mvccGC', 'merge', 'split',
 			CalledOnNullInput: true,
 		},
 	),
+	"crdb_internal.plpgsql_execute": makeBuiltin(tree.FunctionProperties{
+		Category:     builtinconstants.CategoryString,
+		Undocumented: true,
+	},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "query", Typ: types.String},
+				{Name: "params", Typ: types.Any},
+				{Name: "strict", Typ: types.Bool},
+				{Name: "resultTypes", Typ: types.Any},
+			},
+			ReturnType: tree.IdentityReturnType(3),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DNull {
+					return nil, pgerror.New(
+						pgcode.NullValueNotAllowed, "query string argument of EXECUTE is null",
+					)
+				}
+				query := string(tree.MustBeDString(args[0]))
+				params := plpgsqlDynamicParams(args[1])
+				strict := args[2] == tree.DBoolTrue
+				resultTypes := args[3].(tree.TypedExpr).ResolvedType().TupleContents()
+				row, rowCount, err := evalCtx.Planner.PLpgSQLExecuteDynamic(ctx, query, params)
+				if err != nil {
+					return nil, err
+				}
+				if strict {
+					if rowCount == 0 {
+						return nil, pgerror.New(pgcode.NoDataFound, "query returned no rows")
+					} else if rowCount > 1 {
+						return nil, errors.WithHint(
+							pgerror.New(pgcode.TooManyRows, "query returned more than one row"),
+							"Make sure the query returns a single row, or use LIMIT 1.",
+						)
+					}
+				}
+				res := make(tree.Datums, len(resultTypes))
+				for i := 0; i < len(resultTypes); i++ {
+					if i < len(row) {
+						res[i], err = eval.PerformCastNoTruncate(ctx, evalCtx, row[i], resultTypes[i])
+						if err != nil {
+							return nil, err
+						}
+					} else {
+						res[i] = tree.DNull
+					}
+				}
+				tup := tree.MakeDTuple(types.MakeTuple(resultTypes), res...)
+				return &tup, nil
+			},
+			Info:              "This function is used internally to implement the PLpgSQL EXECUTE statement.",
+			Volatility:        volatility.Volatile,
+			CalledOnNullInput: true,
+		},
+	),
+	"crdb_internal.plpgsql_open_dynamic_cursor": makeBuiltin(tree.FunctionProperties{
+		Category:     builtinconstants.CategoryString,
+		Undocumented: true,
+	},
+		tree.Overload{
+			Types: tree.ParamTypes{
+				{Name: "name", Typ: types.RefCursor},
+				{Name: "query", Typ: types.String},
+				{Name: "params", Typ: types.Any},
+				{Name: "withMarker", Typ: types.Bool},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx context.Context, evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DNull {
+					return nil, pgerror.New(
+						pgcode.NullValueNotAllowed, "cursor name for OPEN statement cannot be null",
+					)
+				}
+				if args[1] == tree.DNull {
+					return nil, pgerror.New(
+						pgcode.NullValueNotAllowed, "query string argument of EXECUTE is null",
+					)
+				}
+				cursorName := tree.Name(tree.MustBeDString(args[0]))
+				query := string(tree.MustBeDString(args[1]))
+				params := plpgsqlDynamicParams(args[2])
+				withMarker := args[3] == tree.DBoolTrue
+				return tree.DNull, evalCtx.Planner.PLpgSQLOpenDynamicCursor(
+					ctx, cursorName, query, params, withMarker,
+				)
+			},
+			Info: "This function is used internally to implement the PLpgSQL OPEN ... FOR EXECUTE " +
+				"statement and FOR loops over dynamic queries.",
+			Volatility:        volatility.Volatile,
+			CalledOnNullInput: true,
+		},
+	),
 	"crdb_internal.protect_mvcc_history": makeBuiltin(
 		tree.FunctionProperties{
 			Category:     builtinconstants.CategoryClusterReplication,
@@ -11926,6 +12110,15 @@ func bitmaskXor(aStr, bStr string) (*tree.DBitArray, error) {
 	return bitmaskOp(aStr, bStr, func(a, b byte) byte { return (a ^ b) + '0' })
 }
 
+// plpgsqlDynamicParams returns the parameters for a PLpgSQL dynamic query,
+// which are passed to the builtin function as a tuple.
+func plpgsqlDynamicParams(arg tree.Datum) tree.Datums {
+	if arg == tree.DNull {
+		return nil
+	}
+	return tree.MustBeDTuple(arg).D
+}
+
 // Perform bitwise operation on the 2 bit strings that may have different
 // lengths. The function applies left padding implicitly with 0s. The function
 // also assumes both input strings are only comprised of charactor '0' and '1'.
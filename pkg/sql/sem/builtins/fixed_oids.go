@@ -2607,6 +2607,13 @@ var builtinOidsArray = []string{
 	2644: `crdb_internal.range_stats_with_errors(key: bytes) -> jsonb`,
 	2645: `crdb_internal.lease_holder_with_errors(key: bytes) -> jsonb`,
 	2646: `crdb_internal.pretty_key(raw_key: bytes) -> string`,
+	2647: `crdb_internal.table_diff(table: regclass, start_time: decimal) -> tuple{string AS op, jsonb AS key, jsonb AS before, jsonb AS after}`,
+	2648: `crdb_internal.table_diff(table: regclass, start_time: timestamptz) -> tuple{string AS op, jsonb AS key, jsonb AS before, jsonb AS after}`,
+	2649: `crdb_internal.plpgsql_execute(query: string, params: anyelement, strict: bool, resultTypes: anyelement) -> anyelement`,
+	2650: `crdb_internal.plpgsql_open_dynamic_cursor(name: refcursor, query: string, params: anyelement, withMarker: bool) -> int`,
+	2651: `crdb_internal.pin_statement_plan(fingerprint: string, plan_gist: string) -> bool`,
+	2652: `crdb_internal.unpin_statement_plan(fingerprint: string) -> bool`,
+	2653: `crdb_internal.schedule_index_drop(table_id: int, index_id: int, observation_period: interval) -> int`,
 }
 
 var builtinOidsBySignature map[string]oid.Oid
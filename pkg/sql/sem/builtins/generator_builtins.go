@@ -40,6 +40,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/arith"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/randident"
@@ -188,6 +189,36 @@ var generators = map[string]builtinDefinition{
 			volatility.Stable,
 		),
 	),
+	"crdb_internal.table_diff": makeBuiltin(genProps(),
+		makeGeneratorOverload(
+			tree.ParamTypes{
+				{Name: "table", Typ: types.RegClass},
+				{Name: "start_time", Typ: types.Decimal},
+				// NB: The function can be called with an AOST clause that will be used
+				// as the end of the diffed interval.
+			},
+			tableDiffGeneratorType,
+			makeTableDiffGenerator,
+			"Returns the rows of `table` which were inserted, updated or deleted "+
+				"between `start_time` and the read timestamp of the statement, decoded "+
+				"using the schema of the table as of the read timestamp. `start_time` "+
+				"must be within the GC window of the table.",
+			volatility.Stable,
+		),
+		makeGeneratorOverload(
+			tree.ParamTypes{
+				{Name: "table", Typ: types.RegClass},
+				{Name: "start_time", Typ: types.TimestampTZ},
+			},
+			tableDiffGeneratorType,
+			makeTableDiffGenerator,
+			"Returns the rows of `table` which were inserted, updated or deleted "+
+				"between `start_time` and the read timestamp of the statement, decoded "+
+				"using the schema of the table as of the read timestamp. `start_time` "+
+				"must be within the GC window of the table.",
+			volatility.Stable,
+		),
+	),
 	"generate_series": makeBuiltin(genProps(),
 		// See https://www.postgresql.org/docs/current/static/functions-srf.html#FUNCTIONS-SRF-SERIES
 		makeGeneratorOverload(
@@ -2406,6 +2437,71 @@ func (sp *spanKeyIterator) ResolvedType() *types.T {
 	return spanKeyIteratorType
 }
 
+var tableDiffGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{types.String, types.Jsonb, types.Jsonb, types.Jsonb},
+	[]string{"op", "key", "before", "after"},
+)
+
+// tableDiffGenerator is an eval.ValueGenerator that returns the changes made
+// to a table since a given timestamp.
+type tableDiffGenerator struct {
+	planner   eval.Planner
+	tableID   int64
+	startTime hlc.Timestamp
+
+	rows eval.InternalRows
+}
+
+var _ eval.ValueGenerator = &tableDiffGenerator{}
+
+func makeTableDiffGenerator(
+	_ context.Context, evalCtx *eval.Context, args tree.Datums,
+) (eval.ValueGenerator, error) {
+	// The start time can either be a decimal or a timestampTZ.
+	var startTime hlc.Timestamp
+	if d, ok := tree.AsDDecimal(args[1]); ok {
+		var err error
+		if startTime, err = hlc.DecimalToHLC(&d.Decimal); err != nil {
+			return nil, err
+		}
+	} else {
+		startTime = hlc.Timestamp{WallTime: tree.MustBeDTimestampTZ(args[1]).Time.UnixNano()}
+	}
+	return &tableDiffGenerator{
+		planner:   evalCtx.Planner,
+		tableID:   int64(tree.MustBeDOid(args[0]).Oid),
+		startTime: startTime,
+	}, nil
+}
+
+// ResolvedType implements the eval.ValueGenerator interface.
+func (g *tableDiffGenerator) ResolvedType() *types.T {
+	return tableDiffGeneratorType
+}
+
+// Start implements the eval.ValueGenerator interface.
+func (g *tableDiffGenerator) Start(ctx context.Context, _ *kv.Txn) (err error) {
+	g.rows, err = g.planner.TableDiff(ctx, g.tableID, g.startTime)
+	return err
+}
+
+// Next implements the eval.ValueGenerator interface.
+func (g *tableDiffGenerator) Next(ctx context.Context) (bool, error) {
+	return g.rows.Next(ctx)
+}
+
+// Values implements the eval.ValueGenerator interface.
+func (g *tableDiffGenerator) Values() (tree.Datums, error) {
+	return g.rows.Cur(), nil
+}
+
+// Close implements the eval.ValueGenerator interface.
+func (g *tableDiffGenerator) Close(context.Context) {
+	if g.rows != nil {
+		_ = g.rows.Close()
+	}
+}
+
 type rangeKeyIterator struct {
 	// rangeID is the ID of the range to iterate over. rangeID is set
 	// by the constructor of the rangeKeyIterator.
@@ -296,6 +296,18 @@ func (w *aggregateWindowFunc) Close(ctx context.Context, _ *eval.Context) {
 	w.agg.Close(ctx)
 }
 
+// NewFramableAggregateWindowFunc creates a constructor of a window function
+// that evaluates the aggregate created by aggConstructor over the window frame
+// of each row. The aggregate is only recomputed from scratch if the frame is
+// not the default one.
+func NewFramableAggregateWindowFunc(
+	aggConstructor func(*eval.Context, tree.Datums) eval.AggregateFunc,
+) func(*eval.Context) eval.WindowFunc {
+	return func(evalCtx *eval.Context) eval.WindowFunc {
+		return newFramableAggregateWindow(aggConstructor(evalCtx, nil /* arguments */), aggConstructor)
+	}
+}
+
 // ShouldReset sets shouldReset to true if w is framableAggregateWindowFunc.
 func ShouldReset(w eval.WindowFunc) {
 	if f, ok := w.(*framableAggregateWindowFunc); ok {
@@ -106,6 +106,9 @@ const (
 	StmtExecInsightsTableName              SystemTableName = "statement_execution_insights"
 	TxnExecInsightsTableName               SystemTableName = "transaction_execution_insights"
 	TableMetadata                          SystemTableName = "table_metadata"
+	StatementPlanBaselinesTableName        SystemTableName = "statement_plan_baselines"
+	StatementHintsTableName                SystemTableName = "statement_hints"
+	IndexRecommendationsTableName          SystemTableName = "index_recommendations"
 )
 
 // Oid for virtual database and table.
@@ -222,6 +225,8 @@ const (
 	CrdbInternalFullyQualifiedNamesViewID
 	CrdbInternalStoreLivenessSupportFrom
 	CrdbInternalStoreLivenessSupportFor
+	CrdbInternalRangeMVCCGarbageTableID
+	CrdbInternalIndexDropRecommendationsTableID
 	InformationSchemaID
 	InformationSchemaAdministrableRoleAuthorizationsID
 	InformationSchemaApplicableRolesID
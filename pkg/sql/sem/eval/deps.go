@@ -358,6 +358,28 @@ type Planner interface {
 	// the transaction.
 	FingerprintSpan(ctx context.Context, span roachpb.Span, startTime hlc.Timestamp, allRevisions bool, stripped bool) (uint64, error)
 
+	// TableDiff returns an iterator over the rows of the given table which
+	// were inserted, updated or deleted between startTime and the read
+	// timestamp of the transaction, decoded using the version of the table
+	// descriptor valid at the read timestamp. Each result row consists of the
+	// operation, the primary key and the before and after images of the row as
+	// JSON. The iterator must be closed once the caller is done with it.
+	TableDiff(ctx context.Context, tableID int64, startTime hlc.Timestamp) (InternalRows, error)
+
+	// PinStatementPlan makes the plan with the given gist the pinned plan for
+	// the given statement fingerprint, so that the optimizer is constrained to
+	// use it.
+	PinStatementPlan(ctx context.Context, fingerprint string, planGist string) error
+
+	// UnpinStatementPlan removes the pinned plan of the given statement
+	// fingerprint. It returns true if the fingerprint had a pinned plan.
+	UnpinStatementPlan(ctx context.Context, fingerprint string) (bool, error)
+
+	// ScheduleIndexDrop creates a schedule that makes the given index not
+	// visible, observes the workload for the given period, and then drops the
+	// index. It returns the ID of the schedule.
+	ScheduleIndexDrop(ctx context.Context, tableID int64, indexID int64, observationPeriod time.Duration) (int64, error)
+
 	// QueryRowEx executes the supplied SQL statement and returns a single row, or
 	// nil if no row is found, or an error if more that one row is returned.
 	//
@@ -425,6 +447,23 @@ type Planner interface {
 	// PLpgSQL FETCH statement.
 	PLpgSQLFetchCursor(ctx context.Context, cursor *tree.CursorStmt) (res tree.Datums, err error)
 
+	// PLpgSQLOpenDynamicCursor opens a cursor with the given name over the rows
+	// of the given query string, binding the given parameters to its
+	// placeholders. If withMarker is true, a non-NULL BOOL column is prepended
+	// to each row of the cursor. It is used to implement the PLpgSQL
+	// OPEN ... FOR EXECUTE statement and FOR loops over dynamic queries.
+	PLpgSQLOpenDynamicCursor(
+		ctx context.Context, cursorName tree.Name, query string, params tree.Datums, withMarker bool,
+	) error
+
+	// PLpgSQLExecuteDynamic executes the given query string in the current
+	// transaction, binding the given parameters to its placeholders. It returns
+	// the first row of the result, if any, and the number of rows returned by
+	// the query. It is used to implement the PLpgSQL EXECUTE statement.
+	PLpgSQLExecuteDynamic(
+		ctx context.Context, query string, params tree.Datums,
+	) (firstRow tree.Datums, rowCount int, err error)
+
 	// AutoCommit indicates whether the Planner has flagged the current statement
 	// as eligible for transaction auto-commit.
 	AutoCommit() bool
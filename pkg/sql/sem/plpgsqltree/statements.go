@@ -11,7 +11,6 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
-	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
 type Expr = tree.Expr
@@ -606,7 +605,7 @@ func (s *While) WalkStmt(visitor StatementVisitor) Statement {
 }
 
 // ForLoopControl is an interface covering the loop control structures for the
-// integer range, query, and cursor FOR loops.
+// integer range, query, cursor, and dynamic query FOR loops.
 type ForLoopControl interface {
 	isForLoopControl()
 	Format(ctx *tree.FmtCtx)
@@ -636,6 +635,50 @@ func (c *IntForLoopControl) Format(ctx *tree.FmtCtx) {
 	}
 }
 
+// QueryForLoopControl iterates over the rows returned by a SQL query.
+type QueryForLoopControl struct {
+	Query tree.Statement
+}
+
+var _ ForLoopControl = &QueryForLoopControl{}
+
+func (c *QueryForLoopControl) isForLoopControl() {}
+
+func (c *QueryForLoopControl) Format(ctx *tree.FmtCtx) {
+	ctx.FormatNode(c.Query)
+}
+
+// CursorForLoopControl iterates over the rows returned by a bound cursor. The
+// cursor is opened when the loop starts, and closed when it exits.
+type CursorForLoopControl struct {
+	CursorVar Variable
+}
+
+var _ ForLoopControl = &CursorForLoopControl{}
+
+func (c *CursorForLoopControl) isForLoopControl() {}
+
+func (c *CursorForLoopControl) Format(ctx *tree.FmtCtx) {
+	ctx.FormatNode(&c.CursorVar)
+}
+
+// DynamicForLoopControl iterates over the rows returned by a query string
+// that is evaluated at runtime.
+type DynamicForLoopControl struct {
+	Query  Expr
+	Params []Expr
+}
+
+var _ ForLoopControl = &DynamicForLoopControl{}
+
+func (c *DynamicForLoopControl) isForLoopControl() {}
+
+func (c *DynamicForLoopControl) Format(ctx *tree.FmtCtx) {
+	ctx.WriteString("EXECUTE ")
+	ctx.FormatNode(c.Query)
+	formatUsingParams(ctx, c.Params)
+}
+
 // stmt_for
 type ForLoop struct {
 	StatementImpl
@@ -682,6 +725,12 @@ func (s *ForLoop) PlpgSQLStatementTag() string {
 	switch s.Control.(type) {
 	case *IntForLoopControl:
 		return "stmt_for_int_loop"
+	case *QueryForLoopControl:
+		return "stmt_for_query_loop"
+	case *CursorForLoopControl:
+		return "stmt_for_cursor_loop"
+	case *DynamicForLoopControl:
+		return "stmt_for_dynamic_loop"
 	}
 	return "stmt_for_unknown"
 }
@@ -706,14 +755,51 @@ func (s *ForLoop) WalkStmt(visitor StatementVisitor) Statement {
 // stmt_foreach_a
 type ForEachArray struct {
 	StatementImpl
-	Label string
-	Var   *Variable
-	Slice int // TODO(drewk): not sure what this is
+	Label  string
+	Target []Variable
+	// Slice is the number of array dimensions that are assigned to the target
+	// on each iteration. Zero indicates that each element is assigned to the
+	// target individually.
+	Slice int
 	Expr  Expr
 	Body  []Statement
 }
 
+func (s *ForEachArray) CopyNode() *ForEachArray {
+	copyNode := *s
+	copyNode.Target = append([]Variable(nil), copyNode.Target...)
+	copyNode.Body = append([]Statement(nil), copyNode.Body...)
+	return &copyNode
+}
+
 func (s *ForEachArray) Format(ctx *tree.FmtCtx) {
+	if s.Label != "" {
+		ctx.WriteString("<<")
+		ctx.FormatNameP(&s.Label)
+		ctx.WriteString(">>\n")
+	}
+	ctx.WriteString("FOREACH ")
+	for i, target := range s.Target {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatName(string(target))
+	}
+	if s.Slice != 0 {
+		ctx.WriteString(fmt.Sprintf(" SLICE %d", s.Slice))
+	}
+	ctx.WriteString(" IN ARRAY ")
+	ctx.FormatNode(s.Expr)
+	ctx.WriteString(" LOOP\n")
+	for _, stmt := range s.Body {
+		ctx.FormatNode(stmt)
+	}
+	ctx.WriteString("END LOOP")
+	if s.Label != "" {
+		ctx.WriteString(" ")
+		ctx.FormatNameP(&s.Label)
+	}
+	ctx.WriteString(";\n")
 }
 
 func (s *ForEachArray) PlpgSQLStatementTag() string {
@@ -721,7 +807,20 @@ func (s *ForEachArray) PlpgSQLStatementTag() string {
 }
 
 func (s *ForEachArray) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, recurse := visitor.Visit(s)
+
+	if recurse {
+		for i, bodyStmt := range s.Body {
+			newBodyStmt := bodyStmt.WalkStmt(visitor)
+			if newBodyStmt != bodyStmt {
+				if newStmt == s {
+					newStmt = s.CopyNode()
+				}
+				newStmt.(*ForEachArray).Body[i] = newBodyStmt
+			}
+		}
+	}
+	return newStmt
 }
 
 // stmt_exit
@@ -822,13 +921,24 @@ func (s *Return) WalkStmt(visitor StatementVisitor) Statement {
 	return newStmt
 }
 
+// stmt_return_next
 type ReturnNext struct {
 	StatementImpl
-	Expr   Expr
-	RetVar Variable
+	Expr Expr
+}
+
+func (s *ReturnNext) CopyNode() *ReturnNext {
+	copyNode := *s
+	return &copyNode
 }
 
 func (s *ReturnNext) Format(ctx *tree.FmtCtx) {
+	ctx.WriteString("RETURN NEXT")
+	if s.Expr != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(s.Expr)
+	}
+	ctx.WriteString(";\n")
 }
 
 func (s *ReturnNext) PlpgSQLStatementTag() string {
@@ -836,17 +946,37 @@ func (s *ReturnNext) PlpgSQLStatementTag() string {
 }
 
 func (s *ReturnNext) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, _ := visitor.Visit(s)
+	return newStmt
 }
 
+// stmt_return_query
 type ReturnQuery struct {
 	StatementImpl
-	Query        Expr
+	SqlStmt tree.Statement
+	// DynamicQuery is set instead of SqlStmt for RETURN QUERY EXECUTE. It is a
+	// string expression that is evaluated at runtime to produce the query, with
+	// Params bound to its placeholders.
 	DynamicQuery Expr
 	Params       []Expr
 }
 
+func (s *ReturnQuery) CopyNode() *ReturnQuery {
+	copyNode := *s
+	copyNode.Params = append([]Expr(nil), s.Params...)
+	return &copyNode
+}
+
 func (s *ReturnQuery) Format(ctx *tree.FmtCtx) {
+	ctx.WriteString("RETURN QUERY ")
+	if s.DynamicQuery != nil {
+		ctx.WriteString("EXECUTE ")
+		ctx.FormatNode(s.DynamicQuery)
+		formatUsingParams(ctx, s.Params)
+	} else {
+		ctx.FormatNode(s.SqlStmt)
+	}
+	ctx.WriteString(";\n")
 }
 
 func (s *ReturnQuery) PlpgSQLStatementTag() string {
@@ -854,7 +984,8 @@ func (s *ReturnQuery) PlpgSQLStatementTag() string {
 }
 
 func (s *ReturnQuery) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, _ := visitor.Visit(s)
+	return newStmt
 }
 
 // stmt_raise
@@ -994,35 +1125,42 @@ func (s *Execute) WalkStmt(visitor StatementVisitor) Statement {
 }
 
 // stmt_dynexecute
-// TODO(chengxiong): query should be a better expression type.
 type DynamicExecute struct {
 	StatementImpl
-	Query  string
-	Into   bool
+	// Query is a string expression that is evaluated at runtime to produce the
+	// SQL command that will be executed.
+	Query  Expr
 	Strict bool
-	Target Variable
+	Target []Variable
+	// Params are the values that are bound to the placeholders ($1, $2, etc.)
+	// of the command through the USING clause.
 	Params []Expr
 }
 
 func (s *DynamicExecute) CopyNode() *DynamicExecute {
 	copyNode := *s
+	copyNode.Target = append([]Variable(nil), s.Target...)
 	copyNode.Params = append([]Expr(nil), s.Params...)
 	return &copyNode
 }
 
 func (s *DynamicExecute) Format(ctx *tree.FmtCtx) {
-	// TODO(drewk): Pretty print the original command
-	ctx.WriteString("EXECUTE a dynamic command")
-	if s.Into {
-		ctx.WriteString(" WITH INTO")
+	ctx.WriteString("EXECUTE ")
+	ctx.FormatNode(s.Query)
+	if s.Target != nil {
+		ctx.WriteString(" INTO ")
 		if s.Strict {
-			ctx.WriteString(" STRICT")
+			ctx.WriteString("STRICT ")
+		}
+		for i := range s.Target {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&s.Target[i])
 		}
 	}
-	if s.Params != nil {
-		ctx.WriteString(" WITH USING")
-	}
-	ctx.WriteString("\n")
+	formatUsingParams(ctx, s.Params)
+	ctx.WriteString(";\n")
 }
 
 func (s *DynamicExecute) PlpgSQLStatementTag() string {
@@ -1034,13 +1172,40 @@ func (s *DynamicExecute) WalkStmt(visitor StatementVisitor) Statement {
 	return newStmt
 }
 
+// formatUsingParams formats the USING clause of a dynamic SQL command.
+func formatUsingParams(ctx *tree.FmtCtx, params []Expr) {
+	for i := range params {
+		if i == 0 {
+			ctx.WriteString(" USING ")
+		} else {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(params[i])
+	}
+}
+
 // stmt_perform
 type Perform struct {
 	StatementImpl
-	Expr Expr
+	// SqlStmt is the SELECT statement that is executed for its side effects.
+	// PERFORM is formatted by replacing the SELECT keyword with PERFORM.
+	SqlStmt tree.Statement
+}
+
+func (s *Perform) CopyNode() *Perform {
+	copyNode := *s
+	return &copyNode
 }
 
 func (s *Perform) Format(ctx *tree.FmtCtx) {
+	start := ctx.Len()
+	ctx.FormatNode(s.SqlStmt)
+	const selectPrefix = "SELECT "
+	formatted := ctx.String()[start:]
+	ctx.Truncate(start)
+	ctx.WriteString("PERFORM ")
+	ctx.WriteString(strings.TrimPrefix(formatted, selectPrefix))
+	ctx.WriteString(";\n")
 }
 
 func (s *Perform) PlpgSQLStatementTag() string {
@@ -1048,7 +1213,8 @@ func (s *Perform) PlpgSQLStatementTag() string {
 }
 
 func (s *Perform) WalkStmt(visitor StatementVisitor) Statement {
-	panic(unimplemented.New("plpgsql visitor", "Unimplemented PLpgSQL visitor pattern"))
+	newStmt, _ := visitor.Visit(s)
+	return newStmt
 }
 
 // stmt_call
@@ -1127,10 +1293,16 @@ type Open struct {
 	CurVar Variable
 	Scroll tree.CursorScrollOption
 	Query  tree.Statement
+	// DynamicQuery is set instead of Query for OPEN ... FOR EXECUTE. It is a
+	// string expression that is evaluated at runtime to produce the query, with
+	// Params bound to its placeholders.
+	DynamicQuery Expr
+	Params       []Expr
 }
 
 func (s *Open) CopyNode() *Open {
 	copyNode := *s
+	copyNode.Params = append([]Expr(nil), s.Params...)
 	return &copyNode
 }
 
@@ -1146,6 +1318,10 @@ func (s *Open) Format(ctx *tree.FmtCtx) {
 	if s.Query != nil {
 		ctx.WriteString(" FOR ")
 		ctx.FormatNode(s.Query)
+	} else if s.DynamicQuery != nil {
+		ctx.WriteString(" FOR EXECUTE ")
+		ctx.FormatNode(s.DynamicQuery)
+		formatUsingParams(ctx, s.Params)
 	}
 	ctx.WriteString(";\n")
 }
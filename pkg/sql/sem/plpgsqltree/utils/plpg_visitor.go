@@ -15,7 +15,6 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/plpgsqltree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
-	unimp "github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
 // PLpgSQLStmtCounter is used to accurately report telemetry for plpgsql
@@ -118,6 +117,37 @@ func simpleVisit(expr tree.Expr, fn tree.SimpleVisitFn) (tree.Expr, error) {
 	return tree.SimpleVisit(expr, fn)
 }
 
+// visitDynamicQuery visits the query string expression and USING parameters of
+// a dynamic SQL command. If any of the expressions are changed, it returns the
+// new expressions and changed=true.
+func visitDynamicQuery(
+	query tree.Expr, params []tree.Expr, fn tree.SimpleVisitFn,
+) (newQuery tree.Expr, newParams []tree.Expr, changed bool, err error) {
+	newQuery, err = simpleVisit(query, fn)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	changed = newQuery != query
+	newParams = params
+	var copied bool
+	for i := range params {
+		var e tree.Expr
+		e, err = simpleVisit(params[i], fn)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if e != params[i] {
+			if !copied {
+				newParams = append([]tree.Expr(nil), params...)
+				copied = true
+			}
+			newParams[i] = e
+			changed = true
+		}
+	}
+	return newQuery, newParams, changed, nil
+}
+
 func (v *SQLStmtVisitor) Visit(
 	stmt plpgsqltree.Statement,
 ) (newStmt plpgsqltree.Statement, recurse bool) {
@@ -153,9 +183,16 @@ func (v *SQLStmtVisitor) Visit(
 		if v.Err != nil {
 			return stmt, false
 		}
-		if t.Query != s {
+		var params []tree.Expr
+		var changed bool
+		e, params, changed, v.Err = visitDynamicQuery(t.DynamicQuery, t.Params, v.Fn)
+		if v.Err != nil {
+			return stmt, false
+		}
+		if t.Query != s || changed {
 			cpy := t.CopyNode()
 			cpy.Query = s
+			cpy.DynamicQuery, cpy.Params = e, params
 			newStmt = cpy
 		}
 	case *plpgsqltree.Declaration:
@@ -278,18 +315,16 @@ func (v *SQLStmtVisitor) Visit(
 		}
 
 	case *plpgsqltree.DynamicExecute:
-		for i, p := range t.Params {
-			e, v.Err = simpleVisit(p, v.Fn)
-			if v.Err != nil {
-				return stmt, false
-			}
-			if t.Params[i] != e {
-				if newStmt != stmt {
-					cpy := t.CopyNode()
-					newStmt = cpy
-				}
-				newStmt.(*plpgsqltree.DynamicExecute).Params[i] = e
-			}
+		var params []tree.Expr
+		var changed bool
+		e, params, changed, v.Err = visitDynamicQuery(t.Query, t.Params, v.Fn)
+		if v.Err != nil {
+			return stmt, false
+		}
+		if changed {
+			cpy := t.CopyNode()
+			cpy.Query, cpy.Params = e, params
+			newStmt = cpy
 		}
 	case *plpgsqltree.Call:
 		e, v.Err = simpleVisit(t.Proc, v.Fn)
@@ -330,11 +365,77 @@ func (v *SQLStmtVisitor) Visit(
 				}
 				newStmt = cpy
 			}
+		case *plpgsqltree.QueryForLoopControl:
+			s, v.Err = simpleStmtVisit(c.Query, v.Fn)
+			if v.Err != nil {
+				return stmt, false
+			}
+			if c.Query != s {
+				cpy := t.CopyNode()
+				cpy.Control = &plpgsqltree.QueryForLoopControl{Query: s}
+				newStmt = cpy
+			}
+		case *plpgsqltree.DynamicForLoopControl:
+			var params []tree.Expr
+			var changed bool
+			e, params, changed, v.Err = visitDynamicQuery(c.Query, c.Params, v.Fn)
+			if v.Err != nil {
+				return stmt, false
+			}
+			if changed {
+				cpy := t.CopyNode()
+				cpy.Control = &plpgsqltree.DynamicForLoopControl{Query: e, Params: params}
+				newStmt = cpy
+			}
 		}
 
-	case *plpgsqltree.ForEachArray, *plpgsqltree.ReturnNext,
-		*plpgsqltree.ReturnQuery, *plpgsqltree.Perform:
-		panic(unimp.New("plpgsql visitor", "Unimplemented PLpgSQL visitor"))
+	case *plpgsqltree.ForEachArray:
+		e, v.Err = simpleVisit(t.Expr, v.Fn)
+		if v.Err != nil {
+			return stmt, false
+		}
+		if t.Expr != e {
+			cpy := t.CopyNode()
+			cpy.Expr = e
+			newStmt = cpy
+		}
+	case *plpgsqltree.ReturnNext:
+		e, v.Err = simpleVisit(t.Expr, v.Fn)
+		if v.Err != nil {
+			return stmt, false
+		}
+		if t.Expr != e {
+			cpy := t.CopyNode()
+			cpy.Expr = e
+			newStmt = cpy
+		}
+	case *plpgsqltree.ReturnQuery:
+		s, v.Err = simpleStmtVisit(t.SqlStmt, v.Fn)
+		if v.Err != nil {
+			return stmt, false
+		}
+		var params []tree.Expr
+		var changed bool
+		e, params, changed, v.Err = visitDynamicQuery(t.DynamicQuery, t.Params, v.Fn)
+		if v.Err != nil {
+			return stmt, false
+		}
+		if t.SqlStmt != s || changed {
+			cpy := t.CopyNode()
+			cpy.SqlStmt = s
+			cpy.DynamicQuery, cpy.Params = e, params
+			newStmt = cpy
+		}
+	case *plpgsqltree.Perform:
+		s, v.Err = simpleStmtVisit(t.SqlStmt, v.Fn)
+		if v.Err != nil {
+			return stmt, false
+		}
+		if t.SqlStmt != s {
+			cpy := t.CopyNode()
+			cpy.SqlStmt = s
+			newStmt = cpy
+		}
 	}
 	if v.Err != nil {
 		return stmt, false
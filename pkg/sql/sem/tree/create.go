@@ -1991,6 +1991,10 @@ type RefreshMaterializedView struct {
 	Name              *UnresolvedObjectName
 	Concurrently      bool
 	RefreshDataOption RefreshDataOption
+	// Incrementally indicates that the view should be brought up to date by
+	// applying the changes made to the underlying tables since the last
+	// refresh, rather than by recomputing the view query.
+	Incrementally bool
 }
 
 // RefreshDataOption corresponds to arguments for the REFRESH MATERIALIZED VIEW
@@ -2022,6 +2026,9 @@ func (node *RefreshMaterializedView) Format(ctx *FmtCtx) {
 	case RefreshDataClear:
 		ctx.WriteString(" WITH NO DATA")
 	}
+	if node.Incrementally {
+		ctx.WriteString(" INCREMENTALLY")
+	}
 }
 
 // CreateStats represents a CREATE STATISTICS statement.
@@ -6,6 +6,7 @@
 package tree
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -105,9 +106,21 @@ func (node *CreateRoutine) Format(ctx *FmtCtx) {
 	}
 	ctx.FormatNode(&node.Name)
 	ctx.WriteByte('(')
-	ctx.FormatNode(node.Params)
+	// The columns of a RETURNS TABLE clause are stored as trailing parameters.
+	params, tableColumns := node.Params, RoutineParams(nil)
+	for i := range params {
+		if params[i].Class == RoutineParamTable {
+			params, tableColumns = params[:i], params[i:]
+			break
+		}
+	}
+	ctx.FormatNode(params)
 	ctx.WriteString(")\n\t")
-	if !node.IsProcedure && node.ReturnType != nil {
+	if len(tableColumns) > 0 {
+		ctx.WriteString("RETURNS TABLE (")
+		ctx.FormatNode(tableColumns)
+		ctx.WriteString(")\n\t")
+	} else if !node.IsProcedure && node.ReturnType != nil {
 		ctx.WriteString("RETURNS ")
 		if node.ReturnType.SetOf {
 			ctx.WriteString("SETOF ")
@@ -166,6 +179,138 @@ func (node *CreateRoutine) Format(ctx *FmtCtx) {
 	}
 }
 
+// CreateAggregate represents a CREATE AGGREGATE statement.
+type CreateAggregate struct {
+	Replace bool
+	Name    RoutineName
+	Params  RoutineParams
+	Options AggregateOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateAggregate) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("AGGREGATE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte('(')
+	ctx.FormatNode(node.Params)
+	ctx.WriteString(") (")
+	ctx.FormatNode(&node.Options)
+	ctx.WriteByte(')')
+}
+
+// AggregateOption is a single "attribute = value" item in the definition list
+// of a CREATE AGGREGATE statement, as produced by the parser. Exactly one of
+// Type and Value is set. Routine names (e.g. SFUNC) are parsed as type
+// references, since the grammar cannot distinguish the two.
+type AggregateOption struct {
+	Name  string
+	Type  ResolvableTypeReference
+	Value *string
+}
+
+// AggregateOptions contains the validated attributes of a user-defined
+// aggregate.
+type AggregateOptions struct {
+	// StateFunc is the state transition function, which is invoked with the
+	// current state and the input values for each row.
+	StateFunc RoutineName
+	// StateType is the type of the aggregate state.
+	StateType ResolvableTypeReference
+	// FinalFunc, if set, computes the result of the aggregate from the final
+	// state.
+	FinalFunc *RoutineName
+	// CombineFunc, if set, merges two partial states into one.
+	CombineFunc *RoutineName
+	// InitCond, if set, is the initial value of the state in string form.
+	InitCond *string
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateOptions) Format(ctx *FmtCtx) {
+	ctx.WriteString("SFUNC = ")
+	ctx.FormatNode(&node.StateFunc)
+	ctx.WriteString(", STYPE = ")
+	ctx.FormatTypeReference(node.StateType)
+	if node.FinalFunc != nil {
+		ctx.WriteString(", FINALFUNC = ")
+		ctx.FormatNode(node.FinalFunc)
+	}
+	if node.CombineFunc != nil {
+		ctx.WriteString(", COMBINEFUNC = ")
+		ctx.FormatNode(node.CombineFunc)
+	}
+	if node.InitCond != nil {
+		ctx.WriteString(", INITCOND = ")
+		ctx.FormatNode(NewStrVal(*node.InitCond))
+	}
+}
+
+// MakeAggregateOptions validates the definition list of a CREATE AGGREGATE
+// statement.
+func MakeAggregateOptions(opts []AggregateOption) (AggregateOptions, error) {
+	var res AggregateOptions
+	routineName := func(opt AggregateOption) (*RoutineName, error) {
+		name, ok := opt.Type.(*UnresolvedObjectName)
+		if !ok {
+			return nil, pgerror.Newf(pgcode.Syntax,
+				"aggregate attribute %q requires a function name", opt.Name)
+		}
+		fn := name.ToRoutineName()
+		return &fn, nil
+	}
+	seen := make(map[string]struct{}, len(opts))
+	for _, opt := range opts {
+		name := strings.ToLower(opt.Name)
+		if _, ok := seen[name]; ok {
+			return AggregateOptions{}, errors.Wrapf(ErrConflictingRoutineOption, "%s", name)
+		}
+		seen[name] = struct{}{}
+		var err error
+		switch name {
+		case "sfunc":
+			var fn *RoutineName
+			if fn, err = routineName(opt); err == nil {
+				res.StateFunc = *fn
+			}
+		case "stype":
+			if opt.Type == nil {
+				return AggregateOptions{}, pgerror.Newf(pgcode.Syntax,
+					"aggregate attribute %q requires a type name", opt.Name)
+			}
+			res.StateType = opt.Type
+		case "finalfunc":
+			res.FinalFunc, err = routineName(opt)
+		case "combinefunc":
+			res.CombineFunc, err = routineName(opt)
+		case "initcond":
+			if opt.Value == nil {
+				return AggregateOptions{}, pgerror.Newf(pgcode.Syntax,
+					"aggregate attribute %q requires a string constant", opt.Name)
+			}
+			res.InitCond = opt.Value
+		default:
+			return AggregateOptions{}, pgerror.Newf(pgcode.Syntax,
+				"aggregate attribute %q not recognized", opt.Name)
+		}
+		if err != nil {
+			return AggregateOptions{}, err
+		}
+	}
+	if res.StateType == nil {
+		return AggregateOptions{}, pgerror.New(pgcode.InvalidFunctionDefinition,
+			"aggregate stype must be specified")
+	}
+	if _, ok := seen["sfunc"]; !ok {
+		return AggregateOptions{}, pgerror.New(pgcode.InvalidFunctionDefinition,
+			"aggregate sfunc must be specified")
+	}
+	return res, nil
+}
+
 // RoutineBody represent a list of statements in a UDF body.
 type RoutineBody struct {
 	// Stmts is populated during parsing. Unlike BodyStatements, we don't need
@@ -199,6 +344,10 @@ func (RoutineLeakproof) routineOption()         {}
 func (RoutineBodyStr) routineOption()           {}
 func (RoutineLanguage) routineOption()          {}
 func (RoutineSecurity) routineOption()          {}
+func (RoutineCost) routineOption()              {}
+func (RoutineRows) routineOption()              {}
+func (RoutineParallel) routineOption()          {}
+func (*RoutineSetVar) routineOption()           {}
 
 // RoutineNullInputBehavior represent the UDF property on null parameters.
 type RoutineNullInputBehavior int
@@ -333,6 +482,113 @@ func (node RoutineSecurity) Format(ctx *FmtCtx) {
 	}
 }
 
+// RoutineCost is the estimated execution cost of a routine, in units of
+// cpu_operator_cost.
+type RoutineCost float64
+
+// Format implements the NodeFormatter interface.
+func (node RoutineCost) Format(ctx *FmtCtx) {
+	ctx.WriteString("COST ")
+	ctx.WriteString(strconv.FormatFloat(float64(node), 'g', -1, 64))
+}
+
+// RoutineRows is the estimated number of rows returned by a set-returning
+// routine.
+type RoutineRows float64
+
+// Format implements the NodeFormatter interface.
+func (node RoutineRows) Format(ctx *FmtCtx) {
+	ctx.WriteString("ROWS ")
+	ctx.WriteString(strconv.FormatFloat(float64(node), 'g', -1, 64))
+}
+
+// RoutineParallel indicates whether a routine is safe to run in parallel
+// mode. It is recorded for compatibility with Postgres, but it does not affect
+// how the routine is executed.
+type RoutineParallel int
+
+const (
+	// RoutineParallelUnsafe indicates that the routine cannot be executed in
+	// parallel mode. This is the default if no parallel option is provided.
+	RoutineParallelUnsafe RoutineParallel = iota
+	// RoutineParallelRestricted indicates that the routine can be executed in
+	// parallel mode, but only by the parallel group leader.
+	RoutineParallelRestricted
+	// RoutineParallelSafe indicates that the routine is safe to execute in
+	// parallel mode.
+	RoutineParallelSafe
+)
+
+// Format implements the NodeFormatter interface.
+func (node RoutineParallel) Format(ctx *FmtCtx) {
+	ctx.WriteString("PARALLEL ")
+	switch node {
+	case RoutineParallelUnsafe:
+		ctx.WriteString("UNSAFE")
+	case RoutineParallelRestricted:
+		ctx.WriteString("RESTRICTED")
+	case RoutineParallelSafe:
+		ctx.WriteString("SAFE")
+	default:
+		panic(pgerror.New(pgcode.InvalidParameterValue, "unknown routine option"))
+	}
+}
+
+// AsRoutineParallel converts a string to a RoutineParallel. An error is
+// returned if the string is not a valid parallel mode.
+func AsRoutineParallel(parallel string) (RoutineParallel, error) {
+	switch strings.ToLower(parallel) {
+	case "unsafe":
+		return RoutineParallelUnsafe, nil
+	case "restricted":
+		return RoutineParallelRestricted, nil
+	case "safe":
+		return RoutineParallelSafe, nil
+	}
+	return 0, pgerror.Newf(pgcode.InvalidParameterValue,
+		"parameter \"parallel\" must be SAFE, RESTRICTED, or UNSAFE")
+}
+
+// RoutineSetVar represents a SET or RESET clause of a routine definition. The
+// session variables set by a routine take effect only while it is executing.
+type RoutineSetVar struct {
+	Name   string
+	Values Exprs
+	// FromCurrent is true for SET ... FROM CURRENT, which captures the value of
+	// the variable in the session that creates the routine.
+	FromCurrent bool
+	// Reset is true for RESET clauses, which remove the setting of the variable
+	// from the routine. If ResetAll is also true, all settings are removed.
+	Reset    bool
+	ResetAll bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RoutineSetVar) Format(ctx *FmtCtx) {
+	if node.ResetAll {
+		ctx.WriteString("RESET ALL")
+		return
+	}
+	if node.Reset {
+		ctx.WriteString("RESET ")
+	} else {
+		ctx.WriteString("SET ")
+	}
+	ctx.WithFlags(ctx.flags & ^FmtAnonymize & ^FmtMarkRedactionNode, func() {
+		// Session var names never contain PII and should be distinguished
+		// for feature tracking purposes.
+		ctx.FormatNameP(&node.Name)
+	})
+	switch {
+	case node.Reset:
+	case node.FromCurrent:
+		ctx.WriteString(" FROM CURRENT")
+	default:
+		ctx.WriteString(" = ")
+		ctx.FormatNode(&node.Values)
+	}
+}
+
 // RoutineBodyStr is a string containing all statements in a UDF body.
 type RoutineBodyStr string
 
@@ -380,7 +636,7 @@ type RoutineParam struct {
 // Format implements the NodeFormatter interface.
 func (node *RoutineParam) Format(ctx *FmtCtx) {
 	switch node.Class {
-	case RoutineParamDefault:
+	case RoutineParamDefault, RoutineParamTable:
 	case RoutineParamIn:
 		ctx.WriteString("IN ")
 	case RoutineParamOut:
@@ -418,13 +674,16 @@ const (
 	RoutineParamInOut
 	// RoutineParamVariadic args are variadic.
 	RoutineParamVariadic
+	// RoutineParamTable args are the columns of a RETURNS TABLE clause. They
+	// behave like OUT args.
+	RoutineParamTable
 )
 
 // IsInParamClass returns true if the given parameter class specifies an input
-// parameter (i.e. either unspecified, IN or, INOUT).
+// parameter (i.e. either unspecified, IN, INOUT or VARIADIC).
 func IsInParamClass(class RoutineParamClass) bool {
 	switch class {
-	case RoutineParamDefault, RoutineParamIn, RoutineParamInOut:
+	case RoutineParamDefault, RoutineParamIn, RoutineParamInOut, RoutineParamVariadic:
 		return true
 	default:
 		return false
@@ -432,24 +691,36 @@ func IsInParamClass(class RoutineParamClass) bool {
 }
 
 // IsOutParamClass returns true if the given parameter class specifies an output
-// parameter (i.e. either OUT or INOUT).
+// parameter (i.e. either OUT, INOUT or TABLE).
 func IsOutParamClass(class RoutineParamClass) bool {
 	switch class {
-	case RoutineParamOut, RoutineParamInOut:
+	case RoutineParamOut, RoutineParamInOut, RoutineParamTable:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsOutOnlyParamClass returns true if the given parameter class specifies an
+// output parameter that is not also an input parameter (i.e. either OUT or
+// TABLE).
+func IsOutOnlyParamClass(class RoutineParamClass) bool {
+	switch class {
+	case RoutineParamOut, RoutineParamTable:
 		return true
 	default:
 		return false
 	}
 }
 
-// IsInParam returns true if the parameter is an input parameter (i.e. either IN
-// or INOUT).
+// IsInParam returns true if the parameter is an input parameter (i.e. either
+// IN, INOUT or VARIADIC).
 func (node *RoutineParam) IsInParam() bool {
 	return IsInParamClass(node.Class)
 }
 
 // IsOutParam returns true if the parameter is an output parameter (i.e. either
-// OUT or INOUT).
+// OUT, INOUT or TABLE).
 func (node *RoutineParam) IsOutParam() bool {
 	return IsOutParamClass(node.Class)
 }
@@ -460,10 +731,12 @@ type RoutineReturnType struct {
 	SetOf bool
 }
 
-// DropRoutine represents a DROP FUNCTION or DROP PROCEDURE statement.
+// DropRoutine represents a DROP FUNCTION, DROP PROCEDURE or DROP AGGREGATE
+// statement.
 type DropRoutine struct {
 	IfExists     bool
 	Procedure    bool
+	Aggregate    bool
 	Routines     RoutineObjs
 	DropBehavior DropBehavior
 }
@@ -472,6 +745,8 @@ type DropRoutine struct {
 func (node *DropRoutine) Format(ctx *FmtCtx) {
 	if node.Procedure {
 		ctx.WriteString("DROP PROCEDURE ")
+	} else if node.Aggregate {
+		ctx.WriteString("DROP AGGREGATE ")
 	} else {
 		ctx.WriteString("DROP FUNCTION ")
 	}
@@ -531,18 +806,21 @@ func (node *AlterFunctionOptions) Format(ctx *FmtCtx) {
 	}
 }
 
-// AlterRoutineRename represents a ALTER FUNCTION...RENAME or
-// ALTER PROCEDURE...RENAME statement.
+// AlterRoutineRename represents a ALTER FUNCTION...RENAME,
+// ALTER PROCEDURE...RENAME or ALTER AGGREGATE...RENAME statement.
 type AlterRoutineRename struct {
 	Function  RoutineObj
 	NewName   Name
 	Procedure bool
+	Aggregate bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AlterRoutineRename) Format(ctx *FmtCtx) {
 	if node.Procedure {
 		ctx.WriteString("ALTER PROCEDURE ")
+	} else if node.Aggregate {
+		ctx.WriteString("ALTER AGGREGATE ")
 	} else {
 		ctx.WriteString("ALTER FUNCTION ")
 	}
@@ -551,18 +829,21 @@ func (node *AlterRoutineRename) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.NewName)
 }
 
-// AlterRoutineSetSchema represents a ALTER FUNCTION...SET SCHEMA or
-// ALTER PROCEDURE...SET SCHEMA statement.
+// AlterRoutineSetSchema represents a ALTER FUNCTION...SET SCHEMA,
+// ALTER PROCEDURE...SET SCHEMA or ALTER AGGREGATE...SET SCHEMA statement.
 type AlterRoutineSetSchema struct {
 	Function      RoutineObj
 	NewSchemaName Name
 	Procedure     bool
+	Aggregate     bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AlterRoutineSetSchema) Format(ctx *FmtCtx) {
 	if node.Procedure {
 		ctx.WriteString("ALTER PROCEDURE ")
+	} else if node.Aggregate {
+		ctx.WriteString("ALTER AGGREGATE ")
 	} else {
 		ctx.WriteString("ALTER FUNCTION ")
 	}
@@ -571,18 +852,21 @@ func (node *AlterRoutineSetSchema) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.NewSchemaName)
 }
 
-// AlterRoutineSetOwner represents the ALTER FUNCTION...OWNER TO or
-// ALTER PROCEDURE...OWNER TO statement.
+// AlterRoutineSetOwner represents the ALTER FUNCTION...OWNER TO,
+// ALTER PROCEDURE...OWNER TO or ALTER AGGREGATE...OWNER TO statement.
 type AlterRoutineSetOwner struct {
 	Function  RoutineObj
 	NewOwner  RoleSpec
 	Procedure bool
+	Aggregate bool
 }
 
 // Format implements the NodeFormatter interface.
 func (node *AlterRoutineSetOwner) Format(ctx *FmtCtx) {
 	if node.Procedure {
 		ctx.WriteString("ALTER PROCEDURE ")
+	} else if node.Aggregate {
+		ctx.WriteString("ALTER AGGREGATE ")
 	} else {
 		ctx.WriteString("ALTER FUNCTION ")
 	}
@@ -664,11 +948,12 @@ func ComputedColumnExprContext(isVirtual bool) SchemaExprContext {
 // routine options in the given slice.
 func ValidateRoutineOptions(options RoutineOptions, isProc bool) error {
 	var hasLang, hasBody, hasLeakProof, hasVolatility, hasNullInputBehavior, hasSecurity bool
+	var hasCost, hasRows, hasParallel bool
 	conflictingErr := func(opt RoutineOption) error {
 		return errors.Wrapf(ErrConflictingRoutineOption, "%s", AsString(opt))
 	}
 	for _, option := range options {
-		switch option.(type) {
+		switch t := option.(type) {
 		case RoutineLanguage:
 			if hasLang {
 				return conflictingErr(option)
@@ -708,6 +993,39 @@ func ValidateRoutineOptions(options RoutineOptions, isProc bool) error {
 				return conflictingErr(option)
 			}
 			hasSecurity = true
+		case RoutineCost:
+			if isProc {
+				return pgerror.Newf(pgcode.InvalidFunctionDefinition, "cost attribute not allowed in procedure definition")
+			}
+			if hasCost {
+				return conflictingErr(option)
+			}
+			if t <= 0 {
+				return pgerror.New(pgcode.InvalidParameterValue, "COST must be positive")
+			}
+			hasCost = true
+		case RoutineRows:
+			if isProc {
+				return pgerror.Newf(pgcode.InvalidFunctionDefinition, "rows attribute not allowed in procedure definition")
+			}
+			if hasRows {
+				return conflictingErr(option)
+			}
+			if t <= 0 {
+				return pgerror.New(pgcode.InvalidParameterValue, "ROWS must be positive")
+			}
+			hasRows = true
+		case RoutineParallel:
+			if isProc {
+				return pgerror.Newf(pgcode.InvalidFunctionDefinition, "parallel attribute not allowed in procedure definition")
+			}
+			if hasParallel {
+				return conflictingErr(option)
+			}
+			hasParallel = true
+		case *RoutineSetVar:
+			// Multiple SET and RESET clauses are allowed; later clauses override
+			// earlier ones.
 		default:
 			return pgerror.Newf(pgcode.InvalidParameterValue, "unknown function option: ", AsString(option))
 		}
@@ -1272,6 +1272,11 @@ type FuncExpr struct {
 	// InCall is true when the FuncExpr is part of a CALL statement.
 	InCall bool
 
+	// Variadic is true when the last argument is marked with the VARIADIC
+	// keyword, e.g. f(1, VARIADIC ARRAY[2, 3]). The argument is then passed
+	// directly as the array for the VARIADIC parameter of the function.
+	Variadic bool
+
 	typeAnnotation
 	fnProps *FunctionProperties
 	fn      *Overload
@@ -1387,7 +1392,17 @@ func (node *FuncExpr) Format(ctx *FmtCtx) {
 
 	ctx.WriteByte('(')
 	ctx.WriteString(typ)
-	ctx.FormatNode(&node.Exprs)
+	if node.Variadic && len(node.Exprs) > 0 {
+		fixedArgs := node.Exprs[:len(node.Exprs)-1]
+		if len(fixedArgs) > 0 {
+			ctx.FormatNode(&fixedArgs)
+			ctx.WriteString(", ")
+		}
+		ctx.WriteString("VARIADIC ")
+		ctx.FormatNode(node.Exprs[len(node.Exprs)-1])
+	} else {
+		ctx.FormatNode(&node.Exprs)
+	}
 	if node.AggType == GeneralAgg && len(node.OrderBy) > 0 {
 		ctx.WriteByte(' ')
 		ctx.FormatNode(&node.OrderBy)
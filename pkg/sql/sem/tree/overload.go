@@ -299,11 +299,49 @@ type Overload struct {
 	// UDFContainsOnlySignature is false, then DEFAULT expressions are included
 	// into RoutineParams.
 	DefaultExprs Exprs
+	// Variadic is true if the last input parameter of a user-defined routine
+	// is a VARIADIC parameter. Types contains the array type of that
+	// parameter, and unless the VARIADIC keyword is used in the invocation,
+	// the caller supplies one or more arguments of the array element type in
+	// its place.
+	Variadic bool
 
 	// SecurityMode is true when privilege checks during function execution
 	// should be performed against the function owner rather than the invoking
 	// user.
 	SecurityMode RoutineSecurity
+
+	// Cost is the user-provided estimated execution cost of a user-defined
+	// routine, in units of cpu_operator_cost. It is zero if no COST was
+	// specified.
+	Cost float64
+
+	// Rows is the user-provided estimated number of rows returned by a
+	// set-returning user-defined routine. It is zero if no ROWS was specified.
+	Rows float64
+
+	// SessionVars contains the session variables set by the SET clauses of a
+	// user-defined routine.
+	SessionVars []RoutineSessionVar
+
+	// UDFAggregate is set for user-defined aggregate functions, which have
+	// Class AggregateClass. It is only set when UDFContainsOnlySignature is
+	// false.
+	UDFAggregate *UDFAggregate
+}
+
+// UDFAggregate describes how a user-defined aggregate function is evaluated
+// using other user-defined functions.
+type UDFAggregate struct {
+	// StateFunc is the OID of the state transition function.
+	StateFunc oid.Oid
+	// StateType is the type of the aggregate state.
+	StateType *types.T
+	// FinalFunc is the OID of the final function, or zero if there is none.
+	FinalFunc oid.Oid
+	// InitCond is the string form of the initial state, or nil if the initial
+	// state is NULL.
+	InitCond *string
 }
 
 // params implements the overloadImpl interface.
@@ -549,6 +587,24 @@ func (p ParamTypes) String() string {
 	return s.String()
 }
 
+// ExpandVariadic returns the parameter list with the trailing VARIADIC
+// parameter, which must be of an array type, replaced by enough parameters of
+// its element type to accept numArgs arguments. It returns false if numArgs
+// does not include at least one argument for the VARIADIC parameter.
+func (p ParamTypes) ExpandVariadic(numArgs int) (_ ParamTypes, ok bool) {
+	if len(p) == 0 || numArgs < len(p) {
+		return nil, false
+	}
+	variadicParam := p[len(p)-1]
+	elemParam := ParamType{Name: variadicParam.Name, Typ: variadicParam.Typ.ArrayContents()}
+	expanded := make(ParamTypes, numArgs)
+	copy(expanded, p[:len(p)-1])
+	for i := len(p) - 1; i < numArgs; i++ {
+		expanded[i] = elemParam
+	}
+	return expanded, true
+}
+
 // StringWithDefaultExprs extends the stringified form of ParamTypes with the
 // corresponding DEFAULT expressions. defaultExprs is expected to have length no
 // longer than p and to correspond to the "suffix" of p.
@@ -808,6 +864,27 @@ func getOverloadTypeChecker(o overloadSet, exprs ...Expr) *overloadTypeChecker {
 	return s
 }
 
+// expandVariadicParams replaces the parameter lists of VARIADIC user-defined
+// routine overloads with lists that accept the arguments of an invocation which
+// does not use the VARIADIC keyword. The trailing VARIADIC array parameter is
+// replaced by one parameter of the array element type for each remaining
+// argument.
+func (s *overloadTypeChecker) expandVariadicParams() {
+	for i := range s.overloads {
+		ol, ok := s.overloads[i].(*Overload)
+		if !ok || !ol.Variadic {
+			continue
+		}
+		params, ok := ol.Types.(ParamTypes)
+		if !ok {
+			continue
+		}
+		if expanded, ok := params.ExpandVariadic(len(s.exprs)); ok {
+			s.params[i] = expanded
+		}
+	}
+}
+
 func (s *overloadTypeChecker) release() {
 	for i := range s.overloads {
 		s.overloads[i] = nil
@@ -1064,13 +1141,15 @@ func (s *overloadTypeChecker) typeCheckOverloadedExprs(
 
 	// Remove any overloads with polymorphic parameters for which the supplied
 	// argument types are invalid.
-	s.overloadIdxs = filterOverloads(s.overloadIdxs, s.overloads, func(o overloadImpl) bool {
+	s.overloadIdxs = filterParams(s.overloadIdxs, s.overloads, s.params, func(o overloadImpl, p TypeList) bool {
 		ol, ok := o.(*Overload)
 		if !ok || ol.Type == BuiltinRoutine {
 			// Don't filter builtin routines.
 			return true
 		}
-		params := ol.Types.(ParamTypes)
+		// Note that the parameter list of a VARIADIC routine has already been
+		// expanded to match the supplied arguments, if necessary.
+		params := p.(ParamTypes)
 		var outParams ParamTypes
 		if ol.Type == ProcedureRoutine && foundOutParams {
 			outParams = ol.OutParamTypes.(ParamTypes)
@@ -1111,6 +1190,11 @@ func (s *overloadTypeChecker) typeCheckOverloadedExprs(
 		); !ok {
 			return false
 		}
+		if ok, _, _ = ResolveAnyCompatibleArgType(
+			params[:len(argTypes)], argTypes, false, /* enforceConsistency */
+		); !ok {
+			return false
+		}
 		if ol.Type != ProcedureRoutine || !foundOutParams {
 			return true
 		}
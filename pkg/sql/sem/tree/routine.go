@@ -141,6 +141,38 @@ type RoutineExpr struct {
 	// CursorDeclaration contains the information needed to open a SQL cursor with
 	// the result of the *first* body statement. It may be unset.
 	CursorDeclaration *RoutineOpenCursor
+
+	// ResultBuffer is set for a set-returning PL/pgSQL routine. The rows
+	// returned by the routine are those added to the buffer by RETURN NEXT and
+	// RETURN QUERY statements, rather than the result of the last body statement.
+	ResultBuffer *RoutineResultBuffer
+
+	// AppendToResultBuffer, if set, indicates that the result of the *first*
+	// body statement should be added to the given buffer, which is owned by a
+	// set-returning ancestor routine. It may be unset.
+	AppendToResultBuffer *RoutineResultBuffer
+
+	// ResumeAfterTxnControl is set for a routine that continues execution of a
+	// stored procedure after a nested CALL statement, when the called procedure
+	// may COMMIT or ROLLBACK. If the nested procedure paused execution, the
+	// routine is not evaluated; instead, ResumeAfterTxnControl is used to build
+	// a plan that resumes both the nested and the calling procedure in the new
+	// transaction. It may be unset.
+	ResumeAfterTxnControl StoredProcResumeGenerator
+
+	// SessionVars contains the session variables set by the SET clauses of the
+	// routine definition. They are applied for the duration of the routine's
+	// execution, and the previous values are restored when it finishes.
+	SessionVars []RoutineSessionVar
+}
+
+// RoutineSessionVar is a session variable setting from the SET clause of a
+// routine definition.
+type RoutineSessionVar struct {
+	// Name is the name of the session variable.
+	Name string
+	// Value is the string form of the value of the variable.
+	Value string
 }
 
 // NewTypedRoutineExpr returns a new RoutineExpr that is well-typed.
@@ -159,22 +191,26 @@ func NewTypedRoutineExpr(
 	blockStart bool,
 	blockState *BlockState,
 	cursorDeclaration *RoutineOpenCursor,
+	resultBuffer *RoutineResultBuffer,
+	appendToResultBuffer *RoutineResultBuffer,
 ) *RoutineExpr {
 	return &RoutineExpr{
-		Args:              args,
-		ForEachPlan:       gen,
-		Typ:               typ,
-		EnableStepping:    enableStepping,
-		Name:              name,
-		CalledOnNullInput: calledOnNullInput,
-		MultiColOutput:    multiColOutput,
-		Generator:         generator,
-		TailCall:          tailCall,
-		Procedure:         procedure,
-		TriggerFunc:       triggerFunc,
-		BlockStart:        blockStart,
-		BlockState:        blockState,
-		CursorDeclaration: cursorDeclaration,
+		Args:                 args,
+		ForEachPlan:          gen,
+		Typ:                  typ,
+		EnableStepping:       enableStepping,
+		Name:                 name,
+		CalledOnNullInput:    calledOnNullInput,
+		MultiColOutput:       multiColOutput,
+		Generator:            generator,
+		TailCall:             tailCall,
+		Procedure:            procedure,
+		TriggerFunc:          triggerFunc,
+		BlockStart:           blockStart,
+		BlockState:           blockState,
+		CursorDeclaration:    cursorDeclaration,
+		ResultBuffer:         resultBuffer,
+		AppendToResultBuffer: appendToResultBuffer,
 	}
 }
 
@@ -215,6 +251,17 @@ type RoutineExceptionHandler struct {
 	Actions []*RoutineExpr
 }
 
+// HasCode returns true if the exception handler explicitly names the given
+// code, ignoring the OTHERS condition.
+func (h *RoutineExceptionHandler) HasCode(code pgcode.Code) bool {
+	for _, c := range h.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
 // RoutineOpenCursor stores the information needed to correctly open a cursor
 // with the output of a routine.
 type RoutineOpenCursor struct {
@@ -231,6 +278,23 @@ type RoutineOpenCursor struct {
 	CursorSQL string
 }
 
+// RoutineResultBuffer accumulates the rows returned by a set-returning PL/pgSQL
+// routine. The routine that owns the buffer sets Writer before executing its
+// body statements, and sub-routines that implement RETURN NEXT and RETURN
+// QUERY add their rows through it. The rows are buffered in a row container
+// that spills to disk, so the full result set need not fit in memory. The rows
+// are only returned once the routine finishes; they are not streamed.
+type RoutineResultBuffer struct {
+	// Writer accepts the rows that are added to the buffer. It currently maps to
+	// the rowResultWriter interface in the sql package. We use the empty
+	// interface here to avoid import cycles.
+	Writer RoutineResultWriter
+}
+
+// RoutineResultWriter is used to add rows to a RoutineResultBuffer. See the
+// Writer field of RoutineResultBuffer.
+type RoutineResultWriter interface{}
+
 // BlockState is shared state between all routines that make up a PLpgSQL block.
 // It allows for coordination between the routines for exception handling.
 type BlockState struct {
@@ -278,6 +342,15 @@ type BlockState struct {
 	// PL/pgSQL block. It is used to close (only) cursors which were opened within
 	// the scope of the block when an exception is caught.
 	CursorTimestamp *time.Time
+
+	// InProcedure is true if the block belongs to a procedure. A procedure is
+	// always invoked by a CALL statement of its own, so once one of its blocks
+	// catches an error, the rest of the enclosing statement consists only of the
+	// procedure's own statements. Transaction retry errors, which move the
+	// transaction to a new read snapshot when caught, are only caught by such
+	// blocks. Otherwise, the rest of an enclosing statement which had already
+	// read at the old snapshot would observe the new one.
+	InProcedure bool
 }
 
 // StoredProcTxnOp indicates whether a stored procedure has requested that the
@@ -314,12 +387,22 @@ type TxnControlPlanGenerator func(
 	ctx context.Context, args Datums,
 ) (StoredProcContinuation, error)
 
+// StoredProcResumeGenerator builds the plan for a StoredProcContinuation that
+// resumes execution of a stored procedure after a nested CALL statement paused
+// in order to execute a COMMIT or ROLLBACK statement. The given nested
+// continuation resumes execution of the called procedure, and its result is
+// supplied to the calling procedure in place of the last argument.
+type StoredProcResumeGenerator func(
+	ctx context.Context, args Datums, nested StoredProcContinuation,
+) (StoredProcContinuation, error)
+
 // TxnControlExpr implements PL/pgSQL COMMIT and ROLLBACK statements. It directs
 // the session to end the current transaction, and provides a plan to resume
 // execution in a new transaction in the form of StoredProcContinuation.
 type TxnControlExpr struct {
 	Op    StoredProcTxnOp
 	Modes TransactionModes
+	Chain bool
 	Args  TypedExprs
 	Gen   TxnControlPlanGenerator
 
@@ -333,6 +416,7 @@ var _ Expr = &TxnControlExpr{}
 func NewTxnControlExpr(
 	opType StoredProcTxnOp,
 	txnModes TransactionModes,
+	chain bool,
 	args TypedExprs,
 	gen TxnControlPlanGenerator,
 	name string,
@@ -341,6 +425,7 @@ func NewTxnControlExpr(
 	return &TxnControlExpr{
 		Op:    opType,
 		Modes: txnModes,
+		Chain: chain,
 		Args:  args,
 		Gen:   gen,
 		Name:  name,
@@ -367,7 +452,11 @@ func (node *TxnControlExpr) Format(ctx *FmtCtx) {
 			panic(errors.AssertionFailedf("called Format for no-op txn control expr"))
 		}
 	}
-	ctx.Printf("%s; CALL %s(", node.Op, node.Name)
+	ctx.Printf("%s", node.Op)
+	if node.Chain {
+		ctx.WriteString(" AND CHAIN")
+	}
+	ctx.Printf("; CALL %s(", node.Name)
 	ctx.FormatNode(&node.Args)
 	ctx.WriteByte(')')
 }
@@ -474,6 +474,26 @@ func (node *ShowDatabaseIndexes) Format(ctx *FmtCtx) {
 	}
 }
 
+// ShowIndexRecommendations represents a SHOW INDEX RECOMMENDATIONS statement.
+// If Table is nil, the recommendations for the indexes of all the tables in
+// Database are shown.
+type ShowIndexRecommendations struct {
+	Table    *UnresolvedObjectName
+	Database Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowIndexRecommendations) Format(ctx *FmtCtx) {
+	ctx.WriteString("SHOW INDEX RECOMMENDATIONS")
+	if node.Table != nil {
+		ctx.WriteString(" FROM ")
+		ctx.FormatNode(node.Table)
+	} else if node.Database != "" {
+		ctx.WriteString(" FROM DATABASE ")
+		ctx.FormatNode(&node.Database)
+	}
+}
+
 // ShowQueries represents a SHOW STATEMENTS statement.
 type ShowQueries struct {
 	All     bool
@@ -1358,6 +1378,14 @@ const (
 	// ScheduledChangefeedExecutor is an executor responsible for
 	// the execution of the scheduled changefeeds.
 	ScheduledChangefeedExecutor
+
+	// ScheduledIndexAdvisorExecutor is an executor responsible for the
+	// computation of workload index recommendations.
+	ScheduledIndexAdvisorExecutor
+
+	// ScheduledIndexDropExecutor is an executor responsible for making an index
+	// not visible, observing the workload, and then dropping the index.
+	ScheduledIndexDropExecutor
 )
 
 var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
@@ -1367,6 +1395,8 @@ var scheduleExecutorInternalNames = map[ScheduledJobExecutorType]string{
 	ScheduledRowLevelTTLExecutor:        "scheduled-row-level-ttl-executor",
 	ScheduledSchemaTelemetryExecutor:    "scheduled-schema-telemetry-executor",
 	ScheduledChangefeedExecutor:         "scheduled-changefeed-executor",
+	ScheduledIndexAdvisorExecutor:       "scheduled-index-advisor-executor",
+	ScheduledIndexDropExecutor:          "scheduled-index-drop-executor",
 }
 
 // InternalName returns an internal executor name.
@@ -1388,6 +1418,10 @@ func (t ScheduledJobExecutorType) UserName() string {
 		return "SCHEMA TELEMETRY"
 	case ScheduledChangefeedExecutor:
 		return "CHANGEFEED"
+	case ScheduledIndexAdvisorExecutor:
+		return "INDEX ADVISOR"
+	case ScheduledIndexDropExecutor:
+		return "INDEX DROP"
 	}
 	return "unsupported-executor"
 }
@@ -115,3 +115,25 @@ func (node *Scatter) Format(ctx *FmtCtx) {
 		ctx.WriteString(")")
 	}
 }
+
+// RunGC represents an `ALTER RANGE .. GC` or `ALTER TABLE .. GC NOW`
+// statement.
+type RunGC struct {
+	// RangeID is set for ALTER RANGE .. GC.
+	RangeID Expr
+	// TableOrIndex is set for ALTER TABLE .. GC NOW.
+	TableOrIndex TableIndexName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RunGC) Format(ctx *FmtCtx) {
+	if node.RangeID != nil {
+		ctx.WriteString("ALTER RANGE ")
+		ctx.FormatNode(node.RangeID)
+		ctx.WriteString(" GC")
+		return
+	}
+	ctx.WriteString("ALTER TABLE ")
+	ctx.FormatNode(&node.TableOrIndex)
+	ctx.WriteString(" GC NOW")
+}
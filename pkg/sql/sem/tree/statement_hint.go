@@ -0,0 +1,51 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+
+// CreateStatementHint represents a CREATE STATEMENT HINT statement.
+type CreateStatementHint struct {
+	Fingerprint string
+	Hint        string
+}
+
+var _ Statement = &CreateStatementHint{}
+
+// Format implements the NodeFormatter interface.
+func (n *CreateStatementHint) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE STATEMENT HINT FOR ")
+	formatStatementHintString(ctx, n.Fingerprint)
+	ctx.WriteString(" USING ")
+	formatStatementHintString(ctx, n.Hint)
+}
+
+// DropStatementHint represents a DROP STATEMENT HINT statement. If Hint is
+// empty, all hints for the fingerprint are dropped.
+type DropStatementHint struct {
+	Fingerprint string
+	Hint        string
+}
+
+var _ Statement = &DropStatementHint{}
+
+// Format implements the NodeFormatter interface.
+func (n *DropStatementHint) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP STATEMENT HINT FOR ")
+	formatStatementHintString(ctx, n.Fingerprint)
+	if n.Hint != "" {
+		ctx.WriteString(" USING ")
+		formatStatementHintString(ctx, n.Hint)
+	}
+}
+
+func formatStatementHintString(ctx *FmtCtx, s string) {
+	if ctx.flags.HasFlags(FmtHideConstants) {
+		ctx.WriteString("'_'")
+	} else {
+		lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, s, ctx.flags.EncodeFlags())
+	}
+}
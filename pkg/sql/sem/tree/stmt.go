@@ -88,6 +88,7 @@ const (
 	AlterTableTag          = "ALTER TABLE"
 	BackupTag              = "BACKUP"
 	CreateIndexTag         = "CREATE INDEX"
+	CreateAggregateTag     = "CREATE AGGREGATE"
 	CreateFunctionTag      = "CREATE FUNCTION"
 	CreateProcedureTag     = "CREATE PROCEDURE"
 	CreateTriggerTag       = "CREATE TRIGGER"
@@ -102,6 +103,7 @@ const (
 	CommentOnTableTag      = "COMMENT ON TABLE"
 	CommentOnTypeTag       = "COMMENT ON TYPE"
 	DropDatabaseTag        = "DROP DATABASE"
+	DropAggregateTag       = "DROP AGGREGATE"
 	DropFunctionTag        = "DROP FUNCTION"
 	DropProcedureTag       = "DROP PROCEDURE"
 	DropTriggerTag         = "DROP TRIGGER"
@@ -176,7 +178,7 @@ func CanWriteData(stmt Statement) bool {
 	case *Backup:
 		return true
 	// CockroachDB extensions.
-	case *Split, *Unsplit, *Relocate, *RelocateRange, *Scatter:
+	case *Split, *Unsplit, *Relocate, *RelocateRange, *Scatter, *RunGC:
 		return true
 	// Replication operations.
 	case *CreateTenantFromReplication, *AlterTenantReplication, *CreateLogicalReplicationStream:
@@ -1035,6 +1037,15 @@ func (*CreateStats) StatementType() StatementType { return TypeDDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CreateStats) StatementTag() string { return "CREATE STATISTICS" }
 
+// StatementReturnType implements the Statement interface.
+func (*CreateStatementHint) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*CreateStatementHint) StatementType() StatementType { return TypeDCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateStatementHint) StatementTag() string { return "CREATE STATEMENT HINT" }
+
 // StatementReturnType implements the Statement interface.
 func (*Deallocate) StatementReturnType() StatementReturnType { return Ack }
 
@@ -1169,6 +1180,15 @@ func (*DropTenant) StatementType() StatementType { return TypeDCL }
 // StatementTag implements the Statement interface.
 func (*DropTenant) StatementTag() string { return "DROP VIRTUAL CLUSTER" }
 
+// StatementReturnType implements the Statement interface.
+func (*DropStatementHint) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*DropStatementHint) StatementType() StatementType { return TypeDCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropStatementHint) StatementTag() string { return "DROP STATEMENT HINT" }
+
 // StatementReturnType implements the Statement interface.
 func (*Execute) StatementReturnType() StatementReturnType { return Unknown }
 
@@ -1456,6 +1476,15 @@ func (*RollbackTransaction) StatementType() StatementType { return TypeTCL }
 // StatementTag returns a short string identifying the type of statement.
 func (*RollbackTransaction) StatementTag() string { return "ROLLBACK" }
 
+// StatementReturnType implements the Statement interface.
+func (*RunGC) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*RunGC) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*RunGC) StatementTag() string { return "GC" }
+
 // StatementReturnType implements the Statement interface.
 func (*Savepoint) StatementReturnType() StatementReturnType { return Ack }
 
@@ -1745,6 +1774,15 @@ func (*ShowIndexes) StatementType() StatementType { return TypeDML }
 // StatementTag returns a short string identifying the type of statement.
 func (*ShowIndexes) StatementTag() string { return "SHOW INDEXES FROM TABLE" }
 
+// StatementReturnType implements the Statement interface.
+func (*ShowIndexRecommendations) StatementReturnType() StatementReturnType { return Rows }
+
+// StatementType implements the Statement interface.
+func (*ShowIndexRecommendations) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*ShowIndexRecommendations) StatementTag() string { return "SHOW INDEX RECOMMENDATIONS" }
+
 // StatementReturnType implements the Statement interface.
 func (*ShowPartitions) StatementReturnType() StatementReturnType { return Rows }
 
@@ -2219,6 +2257,15 @@ func (n *CreateRoutine) StatementTag() string {
 	return CreateFunctionTag
 }
 
+// StatementReturnType implements the Statement interface.
+func (*CreateAggregate) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateAggregate) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CreateAggregate) StatementTag() string { return CreateAggregateTag }
+
 // StatementReturnType implements the Statement interface.
 func (*RoutineReturn) StatementReturnType() StatementReturnType { return Rows }
 
@@ -2239,6 +2286,9 @@ func (n *DropRoutine) StatementTag() string {
 	if n.Procedure {
 		return DropProcedureTag
 	}
+	if n.Aggregate {
+		return DropAggregateTag
+	}
 	return DropFunctionTag
 }
 
@@ -2283,6 +2333,8 @@ func (*AlterRoutineRename) StatementType() StatementType { return TypeDDL }
 func (n *AlterRoutineRename) StatementTag() string {
 	if n.Procedure {
 		return "ALTER PROCEDURE"
+	} else if n.Aggregate {
+		return "ALTER AGGREGATE"
 	} else {
 		return "ALTER FUNCTION"
 	}
@@ -2298,6 +2350,8 @@ func (*AlterRoutineSetSchema) StatementType() StatementType { return TypeDDL }
 func (n *AlterRoutineSetSchema) StatementTag() string {
 	if n.Procedure {
 		return "ALTER PROCEDURE"
+	} else if n.Aggregate {
+		return "ALTER AGGREGATE"
 	} else {
 		return "ALTER FUNCTION"
 	}
@@ -2313,6 +2367,8 @@ func (*AlterRoutineSetOwner) StatementType() StatementType { return TypeDDL }
 func (n *AlterRoutineSetOwner) StatementTag() string {
 	if n.Procedure {
 		return "ALTER PROCEDURE"
+	} else if n.Aggregate {
+		return "ALTER AGGREGATE"
 	} else {
 		return "ALTER FUNCTION"
 	}
@@ -2403,6 +2459,7 @@ func (n *CommitTransaction) String() string                   { return AsString(
 func (n *CopyFrom) String() string                            { return AsString(n) }
 func (n *CopyTo) String() string                              { return AsString(n) }
 func (n *CreateChangefeed) String() string                    { return AsString(n) }
+func (n *CreateAggregate) String() string                     { return AsString(n) }
 func (n *CreateDatabase) String() string                      { return AsString(n) }
 func (n *CreateExtension) String() string                     { return AsString(n) }
 func (n *CreateRoutine) String() string                       { return AsString(n) }
@@ -2416,6 +2473,7 @@ func (n *CreateTenantFromReplication) String() string         { return AsString(
 func (n *CreateSchema) String() string                        { return AsString(n) }
 func (n *CreateSequence) String() string                      { return AsString(n) }
 func (n *CreateStats) String() string                         { return AsString(n) }
+func (n *CreateStatementHint) String() string                 { return AsString(n) }
 func (n *CreateView) String() string                          { return AsString(n) }
 func (n *Deallocate) String() string                          { return AsString(n) }
 func (n *Delete) String() string                              { return AsString(n) }
@@ -2432,6 +2490,7 @@ func (n *DropType) String() string                            { return AsString(
 func (n *DropView) String() string                            { return AsString(n) }
 func (n *DropRole) String() string                            { return AsString(n) }
 func (n *DropTenant) String() string                          { return AsString(n) }
+func (n *DropStatementHint) String() string                   { return AsString(n) }
 func (n *Execute) String() string                             { return AsString(n) }
 func (n *Explain) String() string                             { return AsString(n) }
 func (n *ExplainAnalyze) String() string                      { return AsString(n) }
@@ -2463,6 +2522,7 @@ func (n *Revoke) String() string                              { return AsString(
 func (n *RevokeRole) String() string                          { return AsString(n) }
 func (n *RollbackToSavepoint) String() string                 { return AsString(n) }
 func (n *RollbackTransaction) String() string                 { return AsString(n) }
+func (n *RunGC) String() string                               { return AsString(n) }
 func (n *Savepoint) String() string                           { return AsString(n) }
 func (n *Scatter) String() string                             { return AsString(n) }
 func (n *ScheduledBackup) String() string                     { return AsString(n) }
@@ -2500,6 +2560,7 @@ func (n *ShowGrants) String() string                          { return AsString(
 func (n *ShowHistogram) String() string                       { return AsString(n) }
 func (n *ShowSchedules) String() string                       { return AsString(n) }
 func (n *ShowIndexes) String() string                         { return AsString(n) }
+func (n *ShowIndexRecommendations) String() string            { return AsString(n) }
 func (n *ShowJobs) String() string                            { return AsString(n) }
 func (n *ShowChangefeedJobs) String() string                  { return AsString(n) }
 func (n *ShowLastQueryStatistics) String() string             { return AsString(n) }
@@ -1206,6 +1206,26 @@ func (expr *FuncExpr) TypeCheck(
 			"%s()", def.Name)
 	}
 
+	if expr.Variadic {
+		// An argument marked with the VARIADIC keyword can only be supplied to
+		// a routine with a VARIADIC parameter, in which case it is matched
+		// against the array type of that parameter.
+		variadicOverloads := make([]QualifiedOverload, 0, len(def.Overloads))
+		var sawBuiltin bool
+		for _, o := range def.Overloads {
+			if o.Variadic {
+				variadicOverloads = append(variadicOverloads, o)
+			} else if o.Type == BuiltinRoutine {
+				sawBuiltin = true
+			}
+		}
+		if len(variadicOverloads) == 0 && sawBuiltin {
+			return nil, unimplemented.NewWithIssuef(88947,
+				"VARIADIC arguments for builtin function %s()", def.Name)
+		}
+		def = &ResolvedFunctionDefinition{Name: def.Name, Overloads: variadicOverloads}
+	}
+
 	typeNames := func(typedExprs []TypedExpr) string {
 		var sb strings.Builder
 		sb.WriteByte('(')
@@ -1223,6 +1243,9 @@ func (expr *FuncExpr) TypeCheck(
 		(*qualifiedOverloads)(&def.Overloads), expr.Exprs...,
 	)
 	defer s.release()
+	if !expr.Variadic {
+		s.expandVariadicParams()
+	}
 
 	if err = expr.typeCheckWithFuncAncestor(semaCtx, func() error {
 		if err := s.typeCheckOverloadedExprs(ctx, semaCtx, desired, false /* inBinOp */); err != nil {
@@ -1251,6 +1274,9 @@ func (expr *FuncExpr) TypeCheck(
 				}()
 				s2 := getOverloadTypeChecker((*qualifiedOverloads)(&functionOverloads), expr.Exprs...)
 				defer s2.release()
+				if !expr.Variadic {
+					s2.expandVariadicParams()
+				}
 				err2 := s2.typeCheckOverloadedExprs(ctx, semaCtx, desired, false /* inBinOp */)
 				if err2 == nil && len(s2.overloadIdxs) > 0 {
 					// This time we found a match, so return the proper error.
@@ -3643,10 +3669,13 @@ func getMostSignificantOverload(
 // true if the supplied argument types are valid, as well as the determined
 // element type (nil if there were no polymorphic parameters).
 //
-// CRDB currently supports two polymorphic types:
+// ResolvePolymorphicArgTypes handles two polymorphic types:
 // * ANYELEMENT allows any argument type.
 // * ANYARRAY allows only array types.
 //
+// The ANYCOMPATIBLE family of polymorphic types is resolved separately by
+// ResolveAnyCompatibleArgType.
+//
 // The rules for argument validity are as follows:
 //  1. The arguments supplied for ANYELEMENT parameters must all have the same
 //     type.
@@ -3668,7 +3697,7 @@ func ResolvePolymorphicArgTypes(
 	var anyArrayTyp *types.T
 	for i := range paramTypes {
 		paramTyp := paramTypes[i].Typ
-		if !paramTyp.IsPolymorphicType() {
+		if !paramTyp.IsPolymorphicType() || paramTyp.IsAnyCompatibleType() {
 			continue
 		}
 		argTyp := argTypes[i]
@@ -3733,6 +3762,70 @@ func ResolvePolymorphicArgTypes(
 	return true, numPolyParams, anyElemTyp
 }
 
+// ResolveAnyCompatibleArgType iterates through the list of routine parameters
+// and supplied arguments, and attempts to determine the common concrete type
+// for any parameters of the ANYCOMPATIBLE family of polymorphic types. It
+// returns true if the supplied argument types are valid, the number of
+// ANYCOMPATIBLE parameters, and the determined common type (nil if there were
+// no such parameters, or if all of the supplied arguments were NULL).
+//
+// The rules for argument validity are as follows:
+//  1. The arguments supplied for ANYCOMPATIBLE parameters, as well as the
+//     element types of the arguments supplied for ANYCOMPATIBLEARRAY
+//     parameters, must all be implicitly castable to a common type.
+//  2. The arguments supplied for ANYCOMPATIBLEARRAY parameters must be arrays.
+//  3. NULL arguments are exempt from the above two rules.
+//
+// enforceConsistency, if true, indicates that ResolveAnyCompatibleArgType
+// should throw a suitable error in the case of invalid arguments, rather than
+// returning with ok=false.
+func ResolveAnyCompatibleArgType(
+	paramTypes ParamTypes, argTypes []*types.T, enforceConsistency bool,
+) (ok bool, numPolyParams int, _ *types.T) {
+	var commonTyp *types.T
+	for i := range paramTypes {
+		paramTyp := paramTypes[i].Typ
+		if !paramTyp.IsAnyCompatibleType() {
+			continue
+		}
+		argTyp := argTypes[i]
+		numPolyParams++
+		if argTyp.Family() == types.UnknownFamily {
+			continue
+		}
+		if paramTyp.Family() == types.ArrayFamily {
+			if argTyp.Family() != types.ArrayFamily {
+				if enforceConsistency {
+					panic(pgerror.Newf(pgcode.DatatypeMismatch,
+						"argument declared anycompatiblearray is not an array but type %s", argTyp,
+					))
+				}
+				return false, 0, nil
+			}
+			argTyp = argTyp.ArrayContents()
+		}
+		switch {
+		case commonTyp == nil:
+			commonTyp = argTyp
+		case commonTyp.Identical(argTyp):
+		case cast.ValidCast(commonTyp, argTyp, cast.ContextImplicit):
+			// Prefer the type that the other arguments can be implicitly cast
+			// to.
+			commonTyp = argTyp
+		case cast.ValidCast(argTyp, commonTyp, cast.ContextImplicit):
+		default:
+			if enforceConsistency {
+				err := pgerror.New(pgcode.DatatypeMismatch,
+					"arguments of anycompatible family cannot be cast to a common type",
+				)
+				panic(errors.WithDetailf(err, "%s versus %s", commonTyp, argTyp))
+			}
+			return false, 0, nil
+		}
+	}
+	return true, numPolyParams, commonTyp
+}
+
 // UnsupportedTypeChecker is used to check that a type is supported by the
 // current cluster version. It is an interface because some packages cannot
 // import the clusterversion package.
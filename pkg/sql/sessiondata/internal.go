@@ -77,6 +77,9 @@ type InternalExecutorOverride struct {
 	// executor session is responsible for ensuring that every row it writes via
 	// the internal executor had this origin timestamp.
 	OriginTimestampForLogicalDataReplication hlc.Timestamp
+	// AllowMaterializedViewMutations, if true, allows the statements to write
+	// directly to materialized views.
+	AllowMaterializedViewMutations bool
 	// PlanCacheMode, if set, overrides the plan_cache_mode session variable.
 	PlanCacheMode *sessiondatapb.PlanCacheMode
 	// GrowStackSize, if true, indicates that the connExecutor goroutine stack
@@ -228,6 +228,14 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 		maybeWriteComma(f)
 		f.Printf("\tlease_preferences = %s", lexbase.EscapeSQLString(prefs))
 	}
+	if zone.RateLimitRequestsPerSecond != nil {
+		maybeWriteComma(f)
+		f.Printf("\trate_limit.requests_per_second = %d", *zone.RateLimitRequestsPerSecond)
+	}
+	if zone.RateLimitWriteBytesPerSecond != nil {
+		maybeWriteComma(f)
+		f.Printf("\trate_limit.write_bytes_per_second = %d", *zone.RateLimitWriteBytesPerSecond)
+	}
 	return f.String(), nil
 }
 
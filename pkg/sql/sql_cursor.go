@@ -11,12 +11,16 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/clusterunique"
 	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser/statements"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -330,6 +334,85 @@ func (p *planner) PLpgSQLFetchCursor(
 	return res, err
 }
 
+// PLpgSQLOpenDynamicCursor opens a cursor with the given name over the rows of
+// the given query string, binding the given parameters to its placeholders. It
+// is used to implement the PLpgSQL OPEN ... FOR EXECUTE statement, as well as
+// FOR loops over dynamic queries.
+//
+// The query is executed with the internal executor in the current transaction.
+// Similar to cursors opened for static queries within a routine, the query is
+// executed eagerly, and its result is stored in a row container. If withMarker
+// is true, a non-NULL BOOL column is prepended to each row. This allows the
+// caller to distinguish a row from the NULL result of fetching from an
+// exhausted cursor.
+func (p *planner) PLpgSQLOpenDynamicCursor(
+	ctx context.Context, cursorName tree.Name, query string, params tree.Datums, withMarker bool,
+) (err error) {
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return pgerror.Newf(pgcode.InvalidCursorDefinition,
+			"cannot open %s query as cursor", stmt.AST.StatementTag(),
+		)
+	}
+	if sel.With != nil {
+		for _, cte := range sel.With.CTEList {
+			if _, ok := cte.Stmt.(*tree.Select); !ok {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"DECLARE CURSOR must not contain data-modifying statements in WITH",
+				)
+			}
+		}
+	}
+	if err := p.checkIfCursorExists(cursorName); err != nil {
+		return err
+	}
+	rows, err := p.InternalSQLTxn().QueryIteratorEx(
+		ctx, "plpgsql-open-cursor", p.Txn(), sessiondata.NoSessionDataOverride,
+		query, datumsToQueryArgs(params)...,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.CombineErrors(err, rows.Close())
+	}()
+	resultCols := rows.Types()
+	if withMarker {
+		resultCols = append(colinfo.ResultColumns{{Name: "marker", Typ: types.Bool}}, resultCols...)
+	}
+	cursorHelper, err := newPLpgSQLCursorHelper(p, cursorName, query, resultCols)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if !cursorHelper.addedCursor {
+			// The cursor was not created, so the helper must be closed here.
+			err = errors.CombineErrors(err, cursorHelper.Close())
+		}
+	}()
+	for {
+		ok, err := rows.Next(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		row := rows.Cur()
+		if withMarker {
+			row = append(tree.Datums{tree.DBoolTrue}, row...)
+		}
+		if err := cursorHelper.container.AddRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	return cursorHelper.createCursor(p)
+}
+
 type sqlCursor struct {
 	isql.Rows
 	// txn is the transaction object that the internal executor for this cursor
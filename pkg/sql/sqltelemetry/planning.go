@@ -148,6 +148,11 @@ var TurnAutoStatsOffUseCounter = telemetry.GetCounterOnce("sql.plan.automatic-st
 // generation is disabled due to an out of memory error.
 var StatsHistogramOOMCounter = telemetry.GetCounterOnce("sql.plan.stats.histogram-oom")
 
+// AdaptiveReplanCounter is to be incremented whenever a statement is
+// re-planned because an operator detected a gross cardinality misestimate
+// during execution.
+var AdaptiveReplanCounter = telemetry.GetCounterOnce("sql.plan.adaptive-replan")
+
 // JoinAlgoHashUseCounter is to be incremented whenever a hash join node is
 // planned.
 var JoinAlgoHashUseCounter = telemetry.GetCounterOnce("sql.plan.opt.node.join.algo.hash")
@@ -0,0 +1,208 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/stmthints"
+	"github.com/cockroachdb/cockroach/pkg/sql/syntheticprivilege"
+	"github.com/cockroachdb/errors"
+)
+
+type createStatementHintNode struct {
+	fingerprint string
+	hint        stmthints.Hint
+}
+
+// CreateStatementHint attaches a hint to a statement fingerprint.
+// Privileges: MODIFYCLUSTERSETTING.
+func (p *planner) CreateStatementHint(
+	ctx context.Context, n *tree.CreateStatementHint,
+) (planNode, error) {
+	if err := p.checkStatementHintsSupported(ctx, n.StatementTag()); err != nil {
+		return nil, err
+	}
+	hint, err := stmthints.Parse(n.Hint)
+	if err != nil {
+		return nil, err
+	}
+	if hint.Kind == stmthints.SessionVarHint {
+		if err := p.validateStatementHintSessionVar(ctx, hint.SessionVar); err != nil {
+			return nil, err
+		}
+	}
+	return &createStatementHintNode{fingerprint: n.Fingerprint, hint: hint}, nil
+}
+
+func (n *createStatementHintNode) startExec(params runParams) error {
+	created, err := params.ExecCfg().StatementHintsCache.Create(params.ctx, n.fingerprint, n.hint)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"statement hint %q already exists for %q", n.hint.String(), n.fingerprint)
+	}
+	return nil
+}
+
+func (n *createStatementHintNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createStatementHintNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createStatementHintNode) Close(context.Context)        {}
+
+type dropStatementHintNode struct {
+	fingerprint string
+	// hint is nil if all hints of the fingerprint are dropped.
+	hint *stmthints.Hint
+}
+
+// DropStatementHint removes one or all of the hints of a statement
+// fingerprint.
+// Privileges: MODIFYCLUSTERSETTING.
+func (p *planner) DropStatementHint(
+	ctx context.Context, n *tree.DropStatementHint,
+) (planNode, error) {
+	if err := p.checkStatementHintsSupported(ctx, n.StatementTag()); err != nil {
+		return nil, err
+	}
+	node := &dropStatementHintNode{fingerprint: n.Fingerprint}
+	if n.Hint != "" {
+		hint, err := stmthints.Parse(n.Hint)
+		if err != nil {
+			return nil, err
+		}
+		node.hint = &hint
+	}
+	return node, nil
+}
+
+func (n *dropStatementHintNode) startExec(params runParams) error {
+	dropped, err := params.ExecCfg().StatementHintsCache.Drop(params.ctx, n.fingerprint, n.hint)
+	if err != nil {
+		return err
+	}
+	if dropped == 0 {
+		if n.hint != nil {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"statement hint %q does not exist for %q", n.hint.String(), n.fingerprint)
+		}
+		return pgerror.Newf(pgcode.UndefinedObject, "no statement hints exist for %q", n.fingerprint)
+	}
+	return nil
+}
+
+func (n *dropStatementHintNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropStatementHintNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropStatementHintNode) Close(context.Context)        {}
+
+func (p *planner) checkStatementHintsSupported(ctx context.Context, stmtTag string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.V25_1_AddStatementHintsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported until upgrade to v25.1 is finalized", stmtTag)
+	}
+	if p.ExecCfg().StatementHintsCache == nil {
+		return errors.AssertionFailedf("statement hints cache is not initialized")
+	}
+	return p.CheckPrivilege(
+		ctx, syntheticprivilege.GlobalPrivilegeObject, privilege.MODIFYCLUSTERSETTING,
+	)
+}
+
+// statementHintSessionVars are the session variables which can be set by a
+// statement hint. The overrides of a statement hint are only in effect while
+// the statement is planned (see makeOptimizerPlan), so only variables which
+// affect planning are allowed; these are the variables whose values are
+// captured by the memo (see memo.Memo.IsStale). Testing and internal
+// variables are excluded.
+var statementHintSessionVars = map[string]struct{}{
+	"allow_ordinal_column_references":                           {},
+	"cost_scans_with_default_col_size":                          {},
+	"disallow_full_table_scans":                                 {},
+	"enable_durable_locking_for_serializable":                   {},
+	"enable_implicit_fk_locking_for_serializable":               {},
+	"enable_shared_locking_for_serializable":                    {},
+	"enable_zigzag_join":                                        {},
+	"enforce_home_region":                                       {},
+	"large_full_scan_rows":                                      {},
+	"locality_optimized_partitioned_index_scan":                 {},
+	"null_ordered_last":                                         {},
+	"opt_split_scan_limit":                                      {},
+	"optimizer_always_use_histograms":                           {},
+	"optimizer_hoist_uncorrelated_equality_subqueries":          {},
+	"optimizer_merge_joins_enabled":                             {},
+	"optimizer_prove_implication_with_virtual_computed_columns": {},
+	"optimizer_push_limit_into_project_filtered_scan":           {},
+	"optimizer_push_offset_into_index_join":                     {},
+	"optimizer_use_conditional_hoist_fix":                       {},
+	"optimizer_use_forecasts":                                   {},
+	"optimizer_use_histograms":                                  {},
+	"optimizer_use_improved_computed_column_filters_derivation": {},
+	"optimizer_use_improved_disjunction_stats":                  {},
+	"optimizer_use_improved_distinct_on_limit_hint_costing":     {},
+	"optimizer_use_improved_join_elimination":                   {},
+	"optimizer_use_improved_multi_column_selectivity_estimate":  {},
+	"optimizer_use_improved_split_disjunction_for_joins":        {},
+	"optimizer_use_improved_trigram_similarity_selectivity":     {},
+	"optimizer_use_improved_zigzag_join_costing":                {},
+	"optimizer_use_limit_ordering_for_streaming_group_by":       {},
+	"optimizer_use_lock_op_for_serializable":                    {},
+	"optimizer_use_merged_partial_statistics":                   {},
+	"optimizer_use_multicol_stats":                              {},
+	"optimizer_use_not_visible_indexes":                         {},
+	"optimizer_use_polymorphic_parameter_fix":                   {},
+	"optimizer_use_provided_ordering_fix":                       {},
+	"optimizer_use_trigram_similarity_optimization":             {},
+	"optimizer_use_virtual_computed_column_stats":               {},
+	"pg_trgm.similarity_threshold":                              {},
+	"prefer_lookup_joins_for_fks":                               {},
+	"propagate_input_ordering":                                  {},
+	"reorder_joins_limit":                                       {},
+	"unconstrained_non_covering_index_scan_enabled":             {},
+	"variable_inequality_lookup_join_enabled":                   {},
+}
+
+// validateStatementHintSessionVar checks that the session variable of a
+// statement hint exists and that the value is valid for it, by applying it to
+// a copy of the session data.
+func (p *planner) validateStatementHintSessionVar(
+	ctx context.Context, sv tree.RoutineSessionVar,
+) error {
+	_, v, err := getSessionVar(sv.Name, false /* missingOk */)
+	if err != nil {
+		return err
+	}
+	if v.Set == nil {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"parameter %q cannot be set by a statement hint", sv.Name)
+	}
+	if _, ok := statementHintSessionVars[sv.Name]; !ok {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.InvalidParameterValue,
+				"parameter %q cannot be set by a statement hint", sv.Name),
+			"statement hints only apply while the statement is planned, so only "+
+				"parameters which affect query planning can be set",
+		)
+	}
+	m := p.sessionDataMutatorIterator.mutator(
+		false /* applyCallbacks */, p.SessionData().Clone(),
+	)
+	return v.Set(ctx, m, sv.Value)
+}
+
+// lookupStatementHints returns the hints of the current statement's
+// fingerprint, or nil if there are none.
+func (p *planner) lookupStatementHints() *stmthints.Hints {
+	if p.execCfg.StatementHintsCache == nil {
+		return nil
+	}
+	return p.execCfg.StatementHintsCache.Get(p.planningFingerprint())
+}
@@ -79,6 +79,15 @@ var MultiColumnStatisticsClusterMode = settings.RegisterBoolSetting(
 	true,
 	settings.WithPublic)
 
+// MultiColumnHistogramsClusterMode controls the cluster setting for enabling
+// collection of histograms on multi-column statistics.
+var MultiColumnHistogramsClusterMode = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.stats.multi_column_histograms.enabled",
+	"set to true to collect histograms on multi-column statistics",
+	false,
+	settings.WithPublic)
+
 // AutomaticStatisticsMaxIdleTime controls the maximum fraction of time that
 // the sampler processors will be idle when scanning large tables for automatic
 // statistics (in high load scenarios). This value can be tuned to trade off
@@ -60,6 +60,11 @@ func (js *JSONStatistic) SetHistogram(h *HistogramData) error {
 	if typ == nil {
 		return fmt.Errorf("histogram type is unset")
 	}
+	if typ.Family() == types.TupleFamily {
+		// Multi-column histograms are omitted, since there is no SQL syntax for
+		// their tuple type that could be parsed by GetHistogram.
+		return nil
+	}
 	// Use the fully qualified type name in case this is part of injected stats
 	// done across databases. If it is a user-defined type, we need the type name
 	// resolution to be for the correct database.
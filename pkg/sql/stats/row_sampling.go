@@ -8,6 +8,7 @@ package stats
 import (
 	"container/heap"
 	"context"
+	"unsafe"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/memsize"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
@@ -238,6 +239,45 @@ func (sr *SampleReservoir) GetNonNullDatums(
 	return
 }
 
+// GetNonNullTuples returns tuples of the values of the specified columns, for
+// each sample in which none of the columns are NULL. The tuples have the given
+// tuple type. The capacity of the reservoir (K) will shrink if we hit a memory
+// limit while building this return slice. If the capacity goes below
+// minNumSamples, GetNonNullTuples will return an error.
+func (sr *SampleReservoir) GetNonNullTuples(
+	ctx context.Context, memAcc *mon.BoundAccount, colIdxs []int, typ *types.T,
+) (values tree.Datums, err error) {
+	err = sr.retryMaybeResize(ctx, func() error {
+		// Account for the memory we'll use copying the samples into tuples.
+		if memAcc != nil {
+			tupleSize := memsize.DatumOverhead + int64(unsafe.Sizeof(tree.DTuple{})) +
+				memsize.DatumOverhead*int64(len(colIdxs))
+			if err := memAcc.Grow(ctx, tupleSize*int64(len(sr.samples))); err != nil {
+				return err
+			}
+		}
+		values = make(tree.Datums, 0, len(sr.samples))
+	SampleLoop:
+		for _, sample := range sr.samples {
+			tuple := make(tree.Datums, len(colIdxs))
+			for i, colIdx := range colIdxs {
+				d := sample.Row[colIdx].Datum
+				if d == nil {
+					values = nil
+					return errors.AssertionFailedf("value in column %d not decoded", colIdx)
+				}
+				if d == tree.DNull {
+					continue SampleLoop
+				}
+				tuple[i] = d
+			}
+			values = append(values, tree.NewDTuple(typ, tuple...))
+		}
+		return nil
+	})
+	return
+}
+
 func (sr *SampleReservoir) copyRow(
 	ctx context.Context, evalCtx *eval.Context, dst, src rowenc.EncDatumRow,
 ) error {
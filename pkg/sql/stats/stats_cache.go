@@ -849,8 +849,8 @@ ORDER BY "createdAt" DESC, "columnIDs" DESC, "statisticID" DESC
 		statsList = append(statsList, stats)
 		// Keep track of user-defined types used in histograms.
 		if udt != nil {
-			// TODO(49698): If we ever support multi-column histograms we'll need to
-			// build this mapping in a different way.
+			// Multi-column histograms are not collected on columns with
+			// user-defined types, so only single-column histograms are relevant.
 			if len(stats.ColumnIDs) == 1 {
 				colID := stats.ColumnIDs[0]
 				if udts == nil {
@@ -0,0 +1,249 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package stmthints
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// Enabled controls whether statement hints are applied by the optimizer.
+var Enabled = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.statement_hints.enabled",
+	"if enabled, the hints stored in system.statement_hints are applied when "+
+		"planning statements with a matching fingerprint",
+	true,
+	settings.WithPublic)
+
+var pollingInterval = settings.RegisterDurationSetting(
+	settings.ApplicationLevel,
+	"sql.statement_hints.poll_interval",
+	"rate at which the stmthints.Cache polls for statement hints, set to zero to disable",
+	10*time.Second,
+	settings.NonNegativeDuration,
+)
+
+// Cache maintains a view of the hints in system.statement_hints.
+type Cache struct {
+	mu struct {
+		// NOTE: This lock can't be held while the cache runs any statements
+		// internally; it'd deadlock.
+		syncutil.Mutex
+		// hints maps statement fingerprints to their hints.
+		hints map[string]*Hints
+
+		// epoch is observed before reading system.statement_hints, and then
+		// checked again before loading the table's contents. If the value changed
+		// in between, then the table contents might be stale.
+		epoch int
+	}
+	st *cluster.Settings
+	db isql.DB
+}
+
+// NewCache constructs a new Cache.
+func NewCache(db isql.DB, st *cluster.Settings) *Cache {
+	return &Cache{
+		db: db,
+		st: st,
+	}
+}
+
+// Start will start the polling loop for the Cache.
+func (c *Cache) Start(ctx context.Context, stopper *stop.Stopper) {
+	ctx, _ = stopper.WithCancelOnQuiesce(ctx)
+
+	// Since background statement hint maintenance is not under user control,
+	// exclude it from cost accounting and control.
+	ctx = multitenant.WithTenantCostControlExemption(ctx)
+
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = stopper.RunAsyncTask(ctx, "statement-hints-poll", c.poll)
+}
+
+func (c *Cache) poll(ctx context.Context) {
+	var (
+		timer               timeutil.Timer
+		lastPoll            time.Time
+		deadline            time.Time
+		pollIntervalChanged = make(chan struct{}, 1)
+		maybeResetTimer     = func() {
+			if interval := pollingInterval.Get(&c.st.SV); interval == 0 {
+				// Setting the interval to zero stops the polling.
+				timer.Stop()
+			} else {
+				newDeadline := lastPoll.Add(interval)
+				if deadline.IsZero() || !deadline.Equal(newDeadline) {
+					deadline = newDeadline
+					timer.Reset(timeutil.Until(deadline))
+				}
+			}
+		}
+		poll = func() {
+			if err := c.pollHints(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Warningf(ctx, "error polling for statement hints: %s", err)
+			}
+			lastPoll = timeutil.Now()
+		}
+	)
+	pollingInterval.SetOnChange(&c.st.SV, func(ctx context.Context) {
+		select {
+		case pollIntervalChanged <- struct{}{}:
+		default:
+		}
+	})
+	for {
+		maybeResetTimer()
+		select {
+		case <-pollIntervalChanged:
+			continue // go back around and maybe reset the timer
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return
+		}
+		poll()
+	}
+}
+
+func (c *Cache) pollHints(ctx context.Context) error {
+	// The table does not exist until the upgrade that adds it has run.
+	if !c.st.Version.IsActive(ctx, clusterversion.V25_1_AddStatementHintsTable) {
+		return nil
+	}
+
+	var rows []tree.Datums
+
+	// Loop until we run the query without straddling an epoch increment.
+	for {
+		c.mu.Lock()
+		epoch := c.mu.epoch
+		c.mu.Unlock()
+
+		it, err := c.db.Executor().QueryIteratorEx(ctx, "statement-hints-poll", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			`SELECT fingerprint, hint FROM system.statement_hints ORDER BY created_at, hint`,
+		)
+		if err != nil {
+			return err
+		}
+		rows = rows[:0]
+		var ok bool
+		for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+			rows = append(rows, it.Cur())
+		}
+		if err != nil {
+			return err
+		}
+
+		c.mu.Lock()
+		// If the epoch changed it means that a hint was created or dropped
+		// manually while the query was running. In that case, if we were to
+		// process the query results normally, we might undo that change.
+		if c.mu.epoch != epoch {
+			c.mu.Unlock()
+			continue
+		}
+		break
+	}
+	defer c.mu.Unlock()
+
+	hints := make(map[string]*Hints)
+	for _, row := range rows {
+		fingerprint := string(tree.MustBeDString(row[0]))
+		hint, err := Parse(string(tree.MustBeDString(row[1])))
+		if err != nil {
+			// Hints are validated when they are created, so this should only
+			// happen if the hint syntax changed in a newer version.
+			log.Warningf(ctx, "ignoring statement hint for %q: %v", fingerprint, err)
+			continue
+		}
+		h, ok := hints[fingerprint]
+		if !ok {
+			h = &Hints{}
+			hints[fingerprint] = h
+		}
+		h.add(hint)
+	}
+	c.mu.hints = hints
+	return nil
+}
+
+// Get returns the hints for the given statement fingerprint, or nil if there
+// are none. The returned hints must not be modified.
+func (c *Cache) Get(fingerprint string) *Hints {
+	if !Enabled.Get(&c.st.SV) {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.hints[fingerprint]
+}
+
+// Create stores the given hint for the given statement fingerprint. It returns
+// false if an identical hint already exists.
+func (c *Cache) Create(ctx context.Context, fingerprint string, hint Hint) (bool, error) {
+	rowsAffected, err := c.db.Executor().ExecEx(ctx, "statement-hints-create", nil, /* txn */
+		sessiondata.NodeUserSessionDataOverride,
+		`INSERT INTO system.statement_hints (fingerprint, hint) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`,
+		fingerprint, hint.String(),
+	)
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, c.refresh(ctx)
+}
+
+// Drop removes the given hint for the given statement fingerprint, or all of
+// its hints if hint is nil. It returns the number of hints that were removed.
+func (c *Cache) Drop(ctx context.Context, fingerprint string, hint *Hint) (int, error) {
+	var rowsAffected int
+	var err error
+	if hint == nil {
+		rowsAffected, err = c.db.Executor().ExecEx(ctx, "statement-hints-drop", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			`DELETE FROM system.statement_hints WHERE fingerprint = $1`,
+			fingerprint,
+		)
+	} else {
+		rowsAffected, err = c.db.Executor().ExecEx(ctx, "statement-hints-drop", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			`DELETE FROM system.statement_hints WHERE fingerprint = $1 AND hint = $2`,
+			fingerprint, hint.String(),
+		)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return rowsAffected, c.refresh(ctx)
+}
+
+// refresh reloads the hints after they were modified on this node. This lets
+// this node use the new hints right away, without waiting for the poller.
+func (c *Cache) refresh(ctx context.Context) error {
+	c.mu.Lock()
+	c.mu.epoch++
+	c.mu.Unlock()
+	return c.pollHints(ctx)
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+// Package stmthints implements external statement hints. An operator can
+// attach hints to a statement fingerprint with CREATE STATEMENT HINT, without
+// changing the SQL issued by the application. The hints are stored in
+// system.statement_hints and cached on every node by the Cache, which is
+// consulted while planning statements.
+package stmthints
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// Kind identifies the kind of a statement hint.
+type Kind int
+
+const (
+	// IndexHint is a hint of the form "table@index" or "table@{...}", which
+	// applies the given index flags to references to the table.
+	IndexHint Kind = iota
+	// JoinHint is a hint of the form "<algorithm> JOIN", which applies the
+	// given join algorithm to the joins in the statement.
+	JoinHint
+	// SessionVarHint is a hint of the form "SET name = value", which overrides
+	// a session variable while the statement is planned.
+	SessionVarHint
+)
+
+// Hint is a single parsed statement hint.
+type Hint struct {
+	Kind Kind
+	// Table and IndexFlags are set for index hints.
+	Table      tree.Name
+	IndexFlags *tree.IndexFlags
+	// JoinAlgorithm is set for join hints, and is one of tree.AstHash,
+	// tree.AstLookup, tree.AstMerge, tree.AstInverted or tree.AstStraight.
+	JoinAlgorithm string
+	// SessionVar is set for session variable hints.
+	SessionVar tree.RoutineSessionVar
+}
+
+// Parse parses a statement hint. See Kind for the supported forms.
+func Parse(hint string) (Hint, error) {
+	hint = strings.TrimSpace(hint)
+	words := strings.Fields(hint)
+	if len(words) == 0 {
+		return Hint{}, pgerror.New(pgcode.InvalidParameterValue, "statement hint must not be empty")
+	}
+	switch {
+	case strings.EqualFold(words[0], "SET"):
+		return parseSessionVarHint(hint)
+	case len(words) == 2 && strings.EqualFold(words[1], "JOIN"):
+		return parseJoinHint(hint, words[0])
+	default:
+		return parseIndexHint(hint)
+	}
+}
+
+func parseIndexHint(hint string) (Hint, error) {
+	stmt, err := parser.ParseOne("SELECT * FROM " + hint)
+	if err != nil {
+		return Hint{}, invalidHintError(hint, err)
+	}
+	var source *tree.AliasedTableExpr
+	if sel, ok := stmt.AST.(*tree.Select); ok {
+		if clause, ok := sel.Select.(*tree.SelectClause); ok && len(clause.From.Tables) == 1 {
+			source, _ = clause.From.Tables[0].(*tree.AliasedTableExpr)
+		}
+	}
+	if source == nil || source.IndexFlags == nil || source.Ordinality || source.As.Alias != "" {
+		return Hint{}, invalidHintError(hint, nil /* err */)
+	}
+	tn, ok := source.Expr.(*tree.TableName)
+	if !ok {
+		return Hint{}, invalidHintError(hint, nil /* err */)
+	}
+	if err := source.IndexFlags.Check(); err != nil {
+		return Hint{}, invalidHintError(hint, err)
+	}
+	return Hint{Kind: IndexHint, Table: tn.ObjectName, IndexFlags: source.IndexFlags}, nil
+}
+
+func parseJoinHint(hint string, algorithm string) (Hint, error) {
+	algorithm = strings.ToUpper(algorithm)
+	switch algorithm {
+	case tree.AstHash, tree.AstLookup, tree.AstMerge, tree.AstInverted, tree.AstStraight:
+		return Hint{Kind: JoinHint, JoinAlgorithm: algorithm}, nil
+	}
+	return Hint{}, invalidHintError(hint, nil /* err */)
+}
+
+func parseSessionVarHint(hint string) (Hint, error) {
+	stmt, err := parser.ParseOne(hint)
+	if err != nil {
+		return Hint{}, invalidHintError(hint, err)
+	}
+	n, ok := stmt.AST.(*tree.SetVar)
+	if !ok || n.Reset || n.ResetAll || n.Local || n.SetRow || len(n.Values) == 0 {
+		return Hint{}, invalidHintError(hint, nil /* err */)
+	}
+	values := make([]string, len(n.Values))
+	for i, expr := range n.Values {
+		switch t := expr.(type) {
+		case *tree.StrVal:
+			values[i] = t.RawString()
+		case *tree.NumVal:
+			values[i] = t.OrigString()
+		case *tree.DBool:
+			values[i] = t.String()
+		case *tree.UnresolvedName:
+			values[i] = t.String()
+		default:
+			return Hint{}, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid statement hint %q: value of %s must be a constant", hint, n.Name)
+		}
+	}
+	return Hint{
+		Kind: SessionVarHint,
+		SessionVar: tree.RoutineSessionVar{
+			Name:  strings.ToLower(n.Name),
+			Value: strings.Join(values, ", "),
+		},
+	}, nil
+}
+
+func invalidHintError(hint string, err error) error {
+	if err != nil {
+		return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid statement hint %q", hint)
+	}
+	return pgerror.Newf(pgcode.InvalidParameterValue,
+		"invalid statement hint %q: expected table@index, <algorithm> JOIN, or SET name = value", hint)
+}
+
+// String returns the canonical form of the hint, which is the form that is
+// stored in system.statement_hints.
+func (h Hint) String() string {
+	switch h.Kind {
+	case IndexHint:
+		tn := tree.MakeUnqualifiedTableName(h.Table)
+		return tree.AsString(&tree.AliasedTableExpr{Expr: &tn, IndexFlags: h.IndexFlags})
+	case JoinHint:
+		return h.JoinAlgorithm + " JOIN"
+	default:
+		return "SET " + h.SessionVar.Name + " = " + lexbase.EscapeSQLString(h.SessionVar.Value)
+	}
+}
+
+// Hints are the combined hints of a statement fingerprint.
+type Hints struct {
+	// IndexFlags maps unqualified table names to the index flags of the index
+	// hint for the table.
+	IndexFlags map[tree.Name]*tree.IndexFlags
+	// JoinHint is the join algorithm of the join hint, if there is one.
+	JoinHint string
+	// SessionVars are the session variable overrides.
+	SessionVars []tree.RoutineSessionVar
+}
+
+// add adds the given hint to the set of hints. Later index and join hints
+// replace earlier hints for the same table or join.
+func (h *Hints) add(hint Hint) {
+	switch hint.Kind {
+	case IndexHint:
+		if h.IndexFlags == nil {
+			h.IndexFlags = make(map[tree.Name]*tree.IndexFlags)
+		}
+		h.IndexFlags[hint.Table] = hint.IndexFlags
+	case JoinHint:
+		h.JoinHint = hint.JoinAlgorithm
+	case SessionVarHint:
+		h.SessionVars = append(h.SessionVars, hint.SessionVar)
+	}
+}
@@ -599,6 +599,30 @@ var tableParams = map[string]tableParam{
 			return nil
 		},
 	},
+	`family_per_column`: {
+		onSet: func(ctx context.Context, po *Setter, semaCtx *tree.SemaContext, evalCtx *eval.Context, key string, datum tree.Datum) error {
+			// The layout determines how the column families are assigned, so
+			// existing data would have to be rewritten to change it.
+			if !po.TableDesc.IsNew() {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"%s can only be set when the table is created", key)
+			}
+			boolVal, err := boolFromDatum(ctx, evalCtx, key, datum)
+			if err != nil {
+				return err
+			}
+			po.TableDesc.FamilyPerColumn = boolVal
+			return nil
+		},
+		onReset: func(ctx context.Context, po *Setter, evalCtx *eval.Context, key string) error {
+			if po.TableDesc.FamilyPerColumn && !po.TableDesc.IsNew() {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"%s cannot be reset after the table is created", key)
+			}
+			po.TableDesc.FamilyPerColumn = false
+			return nil
+		},
+	},
 }
 
 func nonNegativeIntWithMaximum(max int64) func(int64) error {
@@ -0,0 +1,570 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvpb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/fetchpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// Operations reported by TableDiff.
+const (
+	tableDiffInsert = "insert"
+	tableDiffUpdate = "update"
+	tableDiffDelete = "delete"
+)
+
+// TableDiff is part of the eval.Planner interface.
+//
+// The rows changed in (startTime, endTime], where endTime is the read
+// timestamp of the transaction, are discovered with incremental ExportRequests
+// (backed by an MVCCIncrementalIterator) over the table's primary index as of
+// startTime and as of endTime; the two differ if a schema change rebuilt the
+// primary index in the meantime. The before image of each changed row is read
+// at startTime and decoded with the descriptor version valid at startTime,
+// while the after image is read and decoded with the version valid at endTime.
+// Both images are reported using the columns of the table as of endTime:
+// columns dropped since startTime are omitted and columns added since are NULL
+// in the before image.
+//
+// The result rows are produced incrementally by merging two streaming scans
+// of the changed rows, so only the spans of the changed rows are held in
+// memory; they are accounted for against the planner's memory monitor.
+func (p *planner) TableDiff(
+	ctx context.Context, tableID int64, startTime hlc.Timestamp,
+) (_ eval.InternalRows, retErr error) {
+	endTime := p.txn.ReadTimestamp()
+	if startTime.IsEmpty() || endTime.LessEq(startTime) {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+			"start time %s must be before the read timestamp %s", startTime, endTime)
+	}
+
+	// Resolve the descriptor version valid at endTime through the transaction's
+	// descriptor collection.
+	curDesc, err := p.Descriptors().ByIDWithLeased(p.txn).WithoutNonPublic().Get().Table(
+		ctx, descpb.ID(tableID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, curDesc, privilege.SELECT); err != nil {
+		return nil, err
+	}
+
+	execCfg := p.ExecCfg()
+	rows := &tableDiffRows{ctx: ctx, p: p, acc: p.Mon().MakeBoundAccount()}
+	defer func() {
+		if retErr != nil {
+			_ = rows.Close()
+		}
+	}()
+	if rows.cur, err = newTableDiffIndex(ctx, p, p.txn, curDesc, curDesc); err != nil {
+		return nil, err
+	}
+
+	// Resolve the descriptor version valid at startTime. If the table did not
+	// exist yet, every row is reported as inserted.
+	if err := execCfg.InternalDB.DescsTxn(ctx, func(ctx context.Context, txn descs.Txn) error {
+		if err := txn.KV().SetFixedTimestamp(ctx, startTime); err != nil {
+			return err
+		}
+		histDesc, err := txn.Descriptors().ByIDWithoutLeased(txn.KV()).WithoutNonPublic().Get().Table(
+			ctx, descpb.ID(tableID),
+		)
+		if err != nil {
+			if pgerror.GetPGCode(err) == pgcode.UndefinedTable {
+				return nil
+			}
+			return err
+		}
+		// The before images are read in a transaction of their own, which stays
+		// open while the result rows are produced and is rolled back in Close.
+		// The closure may be retried, so release a transaction created by a
+		// previous attempt first.
+		rows.rollbackHistTxn()
+		rows.histTxn = execCfg.DB.NewTxn(ctx, "table diff")
+		if err := rows.histTxn.SetFixedTimestamp(ctx, startTime); err != nil {
+			return err
+		}
+		rows.hist, err = newTableDiffIndex(ctx, p, rows.histTxn, histDesc, curDesc)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if rows.hist != nil && !tableDiffSameKey(rows.hist.index, rows.cur.index) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"primary key of table %q changed after %s", curDesc.GetName(), startTime)
+	}
+
+	// Collect the changed rows as key suffixes following the index prefix, so
+	// that rows from both versions of the primary index can be matched up.
+	changed, err := rows.cur.changedSpans(ctx, execCfg.DB, &rows.acc, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	if rows.hist != nil && rows.hist.index.GetID() != rows.cur.index.GetID() {
+		histChanged, err := rows.hist.changedSpans(ctx, execCfg.DB, &rows.acc, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, histChanged...)
+	}
+	changed, _ = roachpb.MergeSpans(&changed)
+	if len(changed) == 0 {
+		rows.done = true
+		return rows, nil
+	}
+
+	forceProductionValues := p.EvalContext().TestingKnobs.ForceProductionValues
+	if rows.hist != nil {
+		if err := rows.hist.startScan(ctx, changed, forceProductionValues); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.cur.startScan(ctx, changed, forceProductionValues); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// tableDiffRows is the eval.InternalRows returned by TableDiff. It merges the
+// before and after images of the changed rows, which are both sorted by key
+// suffix, to pair up the images of each row.
+type tableDiffRows struct {
+	// ctx is used to release the resources of the rows in Close, which is not
+	// passed a context.
+	ctx       context.Context
+	p         *planner
+	hist, cur *tableDiffIndex
+	// histTxn is the read-only transaction at the start timestamp that hist
+	// reads from. It is never committed, only rolled back in Close.
+	histTxn *kv.Txn
+	// acc accounts for the spans of the changed rows.
+	acc  mon.BoundAccount
+	row  tree.Datums
+	done bool
+}
+
+var _ eval.InternalRows = &tableDiffRows{}
+
+// Next is part of the eval.InternalRows interface.
+func (r *tableDiffRows) Next(ctx context.Context) (bool, error) {
+	for !r.done {
+		var before, after tree.Datums
+		hist, cur := r.hist, r.cur
+		if hist != nil && hist.exhausted() {
+			hist = nil
+		}
+		if cur.exhausted() {
+			cur = nil
+		}
+		switch {
+		case hist == nil && cur == nil:
+			r.done = true
+			return false, nil
+		case cur == nil:
+			before = hist.datums
+		case hist == nil:
+			after = cur.datums
+		default:
+			switch c := bytes.Compare(hist.suffix, cur.suffix); {
+			case c < 0:
+				before, cur = hist.datums, nil
+			case c > 0:
+				after, hist = cur.datums, nil
+			default:
+				before, after = hist.datums, cur.datums
+			}
+		}
+		row, err := tableDiffMakeRow(r.p, r.cur, before, after)
+		if err != nil {
+			return false, err
+		}
+		// Advance the sides whose current row has been consumed.
+		if before != nil {
+			if err := hist.next(ctx); err != nil {
+				return false, err
+			}
+		}
+		if after != nil {
+			if err := cur.next(ctx); err != nil {
+				return false, err
+			}
+		}
+		if row != nil {
+			r.row = row
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Cur is part of the eval.InternalRows interface.
+func (r *tableDiffRows) Cur() tree.Datums {
+	return r.row
+}
+
+// Close is part of the eval.InternalRows interface.
+func (r *tableDiffRows) Close() error {
+	ctx := r.ctx
+	r.done = true
+	if r.hist != nil {
+		r.hist.rf.Close(ctx)
+	}
+	if r.cur != nil {
+		r.cur.rf.Close(ctx)
+	}
+	r.rollbackHistTxn()
+	r.acc.Close(ctx)
+	return nil
+}
+
+// rollbackHistTxn rolls back the transaction the before images are read in,
+// if any.
+func (r *tableDiffRows) rollbackHistTxn() {
+	if r.histTxn == nil {
+		return
+	}
+	if err := r.histTxn.Rollback(r.ctx); err != nil {
+		log.Warningf(r.ctx, "failed to roll back table diff transaction: %v", err)
+	}
+	r.histTxn = nil
+}
+
+// tableDiffSameKey returns whether two primary indexes encode their keys the
+// same way.
+func tableDiffSameKey(a, b catalog.Index) bool {
+	if a.NumKeyColumns() != b.NumKeyColumns() {
+		return false
+	}
+	for i := 0; i < a.NumKeyColumns(); i++ {
+		if a.GetKeyColumnID(i) != b.GetKeyColumnID(i) ||
+			a.GetKeyColumnDirection(i) != b.GetKeyColumnDirection(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// tableDiffMakeRow returns the (op, key, before, after) result row for a row
+// with the given decoded before and after images, or nil if the row is
+// unchanged.
+func tableDiffMakeRow(
+	p *planner, cur *tableDiffIndex, before, after tree.Datums,
+) (tree.Datums, error) {
+	var beforeJSON, afterJSON, keyJSON json.JSON
+	var err error
+	if before != nil {
+		if beforeJSON, err = cur.toJSON(p, before, nil /* ords */); err != nil {
+			return nil, err
+		}
+		if keyJSON, err = cur.toJSON(p, before, cur.keyOrds); err != nil {
+			return nil, err
+		}
+	}
+	if after != nil {
+		if afterJSON, err = cur.toJSON(p, after, nil /* ords */); err != nil {
+			return nil, err
+		}
+		if keyJSON, err = cur.toJSON(p, after, cur.keyOrds); err != nil {
+			return nil, err
+		}
+	}
+
+	op := tableDiffUpdate
+	switch {
+	case beforeJSON == nil:
+		op = tableDiffInsert
+	case afterJSON == nil:
+		op = tableDiffDelete
+	default:
+		// The row may have been rewritten with identical contents, or only
+		// columns which have since been dropped may have changed.
+		if c, err := beforeJSON.Compare(afterJSON); err != nil {
+			return nil, err
+		} else if c == 0 {
+			return nil, nil
+		}
+	}
+	res := tree.Datums{tree.NewDString(op), tree.NewDJSON(keyJSON), tree.DNull, tree.DNull}
+	if beforeJSON != nil {
+		res[2] = tree.NewDJSON(beforeJSON)
+	}
+	if afterJSON != nil {
+		res[3] = tree.NewDJSON(afterJSON)
+	}
+	return res, nil
+}
+
+// tableDiffIndex reads and decodes the rows of one version of a table's
+// primary index. Decoded rows are laid out according to the public columns of
+// the current version of the table.
+type tableDiffIndex struct {
+	index  catalog.Index
+	prefix roachpb.Key
+
+	rf row.Fetcher
+	// cols are the public columns of the current version of the table.
+	cols []catalog.Column
+	// fetchOrds maps each fetched column to its ordinal in cols.
+	fetchOrds []int
+	// keyOrds are the ordinals in cols of the primary key columns.
+	keyOrds []int
+
+	// suffix is the encoded primary key, without the index prefix, of the row
+	// at the current position of the scan, and datums are its decoded values.
+	// datums is nil once the scan is exhausted.
+	suffix []byte
+	datums tree.Datums
+}
+
+// newTableDiffIndex returns a tableDiffIndex that reads the primary index of
+// desc in txn.
+func newTableDiffIndex(
+	ctx context.Context, p *planner, txn *kv.Txn, desc, curDesc catalog.TableDescriptor,
+) (*tableDiffIndex, error) {
+	codec := p.ExecCfg().Codec
+	idx := &tableDiffIndex{
+		index: desc.GetPrimaryIndex(),
+		cols:  curDesc.PublicColumns(),
+	}
+	idx.prefix = rowenc.MakeIndexKeyPrefix(codec, desc.GetID(), idx.index.GetID())
+
+	ordByID := make(map[descpb.ColumnID]int, len(idx.cols))
+	for i, col := range idx.cols {
+		ordByID[col.GetID()] = i
+	}
+	var colIDs []descpb.ColumnID
+	for _, col := range desc.PublicColumns() {
+		if ord, ok := ordByID[col.GetID()]; ok {
+			colIDs = append(colIDs, col.GetID())
+			idx.fetchOrds = append(idx.fetchOrds, ord)
+		}
+	}
+	for i := 0; i < idx.index.NumKeyColumns(); i++ {
+		if ord, ok := ordByID[idx.index.GetKeyColumnID(i)]; ok {
+			idx.keyOrds = append(idx.keyOrds, ord)
+		}
+	}
+
+	var spec fetchpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(&spec, codec, desc, idx.index, colIDs); err != nil {
+		return nil, err
+	}
+	if err := idx.rf.Init(ctx, row.FetcherInitArgs{
+		Txn:        txn,
+		Alloc:      &tree.DatumAlloc{},
+		MemMonitor: p.Mon(),
+		Spec:       &spec,
+	}); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// changedSpans returns the spans, relative to the index prefix, of the rows of
+// the index which were written in (startTime, endTime]. Rows removed by MVCC
+// range tombstones, such as those written when the index is dropped, are
+// covered by the tombstone's bounds. The memory used by the spans is accounted
+// for in acc.
+func (idx *tableDiffIndex) changedSpans(
+	ctx context.Context, db *kv.DB, acc *mon.BoundAccount, startTime, endTime hlc.Timestamp,
+) ([]roachpb.Span, error) {
+	header := kvpb.Header{
+		Timestamp:                   endTime,
+		ReturnElasticCPUResumeSpans: true,
+	}
+	var changed []roachpb.Span
+	span := roachpb.Span{Key: idx.prefix, EndKey: idx.prefix.PrefixEnd()}
+	for {
+		req := &kvpb.ExportRequest{
+			RequestHeader: kvpb.RequestHeader{Key: span.Key, EndKey: span.EndKey},
+			StartTime:     startTime,
+			MVCCFilter:    kvpb.MVCCFilter_Latest,
+		}
+		res, pErr := kv.SendWrappedWith(ctx, db.NonTransactionalSender(), header, req)
+		if pErr != nil {
+			return nil, errors.Wrapf(pErr.GoError(), "error in retrieving changes between %s, %s",
+				startTime, endTime)
+		}
+		exportResp := res.(*kvpb.ExportResponse)
+		for _, file := range exportResp.Files {
+			if err := func() error {
+				it, err := storage.NewMemSSTIterator(file.SST, false, /* verify */
+					storage.IterOptions{
+						KeyTypes:   storage.IterKeyTypePointsAndRanges,
+						LowerBound: keys.MinKey,
+						UpperBound: keys.MaxKey,
+					})
+				if err != nil {
+					return err
+				}
+				defer it.Close()
+				for it.SeekGE(storage.NilKey); ; it.Next() {
+					if ok, err := it.Valid(); err != nil {
+						return err
+					} else if !ok {
+						return nil
+					}
+					hasPoint, hasRange := it.HasPointAndRange()
+					if hasRange && it.RangeKeyChanged() {
+						bounds := it.RangeBounds()
+						sp := roachpb.Span{
+							Key:    idx.keySuffix(bounds.Key),
+							EndKey: idx.keySuffix(bounds.EndKey),
+						}
+						if err := acc.Grow(ctx, int64(sp.MemUsage())); err != nil {
+							return err
+						}
+						changed = append(changed, sp)
+					}
+					if !hasPoint {
+						continue
+					}
+					rowKey, err := keys.EnsureSafeSplitKey(it.UnsafeKey().Key)
+					if err != nil {
+						return err
+					}
+					suffix := idx.keySuffix(rowKey)
+					sp := roachpb.Span{Key: suffix, EndKey: suffix.PrefixEnd()}
+					if err := acc.Grow(ctx, int64(sp.MemUsage())); err != nil {
+						return err
+					}
+					changed = append(changed, sp)
+				}
+			}(); err != nil {
+				return nil, err
+			}
+		}
+
+		// Check if the ExportRequest paginated with a resume span.
+		if exportResp.ResumeSpan == nil {
+			break
+		}
+		span.Key = exportResp.ResumeSpan.Key
+	}
+	return changed, nil
+}
+
+// keySuffix returns a copy of key with the index prefix removed. Keys before
+// the index are mapped to the empty suffix and keys after it to the maximal
+// one.
+func (idx *tableDiffIndex) keySuffix(key roachpb.Key) roachpb.Key {
+	switch {
+	case bytes.HasPrefix(key, idx.prefix):
+		return roachpb.Key(key[len(idx.prefix):]).Clone()
+	case key.Compare(idx.prefix) < 0:
+		return roachpb.Key{}
+	default:
+		return roachpb.Key(keys.MaxKey).Clone()
+	}
+}
+
+// startScan starts a scan of the rows of the index within the given suffix
+// spans and positions it on the first row.
+func (idx *tableDiffIndex) startScan(
+	ctx context.Context, suffixes []roachpb.Span, forceProductionValues bool,
+) error {
+	spans := make(roachpb.Spans, len(suffixes))
+	for i, sp := range suffixes {
+		spans[i] = roachpb.Span{
+			Key:    append(idx.prefix.Clone(), sp.Key...),
+			EndKey: append(idx.prefix.Clone(), sp.EndKey...),
+		}
+		if sp.EndKey.Equal(keys.MaxKey) {
+			spans[i].EndKey = idx.prefix.PrefixEnd()
+		}
+	}
+	if err := idx.rf.StartScan(
+		ctx, spans, nil, /* spanIDs */
+		rowinfra.GetDefaultBatchBytesLimit(forceProductionValues), rowinfra.NoRowLimit,
+	); err != nil {
+		return err
+	}
+	idx.datums = make(tree.Datums, len(idx.cols))
+	return idx.next(ctx)
+}
+
+// exhausted returns whether the scan has no more rows.
+func (idx *tableDiffIndex) exhausted() bool {
+	return idx.datums == nil
+}
+
+// next advances the scan to the next row, decoding it into datums laid out
+// according to the current columns of the table. Columns not present in this
+// version of the table are NULL.
+func (idx *tableDiffIndex) next(ctx context.Context) error {
+	// The fetcher is positioned on the first KV of the next row.
+	key := idx.rf.Key()
+	if key == nil {
+		idx.datums = nil
+		return nil
+	}
+	rowKey, err := keys.EnsureSafeSplitKey(key)
+	if err != nil {
+		return err
+	}
+	idx.suffix = append(idx.suffix[:0], rowKey[len(idx.prefix):]...)
+	fetched, err := idx.rf.NextRowDecoded(ctx)
+	if err != nil {
+		return err
+	}
+	if fetched == nil {
+		idx.datums = nil
+		return nil
+	}
+	for i := range idx.datums {
+		idx.datums[i] = tree.DNull
+	}
+	for i, ord := range idx.fetchOrds {
+		idx.datums[ord] = fetched[i]
+	}
+	return nil
+}
+
+// toJSON builds a JSON object keyed by column name from the datums at the
+// given ordinals, or from all datums if ords is nil.
+func (idx *tableDiffIndex) toJSON(p *planner, datums tree.Datums, ords []int) (json.JSON, error) {
+	if ords == nil {
+		ords = make([]int, len(datums))
+		for i := range ords {
+			ords[i] = i
+		}
+	}
+	sd := p.SessionData()
+	b := json.NewObjectBuilder(len(ords))
+	for _, i := range ords {
+		j, err := tree.AsJSON(datums[i], sd.DataConversionConfig, sd.GetLocation())
+		if err != nil {
+			return nil, err
+		}
+		b.Add(idx.cols[i].GetName(), j)
+	}
+	return b.Build(), nil
+}
@@ -245,6 +245,8 @@ func CalcArrayOid(elemTyp *T) oid.Oid {
 	// init method).
 	if o == oid.T_json {
 		o = oid.T__json
+	} else if o == oidext.T_anycompatible {
+		o = oidext.T_anycompatiblearray
 	} else {
 		o = oidToArrayOid[o]
 	}
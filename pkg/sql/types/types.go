@@ -596,6 +596,23 @@ var (
 	AnyArray = &T{InternalType: InternalType{
 		Family: ArrayFamily, ArrayContents: Any, Oid: oid.T_anyarray, Locale: &emptyLocale}}
 
+	// AnyCompatible is a special type used only during static analysis as a
+	// polymorphic routine parameter type. Like Any, it matches any other type,
+	// but the arguments supplied for AnyCompatible parameters are only required
+	// to be implicitly castable to a common type, rather than being identical.
+	// Execution-time values should never have this type.
+	AnyCompatible = &T{InternalType: InternalType{
+		Family: AnyFamily, Oid: oidext.T_anycompatible, Locale: &emptyLocale}}
+
+	// AnyCompatibleArray is a special type used only during static analysis as
+	// a polymorphic routine parameter type that matches an array having
+	// elements of any (uniform) type. Its element type is resolved together
+	// with any AnyCompatible parameters. Execution-time values should never
+	// have this type.
+	AnyCompatibleArray = &T{InternalType: InternalType{
+		Family: ArrayFamily, ArrayContents: AnyCompatible, Oid: oidext.T_anycompatiblearray,
+		Locale: &emptyLocale}}
+
 	// AnyEnum is a special type only used during static analysis as a wildcard
 	// type that matches an possible enum value. Execution-time values should
 	// never have this type.
@@ -1587,6 +1604,9 @@ func (f Family) Name() redact.SafeString {
 func (t *T) Name() string {
 	switch fam := t.Family(); fam {
 	case AnyFamily:
+		if t.Oid() == oidext.T_anycompatible {
+			return "anycompatible"
+		}
 		return "anyelement"
 
 	case ArrayFamily:
@@ -1597,6 +1617,8 @@ func (t *T) Name() string {
 			return "int2vector"
 		case oid.T_anyarray:
 			return "anyarray"
+		case oidext.T_anycompatiblearray:
+			return "anycompatiblearray"
 		}
 		return t.ArrayContents().Name() + "[]"
 
@@ -1730,6 +1752,9 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
+		if t.Oid() == oidext.T_anycompatible {
+			return "anycompatible"
+		}
 		return "anyelement"
 	case ArrayFamily:
 		switch t.Oid() {
@@ -1739,6 +1764,8 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return "int2vector"
 		case oid.T_anyarray:
 			return "anyarray"
+		case oidext.T_anycompatiblearray:
+			return "anycompatiblearray"
 		}
 		// If we have a typemod specified then pass it down when
 		// formatting the array type.
@@ -2259,6 +2286,7 @@ func (t *T) Equal(other *T) bool {
 func (t *T) IsWildcardType() bool {
 	for _, wildcard := range []*T{
 		Any, AnyArray, AnyCollatedString, AnyEnum, AnyEnumArray, AnyTuple, AnyTupleArray,
+		AnyCompatible, AnyCompatibleArray,
 	} {
 		// Note that pointer comparison is insufficient since we might have
 		// deserialized t from disk.
@@ -2273,7 +2301,7 @@ func (t *T) IsWildcardType() bool {
 // return-type of a polymorphic function. Note that this does not include RECORD
 // (AnyTuple) or RECORD[].
 func (t *T) IsPolymorphicType() bool {
-	for _, poly := range []*T{Any, AnyArray, AnyEnum, AnyEnumArray} {
+	for _, poly := range []*T{Any, AnyArray, AnyEnum, AnyEnumArray, AnyCompatible, AnyCompatibleArray} {
 		if t.Identical(poly) {
 			return true
 		}
@@ -2281,6 +2309,14 @@ func (t *T) IsPolymorphicType() bool {
 	return false
 }
 
+// IsAnyCompatibleType returns true if the type is one of the polymorphic
+// "anycompatible" family of types (ANYCOMPATIBLE or ANYCOMPATIBLEARRAY). The
+// concrete type for these parameters is resolved separately from the other
+// polymorphic types.
+func (t *T) IsAnyCompatibleType() bool {
+	return t.Identical(AnyCompatible) || t.Identical(AnyCompatibleArray)
+}
+
 // IsPseudoType returns true if the type is a pseudotype.
 func (t *T) IsPseudoType() bool {
 	return t.Identical(Trigger) || t.IsPolymorphicType()
@@ -3102,6 +3138,10 @@ var unreservedTypeTokens = map[string]*T{
 
 	"string": String,
 	"uuid":   Uuid,
+
+	// Postgres polymorphic pseudo-types that are not present in OidToType.
+	"anycompatible":      AnyCompatible,
+	"anycompatiblearray": AnyCompatibleArray,
 }
 
 // The following map must include all types predefined in PostgreSQL
@@ -380,12 +380,14 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&completionsNode{}):                         "show completions",
 	reflect.TypeOf(&controlJobsNode{}):                         "control jobs",
 	reflect.TypeOf(&controlSchedulesNode{}):                    "control schedules",
+	reflect.TypeOf(&createAggregateNode{}):                     "create aggregate",
 	reflect.TypeOf(&createDatabaseNode{}):                      "create database",
 	reflect.TypeOf(&createExtensionNode{}):                     "create extension",
 	reflect.TypeOf(&createExternalConnectionNode{}):            "create external connection",
 	reflect.TypeOf(&createFunctionNode{}):                      "create function",
 	reflect.TypeOf(&createIndexNode{}):                         "create index",
 	reflect.TypeOf(&createSequenceNode{}):                      "create sequence",
+	reflect.TypeOf(&createStatementHintNode{}):                 "create statement hint",
 	reflect.TypeOf(&createSchemaNode{}):                        "create schema",
 	reflect.TypeOf(&createStatsNode{}):                         "create statistics",
 	reflect.TypeOf(&createTableNode{}):                         "create table",
@@ -404,6 +406,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&dropIndexNode{}):                           "drop index",
 	reflect.TypeOf(&dropSequenceNode{}):                        "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                          "drop schema",
+	reflect.TypeOf(&dropStatementHintNode{}):                   "drop statement hint",
 	reflect.TypeOf(&dropTableNode{}):                           "drop table",
 	reflect.TypeOf(&dropTenantNode{}):                          "drop tenant",
 	reflect.TypeOf(&dropTypeNode{}):                            "drop type",
@@ -446,6 +449,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&RevokeRoleNode{}):                          "revoke role",
 	reflect.TypeOf(&rowCountNode{}):                            "count",
 	reflect.TypeOf(&rowSourceToPlanNode{}):                     "row source to plan node",
+	reflect.TypeOf(&runGCNode{}):                               "run gc",
 	reflect.TypeOf(&saveTableNode{}):                           "save table",
 	reflect.TypeOf(&scanBufferNode{}):                          "scan buffer",
 	reflect.TypeOf(&scanNode{}):                                "scan",
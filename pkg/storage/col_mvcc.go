@@ -430,6 +430,18 @@ func mvccScanToCols(
 	}
 	defer mvccScanner.release()
 	adapter.scanner = mvccScanner
+	if n := len(indexFetchSpec.NeededFamilyIDs); n > 0 {
+		// Only some column families are needed (e.g. with family_per_column,
+		// each column has its own family), so we let the scanner skip the
+		// other ones. The last needed family is then the final family of each
+		// row in the results.
+		familyIDs := make([]uint32, n)
+		for i, id := range indexFetchSpec.NeededFamilyIDs {
+			familyIDs[i] = uint32(id)
+		}
+		mvccScanner.familyIDs = familyIDs
+		adapter.results.maxFamilyID = familyIDs[n-1]
+	}
 
 	// Try to use the same root monitor (from the store) if the account is
 	// provided.
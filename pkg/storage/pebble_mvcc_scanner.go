@@ -403,6 +403,13 @@ type pebbleMVCCScanner struct {
 	// allowEmpty is false, and the partial row is the first row in the result,
 	// the row will instead be completed by fetching additional KV pairs.
 	wholeRows bool
+	// If set, only the KVs of SQL rows that belong to one of the given column
+	// families are considered, and all other KVs are skipped before their
+	// values are processed. This is used by direct columnar scans of tables
+	// with one column family per column (the family_per_column storage
+	// parameter). Note that the skipped KVs are interleaved with the needed ones
+	// in the storage engine, so the iterator still steps over them.
+	familyIDs []uint32
 	// decodeMVCCHeaders is set by callers who expect to be able
 	// to read the full MVCCValueHeader off of
 	// curUnsafeValue. Used by mvccGet.
@@ -808,6 +815,13 @@ func (p *pebbleMVCCScanner) uncertaintyError(
 // The scanner must be positioned on a point key, possibly with an overlapping
 // range key. Range keys are processed separately in processRangeKeys().
 func (p *pebbleMVCCScanner) getOne(ctx context.Context) (ok, added bool) {
+	if p.familyIDs != nil && !p.includesFamily(p.curUnsafeKey.Key) {
+		// The caller is not interested in this column family, so we skip the
+		// key entirely. This is equivalent to the caller having split the scan
+		// span into per-family spans, so there is no need to check for
+		// conflicts on this key.
+		return true /* ok */, false
+	}
 	if !p.curUnsafeKey.Timestamp.IsEmpty() {
 		// Range key where read ts >= range key ts >= point key ts. Synthesize a
 		// point tombstone for it. Range key conflict checks are done in
@@ -1363,6 +1377,23 @@ func (p *pebbleMVCCScanner) addSynthetic(
 	return p.add(ctx, key, p.keyBuf, value.Value.RawBytes, version.Value)
 }
 
+// includesFamily returns whether the given key belongs to one of the column
+// families in p.familyIDs. With one column family per column, each column
+// family holds a single column of the row. Keys that are not SQL row keys are
+// always included.
+func (p *pebbleMVCCScanner) includesFamily(key roachpb.Key) bool {
+	familyID, err := keys.DecodeFamilyKey(key)
+	if err != nil {
+		return true
+	}
+	for _, id := range p.familyIDs {
+		if id == familyID {
+			return true
+		}
+	}
+	return false
+}
+
 // Seeks to the latest revision of the current key that's still less than or
 // equal to the specified timestamp and adds it to the result set.
 //   - ok indicates whether the iteration should continue.
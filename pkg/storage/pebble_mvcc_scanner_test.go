@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/isolation"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/uncertainty"
@@ -18,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
@@ -448,3 +450,70 @@ func TestMVCCScanWithMVCCValueHeaders(t *testing.T) {
 		getKeyWithScanner(roachpb.Key("dd"))
 	})
 }
+
+func TestMVCCScanWithFamilyIDs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	eng := createTestPebbleEngine()
+	defer eng.Close()
+
+	// Write three rows with three column families each.
+	indexPrefix := keys.SystemSQLCodec.IndexPrefix(104 /* tableID */, 1 /* indexID */)
+	var rowKeys []roachpb.Key
+	for row := int64(1); row <= 3; row++ {
+		rowKey := encoding.EncodeVarintAscending(indexPrefix[:len(indexPrefix):len(indexPrefix)], row)
+		for family := uint32(0); family < 3; family++ {
+			key := roachpb.Key(keys.MakeFamilyKey(rowKey[:len(rowKey):len(rowKey)], family))
+			rowKeys = append(rowKeys, key)
+			require.NoError(t, eng.PutMVCC(
+				MVCCKey{Key: key, Timestamp: hlc.Timestamp{WallTime: 1}},
+				MVCCValue{Value: roachpb.MakeValueFromString(fmt.Sprintf("%d/%d", row, family))},
+			))
+		}
+	}
+	start, end := indexPrefix, indexPrefix.PrefixEnd()
+
+	for _, reverse := range []bool{false, true} {
+		t.Run(fmt.Sprintf("reverse=%t", reverse), func(t *testing.T) {
+			reader := eng.NewReader(StandardDurability)
+			defer reader.Close()
+			iter, err := reader.NewMVCCIterator(ctx, MVCCKeyAndIntentsIterKind, IterOptions{LowerBound: start, UpperBound: end})
+			require.NoError(t, err)
+			defer iter.Close()
+
+			mvccScanner := pebbleMVCCScanner{
+				parent:     iter,
+				memAccount: mon.NewStandaloneUnlimitedAccount(),
+				reverse:    reverse,
+				start:      start,
+				end:        end,
+				ts:         hlc.Timestamp{WallTime: 2},
+				familyIDs:  []uint32{0, 2},
+			}
+			var results pebbleResults
+			mvccScanner.init(nil /* txn */, uncertainty.Interval{}, &results)
+			_, _, _, err = mvccScanner.scan(ctx)
+			require.NoError(t, err)
+
+			var scanned []roachpb.Key
+			require.NoError(t, MVCCScanDecodeKeyValues(results.finish(), func(k MVCCKey, _ []byte) error {
+				scanned = append(scanned, k.Key)
+				return nil
+			}))
+			// Only the keys of families 0 and 2 must be returned.
+			var expected []roachpb.Key
+			for i, key := range rowKeys {
+				if i%3 != 1 {
+					expected = append(expected, key)
+				}
+			}
+			if reverse {
+				for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+					expected[i], expected[j] = expected[j], expected[i]
+				}
+			}
+			require.Equal(t, expected, scanned)
+		})
+	}
+}
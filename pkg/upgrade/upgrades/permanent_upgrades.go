@@ -68,6 +68,7 @@ func bootstrapCluster(
 		{"update system.locations with default location data", updateSystemLocationData},
 		{"create default databases", createDefaultDbs},
 		{"add default SQL schema telemetry schedule", ensureSQLSchemaTelemetrySchedule},
+		{"add default SQL index advisor schedule", ensureIndexAdvisorSchedule},
 		{"create jobs metrics polling job", createJobsMetricsPollingJob},
 		{"create sql activity updater job", createActivityUpdateJobMigration},
 		{"create mvcc stats job", createMVCCStatisticsJob},
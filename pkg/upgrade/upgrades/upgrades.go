@@ -118,6 +118,30 @@ var upgrades = []upgradebase.Upgrade{
 		upgrade.RestoreActionNotRequired("cluster restore does not restore the new field"),
 	),
 
+	upgrade.NewTenantUpgrade(
+		"add statement_plan_baselines table",
+		clusterversion.V25_1_AddStatementPlanBaselinesTable.Version(),
+		upgrade.NoPrecondition,
+		addStatementPlanBaselinesTable,
+		upgrade.RestoreActionNotRequired("cluster restore does not restore this table"),
+	),
+
+	upgrade.NewTenantUpgrade(
+		"add statement_hints table",
+		clusterversion.V25_1_AddStatementHintsTable.Version(),
+		upgrade.NoPrecondition,
+		addStatementHintsTable,
+		upgrade.RestoreActionNotRequired("cluster restore does not restore this table"),
+	),
+
+	upgrade.NewTenantUpgrade(
+		"add index_recommendations table and index advisor schedule",
+		clusterversion.V25_1_AddIndexRecommendationsTable.Version(),
+		upgrade.NoPrecondition,
+		addIndexRecommendationsTable,
+		upgrade.RestoreActionNotRequired("cluster restore does not restore this table"),
+	),
+
 	// Note: when starting a new release version, the first upgrade (for
 	// Vxy_zStart) must be a newFirstUpgrade. Keep this comment at the bottom.
 }
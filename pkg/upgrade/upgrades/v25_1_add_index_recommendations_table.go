@@ -0,0 +1,50 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the CockroachDB Software License
+// included in the /LICENSE file.
+
+package upgrades
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/indexadvisor"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/upgrade"
+	"github.com/cockroachdb/errors"
+)
+
+// addIndexRecommendationsTable creates the system.index_recommendations table
+// if it does not exist, and the schedule of the index advisor job, which
+// records recommendations in it.
+func addIndexRecommendationsTable(
+	ctx context.Context, cv clusterversion.ClusterVersion, d upgrade.TenantDeps,
+) error {
+	if err := createSystemTable(
+		ctx, d.DB, d.Settings, d.Codec,
+		systemschema.IndexRecommendationsTable,
+		tree.LocalityLevelTable,
+	); err != nil {
+		return err
+	}
+	return ensureIndexAdvisorSchedule(ctx, cv, d)
+}
+
+// ensureIndexAdvisorSchedule creates the schedule of the index advisor job if
+// it does not exist. It is also run when a new cluster is bootstrapped.
+func ensureIndexAdvisorSchedule(
+	ctx context.Context, _ clusterversion.ClusterVersion, d upgrade.TenantDeps,
+) error {
+	return d.DB.Txn(ctx, func(ctx context.Context, txn isql.Txn) error {
+		_, err := indexadvisor.CreateIndexAdvisorSchedule(ctx, txn, d.Settings, d.ClusterID)
+		// If the schedule already exists, we have nothing more to do. This
+		// logic makes the upgrade idempotent.
+		if errors.Is(err, indexadvisor.ErrDuplicatedSchedules) {
+			err = nil
+		}
+		return err
+	})
+}
@@ -86,6 +86,19 @@ func (rl *RateLimiter) AdmitN(n int64) bool {
 	return rl.qp.Acquire(context.Background(), (*rateRequestNoWait)(r)) == nil
 }
 
+// RefundN returns n quota that was acquired with WaitN or AdmitN but not used
+// back into the RateLimiter.
+func (rl *RateLimiter) RefundN(n int64) {
+	if n == 0 || rl.isInf.Load() {
+		return
+	}
+	rl.qp.Update(func(res Resource) (shouldNotify bool) {
+		tb := res.(*tokenbucket.TokenBucket)
+		tb.Adjust(tokenbucket.Tokens(n))
+		return true
+	})
+}
+
 // UpdateLimit updates the rate and burst limits. The change in burst will
 // be applied to the current quantity of quota. For example, if the RateLimiter
 // currently had a quota of 5 available with a burst of 10 and the burst is
@@ -231,6 +231,22 @@ func TestRateLimiterWithVerySmallDelta(t *testing.T) {
 	require.NoError(t, <-errCh)
 }
 
+// TestRateLimiterRefund tests that refunded quota can be acquired again.
+func TestRateLimiterRefund(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	t0 := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mt := timeutil.NewManualTime(t0)
+	rl := quotapool.NewRateLimiter("test", 10, 20,
+		quotapool.WithTimeSource(mt))
+
+	require.True(t, rl.AdmitN(15))
+	require.False(t, rl.AdmitN(10))
+	rl.RefundN(5)
+	require.True(t, rl.AdmitN(10))
+	require.False(t, rl.AdmitN(1))
+}
+
 // TestRateLimiterMinimumWait tests that the WithMinimumWait option works.
 func TestRateLimiterMinimumWait(t *testing.T) {
 	defer leaktest.AfterTest(t)()